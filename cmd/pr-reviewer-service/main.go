@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
@@ -16,24 +18,73 @@ import (
 	_ "github.com/lib/pq"
 
 	"github.com/hihikaAAa/PRManager/internal/config"
+	pullrequesthandlerbulkcreate "github.com/hihikaAAa/PRManager/internal/http-server/handlers/pullrequest/bulkCreate"
+	pullrequesthandlerclose "github.com/hihikaAAa/PRManager/internal/http-server/handlers/pullrequest/close"
 	pullrequesthandlercreate "github.com/hihikaAAa/PRManager/internal/http-server/handlers/pullrequest/create"
 	pullrequesthandlersmerge "github.com/hihikaAAa/PRManager/internal/http-server/handlers/pullrequest/merge"
+	pullrequesthandlerhistory "github.com/hihikaAAa/PRManager/internal/http-server/handlers/pullrequest/history"
+	pullrequesthandlerready "github.com/hihikaAAa/PRManager/internal/http-server/handlers/pullrequest/ready"
 	pullrequesthandlerreassign "github.com/hihikaAAa/PRManager/internal/http-server/handlers/pullrequest/reassign"
+	pullrequesthandlerreopen "github.com/hihikaAAa/PRManager/internal/http-server/handlers/pullrequest/reopen"
+	pullrequesthandlerreview "github.com/hihikaAAa/PRManager/internal/http-server/handlers/pullrequest/review"
 	teamhandleradd "github.com/hihikaAAa/PRManager/internal/http-server/handlers/team/add"
+	teamhandleraddmembers "github.com/hihikaAAa/PRManager/internal/http-server/handlers/team/addMembers"
+	teamhandlercodeowners "github.com/hihikaAAa/PRManager/internal/http-server/handlers/team/codeOwners"
 	teamhandlerget "github.com/hihikaAAa/PRManager/internal/http-server/handlers/team/get"
 	teamhandlerdeactivate "github.com/hihikaAAa/PRManager/internal/http-server/handlers/team/deactivate"
+	teamhandlerdelete "github.com/hihikaAAa/PRManager/internal/http-server/handlers/team/delete"
+	teamhandlergetsettings "github.com/hihikaAAa/PRManager/internal/http-server/handlers/team/getSettings"
+	teamhandlerremovemembers "github.com/hihikaAAa/PRManager/internal/http-server/handlers/team/removeMembers"
+	teamhandlerrename "github.com/hihikaAAa/PRManager/internal/http-server/handlers/team/rename"
+	teamhandlersetcodeowners "github.com/hihikaAAa/PRManager/internal/http-server/handlers/team/setCodeOwners"
+	teamhandlersetparent "github.com/hihikaAAa/PRManager/internal/http-server/handlers/team/setParent"
+	teamhandlersetsettings "github.com/hihikaAAa/PRManager/internal/http-server/handlers/team/setSettings"
+	teamhandlertree "github.com/hihikaAAa/PRManager/internal/http-server/handlers/team/tree"
 	userhandlergetreview "github.com/hihikaAAa/PRManager/internal/http-server/handlers/user/getReview"
+	userhandleraddabsence "github.com/hihikaAAa/PRManager/internal/http-server/handlers/user/addAbsence"
+	userhandlerabsences "github.com/hihikaAAa/PRManager/internal/http-server/handlers/user/absences"
+	userhandlerdeleteabsence "github.com/hihikaAAa/PRManager/internal/http-server/handlers/user/deleteAbsence"
+	userhandlerhistory "github.com/hihikaAAa/PRManager/internal/http-server/handlers/user/history"
 	userhandlerisactive "github.com/hihikaAAa/PRManager/internal/http-server/handlers/user/isActive"
+	userhandlerlinkidentity "github.com/hihikaAAa/PRManager/internal/http-server/handlers/user/linkIdentity"
+	userhandlermoveteam "github.com/hihikaAAa/PRManager/internal/http-server/handlers/user/moveTeam"
+	userhandlersetcapacity "github.com/hihikaAAa/PRManager/internal/http-server/handlers/user/setCapacity"
+	webhookhandlerreceive "github.com/hihikaAAa/PRManager/internal/http-server/handlers/webhooks/receive"
 	statsservice "github.com/hihikaAAa/PRManager/internal/services/statsservice"
     statshandler "github.com/hihikaAAa/PRManager/internal/http-server/handlers/stats/getStats"
+    healthhandlerready "github.com/hihikaAAa/PRManager/internal/http-server/handlers/health/ready"
+	subscriberhandleradd "github.com/hihikaAAa/PRManager/internal/http-server/handlers/subscribers/add"
+	subscriberhandlerdelete "github.com/hihikaAAa/PRManager/internal/http-server/handlers/subscribers/delete"
+	subscriberhandlerlist "github.com/hihikaAAa/PRManager/internal/http-server/handlers/subscribers/list"
+	mwauth "github.com/hihikaAAa/PRManager/internal/http-server/middleware/auth"
+	mwtracing "github.com/hihikaAAa/PRManager/internal/http-server/middleware/tracing"
+	mwmetrics "github.com/hihikaAAa/PRManager/internal/http-server/middleware/metrics"
+	mwactor "github.com/hihikaAAa/PRManager/internal/http-server/middleware/actor"
 	mwlogger "github.com/hihikaAAa/PRManager/internal/http-server/middleware/logger"
+	"github.com/hihikaAAa/PRManager/internal/lib/auth"
+	"github.com/hihikaAAa/PRManager/internal/lib/metrics"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
 	slogpretty "github.com/hihikaAAa/PRManager/internal/lib/logger/slogpretty"
 	"github.com/hihikaAAa/PRManager/internal/lib/logger/sl"
+	"github.com/hihikaAAa/PRManager/internal/migrator"
+	"github.com/hihikaAAa/PRManager/internal/repository"
+	"github.com/hihikaAAa/PRManager/internal/repository/memory"
 	"github.com/hihikaAAa/PRManager/internal/repository/postgres"
+	"github.com/hihikaAAa/PRManager/internal/repository/sqlite"
+	"github.com/hihikaAAa/PRManager/internal/services/assigner"
+	"github.com/hihikaAAa/PRManager/internal/services/notifier"
+	"github.com/hihikaAAa/PRManager/internal/services/absenceservice"
+	"github.com/hihikaAAa/PRManager/internal/services/healthservice"
+	"github.com/hihikaAAa/PRManager/internal/services/outbox"
 	"github.com/hihikaAAa/PRManager/internal/services/prservice"
+	"github.com/hihikaAAa/PRManager/internal/services/subscriberservice"
 	"github.com/hihikaAAa/PRManager/internal/services/teamservice"
 	"github.com/hihikaAAa/PRManager/internal/services/userservice"
+	"github.com/hihikaAAa/PRManager/internal/services/webhookservice"
 	"github.com/hihikaAAa/PRManager/internal/storage"
+	"github.com/hihikaAAa/PRManager/internal/webhooks"
+	"github.com/hihikaAAa/PRManager/internal/webhooks/github"
+	"github.com/hihikaAAa/PRManager/internal/webhooks/gitlab"
 )
 
 const (
@@ -42,6 +93,12 @@ const (
 	envProd  = "prod"
 )
 
+const (
+	driverPostgres = "postgres"
+	driverMemory   = "memory"
+	driverSQLite   = "sqlite"
+)
+
 func main() {
 	_ = godotenv.Load("local.env")
 
@@ -49,53 +106,212 @@ func main() {
 
 	log := setupLogger(cfg.Env)
 
-	db, err := storage.New(cfg.DB.DSN)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(cfg, os.Args[2:]))
+	}
+
+	repos, db, migr, err := setupStorage(cfg)
 	if err != nil {
 		log.Error("failed to init storage", sl.Err(err))
 		os.Exit(1)
 	}
-	defer db.Close()
+	if db != nil {
+		defer db.Close()
+	}
+	log.Info("storage initialized", slog.String("driver", cfg.Storage.Driver))
+
+	if cfg.Migrations.OnStart && migr != nil {
+		if err := migrateOnStart(cfg, log, migr); err != nil {
+			log.Error("failed to apply migrations", sl.Err(err))
+			os.Exit(1)
+		}
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Enabled:     cfg.Tracing.Enabled,
+		Endpoint:    cfg.Tracing.Endpoint,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		log.Error("failed to init tracing", sl.Err(err))
+		os.Exit(1)
+	}
+
+	if db != nil {
+		if err := metrics.RegisterDB(db, cfg.Storage.Driver); err != nil {
+			log.Error("failed to register db metrics", sl.Err(err))
+			os.Exit(1)
+		}
+	}
+	if err := metrics.RegisterOpenReviews(repos.PRs); err != nil {
+		log.Error("failed to register open reviews metric", sl.Err(err))
+		os.Exit(1)
+	}
+
+	prRepo := repos.PRs
+	userRepo := repos.Users
+	teamRepo := repos.Teams
+	subscriberRepo := repos.Subscribers
+	outboxRepo := repos.Outbox
+
+	reviewerAssigner, err := assigner.New(userRepo, teamRepo, prRepo, cfg.Reviewers.Strategy)
+	if err != nil {
+		log.Error("failed to init reviewer assigner", sl.Err(err))
+		os.Exit(1)
+	}
+
+	notif := notifier.New(log, subscriberRepo, notifier.Config{
+		MaxAttempts:    cfg.Notifications.MaxAttempts,
+		InitialBackoff: cfg.Notifications.InitialBackoff,
+		MaxBackoff:     cfg.Notifications.MaxBackoff,
+		RequestTimeout: cfg.Notifications.RequestTimeout,
+		PollInterval:   cfg.Notifications.PollInterval,
+		BatchSize:      cfg.Notifications.BatchSize,
+		Lease:          cfg.Notifications.Lease,
+	})
+	sinks, err := outboxSinks(cfg, log, notif)
+	if err != nil {
+		log.Error("failed to init outbox sinks", sl.Err(err))
+		os.Exit(1)
+	}
+	relay := outbox.NewRelay(log, outboxRepo, outbox.Config{
+		PollInterval: cfg.Outbox.PollInterval,
+		BatchSize:    cfg.Outbox.BatchSize,
+		Lease:        cfg.Outbox.Lease,
+		MaxAttempts:  cfg.Outbox.MaxAttempts,
+		Retention:    cfg.Outbox.Retention,
+	}, sinks...)
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		relay.Run(workersCtx)
+	}()
+
+	notifierDone := make(chan struct{})
+	go func() {
+		defer close(notifierDone)
+		notif.Run(workersCtx)
+	}()
 
-	prRepo := postgres.New(db)
-	userRepo := postgres.NewUserRepository(db)
-	teamRepo := postgres.NewTeamRepository(db)
+	prService := prservice.New(prRepo, userRepo, reviewerAssigner)
+	teamService := teamservice.New(userRepo, teamRepo, prRepo, reviewerAssigner)
+	absenceService := absenceservice.New(repos.Absences, userRepo)
 
-	prService := prservice.New(prRepo, userRepo)
-	teamService := teamservice.New(userRepo, teamRepo, prRepo)
+	absenceWorker := absenceservice.NewWorker(log, repos.Absences, teamService, absenceservice.Config{
+		PollInterval:   cfg.Absences.PollInterval,
+		BatchSize:      cfg.Absences.BatchSize,
+		InitialBackoff: cfg.Absences.InitialBackoff,
+		MaxBackoff:     cfg.Absences.MaxBackoff,
+	})
+	absenceDone := make(chan struct{})
+	go func() {
+		defer close(absenceDone)
+		absenceWorker.Run(workersCtx)
+	}()
+	subscriberService := subscriberservice.New(subscriberRepo)
 	userService := userservice.New(prRepo, userRepo)
-	statService := statsservice.New(prRepo)
+	statService := statsservice.New(prRepo, teamRepo)
+	webhookService := webhookservice.New(prService, userRepo)
+
+	healthService := healthservice.New(log)
+	if db != nil {
+		healthService.SetDatabase(db)
+	}
+	if migr != nil {
+		healthService.SetMigrations(migr)
+	}
+	healthService.AddWorker("outbox_relay", relay)
+	healthService.AddWorker("absence_worker", absenceWorker)
+	healthService.AddWorker("webhook_retries", notif)
 
 
 	router := chi.NewRouter()
 
 	router.Use(middleware.RequestID)
+	router.Use(mwtracing.New())
 	router.Use(mwlogger.New(log))
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.URLFormat)
+	router.Use(mwmetrics.New())
+	router.Use(mwactor.New())
 
-	router.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+	live := func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte(`{"status":"ok"}`))
-	})
-
-	router.Route("/team", func(r chi.Router) {
-		r.Post("/add", teamhandleradd.New(log, teamService))
-		r.Get("/get", teamhandlerget.New(log, teamService))
-		r.Post("/deactivate", teamhandlerdeactivate.New(log, teamService))
-	})
-
-	router.Route("/users", func(r chi.Router) {
-		r.Post("/setIsActive", userhandlerisactive.New(log, userService))
-		r.Get("/getReview", userhandlergetreview.New(log, userService))
-	})
+	}
+	router.Get("/health", live)
+	router.Get("/livez", live)
+	router.Get("/readyz", healthhandlerready.New(log, healthService))
+	router.Handle("/metrics", metrics.Handler())
 
-	router.Route("/pullRequest", func(r chi.Router) {
-		r.Post("/create", pullrequesthandlercreate.New(log, prService))
-		r.Post("/merge", pullrequesthandlersmerge.New(log, prService))
-		r.Post("/reassign", pullrequesthandlerreassign.New(log, prService))
+	authenticate, requireRole, err := setupAuth(cfg, log)
+	if err != nil {
+		log.Error("failed to init auth", sl.Err(err))
+		os.Exit(1)
+	}
+	leads := requireRole(auth.RoleAdmin, auth.RoleTeamLead)
+	admins := requireRole(auth.RoleAdmin)
+
+	router.Group(func(router chi.Router) {
+		router.Use(authenticate)
+
+		router.Route("/team", func(r chi.Router) {
+			r.With(leads).Post("/add", teamhandleradd.New(log, teamService))
+			r.Get("/get", teamhandlerget.New(log, teamService))
+			r.With(leads).Post("/deactivate", teamhandlerdeactivate.New(log, teamService))
+			r.With(leads).Post("/addMembers", teamhandleraddmembers.New(log, teamService))
+			r.With(leads).Post("/removeMembers", teamhandlerremovemembers.New(log, teamService))
+			r.With(leads).Post("/rename", teamhandlerrename.New(log, teamService))
+			r.With(leads).Post("/delete", teamhandlerdelete.New(log, teamService))
+			r.Get("/tree", teamhandlertree.New(log, teamService))
+			r.With(leads).Post("/setParent", teamhandlersetparent.New(log, teamService))
+			r.Get("/settings", teamhandlergetsettings.New(log, teamService))
+			r.With(leads).Post("/setSettings", teamhandlersetsettings.New(log, teamService))
+			r.Get("/codeOwners", teamhandlercodeowners.New(log, teamService))
+			r.With(leads).Post("/setCodeOwners", teamhandlersetcodeowners.New(log, teamService))
+		})
+
+		router.Route("/users", func(r chi.Router) {
+			r.With(leads).Post("/setIsActive", userhandlerisactive.New(log, userService))
+			r.With(leads).Post("/setCapacity", userhandlersetcapacity.New(log, userService))
+			r.Get("/getReview", userhandlergetreview.New(log, userService))
+			r.With(admins).Post("/linkIdentity", userhandlerlinkidentity.New(log, userService))
+			r.With(leads).Post("/moveTeam", userhandlermoveteam.New(log, teamService))
+			r.Get("/history", userhandlerhistory.New(log, userService))
+			r.Post("/addAbsence", userhandleraddabsence.New(log, absenceService))
+			r.Get("/absences", userhandlerabsences.New(log, absenceService))
+			r.Post("/deleteAbsence", userhandlerdeleteabsence.New(log, absenceService))
+		})
+
+		router.Route("/pullRequest", func(r chi.Router) {
+			r.Post("/create", pullrequesthandlercreate.New(log, prService))
+			r.Post("/bulkCreate", pullrequesthandlerbulkcreate.New(log, prService))
+			r.Post("/merge", pullrequesthandlersmerge.New(log, prService))
+			r.Post("/reassign", pullrequesthandlerreassign.New(log, prService))
+			r.Post("/review", pullrequesthandlerreview.New(log, prService, userService))
+			r.Post("/ready", pullrequesthandlerready.New(log, prService))
+			r.Post("/close", pullrequesthandlerclose.New(log, prService))
+			r.Post("/reopen", pullrequesthandlerreopen.New(log, prService))
+			r.Get("/history", pullrequesthandlerhistory.New(log, prService))
+		})
+
+		router.Route("/subscribers", func(r chi.Router) {
+			r.With(admins).Post("/add", subscriberhandleradd.New(log, subscriberService))
+			r.Get("/list", subscriberhandlerlist.New(log, subscriberService))
+			r.With(admins).Post("/delete", subscriberhandlerdelete.New(log, subscriberService))
+		})
+
+		router.Get("/stats", statshandler.New(log, statService))
 	})
 
-	router.Get("/stats", statshandler.New(log, statService))
+	for _, provider := range webhookProviders(cfg, log) {
+		router.Post("/webhooks/"+provider.Name(), webhookhandlerreceive.New(log, provider, webhookService))
+	}
 
 	srv := &http.Server{
 		Addr: cfg.HTTPServer.Address,      
@@ -119,6 +335,9 @@ func main() {
 
 	log.Info("shutting down server...")
 
+	healthService.ShutDown()
+	time.Sleep(cfg.HTTPServer.ShutdownDelay)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -127,8 +346,120 @@ func main() {
 	} else {
 		log.Info("server gracefully stopped")
 	}
+
+	stopWorkers()
+	<-relayDone
+	<-notifierDone
+	<-absenceDone
+
+	if err := shutdownTracing(ctx); err != nil {
+		log.Error("tracing shutdown error", sl.Err(err))
+	}
+}
+
+
+// setupStorage opens the backend selected by storage.driver.
+// The database and the migrator are nil for backends without a schema.
+func setupStorage(cfg *config.Config) (repository.Repositories, *sql.DB, *migrator.Migrator, error) {
+	switch cfg.Storage.Driver {
+	case driverPostgres:
+		if cfg.DB.DSN == "" {
+			return repository.Repositories{}, nil, nil, fmt.Errorf("db.dsn is required for the %s driver", driverPostgres)
+		}
+		db, err := storage.New(cfg.DB.DSN)
+		if err != nil {
+			return repository.Repositories{}, nil, nil, err
+		}
+		migr, err := postgres.Migrator(db)
+		if err != nil {
+			db.Close()
+			return repository.Repositories{}, nil, nil, err
+		}
+		return postgres.Repositories(db), db, migr, nil
+	case driverSQLite:
+		db, err := sqlite.New(cfg.Storage.SQLite.Path)
+		if err != nil {
+			return repository.Repositories{}, nil, nil, err
+		}
+		migr, err := sqlite.Migrator(db)
+		if err != nil {
+			db.Close()
+			return repository.Repositories{}, nil, nil, err
+		}
+		return sqlite.Repositories(db), db, migr, nil
+	case driverMemory:
+		return memory.NewStorage().Repositories(), nil, nil, nil
+	default:
+		return repository.Repositories{}, nil, nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
 }
 
+// outboxSinks builds the sinks listed in outbox.sinks.
+func outboxSinks(cfg *config.Config, log *slog.Logger, notif *notifier.Notifier) ([]outbox.Sink, error) {
+	sinks := make([]outbox.Sink, 0, len(cfg.Outbox.Sinks))
+	for _, name := range cfg.Outbox.Sinks {
+		switch name {
+		case "log":
+			sinks = append(sinks, outbox.NewLogSink(log))
+		case "webhook":
+			sinks = append(sinks, notif)
+		default:
+			return nil, fmt.Errorf("unknown outbox sink %q", name)
+		}
+	}
+	return sinks, nil
+}
+
+// setupAuth returns the authentication middleware and a role check. With auth.enabled = false
+// both let every request through and the actor is taken from X-Actor-ID.
+func setupAuth(cfg *config.Config, log *slog.Logger) (func(http.Handler) http.Handler, func(...auth.Role) func(http.Handler) http.Handler, error) {
+	if !cfg.Auth.Enabled {
+		log.Warn("auth disabled: every endpoint is open, set auth.enabled to protect the API")
+		pass := func(next http.Handler) http.Handler { return next }
+		return pass, func(...auth.Role) func(http.Handler) http.Handler { return pass }, nil
+	}
+
+	authCfg := auth.Config{
+		HS256Secret: []byte(cfg.Auth.JWT.HS256Secret),
+		Issuer:      cfg.Auth.JWT.Issuer,
+		Audience:    cfg.Auth.JWT.Audience,
+	}
+	for _, t := range cfg.Auth.Tokens {
+		authCfg.Tokens = append(authCfg.Tokens, auth.StaticToken{Token: t.Token, UserID: t.UserID, Role: auth.Role(t.Role)})
+	}
+	if path := cfg.Auth.JWT.RS256PublicKeyFile; path != "" {
+		key, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read rs256 public key: %w", err)
+		}
+		authCfg.RS256PublicKey = key
+	}
+
+	authenticator, err := auth.NewAuthenticator(authCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mwauth.New(log, authenticator), mwauth.RequireRole, nil
+}
+
+// webhookProviders returns the providers that have a secret configured.
+func webhookProviders(cfg *config.Config, log *slog.Logger) []webhooks.Provider {
+	var providers []webhooks.Provider
+
+	if cfg.Webhooks.GitHub.Secret != "" {
+		providers = append(providers, github.NewProvider(cfg.Webhooks.GitHub.Secret))
+	} else {
+		log.Info("github webhook disabled: webhooks.github.secret is not set")
+	}
+
+	if cfg.Webhooks.GitLab.Token != "" {
+		providers = append(providers, gitlab.NewProvider(cfg.Webhooks.GitLab.Token))
+	} else {
+		log.Info("gitlab webhook disabled: webhooks.gitlab.token is not set")
+	}
+
+	return providers
+}
 
 func setupLogger(env string) *slog.Logger {
 	var log *slog.Logger
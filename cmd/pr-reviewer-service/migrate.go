@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/hihikaAAa/PRManager/internal/config"
+	"github.com/hihikaAAa/PRManager/internal/migrator"
+)
+
+const migrateUsage = "usage: pr-reviewer-service migrate up | down [N] | status"
+
+// runMigrate implements the "migrate" subcommand and returns the exit code.
+func runMigrate(cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	_, db, migr, err := setupStorage(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to init storage:", err)
+		return 1
+	}
+	if db != nil {
+		defer db.Close()
+	}
+	if migr == nil {
+		fmt.Fprintf(os.Stderr, "storage driver %q has no migrations\n", cfg.Storage.Driver)
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Migrations.Timeout)
+	defer cancel()
+
+	switch args[0] {
+	case "up":
+		applied, err := migr.Up(ctx)
+		for _, v := range applied {
+			fmt.Printf("applied %03d\n", v)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("no change")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				fmt.Fprintln(os.Stderr, migrateUsage)
+				return 2
+			}
+		}
+		reverted, err := migr.Down(ctx, steps)
+		for _, v := range reverted {
+			fmt.Printf("reverted %03d\n", v)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	case "status":
+		st, err := migr.Status(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("version: %d", st.Version)
+		if st.NotMigrated {
+			fmt.Print(" (not migrated)")
+		}
+		if st.Dirty {
+			fmt.Print(" (dirty)")
+		}
+		fmt.Println()
+		for _, m := range st.Migrations {
+			state := "pending"
+			if m.Version <= st.Version {
+				state = "applied"
+			}
+			fmt.Printf("%-8s %03d_%s\n", state, m.Version, m.Name)
+		}
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	return 0
+}
+
+// migrateOnStart applies pending migrations before the service starts serving.
+func migrateOnStart(cfg *config.Config, log *slog.Logger, migr *migrator.Migrator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Migrations.Timeout)
+	defer cancel()
+
+	applied, err := migr.Up(ctx)
+	if err != nil {
+		return err
+	}
+	log.Info("migrations applied", slog.Any("versions", applied), slog.Int("latest", migr.Latest()))
+	return nil
+}
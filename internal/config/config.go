@@ -12,15 +12,90 @@ type Config struct {
     Env string `yaml:"env" env-default:"local"`
 
     HTTPServer struct {
-        Address      string        `yaml:"address" env-default:":8080"`
-        ReadTimeout  time.Duration `yaml:"read_timeout" env-default:"5s"`
-        WriteTimeout time.Duration `yaml:"write_timeout" env-default:"5s"`
-        IdleTimeout  time.Duration `yaml:"idle_timeout" env-default:"60s"`
+        Address       string        `yaml:"address" env-default:":8080"`
+        ReadTimeout   time.Duration `yaml:"read_timeout" env-default:"5s"`
+        WriteTimeout  time.Duration `yaml:"write_timeout" env-default:"5s"`
+        IdleTimeout   time.Duration `yaml:"idle_timeout" env-default:"60s"`
+        // ShutdownDelay is how long /readyz fails before the server stops accepting connections.
+        ShutdownDelay time.Duration `yaml:"shutdown_delay" env-default:"0s"`
     } `yaml:"http_server"`
 
+    Storage struct {
+        Driver string `yaml:"driver" env:"STORAGE_DRIVER" env-default:"postgres"`
+        SQLite struct {
+            Path string `yaml:"path" env:"SQLITE_PATH" env-default:"prmanager.db"`
+        } `yaml:"sqlite"`
+    } `yaml:"storage"`
+
     DB struct {
-		DSN string `yaml:"dsn" env-required:"true"`
+		DSN string `yaml:"dsn"`
     } `yaml:"db"`
+
+    Migrations struct {
+        OnStart bool          `yaml:"on_start" env:"MIGRATE_ON_START" env-default:"false"`
+        Timeout time.Duration `yaml:"timeout" env-default:"1m"`
+    } `yaml:"migrations"`
+
+    Reviewers struct {
+        Strategy string `yaml:"strategy" env:"REVIEWER_STRATEGY" env-default:"random"`
+    } `yaml:"reviewers"`
+
+    Outbox struct {
+        PollInterval time.Duration `yaml:"poll_interval" env-default:"1s"`
+        BatchSize    int           `yaml:"batch_size" env-default:"100"`
+        Lease        time.Duration `yaml:"lease" env-default:"2m"`
+        MaxAttempts  int           `yaml:"max_attempts" env-default:"10"`
+        Retention    time.Duration `yaml:"retention" env-default:"168h"`
+        Sinks        []string      `yaml:"sinks" env:"OUTBOX_SINKS" env-default:"log,webhook"`
+    } `yaml:"outbox"`
+
+    Absences struct {
+        PollInterval   time.Duration `yaml:"poll_interval" env-default:"1m"`
+        BatchSize      int           `yaml:"batch_size" env-default:"100"`
+        InitialBackoff time.Duration `yaml:"initial_backoff" env-default:"1m"`
+        MaxBackoff     time.Duration `yaml:"max_backoff" env-default:"1h"`
+    } `yaml:"absences"`
+
+    Notifications struct {
+        MaxAttempts    int           `yaml:"max_attempts" env-default:"5"`
+        InitialBackoff time.Duration `yaml:"initial_backoff" env-default:"1s"`
+        MaxBackoff     time.Duration `yaml:"max_backoff" env-default:"1m"`
+        RequestTimeout time.Duration `yaml:"request_timeout" env-default:"5s"`
+        PollInterval   time.Duration `yaml:"poll_interval" env-default:"1s"`
+        BatchSize      int           `yaml:"batch_size" env-default:"100"`
+        Lease          time.Duration `yaml:"lease" env-default:"2m"`
+    } `yaml:"notifications"`
+
+    Tracing struct {
+        Enabled     bool    `yaml:"enabled" env:"TRACING_ENABLED" env-default:"false"`
+        Endpoint    string  `yaml:"endpoint" env:"TRACING_ENDPOINT"`
+        ServiceName string  `yaml:"service_name" env-default:"pr-reviewer-service"`
+        SampleRatio float64 `yaml:"sample_ratio" env-default:"1"`
+    } `yaml:"tracing"`
+
+    Auth struct {
+        Enabled bool `yaml:"enabled" env:"AUTH_ENABLED" env-default:"false"`
+        Tokens  []struct {
+            Token  string `yaml:"token"`
+            UserID string `yaml:"user_id"`
+            Role   string `yaml:"role"`
+        } `yaml:"tokens"`
+        JWT struct {
+            HS256Secret        string `yaml:"hs256_secret" env:"JWT_HS256_SECRET"`
+            RS256PublicKeyFile string `yaml:"rs256_public_key_file" env:"JWT_RS256_PUBLIC_KEY_FILE"`
+            Issuer             string `yaml:"issuer"`
+            Audience           string `yaml:"audience"`
+        } `yaml:"jwt"`
+    } `yaml:"auth"`
+
+    Webhooks struct {
+        GitHub struct {
+            Secret string `yaml:"secret" env:"GITHUB_WEBHOOK_SECRET"`
+        } `yaml:"github"`
+        GitLab struct {
+            Token string `yaml:"token" env:"GITLAB_WEBHOOK_TOKEN"`
+        } `yaml:"gitlab"`
+    } `yaml:"webhooks"`
 }
 
 func MustLoad() *Config{
@@ -0,0 +1,64 @@
+package assignment
+
+import (
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+)
+
+// Change is an entry of the reviewer assignment history.
+// OldReviewerID is empty for assignments, NewReviewerID is empty for removals.
+type Change struct {
+	ID            int64
+	PullRequestID string
+	OldReviewerID string
+	NewReviewerID string
+	Reason        string
+	FromFallback  bool
+	Actor         string
+	CreatedAt     time.Time
+}
+
+// Handover is a prepared change of an open PR's reviewers: NewReviewerID replaces
+// ReviewerID, or, when it is empty, ReviewerID is removed. Event describes the change
+// and is stored with it.
+type Handover struct {
+	PullRequestID string
+	ReviewerID    string
+	NewReviewerID string
+	Event         event.Event
+}
+
+// FromEvent returns the history entries described by a domain event.
+// Events that do not change reviewers (merge) produce none.
+func FromEvent(ev event.Event) []Change {
+	var out []Change
+	switch d := ev.Data.(type) {
+	case event.ReviewersAssigned:
+		fallback := make(map[string]struct{}, len(d.Fallback))
+		for _, id := range d.Fallback {
+			fallback[id] = struct{}{}
+		}
+		for _, id := range d.Reviewers {
+			c := Change{PullRequestID: d.PullRequestID, NewReviewerID: id, Reason: d.Reason}
+			if _, ok := fallback[id]; ok {
+				c.Reason = event.ReasonFallback
+				c.FromFallback = true
+			}
+			out = append(out, c)
+		}
+	case event.ReviewerReassigned:
+		out = append(out, Change{
+			PullRequestID: d.PullRequestID, OldReviewerID: d.OldReviewerID, NewReviewerID: d.NewReviewerID,
+			Reason: d.Reason, FromFallback: d.FromFallback,
+		})
+	case event.ReviewerRemoved:
+		out = append(out, Change{PullRequestID: d.PullRequestID, OldReviewerID: d.ReviewerID, Reason: d.Reason})
+	}
+
+	for i := range out {
+		out[i].Actor = ev.Actor
+		out[i].CreatedAt = ev.OccurredAt
+	}
+	return out
+}
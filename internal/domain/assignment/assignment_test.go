@@ -0,0 +1,58 @@
+package assignment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	"github.com/hihikaAAa/PRManager/internal/lib/actor"
+)
+
+func TestFromEvent_AssignedMarksFallback(t *testing.T) {
+	ctx := actor.WithActor(context.Background(), "u1")
+	ev := event.New(ctx, event.TypeReviewersAssigned, event.ReviewersAssigned{
+		PullRequestID: "pr-1", Reviewers: []string{"u2", "u9"}, Fallback: []string{"u9"}, Reason: event.ReasonAutoCreate,
+	})
+
+	got := FromEvent(ev)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 changes, got %d", len(got))
+	}
+	if got[0].NewReviewerID != "u2" || got[0].Reason != event.ReasonAutoCreate || got[0].FromFallback {
+		t.Fatalf("unexpected team change: %+v", got[0])
+	}
+	if got[1].NewReviewerID != "u9" || got[1].Reason != event.ReasonFallback || !got[1].FromFallback {
+		t.Fatalf("unexpected fallback change: %+v", got[1])
+	}
+	for _, c := range got {
+		if c.Actor != "u1" || !c.CreatedAt.Equal(ev.OccurredAt) || c.OldReviewerID != "" {
+			t.Fatalf("unexpected change: %+v", c)
+		}
+	}
+}
+
+func TestFromEvent_ReassignedAndRemoved(t *testing.T) {
+	ctx := context.Background()
+	reassigned := event.New(ctx, event.TypeReviewerReassigned, event.ReviewerReassigned{
+		PullRequestID: "pr-1", OldReviewerID: "u2", NewReviewerID: "u3", Reason: event.ReasonManualReassign, FromFallback: true,
+	})
+	got := FromEvent(reassigned)
+	if len(got) != 1 || got[0].OldReviewerID != "u2" || got[0].NewReviewerID != "u3" || !got[0].FromFallback || got[0].Actor != actor.System {
+		t.Fatalf("unexpected reassignment: %+v", got)
+	}
+
+	removed := event.New(ctx, event.TypeReviewerRemoved, event.ReviewerRemoved{
+		PullRequestID: "pr-1", ReviewerID: "u3", Reason: event.ReasonPRClosed,
+	})
+	got = FromEvent(removed)
+	if len(got) != 1 || got[0].OldReviewerID != "u3" || got[0].NewReviewerID != "" || got[0].Reason != event.ReasonPRClosed {
+		t.Fatalf("unexpected removal: %+v", got)
+	}
+}
+
+func TestFromEvent_MergeHasNoChanges(t *testing.T) {
+	ev := event.New(context.Background(), event.TypePRMerged, event.PRMerged{PullRequestID: "pr-1"})
+	if got := FromEvent(ev); len(got) != 0 {
+		t.Fatalf("expected no changes, got %+v", got)
+	}
+}
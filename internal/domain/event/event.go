@@ -0,0 +1,102 @@
+package event
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/lib/actor"
+)
+
+// Type names a change of reviewer assignments that subscribers can be notified about.
+type Type string
+
+const (
+	TypeReviewersAssigned  Type = "reviewers.assigned"
+	TypeReviewerReassigned Type = "reviewer.reassigned"
+	TypeReviewerRemoved    Type = "reviewer.removed"
+	TypePRMerged           Type = "pull_request.merged"
+)
+
+// Reasons for reviewer changes.
+const (
+	// ReasonAutoCreate is an automatic assignment when a PR becomes reviewable (create, ready, reopen).
+	ReasonAutoCreate = "auto_create"
+	// ReasonFallback is an automatic assignment filled from a fallback team.
+	ReasonFallback         = "fallback"
+	ReasonManualReassign   = "manual_reassign"
+	ReasonTeamDeactivation = "team_deactivation"
+	ReasonPRClosed         = "pr_closed"
+	// ReasonImport keeps the reviewers of a PR imported with its existing review assignments.
+	ReasonImport = "import"
+	// ReasonTeamChange hands over the reviews of a member who left the team (removed or moved).
+	ReasonTeamChange = "team_change"
+	// ReasonAbsence hands over the reviews of a member whose out-of-office window has started.
+	ReasonAbsence = "absence"
+)
+
+func Types() []Type {
+	return []Type{TypeReviewersAssigned, TypeReviewerReassigned, TypeReviewerRemoved, TypePRMerged}
+}
+
+func (t Type) Valid() bool {
+	for _, known := range Types() {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a domain event. Data is one of the payload structs below,
+// or json.RawMessage for events read back from the outbox.
+type Event struct {
+	ID         string    `json:"id"`
+	Type       Type      `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Actor      string    `json:"actor"`
+	Data       any       `json:"data"`
+}
+
+type ReviewersAssigned struct {
+	PullRequestID   string   `json:"pull_request_id"`
+	PullRequestName string   `json:"pull_request_name"`
+	AuthorID        string   `json:"author_id"`
+	Reviewers       []string `json:"reviewers"`
+	// Fallback is the subset of Reviewers taken from fallback teams.
+	Fallback []string `json:"fallback,omitempty"`
+	Reason   string   `json:"reason"`
+}
+
+type ReviewerReassigned struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id"`
+	Reason        string `json:"reason"`
+	FromFallback  bool   `json:"from_fallback,omitempty"`
+}
+
+type ReviewerRemoved struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+	Reason        string `json:"reason"`
+}
+
+type PRMerged struct {
+	PullRequestID string    `json:"pull_request_id"`
+	AuthorID      string    `json:"author_id"`
+	Reviewers     []string  `json:"reviewers"`
+	MergedAt      time.Time `json:"merged_at"`
+}
+
+// New creates an event on behalf of the actor stored in ctx.
+func New(ctx context.Context, t Type, data any) Event {
+	return Event{ID: newID(), Type: t, OccurredAt: time.Now().UTC(), Actor: actor.FromContext(ctx), Data: data}
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
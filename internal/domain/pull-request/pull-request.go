@@ -1,6 +1,7 @@
 package pullrequest
 
 import (
+	"errors"
 	"time"
 )
 
@@ -9,17 +10,27 @@ type Status string
 const(
 	StatusOpen Status = "OPEN"
 	StatusMerged Status = "MERGED"
+	StatusDraft Status = "DRAFT"
+	StatusClosed Status = "CLOSED"
 )
 
+var ErrInvalidTransition = errors.New("invalid pull request status transition")
+
 type PullRequest struct{
 	ID string
 	Name string
 	AuthorID string
 	Status Status
 	Reviewers []string
+	// FallbackReviewers lists reviewers taken from fallback teams during the last assignment.
+	FallbackReviewers []string
+	Reviews []Review
+	// ChangedFiles are the paths the PR touches; owners of these paths are preferred as reviewers.
+	ChangedFiles []string
 
 	CreatedAt time.Time
 	MergedAt *time.Time
+	ClosedAt *time.Time
 }
 
 type PullRequestShort struct{
@@ -29,10 +40,52 @@ type PullRequestShort struct{
 	Status Status `json:"status"`
 }
 
-func (pr *PullRequest) Merge(t time.Time) {
-    if pr.Status == StatusMerged {
-        return
-    }
-    pr.Status = StatusMerged
-    pr.MergedAt = &t
+// Merge moves an OPEN pull request to MERGED. Merging twice is a no-op.
+func (pr *PullRequest) Merge(t time.Time) error {
+	switch pr.Status {
+	case StatusMerged:
+		return nil
+	case StatusOpen:
+		pr.Status = StatusMerged
+		pr.MergedAt = &t
+		return nil
+	default:
+		return ErrInvalidTransition
+	}
+}
+
+// MarkReady moves a DRAFT pull request to OPEN so reviewers can be assigned.
+func (pr *PullRequest) MarkReady() error {
+	if pr.Status != StatusDraft {
+		return ErrInvalidTransition
+	}
+	pr.Status = StatusOpen
+	return nil
+}
+
+// Close abandons an OPEN or DRAFT pull request and frees its reviewers.
+// Closing twice is a no-op.
+func (pr *PullRequest) Close(t time.Time) error {
+	switch pr.Status {
+	case StatusClosed:
+		return nil
+	case StatusOpen, StatusDraft:
+		pr.Status = StatusClosed
+		pr.ClosedAt = &t
+		pr.Reviewers = nil
+		pr.Reviews = nil
+		return nil
+	default:
+		return ErrInvalidTransition
+	}
+}
+
+// Reopen moves a CLOSED pull request back to OPEN. Reviewers are assigned anew.
+func (pr *PullRequest) Reopen() error {
+	if pr.Status != StatusClosed {
+		return ErrInvalidTransition
+	}
+	pr.Status = StatusOpen
+	pr.ClosedAt = nil
+	return nil
 }
@@ -1,6 +1,7 @@
 package pullrequest
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -53,3 +54,86 @@ func TestPullRequestMerge_Idempotent(t *testing.T) {
 		t.Fatalf("expected MergedAt to stay %v, got %v", first, pr.MergedAt)
 	}
 }
+
+func TestPullRequestMerge_NotOpen(t *testing.T) {
+	t.Parallel()
+
+	for _, status := range []Status{StatusDraft, StatusClosed} {
+		pr := &PullRequest{ID: "pr-1", Status: status}
+
+		if err := pr.Merge(time.Now()); !errors.Is(err, ErrInvalidTransition) {
+			t.Fatalf("merge from %q: expected ErrInvalidTransition, got %v", status, err)
+		}
+		if pr.Status != status || pr.MergedAt != nil {
+			t.Fatalf("merge from %q must not change the PR, got %#v", status, pr)
+		}
+	}
+}
+
+func TestPullRequestMarkReady(t *testing.T) {
+	t.Parallel()
+
+	pr := &PullRequest{ID: "pr-1", Status: StatusDraft}
+	if err := pr.MarkReady(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pr.Status != StatusOpen {
+		t.Fatalf("expected status %q, got %q", StatusOpen, pr.Status)
+	}
+
+	if err := pr.MarkReady(); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition for OPEN PR, got %v", err)
+	}
+}
+
+func TestPullRequestClose(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for _, status := range []Status{StatusOpen, StatusDraft} {
+		pr := &PullRequest{ID: "pr-1", Status: status, Reviewers: []string{"u2"}}
+		if err := pr.Close(now); err != nil {
+			t.Fatalf("close from %q: unexpected error: %v", status, err)
+		}
+		if pr.Status != StatusClosed || pr.ClosedAt == nil || !pr.ClosedAt.Equal(now) {
+			t.Fatalf("close from %q: unexpected PR %#v", status, pr)
+		}
+		if len(pr.Reviewers) != 0 {
+			t.Fatalf("close from %q: reviewers must be freed, got %v", status, pr.Reviewers)
+		}
+	}
+
+	closed := &PullRequest{ID: "pr-1", Status: StatusClosed, ClosedAt: &now}
+	if err := closed.Close(now.Add(time.Hour)); err != nil {
+		t.Fatalf("second close must be a no-op, got %v", err)
+	}
+	if !closed.ClosedAt.Equal(now) {
+		t.Fatalf("expected ClosedAt to stay %v, got %v", now, closed.ClosedAt)
+	}
+
+	merged := &PullRequest{ID: "pr-1", Status: StatusMerged}
+	if err := merged.Close(now); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition for MERGED PR, got %v", err)
+	}
+}
+
+func TestPullRequestReopen(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	pr := &PullRequest{ID: "pr-1", Status: StatusClosed, ClosedAt: &now}
+	if err := pr.Reopen(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pr.Status != StatusOpen || pr.ClosedAt != nil {
+		t.Fatalf("unexpected PR after reopen: %#v", pr)
+	}
+
+	for _, status := range []Status{StatusOpen, StatusDraft, StatusMerged} {
+		pr := &PullRequest{ID: "pr-1", Status: status}
+		if err := pr.Reopen(); !errors.Is(err, ErrInvalidTransition) {
+			t.Fatalf("reopen from %q: expected ErrInvalidTransition, got %v", status, err)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package pullrequest
+
+import (
+	"errors"
+	"time"
+)
+
+type ReviewState string
+
+const (
+	ReviewPending          ReviewState = "PENDING"
+	ReviewApproved         ReviewState = "APPROVED"
+	ReviewChangesRequested ReviewState = "CHANGES_REQUESTED"
+	ReviewCommented        ReviewState = "COMMENTED"
+)
+
+var ErrInvalidReviewState = errors.New("invalid review state")
+
+// Review is the state of a single assigned reviewer.
+// UpdatedAt is nil until the reviewer submits a review.
+type Review struct {
+	UserID    string
+	State     ReviewState
+	UpdatedAt *time.Time
+}
+
+// ValidSubmission reports whether a reviewer may submit the state.
+// PENDING is only assigned by the service itself.
+func (s ReviewState) ValidSubmission() bool {
+	switch s {
+	case ReviewApproved, ReviewChangesRequested, ReviewCommented:
+		return true
+	default:
+		return false
+	}
+}
+
+func PendingReviews(reviewers []string) []Review {
+	reviews := make([]Review, 0, len(reviewers))
+	for _, id := range reviewers {
+		reviews = append(reviews, Review{UserID: id, State: ReviewPending})
+	}
+	return reviews
+}
+
+func (pr *PullRequest) Approvals() int {
+	cnt := 0
+	for _, r := range pr.Reviews {
+		if r.State == ReviewApproved {
+			cnt++
+		}
+	}
+	return cnt
+}
@@ -0,0 +1,52 @@
+package pullrequest
+
+import "testing"
+
+func TestReviewStateValidSubmission(t *testing.T) {
+	t.Parallel()
+
+	valid := []ReviewState{ReviewApproved, ReviewChangesRequested, ReviewCommented}
+	for _, s := range valid {
+		if !s.ValidSubmission() {
+			t.Fatalf("expected %q to be a valid submission", s)
+		}
+	}
+
+	invalid := []ReviewState{ReviewPending, "", "LGTM"}
+	for _, s := range invalid {
+		if s.ValidSubmission() {
+			t.Fatalf("expected %q to be rejected", s)
+		}
+	}
+}
+
+func TestPendingReviews(t *testing.T) {
+	t.Parallel()
+
+	got := PendingReviews([]string{"u2", "u3"})
+	if len(got) != 2 {
+		t.Fatalf("expected 2 reviews, got %d", len(got))
+	}
+	for _, r := range got {
+		if r.State != ReviewPending || r.UpdatedAt != nil {
+			t.Fatalf("expected pending review without timestamp, got %#v", r)
+		}
+	}
+}
+
+func TestPullRequestApprovals(t *testing.T) {
+	t.Parallel()
+
+	pr := &PullRequest{
+		Reviews: []Review{
+			{UserID: "u2", State: ReviewApproved},
+			{UserID: "u3", State: ReviewChangesRequested},
+			{UserID: "u4", State: ReviewApproved},
+			{UserID: "u5", State: ReviewPending},
+		},
+	}
+
+	if got := pr.Approvals(); got != 2 {
+		t.Fatalf("expected 2 approvals, got %d", got)
+	}
+}
@@ -0,0 +1,78 @@
+package subscriber
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+)
+
+var ErrInvalidSubscriber = errors.New("invalid subscriber")
+
+// Subscriber receives signed POSTs for the events it is subscribed to.
+// An empty Events list means all events.
+type Subscriber struct {
+	ID        int64
+	URL       string
+	Secret    string
+	Events    []event.Type
+	IsActive  bool
+	CreatedAt time.Time
+}
+
+func (s Subscriber) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidSubscriber
+	}
+	if s.Secret == "" {
+		return ErrInvalidSubscriber
+	}
+	for _, t := range s.Events {
+		if !t.Valid() {
+			return ErrInvalidSubscriber
+		}
+	}
+	return nil
+}
+
+func (s Subscriber) Wants(t event.Type) bool {
+	if !s.IsActive {
+		return false
+	}
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+// DeadLetter is a delivery that failed after all retries.
+type DeadLetter struct {
+	ID           int64
+	SubscriberID int64
+	EventID      string
+	EventType    event.Type
+	Payload      []byte
+	Attempts     int
+	LastError    string
+	CreatedAt    time.Time
+}
+
+// Retry is a delivery waiting for its next attempt. Attempts counts the failed ones;
+// it is 0 for a delivery queued behind earlier retries of the same subscriber.
+type Retry struct {
+	ID            int64
+	SubscriberID  int64
+	EventID       string
+	EventType     event.Type
+	Payload       []byte
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+}
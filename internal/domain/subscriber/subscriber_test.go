@@ -0,0 +1,49 @@
+package subscriber
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		sub     Subscriber
+		wantErr bool
+	}{
+		{name: "valid", sub: Subscriber{URL: "https://hooks.example.com/pr", Secret: "s"}},
+		{name: "valid with events", sub: Subscriber{URL: "http://localhost:9000", Secret: "s", Events: []event.Type{event.TypePRMerged}}},
+		{name: "no secret", sub: Subscriber{URL: "https://hooks.example.com"}, wantErr: true},
+		{name: "bad scheme", sub: Subscriber{URL: "ftp://hooks.example.com", Secret: "s"}, wantErr: true},
+		{name: "no host", sub: Subscriber{URL: "https://", Secret: "s"}, wantErr: true},
+		{name: "unknown event", sub: Subscriber{URL: "https://hooks.example.com", Secret: "s", Events: []event.Type{"pr.exploded"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.sub.Validate()
+			if tt.wantErr != errors.Is(err, ErrInvalidSubscriber) {
+				t.Fatalf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWants(t *testing.T) {
+	all := Subscriber{IsActive: true}
+	if !all.Wants(event.TypeReviewerRemoved) {
+		t.Fatalf("subscriber without events must want everything")
+	}
+
+	merged := Subscriber{IsActive: true, Events: []event.Type{event.TypePRMerged}}
+	if !merged.Wants(event.TypePRMerged) || merged.Wants(event.TypeReviewersAssigned) {
+		t.Fatalf("unexpected Wants result for %v", merged.Events)
+	}
+
+	inactive := Subscriber{}
+	if inactive.Wants(event.TypePRMerged) {
+		t.Fatalf("inactive subscriber must not want events")
+	}
+}
@@ -0,0 +1,125 @@
+package team
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidCodeOwners = errors.New("invalid code owners")
+
+// OwnershipRule maps a CODEOWNERS-style path pattern to the users owning the matching files.
+type OwnershipRule struct {
+	Pattern string
+	Owners  []string
+}
+
+// CodeOwners are the ownership rules of a team. As in CODEOWNERS files, a later rule
+// overrides the earlier ones for the paths it matches.
+//
+// Patterns follow the CODEOWNERS syntax: a pattern starting with or containing a
+// slash is anchored at the repository root, other patterns match at any depth;
+// "*" and "?" do not cross directories while "**" does; a pattern matches the path
+// itself and everything below it, except that "dir/*" only matches the files
+// directly in dir. Negation ("!") is not supported.
+type CodeOwners struct {
+	TeamName string
+	Rules    []OwnershipRule
+}
+
+func (c CodeOwners) Validate() error {
+	for _, r := range c.Rules {
+		if len(r.Owners) == 0 {
+			return ErrInvalidCodeOwners
+		}
+		for _, o := range r.Owners {
+			if o == "" {
+				return ErrInvalidCodeOwners
+			}
+		}
+		if _, err := compilePattern(r.Pattern); err != nil {
+			return ErrInvalidCodeOwners
+		}
+	}
+	return nil
+}
+
+// OwnersOf returns the owners of the paths in the order they are first found.
+// Paths no rule matches have no owners.
+func (c CodeOwners) OwnersOf(paths []string) []string {
+	if len(c.Rules) == 0 || len(paths) == 0 {
+		return nil
+	}
+
+	patterns := make([]*regexp.Regexp, len(c.Rules))
+	for i, r := range c.Rules {
+		// Stored rules are validated, so a pattern that does not compile matches nothing.
+		patterns[i], _ = compilePattern(r.Pattern)
+	}
+
+	var owners []string
+	seen := make(map[string]struct{})
+	for _, p := range paths {
+		p = strings.TrimPrefix(p, "/")
+		for i := len(c.Rules) - 1; i >= 0; i-- {
+			if patterns[i] == nil || !patterns[i].MatchString(p) {
+				continue
+			}
+			for _, o := range c.Rules[i].Owners {
+				if _, ok := seen[o]; !ok {
+					seen[o] = struct{}{}
+					owners = append(owners, o)
+				}
+			}
+			break
+		}
+	}
+	return owners
+}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	p := strings.TrimSpace(pattern)
+	if p == "" || strings.HasPrefix(p, "!") || strings.ContainsAny(p, " \t#") {
+		return nil, ErrInvalidCodeOwners
+	}
+
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return nil, ErrInvalidCodeOwners
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+		case p[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case strings.HasSuffix(p, "/*"):
+		// "docs/*" owns the files in docs but not those in its subdirectories.
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(b.String())
+}
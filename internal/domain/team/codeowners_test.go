@@ -0,0 +1,91 @@
+package team
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCompilePattern(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"*", "main.go", true},
+		{"*", "internal/db/db.go", true},
+		{"*.go", "internal/db/db.go", true},
+		{"*.go", "README.md", false},
+		{"/docs/", "docs/api.md", true},
+		{"/docs/", "pkg/docs/api.md", false},
+		{"/docs/", "docs", false},
+		{"docs/", "pkg/docs/api.md", true},
+		{"docs", "docs", true},
+		{"docs/*", "docs/api.md", true},
+		{"docs/*", "docs/v1/api.md", false},
+		{"internal/db", "internal/db/db.go", true},
+		{"internal/db", "pkg/internal/db/db.go", false},
+		{"/internal/db/**/*.sql", "internal/db/migrations/001.sql", true},
+		{"/internal/db/**/*.sql", "internal/db/001.sql", true},
+		{"**/migrations", "a/b/migrations/001.sql", true},
+		{"?.md", "a.md", true},
+		{"?.md", "ab.md", false},
+		{"go.mod", "go.mod.bak", false},
+	}
+	for _, c := range cases {
+		re, err := compilePattern(c.pattern)
+		if err != nil {
+			t.Fatalf("%q: %v", c.pattern, err)
+		}
+		if got := re.MatchString(c.path); got != c.match {
+			t.Errorf("%q vs %q: expected %v, got %v", c.pattern, c.path, c.match, got)
+		}
+	}
+}
+
+func TestCodeOwnersValidate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		c    CodeOwners
+		ok   bool
+	}{
+		{name: "no rules", c: CodeOwners{}, ok: true},
+		{name: "rules", c: CodeOwners{Rules: []OwnershipRule{{Pattern: "*", Owners: []string{"u1"}}, {Pattern: "/db/", Owners: []string{"u2", "u3"}}}}, ok: true},
+		{name: "empty pattern", c: CodeOwners{Rules: []OwnershipRule{{Pattern: " ", Owners: []string{"u1"}}}}},
+		{name: "root only", c: CodeOwners{Rules: []OwnershipRule{{Pattern: "/", Owners: []string{"u1"}}}}},
+		{name: "negation", c: CodeOwners{Rules: []OwnershipRule{{Pattern: "!*.go", Owners: []string{"u1"}}}}},
+		{name: "no owners", c: CodeOwners{Rules: []OwnershipRule{{Pattern: "*"}}}},
+		{name: "empty owner", c: CodeOwners{Rules: []OwnershipRule{{Pattern: "*", Owners: []string{""}}}}},
+	}
+	for _, c := range cases {
+		err := c.c.Validate()
+		if c.ok && err != nil {
+			t.Errorf("%s: unexpected error %v", c.name, err)
+		}
+		if !c.ok && !errors.Is(err, ErrInvalidCodeOwners) {
+			t.Errorf("%s: expected ErrInvalidCodeOwners, got %v", c.name, err)
+		}
+	}
+}
+
+func TestOwnersOf_LastMatchingRuleWins(t *testing.T) {
+	t.Parallel()
+
+	c := CodeOwners{Rules: []OwnershipRule{
+		{Pattern: "*", Owners: []string{"u1"}},
+		{Pattern: "/internal/db/", Owners: []string{"u2", "u3"}},
+		{Pattern: "*.sql", Owners: []string{"u4"}},
+	}}
+
+	got := c.OwnersOf([]string{"/internal/db/db.go", "internal/db/migrations/001.sql", "README.md", "internal/db/tx.go"})
+	if want := []string{"u2", "u3", "u4", "u1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if got := (CodeOwners{}).OwnersOf([]string{"main.go"}); got != nil {
+		t.Fatalf("expected no owners, got %v", got)
+	}
+}
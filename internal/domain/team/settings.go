@@ -0,0 +1,58 @@
+package team
+
+import "errors"
+
+const (
+	DefaultReviewersRequired = 2
+	DefaultMinReviewers      = 0
+)
+
+var ErrInvalidSettings = errors.New("invalid team settings")
+
+// Settings controls reviewer assignment for PRs authored by team members.
+// Empty Strategy means the deployment-wide default strategy.
+// FallbackTeams are asked in order when the team itself has too few candidates.
+// RequiredApprovals blocks merging until that many reviewers approved; 0 disables the check.
+// SearchParents widens the candidate search to the parent teams, nearest first, before
+// the fallback teams.
+// A team without settings of its own inherits those of its nearest ancestor that has
+// them; InheritedFrom names that ancestor and is empty for the team's own settings.
+type Settings struct {
+	TeamName          string
+	ReviewersRequired int
+	MinReviewers      int
+	Strategy          string
+	FallbackTeams     []string
+	RequiredApprovals int
+	SearchParents     bool
+	InheritedFrom     string
+}
+
+func DefaultSettings(teamName string) Settings {
+	return Settings{
+		TeamName:          teamName,
+		ReviewersRequired: DefaultReviewersRequired,
+		MinReviewers:      DefaultMinReviewers,
+	}
+}
+
+func (s Settings) Validate() error {
+	if s.ReviewersRequired < 0 || s.MinReviewers < 0 || s.RequiredApprovals < 0 {
+		return ErrInvalidSettings
+	}
+	if s.MinReviewers > s.ReviewersRequired {
+		return ErrInvalidSettings
+	}
+
+	seen := make(map[string]struct{}, len(s.FallbackTeams))
+	for _, fb := range s.FallbackTeams {
+		if fb == "" || fb == s.TeamName {
+			return ErrInvalidSettings
+		}
+		if _, ok := seen[fb]; ok {
+			return ErrInvalidSettings
+		}
+		seen[fb] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package team
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDefaultSettings(t *testing.T) {
+	t.Parallel()
+
+	s := DefaultSettings("backend")
+	if s.TeamName != "backend" || s.ReviewersRequired != 2 || s.MinReviewers != 0 || s.Strategy != "" {
+		t.Fatalf("unexpected defaults: %#v", s)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("defaults must be valid, got %v", err)
+	}
+}
+
+func TestSettingsValidate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		s    Settings
+		ok   bool
+	}{
+		{name: "one reviewer", s: Settings{ReviewersRequired: 1, MinReviewers: 1}, ok: true},
+		{name: "three reviewers", s: Settings{ReviewersRequired: 3, MinReviewers: 2}, ok: true},
+		{name: "no reviewers", s: Settings{}, ok: true},
+		{name: "negative required", s: Settings{ReviewersRequired: -1}},
+		{name: "negative min", s: Settings{ReviewersRequired: 1, MinReviewers: -1}},
+		{name: "min above required", s: Settings{ReviewersRequired: 1, MinReviewers: 2}},
+		{name: "required approvals", s: Settings{ReviewersRequired: 2, RequiredApprovals: 2}, ok: true},
+		{name: "negative approvals", s: Settings{ReviewersRequired: 2, RequiredApprovals: -1}},
+		{name: "fallbacks", s: Settings{TeamName: "docs", ReviewersRequired: 1, FallbackTeams: []string{"backend", "frontend"}}, ok: true},
+		{name: "self fallback", s: Settings{TeamName: "docs", ReviewersRequired: 1, FallbackTeams: []string{"docs"}}},
+		{name: "duplicate fallback", s: Settings{TeamName: "docs", ReviewersRequired: 1, FallbackTeams: []string{"backend", "backend"}}},
+		{name: "empty fallback", s: Settings{TeamName: "docs", ReviewersRequired: 1, FallbackTeams: []string{""}}},
+	}
+
+	for _, tc := range cases {
+		err := tc.s.Validate()
+		if tc.ok && err != nil {
+			t.Fatalf("%s: unexpected error %v", tc.name, err)
+		}
+		if !tc.ok && !errors.Is(err, ErrInvalidSettings) {
+			t.Fatalf("%s: expected ErrInvalidSettings, got %v", tc.name, err)
+		}
+	}
+}
@@ -1,10 +1,53 @@
 package team
 
 import (
+	"errors"
+	"sort"
+
 	"github.com/hihikaAAa/PRManager/internal/domain/user"
 )
 
-type Team struct{
+// ErrInvalidParent is a parent that would make the team its own ancestor.
+var ErrInvalidParent = errors.New("team cannot be its own ancestor")
+
+// Team is a node of the team hierarchy. ParentTeam is empty for top-level teams.
+type Team struct {
+	TeamName   string
+	ParentTeam string
+	Members    []*user.User
+}
+
+// Node is a team with its sub-teams.
+type Node struct {
 	TeamName string
-	Members []*user.User
-}
\ No newline at end of file
+	Children []*Node
+}
+
+// BuildTree arranges the teams into trees ordered by name. Teams whose parent is
+// not in the list become roots.
+func BuildTree(teams []Team) []*Node {
+	nodes := make(map[string]*Node, len(teams))
+	for _, t := range teams {
+		nodes[t.TeamName] = &Node{TeamName: t.TeamName}
+	}
+
+	var roots []*Node
+	for _, t := range teams {
+		n := nodes[t.TeamName]
+		if parent, ok := nodes[t.ParentTeam]; ok && t.ParentTeam != "" {
+			parent.Children = append(parent.Children, n)
+			continue
+		}
+		roots = append(roots, n)
+	}
+
+	sortNodes(roots)
+	return roots
+}
+
+func sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].TeamName < nodes[j].TeamName })
+	for _, n := range nodes {
+		sortNodes(n.Children)
+	}
+}
@@ -0,0 +1,28 @@
+package team
+
+import "testing"
+
+func TestBuildTree(t *testing.T) {
+	t.Parallel()
+
+	roots := BuildTree([]Team{
+		{TeamName: "search", ParentTeam: "platform"},
+		{TeamName: "org"},
+		{TeamName: "platform", ParentTeam: "org"},
+		{TeamName: "infra", ParentTeam: "platform"},
+		{TeamName: "docs"},
+		{TeamName: "orphan", ParentTeam: "gone"},
+	})
+
+	if len(roots) != 3 || roots[0].TeamName != "docs" || roots[1].TeamName != "org" || roots[2].TeamName != "orphan" {
+		t.Fatalf("unexpected roots: %+v", roots)
+	}
+	org := roots[1]
+	if len(org.Children) != 1 || org.Children[0].TeamName != "platform" {
+		t.Fatalf("unexpected org children: %+v", org.Children)
+	}
+	platform := org.Children[0]
+	if len(platform.Children) != 2 || platform.Children[0].TeamName != "infra" || platform.Children[1].TeamName != "search" {
+		t.Fatalf("unexpected platform children: %+v", platform.Children)
+	}
+}
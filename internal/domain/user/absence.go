@@ -0,0 +1,35 @@
+package user
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInvalidAbsence = errors.New("absence must end after it starts")
+
+// Absence is an out-of-office window: the user gets no new reviews from StartsAt
+// until EndsAt, and their open reviews are handed over when it starts.
+// HandledAt is set once that handover is done. Attempts counts the failed
+// handovers; the next one is not tried before NextAttemptAt.
+type Absence struct {
+	ID            int64
+	UserID        string
+	StartsAt      time.Time
+	EndsAt        time.Time
+	Reason        string
+	HandledAt     *time.Time
+	Attempts      int
+	NextAttemptAt *time.Time
+}
+
+func (a Absence) Validate() error {
+	if a.StartsAt.IsZero() || !a.EndsAt.After(a.StartsAt) {
+		return ErrInvalidAbsence
+	}
+	return nil
+}
+
+// ActiveAt reports whether the user is away at t.
+func (a Absence) ActiveAt(t time.Time) bool {
+	return !t.Before(a.StartsAt) && t.Before(a.EndsAt)
+}
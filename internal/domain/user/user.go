@@ -1,8 +1,19 @@
 package user
 
+import "errors"
+
+var ErrInvalidCapacity = errors.New("invalid review capacity")
+
+// MaxOpenReviews limits the open PRs the user reviews at once; 0 means no limit.
 type User struct{
 	ID string
 	Name string
 	IsActive bool
 	TeamName string
+	MaxOpenReviews int
+}
+
+// AtCapacity reports whether the user already reviews as many open PRs as allowed.
+func (u *User) AtCapacity(openReviews int) bool{
+	return u.MaxOpenReviews > 0 && openReviews >= u.MaxOpenReviews
 }
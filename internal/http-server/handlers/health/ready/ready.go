@@ -0,0 +1,30 @@
+package healthhandlerready
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/hihikaAAa/PRManager/internal/services/healthservice"
+)
+
+type ReadinessChecker interface {
+	Ready(ctx context.Context) healthservice.Report
+}
+
+// New responds with the readiness report as is: 200 when ready, 503 otherwise.
+func New(log *slog.Logger, checker ReadinessChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.health.ready"
+
+		logger := log.With(slog.String("op", op))
+
+		rep := checker.Ready(r.Context())
+		if !rep.Ready() {
+			logger.Warn("instance is not ready", slog.Any("report", rep))
+			render.Status(r, http.StatusServiceUnavailable)
+		}
+		render.JSON(w, r, rep)
+	}
+}
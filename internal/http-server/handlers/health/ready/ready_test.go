@@ -0,0 +1,66 @@
+package healthhandlerready
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	"github.com/hihikaAAa/PRManager/internal/services/healthservice"
+)
+
+type checkerMock struct {
+	rep healthservice.Report
+}
+
+func (m *checkerMock) Ready(ctx context.Context) healthservice.Report {
+	return m.rep
+}
+
+func TestReady(t *testing.T) {
+	tests := []struct {
+		name       string
+		rep        healthservice.Report
+		wantStatus int
+	}{
+		{
+			name:       "ready",
+			rep:        healthservice.Report{Status: healthservice.StatusOK, Database: &healthservice.Check{Status: healthservice.StatusOK}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "database down",
+			rep:        healthservice.Report{Status: healthservice.StatusFail, Database: &healthservice.Check{Status: healthservice.StatusFail, Error: "connection refused"}},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "shutting down",
+			rep:        healthservice.Report{Status: healthservice.StatusFail, ShuttingDown: true},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(slogdiscard.NewDiscardLogger(), &checkerMock{rep: tt.rep})
+			rr := httptest.NewRecorder()
+			h(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, rr.Code)
+			}
+			var got healthservice.Report
+			if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tt.rep.Status || got.ShuttingDown != tt.rep.ShuttingDown {
+				t.Fatalf("unexpected body: %s", rr.Body.String())
+			}
+			if tt.rep.Database != nil && (got.Database == nil || *got.Database != *tt.rep.Database) {
+				t.Fatalf("unexpected database check: %s", rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package pullrequesthandlerbulkcreate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	pullrequestitem "github.com/hihikaAAa/PRManager/internal/http-server/handlers/pullrequest/item"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/services/prservice"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+const (
+	// MaxItems is the largest number of PRs accepted in one request.
+	MaxItems = 1000
+	maxBody  = 8 << 20
+
+	contentTypeNDJSON = "application/x-ndjson"
+)
+
+type BulkCreator interface {
+	BulkCreate(ctx context.Context, items []prservice.BulkItem) []prservice.BulkResult
+}
+
+type bulkItemRequest struct {
+	PullRequestID   string   `json:"pull_request_id"`
+	PullRequestName string   `json:"pull_request_name"`
+	AuthorID        string   `json:"author_id"`
+	Draft           bool     `json:"draft"`
+	Reviewers       []string `json:"reviewers"`
+}
+
+type bulkCreateResponse struct {
+	Created int          `json:"created"`
+	Failed  int          `json:"failed"`
+	Results []resultItem `json:"results"`
+}
+
+type resultItem struct {
+	Index         int                          `json:"index"`
+	PullRequestID string                       `json:"pull_request_id"`
+	PullRequest   *pullrequestitem.PullRequest `json:"pr,omitempty"`
+	Error         *errorItem                   `json:"error,omitempty"`
+}
+
+type errorItem struct {
+	Code    httpresp.ErrorCode `json:"code"`
+	Message string             `json:"message"`
+}
+
+// New accepts a JSON array of PRs, or one PR per line with Content-Type application/x-ndjson.
+// Every item gets a result with the error code /pullRequest/create would return.
+func New(log *slog.Logger, creator BulkCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.pull-request.bulkCreate"
+
+		logger := log.With(slog.String("op", op))
+
+		reqs, err := decodeItems(http.MaxBytesReader(w, r.Body, maxBody), isNDJSON(r))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			switch {
+			case errors.Is(err, errTooManyItems), errors.As(err, &tooLarge):
+				httpresp.WriteError(w, r, http.StatusRequestEntityTooLarge, httpresp.CodeNotFound, fmt.Sprintf("too many pull requests, at most %d per request", MaxItems))
+			default:
+				httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid json")
+			}
+			return
+		}
+		if len(reqs) == 0 {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "no pull requests")
+			return
+		}
+
+		resp := bulkCreateResponse{Results: make([]resultItem, len(reqs))}
+		items := make([]prservice.BulkItem, 0, len(reqs))
+		positions := make([]int, 0, len(reqs))
+		for i, req := range reqs {
+			resp.Results[i] = resultItem{Index: i, PullRequestID: req.PullRequestID}
+			if req.PullRequestID == "" || req.AuthorID == "" || req.PullRequestName == "" {
+				resp.Results[i].Error = &errorItem{Code: httpresp.CodeNotFound, Message: "missing required parameters"}
+				continue
+			}
+			items = append(items, prservice.BulkItem{
+				ID: req.PullRequestID, Name: req.PullRequestName, AuthorID: req.AuthorID,
+				Draft: req.Draft, Reviewers: req.Reviewers,
+			})
+			positions = append(positions, i)
+		}
+
+		for j, res := range creator.BulkCreate(r.Context(), items) {
+			item := &resp.Results[positions[j]]
+			if res.Err != nil {
+				item.Error = mapError(logger, item.PullRequestID, res.Err)
+				continue
+			}
+			pr := pullrequestitem.New(res.PR)
+			item.PullRequest = &pr
+		}
+
+		for _, item := range resp.Results {
+			if item.Error != nil {
+				resp.Failed++
+			} else {
+				resp.Created++
+			}
+		}
+
+		logger.Info("prs imported", slog.Int("created", resp.Created), slog.Int("failed", resp.Failed))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
+
+var errTooManyItems = errors.New("too many items")
+
+func isNDJSON(r *http.Request) bool {
+	mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mt == contentTypeNDJSON
+}
+
+// decodeItems reads a JSON array, or a stream of JSON objects when ndjson is set.
+func decodeItems(body io.Reader, ndjson bool) ([]bulkItemRequest, error) {
+	dec := json.NewDecoder(body)
+	if !ndjson {
+		var reqs []bulkItemRequest
+		if err := dec.Decode(&reqs); err != nil {
+			return nil, err
+		}
+		if len(reqs) > MaxItems {
+			return nil, errTooManyItems
+		}
+		return reqs, nil
+	}
+
+	var reqs []bulkItemRequest
+	for {
+		var req bulkItemRequest
+		err := dec.Decode(&req)
+		if err == io.EOF {
+			return reqs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(reqs) == MaxItems {
+			return nil, errTooManyItems
+		}
+		reqs = append(reqs, req)
+	}
+}
+
+func mapError(logger *slog.Logger, prID string, err error) *errorItem {
+	switch {
+	case errors.Is(err, serviceerrors.ErrPRExists):
+		return &errorItem{Code: httpresp.CodePRExists, Message: "PR id already exists"}
+	case errors.Is(err, repo_errors.ErrUserNotFound), errors.Is(err, repo_errors.ErrTeamNotFound), errors.Is(err, serviceerrors.ErrUserNotFound):
+		return &errorItem{Code: httpresp.CodeNotFound, Message: "author, reviewer or team not found"}
+	case errors.Is(err, serviceerrors.ErrCandidatesAtCapacity):
+		return &errorItem{Code: httpresp.CodeNoCandidate, Message: "all candidates are at review capacity"}
+	case errors.Is(err, serviceerrors.ErrNoCandidates):
+		return &errorItem{Code: httpresp.CodeNoCandidate, Message: "not enough active reviewers in team"}
+	case errors.Is(err, serviceerrors.ErrInvalidReviewers):
+		return &errorItem{Code: httpresp.CodeInvalidReviewers, Message: "reviewers must be distinct users other than the author, drafts have none"}
+	default:
+		logger.Error("failed to import PR", slog.String("prID", prID), slog.Any("err", err))
+		return &errorItem{Code: httpresp.CodeNotFound, Message: "internal error"}
+	}
+}
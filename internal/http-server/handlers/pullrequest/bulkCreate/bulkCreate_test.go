@@ -0,0 +1,146 @@
+package pullrequesthandlerbulkcreate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	"github.com/hihikaAAa/PRManager/internal/services/prservice"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type bulkCreatorMock struct {
+	items   []prservice.BulkItem
+	results map[string]prservice.BulkResult
+}
+
+func (m *bulkCreatorMock) BulkCreate(ctx context.Context, items []prservice.BulkItem) []prservice.BulkResult {
+	m.items = items
+	out := make([]prservice.BulkResult, len(items))
+	for i, it := range items {
+		if res, ok := m.results[it.ID]; ok {
+			out[i] = res
+			continue
+		}
+		out[i] = prservice.BulkResult{PR: &pullrequest.PullRequest{
+			ID: it.ID, Name: it.Name, AuthorID: it.AuthorID, Status: pullrequest.StatusOpen, Reviewers: it.Reviewers,
+		}}
+	}
+	return out
+}
+
+func doRequest(t *testing.T, m *bulkCreatorMock, contentType, body string) (*httptest.ResponseRecorder, bulkCreateResponse) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/bulkCreate", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	rr := httptest.NewRecorder()
+	New(slogdiscard.NewDiscardLogger(), m).ServeHTTP(rr, req)
+
+	var resp bulkCreateResponse
+	if rr.Code == http.StatusOK {
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid response %q: %v", rr.Body.String(), err)
+		}
+	}
+	return rr, resp
+}
+
+func TestBulkCreate_JSONArray(t *testing.T) {
+	m := &bulkCreatorMock{results: map[string]prservice.BulkResult{
+		"pr-2": {Err: serviceerrors.ErrPRExists},
+		"pr-3": {Err: serviceerrors.ErrUserNotFound},
+		"pr-4": {Err: serviceerrors.ErrInvalidReviewers},
+	}}
+	body := `[
+		{"pull_request_id":"pr-1","pull_request_name":"One","author_id":"u1","reviewers":["u2"]},
+		{"pull_request_id":"pr-2","pull_request_name":"Two","author_id":"u1"},
+		{"pull_request_id":"pr-x","author_id":"u1"},
+		{"pull_request_id":"pr-3","pull_request_name":"Three","author_id":"nobody"},
+		{"pull_request_id":"pr-4","pull_request_name":"Four","author_id":"u1","reviewers":["u1"]}
+	]`
+
+	rr, resp := doRequest(t, m, "application/json", body)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if resp.Created != 1 || resp.Failed != 4 || len(resp.Results) != 5 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	if len(m.items) != 4 {
+		t.Fatalf("invalid item was passed to the service: %+v", m.items)
+	}
+	if m.items[0].Reviewers[0] != "u2" || m.items[1].Reviewers != nil {
+		t.Fatalf("reviewers were not passed as given: %+v", m.items)
+	}
+
+	want := []httpresp.ErrorCode{"", httpresp.CodePRExists, httpresp.CodeNotFound, httpresp.CodeNotFound, httpresp.CodeInvalidReviewers}
+	for i, code := range want {
+		res := resp.Results[i]
+		if res.Index != i {
+			t.Fatalf("result %d has index %d", i, res.Index)
+		}
+		if code == "" {
+			if res.Error != nil || res.PullRequest == nil || res.PullRequest.PullRequestID != "pr-1" {
+				t.Fatalf("result %d: unexpected %+v", i, res)
+			}
+			continue
+		}
+		if res.Error == nil || res.Error.Code != code || res.PullRequest != nil {
+			t.Fatalf("result %d: expected %s, got %+v", i, code, res)
+		}
+	}
+}
+
+func TestBulkCreate_NDJSON(t *testing.T) {
+	m := &bulkCreatorMock{}
+	body := `{"pull_request_id":"pr-1","pull_request_name":"One","author_id":"u1"}
+{"pull_request_id":"pr-2","pull_request_name":"Two","author_id":"u1","draft":true}
+`
+
+	rr, resp := doRequest(t, m, "application/x-ndjson; charset=utf-8", body)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if resp.Created != 2 || len(m.items) != 2 || !m.items[1].Draft {
+		t.Fatalf("unexpected response %+v, items %+v", resp, m.items)
+	}
+}
+
+func TestBulkCreate_BadRequests(t *testing.T) {
+	var tooMany strings.Builder
+	for i := 0; i <= MaxItems; i++ {
+		fmt.Fprintf(&tooMany, `{"pull_request_id":"pr-%d","pull_request_name":"PR","author_id":"u1"}`+"\n", i)
+	}
+
+	cases := []struct {
+		name, contentType, body string
+		code                    int
+	}{
+		{"invalid json", "application/json", `[{"pull_request_id":`, http.StatusBadRequest},
+		{"object instead of array", "application/json", `{"pull_request_id":"pr-1"}`, http.StatusBadRequest},
+		{"empty", "application/json", `[]`, http.StatusBadRequest},
+		{"empty ndjson", "application/x-ndjson", ``, http.StatusBadRequest},
+		{"too many", "application/x-ndjson", tooMany.String(), http.StatusRequestEntityTooLarge},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &bulkCreatorMock{}
+			rr, _ := doRequest(t, m, tc.contentType, tc.body)
+			if rr.Code != tc.code {
+				t.Fatalf("expected %d, got %d: %s", tc.code, rr.Code, rr.Body.String())
+			}
+			if m.items != nil {
+				t.Fatal("service was called")
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package pullrequesthandlerclose
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	pullrequestitem "github.com/hihikaAAa/PRManager/internal/http-server/handlers/pullrequest/item"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type PrCloser interface {
+	Close(ctx context.Context, id string) (*pullrequest.PullRequest, error)
+}
+
+type prCloseRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
+type prCloseResponse struct {
+	PullRequest pullRequestItem `json:"pr"`
+}
+
+type pullRequestItem struct {
+	pullrequestitem.PullRequest
+	ClosedAt *time.Time `json:"closedAt,omitempty"`
+}
+
+func New(log *slog.Logger, svc PrCloser) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.pull-request.close"
+
+		logger := log.With(slog.String("op", op))
+
+		var req prCloseRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid json")
+			return
+		}
+		if req.PullRequestID == "" {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "pr id is required")
+			return
+		}
+
+		pullreq, err := svc.Close(r.Context(), req.PullRequestID)
+		if err != nil {
+			switch {
+			case errors.Is(err, repo_errors.ErrPRNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "pr is not found")
+			case errors.Is(err, pullrequest.ErrInvalidTransition):
+				httpresp.WriteError(w, r, http.StatusConflict, httpresp.CodeInvalidTransition, "merged pr cannot be closed")
+			default:
+				logger.Error("failed to close PR", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := prCloseResponse{PullRequest: pullRequestItem{
+			PullRequest: pullrequestitem.New(pullreq),
+			ClosedAt:    pullreq.ClosedAt,
+		}}
+
+		logger.Info("pr closed", slog.String("prID", resp.PullRequest.PullRequestID))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
@@ -0,0 +1,112 @@
+package pullrequesthandlerclose
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type mockClose struct {
+	pr       *pullrequest.PullRequest
+	err      error
+	calledID string
+}
+
+func (m *mockClose) Close(ctx context.Context, id string) (*pullrequest.PullRequest, error) {
+	m.calledID = id
+	return m.pr, m.err
+}
+
+func newTestLogger() *slog.Logger {
+	return slogdiscard.NewDiscardLogger()
+}
+
+func TestClosePR_Success(t *testing.T) {
+	log := newTestLogger()
+	mock := &mockClose{
+		pr: &pullrequest.PullRequest{
+			ID:        "pr-1",
+			Name:      "Add",
+			AuthorID:  "u1",
+			Status:    pullrequest.StatusClosed,
+			Reviewers: nil,
+		},
+	}
+	h := New(log, mock)
+
+	body := []byte(`{"pull_request_id":"pr-1"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/close", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if mock.calledID != "pr-1" {
+		t.Fatalf("expected pr-1, got %q", mock.calledID)
+	}
+	if body := rr.Body.String(); !strings.Contains(body, `"status":"CLOSED"`) {
+		t.Fatalf("unexpected body: %s", body)
+	}
+}
+
+func TestClosePR_MissingID(t *testing.T) {
+	log := newTestLogger()
+	mock := &mockClose{}
+	h := New(log, mock)
+
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/close", bytes.NewReader([]byte(`{}`)))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+	if mock.calledID != "" {
+		t.Fatalf("service must not be called on validation error")
+	}
+}
+
+func TestClosePR_NotFound(t *testing.T) {
+	log := newTestLogger()
+	h := New(log, &mockClose{err: repo_errors.ErrPRNotFound})
+
+	body := []byte(`{"pull_request_id":"unknown"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/close", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+}
+
+func TestClosePR_InvalidTransition(t *testing.T) {
+	log := newTestLogger()
+	h := New(log, &mockClose{err: pullrequest.ErrInvalidTransition})
+
+	body := []byte(`{"pull_request_id":"pr-1"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/close", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), string(httpresp.CodeInvalidTransition)) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
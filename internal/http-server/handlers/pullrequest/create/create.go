@@ -8,31 +8,31 @@ import (
 
 	"github.com/go-chi/render"
 	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	pullrequestitem "github.com/hihikaAAa/PRManager/internal/http-server/handlers/pullrequest/item"
 	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
-	"github.com/hihikaAAa/PRManager/internal/repository/postgres/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
 	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
 )
 
 type PrCreator interface{
-	Create(ctx context.Context, id,name,authorID string)(*pullrequest.PullRequest, error)
+	Create(ctx context.Context, id,name,authorID string, draft bool, changedFiles []string)(*pullrequest.PullRequest, error)
 }
 
 type prCreateRequest struct{
 	PullRequestID string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
 	AuthorID string `json:"author_id"`
+	Draft bool `json:"draft"`
+	ChangedFiles []string `json:"changed_files"`
 }
 
 type prCreateResponse struct{
 	PullRequest pullRequestItem `json:"pr"`
 }
 
-type pullRequestItem struct{
-	PullRequestID string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID string `json:"author_id"`
-	Status string `json:"status"`
-	AssignedReviewers []string `json:"assigned_reviewers"`
+type pullRequestItem struct {
+	pullrequestitem.PullRequest
+	ChangedFiles []string `json:"changed_files,omitempty"`
 }
 
 func New(log *slog.Logger, prCreator PrCreator) http.HandlerFunc{
@@ -51,13 +51,17 @@ func New(log *slog.Logger, prCreator PrCreator) http.HandlerFunc{
 			return
 		}
 
-		pullreq, err := prCreator.Create(r.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID)
+		pullreq, err := prCreator.Create(r.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID, req.Draft, req.ChangedFiles)
 		if err != nil {
 			switch {
 			case errors.Is(err, serviceerrors.ErrPRExists):
 				httpresp.WriteError(w, r, http.StatusConflict, httpresp.CodePRExists, "PR id already exists")
-			case errors.Is(err, repo_errors.ErrUserNotFound),errors.Is(err, repo_errors.ErrTeamNotFound):
+			case errors.Is(err, repo_errors.ErrUserNotFound),errors.Is(err, repo_errors.ErrTeamNotFound),errors.Is(err, serviceerrors.ErrUserNotFound):
 				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "author or team not found")
+			case errors.Is(err, serviceerrors.ErrCandidatesAtCapacity):
+				httpresp.WriteError(w, r, http.StatusConflict, httpresp.CodeNoCandidate, "all candidates are at review capacity")
+			case errors.Is(err, serviceerrors.ErrNoCandidates):
+				httpresp.WriteError(w, r, http.StatusConflict, httpresp.CodeNoCandidate, "not enough active reviewers in team")
 			default:
 				logger.Error("failed to create PR", slog.Any("err", err))
 				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
@@ -65,15 +69,12 @@ func New(log *slog.Logger, prCreator PrCreator) http.HandlerFunc{
 			return
 		}
 		resp := prCreateResponse{PullRequest: pullRequestItem{
-			PullRequestID: pullreq.ID,
-			PullRequestName: pullreq.Name,
-			AuthorID: pullreq.AuthorID,
-			Status: string(pullreq.Status),
-			AssignedReviewers: pullreq.Reviewers,
+			PullRequest:  pullrequestitem.New(pullreq),
+			ChangedFiles: pullreq.ChangedFiles,
 		}}
 
 		logger.Info("pr created", slog.String("prID", resp.PullRequest.PullRequestID))
 		render.Status(r, http.StatusCreated)
 		render.JSON(w, r, resp)
 	}
-}
\ No newline at end of file
+}
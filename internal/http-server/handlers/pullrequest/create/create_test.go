@@ -12,16 +12,20 @@ import (
 	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
 	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
 	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
-	"github.com/hihikaAAa/PRManager/internal/repository/postgres/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
 	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
 )
 
 type prCreatorMock struct {
 	pr *pullrequest.PullRequest
 	err error
+	calledDraft bool
+	calledFiles []string
 }
 
-func (m *prCreatorMock) Create(ctx context.Context, id, name, authorID string) (*pullrequest.PullRequest, error) {
+func (m *prCreatorMock) Create(ctx context.Context, id, name, authorID string, draft bool, changedFiles []string) (*pullrequest.PullRequest, error) {
+	m.calledDraft = draft
+	m.calledFiles = changedFiles
 	return m.pr, m.err
 }
 
@@ -94,3 +98,129 @@ func TestCreatePR_AuthorOrTeamNotFound(t *testing.T) {
 		t.Fatalf("expected 404, got %d", rr.Code)
 	}
 }
+
+func TestCreatePR_NotEnoughCandidates(t *testing.T) {
+	log := newTestLogger()
+	mock := &prCreatorMock{err: serviceerrors.ErrNoCandidates}
+	h := New(log, mock)
+
+	body := []byte(`{"pull_request_id":"pr-1","pull_request_name":"Add","author_id":"u1"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), string(httpresp.CodeNoCandidate)) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestCreatePR_CandidatesAtCapacity(t *testing.T) {
+	log := newTestLogger()
+	mock := &prCreatorMock{err: serviceerrors.ErrCandidatesAtCapacity}
+	h := New(log, mock)
+
+	body := []byte(`{"pull_request_id":"pr-1","pull_request_name":"Add","author_id":"u1"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), string(httpresp.CodeNoCandidate)) || !strings.Contains(rr.Body.String(), "capacity") {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestCreatePR_FallbackReviewers(t *testing.T) {
+	log := newTestLogger()
+	mock := &prCreatorMock{
+		pr: &pullrequest.PullRequest{
+			ID:                "pr-2",
+			Name:              "Docs",
+			AuthorID:          "u1",
+			Status:            pullrequest.StatusOpen,
+			Reviewers:         []string{"u2", "u7"},
+			FallbackReviewers: []string{"u7"},
+		},
+	}
+	h := New(log, mock)
+
+	body := []byte(`{"pull_request_id":"pr-2","pull_request_name":"Docs","author_id":"u1"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `"fallback_reviewers":["u7"]`) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestCreatePR_Draft(t *testing.T) {
+	log := newTestLogger()
+	mock := &prCreatorMock{
+		pr: &pullrequest.PullRequest{
+			ID:       "pr-3",
+			Name:     "WIP",
+			AuthorID: "u1",
+			Status:   pullrequest.StatusDraft,
+		},
+	}
+	h := New(log, mock)
+
+	body := []byte(`{"pull_request_id":"pr-3","pull_request_name":"WIP","author_id":"u1","draft":true}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rr.Code)
+	}
+	if !mock.calledDraft {
+		t.Fatalf("expected draft flag to be passed to service")
+	}
+	if !strings.Contains(rr.Body.String(), `"status":"DRAFT"`) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestCreatePR_ChangedFiles(t *testing.T) {
+	log := newTestLogger()
+	mock := &prCreatorMock{
+		pr: &pullrequest.PullRequest{
+			ID:           "pr-4",
+			Name:         "Migrations",
+			AuthorID:     "u1",
+			Status:       pullrequest.StatusOpen,
+			Reviewers:    []string{"u2"},
+			ChangedFiles: []string{"migrations/001.sql", "README.md"},
+		},
+	}
+	h := New(log, mock)
+
+	body := []byte(`{"pull_request_id":"pr-4","pull_request_name":"Migrations","author_id":"u1","changed_files":["migrations/001.sql","README.md"]}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rr.Code)
+	}
+	if len(mock.calledFiles) != 2 || mock.calledFiles[0] != "migrations/001.sql" {
+		t.Fatalf("expected changed files to be passed to service, got %v", mock.calledFiles)
+	}
+	if !strings.Contains(rr.Body.String(), `"changed_files":["migrations/001.sql","README.md"]`) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
@@ -0,0 +1,75 @@
+package pullrequesthandlerhistory
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type PRHistoryGetter interface {
+	History(ctx context.Context, prID string) ([]assignment.Change, error)
+}
+
+type prHistoryResponse struct {
+	PullRequestID string      `json:"pull_request_id"`
+	Events        []eventItem `json:"events"`
+}
+
+type eventItem struct {
+	ID            int64     `json:"id"`
+	OldReviewerID string    `json:"old_reviewer_id,omitempty"`
+	NewReviewerID string    `json:"new_reviewer_id,omitempty"`
+	Reason        string    `json:"reason"`
+	FromFallback  bool      `json:"from_fallback,omitempty"`
+	Actor         string    `json:"actor"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func New(log *slog.Logger, svc PRHistoryGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.pull-request.history"
+
+		logger := log.With(slog.String("op", op))
+
+		prID := r.URL.Query().Get("pull_request_id")
+		if prID == "" {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "pull_request_id is required")
+			return
+		}
+
+		changes, err := svc.History(r.Context(), prID)
+		if err != nil {
+			switch {
+			case errors.Is(err, repo_errors.ErrPRNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "pr is not found")
+			default:
+				logger.Error("failed to fetch PR history", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := prHistoryResponse{PullRequestID: prID, Events: make([]eventItem, 0, len(changes))}
+		for _, c := range changes {
+			resp.Events = append(resp.Events, eventItem{
+				ID:            c.ID,
+				OldReviewerID: c.OldReviewerID,
+				NewReviewerID: c.NewReviewerID,
+				Reason:        c.Reason,
+				FromFallback:  c.FromFallback,
+				Actor:         c.Actor,
+				CreatedAt:     c.CreatedAt,
+			})
+		}
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
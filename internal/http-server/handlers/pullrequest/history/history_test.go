@@ -0,0 +1,89 @@
+package pullrequesthandlerhistory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type historyGetterMock struct {
+	changes []assignment.Change
+	err     error
+	gotID   string
+}
+
+func (m *historyGetterMock) History(ctx context.Context, prID string) ([]assignment.Change, error) {
+	m.gotID = prID
+	return m.changes, m.err
+}
+
+func TestHistory_Success(t *testing.T) {
+	now := time.Now().UTC()
+	mock := &historyGetterMock{changes: []assignment.Change{
+		{ID: 1, PullRequestID: "pr-42", NewReviewerID: "u2", Reason: "auto_create", Actor: "u1", CreatedAt: now},
+		{ID: 2, PullRequestID: "pr-42", OldReviewerID: "u2", Reason: "team_deactivation", Actor: "lead", CreatedAt: now},
+	}}
+	h := New(slogdiscard.NewDiscardLogger(), mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/pullRequest/history?pull_request_id=pr-42", nil)
+	rr := httptest.NewRecorder()
+	h(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if mock.gotID != "pr-42" {
+		t.Fatalf("expected pr-42, got %q", mock.gotID)
+	}
+	var resp prHistoryResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Events) != 2 || resp.Events[1].OldReviewerID != "u2" || resp.Events[1].Reason != "team_deactivation" {
+		t.Fatalf("unexpected events: %+v", resp.Events)
+	}
+}
+
+func TestHistory_MissingID(t *testing.T) {
+	h := New(slogdiscard.NewDiscardLogger(), &historyGetterMock{})
+
+	req := httptest.NewRequest(http.MethodGet, "/pullRequest/history", nil)
+	rr := httptest.NewRecorder()
+	h(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+}
+
+func TestHistory_NotFound(t *testing.T) {
+	h := New(slogdiscard.NewDiscardLogger(), &historyGetterMock{err: repo_errors.ErrPRNotFound})
+
+	req := httptest.NewRequest(http.MethodGet, "/pullRequest/history?pull_request_id=pr-x", nil)
+	rr := httptest.NewRecorder()
+	h(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+}
+
+func TestHistory_InternalError(t *testing.T) {
+	h := New(slogdiscard.NewDiscardLogger(), &historyGetterMock{err: errors.New("db down")})
+
+	req := httptest.NewRequest(http.MethodGet, "/pullRequest/history?pull_request_id=pr-x", nil)
+	rr := httptest.NewRecorder()
+	h(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,46 @@
+// Package pullrequestitem is the JSON form of a pull request shared by the /pullRequest handlers.
+package pullrequestitem
+
+import (
+	"time"
+
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+)
+
+// PullRequest holds the fields of every /pullRequest response; handlers embed it
+// to add the fields of their own transition, such as mergedAt.
+type PullRequest struct {
+	PullRequestID     string   `json:"pull_request_id"`
+	PullRequestName   string   `json:"pull_request_name"`
+	AuthorID          string   `json:"author_id"`
+	Status            string   `json:"status"`
+	AssignedReviewers []string `json:"assigned_reviewers"`
+	FallbackReviewers []string `json:"fallback_reviewers,omitempty"`
+	Reviews           []Review `json:"reviews"`
+}
+
+type Review struct {
+	UserID    string     `json:"user_id"`
+	State     string     `json:"state"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+func New(pr *pullrequest.PullRequest) PullRequest {
+	reviewers := pr.Reviewers
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+	reviews := make([]Review, 0, len(pr.Reviews))
+	for _, rv := range pr.Reviews {
+		reviews = append(reviews, Review{UserID: rv.UserID, State: string(rv.State), UpdatedAt: rv.UpdatedAt})
+	}
+	return PullRequest{
+		PullRequestID:     pr.ID,
+		PullRequestName:   pr.Name,
+		AuthorID:          pr.AuthorID,
+		Status:            string(pr.Status),
+		AssignedReviewers: reviewers,
+		FallbackReviewers: pr.FallbackReviewers,
+		Reviews:           reviews,
+	}
+}
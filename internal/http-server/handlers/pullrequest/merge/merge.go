@@ -9,8 +9,10 @@ import(
 
 	"github.com/go-chi/render"
 	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	pullrequestitem "github.com/hihikaAAa/PRManager/internal/http-server/handlers/pullrequest/item"
 	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
-	"github.com/hihikaAAa/PRManager/internal/repository/postgres/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
 )
 
 type PrMerger interface{
@@ -25,12 +27,8 @@ type prMergerResponse struct{
 	PullRequest pullRequestItem `json:"pr"`
 }
 
-type pullRequestItem struct{
-	PullRequestID string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID string `json:"author_id"`
-	Status string `json:"status"`
-	AssignedReviewers []string `json:"assigned_reviewers"`
+type pullRequestItem struct {
+	pullrequestitem.PullRequest
 	MergedAt *time.Time `json:"mergedAt"`
 }
 
@@ -55,6 +53,10 @@ func New(log *slog.Logger, prMerger PrMerger) http.HandlerFunc{
 			switch{
 				case errors.Is(err,repo_errors.ErrPRNotFound):
 					httpresp.WriteError(w,r,http.StatusNotFound, httpresp.CodeNotFound, "pr is not found")
+				case errors.Is(err, pullrequest.ErrInvalidTransition):
+					httpresp.WriteError(w,r,http.StatusConflict, httpresp.CodeInvalidTransition, "only OPEN pr can be merged")
+				case errors.Is(err, serviceerrors.ErrNotEnoughApprovals):
+					httpresp.WriteError(w,r,http.StatusConflict, httpresp.CodeNotApproved, "pr does not have enough approvals")
 				case errors.Is(err, repo_errors.ErrPRMerged):
 					resp := buildResponse(pullreq)
 					render.Status(r, http.StatusOK)
@@ -77,12 +79,8 @@ func New(log *slog.Logger, prMerger PrMerger) http.HandlerFunc{
 func buildResponse(pr *pullrequest.PullRequest) prMergerResponse {
 	return prMergerResponse{
 		PullRequest: pullRequestItem{
-			PullRequestID:     pr.ID,
-			PullRequestName:   pr.Name,
-			AuthorID:          pr.AuthorID,
-			Status:            string(pr.Status),
-			AssignedReviewers: pr.Reviewers,
-			MergedAt:          pr.MergedAt,
+			PullRequest: pullrequestitem.New(pr),
+			MergedAt:    pr.MergedAt,
 		},
 	}
-}
\ No newline at end of file
+}
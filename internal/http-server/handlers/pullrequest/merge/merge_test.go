@@ -13,7 +13,8 @@ import (
 	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
 	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
 	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
-	"github.com/hihikaAAa/PRManager/internal/repository/postgres/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
 )
 
 type prMergerMock struct {
@@ -75,3 +76,41 @@ func TestMergePR_NotFound(t *testing.T) {
 		t.Fatalf("unexpected body: %s", rr.Body.String())
 	}
 }
+
+func TestMergePR_NotEnoughApprovals(t *testing.T) {
+	log := newTestLogger()
+	mock := &prMergerMock{err: serviceerrors.ErrNotEnoughApprovals}
+	h := New(log, mock)
+
+	body := []byte(`{"pull_request_id":"pr-1"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), string(httpresp.CodeNotApproved)) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestMergePR_Draft(t *testing.T) {
+	log := newTestLogger()
+	mock := &prMergerMock{err: pullrequest.ErrInvalidTransition}
+	h := New(log, mock)
+
+	body := []byte(`{"pull_request_id":"pr-1"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), string(httpresp.CodeInvalidTransition)) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
@@ -10,8 +10,10 @@ import (
 	"github.com/go-chi/render"
 
 	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	pullrequestitem "github.com/hihikaAAa/PRManager/internal/http-server/handlers/pullrequest/item"
 	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
-	"github.com/hihikaAAa/PRManager/internal/repository/postgres/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/lib/auth"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
 	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
 )
 
@@ -30,11 +32,7 @@ type prReassignResponse struct {
 }
 
 type pullRequestItem struct {
-	PullRequestID string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID string `json:"author_id"`
-	Status string `json:"status"`
-	AssignedReviewers []string `json:"assigned_reviewers"`
+	pullrequestitem.PullRequest
 	MergedAt *time.Time `json:"merged_at,omitempty"`
 }
 
@@ -53,6 +51,10 @@ func New(log *slog.Logger, reassigner prReassigner) http.HandlerFunc {
 			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "pull_request_id and old_user_id are required")
 			return
 		}
+		if id, ok := auth.FromContext(r.Context()); ok && id.Role == auth.RoleMember && id.UserID != req.OldUserID {
+			httpresp.WriteError(w, r, http.StatusForbidden, httpresp.CodeForbidden, "members can only reassign themselves")
+			return
+		}
 
 		pullreq, replacedBy, err := reassigner.Reassign(r.Context(), req.PullRequestID, req.OldUserID)
 		if err != nil {
@@ -63,6 +65,8 @@ func New(log *slog.Logger, reassigner prReassigner) http.HandlerFunc {
 				httpresp.WriteError(w, r, http.StatusConflict, httpresp.CodePRMerged, "cannot reassign on merged PR")
 			case errors.Is(err, serviceerrors.ErrReviewerNotFound):
 				httpresp.WriteError(w, r, http.StatusConflict, httpresp.CodeNotAssigned, "reviewer is not assigned to this PR")
+			case errors.Is(err, serviceerrors.ErrCandidatesAtCapacity):
+				httpresp.WriteError(w, r, http.StatusConflict, httpresp.CodeNoCandidate, "all candidates are at review capacity")
 			case errors.Is(err, serviceerrors.ErrNoCandidates):
 				httpresp.WriteError(w, r, http.StatusConflict, httpresp.CodeNoCandidate, "no active replacement candidate in team")
 			default:
@@ -72,13 +76,10 @@ func New(log *slog.Logger, reassigner prReassigner) http.HandlerFunc {
 			return
 		}
 
-		resp := prReassignResponse{ PullRequest: pullRequestItem{
-				PullRequestID:     pullreq.ID,
-				PullRequestName:   pullreq.Name,
-				AuthorID:          pullreq.AuthorID,
-				Status:            string(pullreq.Status),
-				AssignedReviewers: pullreq.Reviewers,
-				MergedAt:          pullreq.MergedAt,
+		resp := prReassignResponse{
+			PullRequest: pullRequestItem{
+				PullRequest: pullrequestitem.New(pullreq),
+				MergedAt:    pullreq.MergedAt,
 			},
 			ReplacedBy: replacedBy,
 		}
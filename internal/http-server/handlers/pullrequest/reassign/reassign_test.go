@@ -11,8 +11,9 @@ import (
 
 	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
 	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	"github.com/hihikaAAa/PRManager/internal/lib/auth"
 	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
-	"github.com/hihikaAAa/PRManager/internal/repository/postgres/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
 	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
 )
 
@@ -20,9 +21,11 @@ type reassignerMock struct {
 	pr *pullrequest.PullRequest
 	replacedBy string
 	err error
+	called bool
 }
 
 func (m *reassignerMock) Reassign(ctx context.Context, prID, oldReviewerID string) (*pullrequest.PullRequest, string, error) {
+	m.called = true
 	return m.pr, m.replacedBy, m.err
 }
 
@@ -78,6 +81,25 @@ func TestReassign_NoCandidate(t *testing.T) {
 	}
 }
 
+func TestReassign_CandidatesAtCapacity(t *testing.T) {
+	log := newTestLogger()
+	mock := &reassignerMock{err: serviceerrors.ErrCandidatesAtCapacity}
+	h := New(log, mock)
+
+	body := []byte(`{"pull_request_id":"pr-1","old_user_id":"u2"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/reassign", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), string(httpresp.CodeNoCandidate)) || !strings.Contains(rr.Body.String(), "capacity") {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
 func TestReassign_NotFound(t *testing.T) {
 	log := newTestLogger()
 	mock := &reassignerMock{err: repo_errors.ErrPRNotFound}
@@ -93,3 +115,37 @@ func TestReassign_NotFound(t *testing.T) {
 		t.Fatalf("expected 404, got %d", rr.Code)
 	}
 }
+
+func TestReassign_MemberOnlySelf(t *testing.T) {
+	tests := []struct {
+		name       string
+		identity   auth.Identity
+		oldUserID  string
+		wantStatus int
+	}{
+		{name: "member reassigns self", identity: auth.Identity{UserID: "u2", Role: auth.RoleMember}, oldUserID: "u2", wantStatus: http.StatusOK},
+		{name: "member reassigns other", identity: auth.Identity{UserID: "u3", Role: auth.RoleMember}, oldUserID: "u2", wantStatus: http.StatusForbidden},
+		{name: "team lead reassigns other", identity: auth.Identity{UserID: "u1", Role: auth.RoleTeamLead}, oldUserID: "u2", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &reassignerMock{pr: &pullrequest.PullRequest{ID: "pr-1", Status: pullrequest.StatusOpen}, replacedBy: "u5"}
+			h := New(newTestLogger(), mock)
+
+			body := []byte(`{"pull_request_id":"pr-1","old_user_id":"` + tt.oldUserID + `"}`)
+			req := httptest.NewRequest(http.MethodPost, "/pullRequest/reassign", bytes.NewReader(body))
+			req = req.WithContext(auth.WithIdentity(req.Context(), tt.identity))
+			rr := httptest.NewRecorder()
+
+			h(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if mock.called != (tt.wantStatus == http.StatusOK) {
+				t.Fatalf("unexpected service call: %v", mock.called)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package pullrequesthandlerreopen
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	pullrequestitem "github.com/hihikaAAa/PRManager/internal/http-server/handlers/pullrequest/item"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type PrReopener interface {
+	Reopen(ctx context.Context, id string) (*pullrequest.PullRequest, error)
+}
+
+type prReopenRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
+type prReopenResponse struct {
+	PullRequest pullRequestItem `json:"pr"`
+}
+
+type pullRequestItem struct {
+	pullrequestitem.PullRequest
+	ClosedAt *time.Time `json:"closedAt,omitempty"`
+}
+
+func New(log *slog.Logger, svc PrReopener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.pull-request.reopen"
+
+		logger := log.With(slog.String("op", op))
+
+		var req prReopenRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid json")
+			return
+		}
+		if req.PullRequestID == "" {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "pr id is required")
+			return
+		}
+
+		pullreq, err := svc.Reopen(r.Context(), req.PullRequestID)
+		if err != nil {
+			switch {
+			case errors.Is(err, repo_errors.ErrPRNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "pr is not found")
+			case errors.Is(err, pullrequest.ErrInvalidTransition):
+				httpresp.WriteError(w, r, http.StatusConflict, httpresp.CodeInvalidTransition, "only CLOSED pr can be reopened")
+			case errors.Is(err, serviceerrors.ErrUserNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "author not found")
+			case errors.Is(err, serviceerrors.ErrCandidatesAtCapacity):
+				httpresp.WriteError(w, r, http.StatusConflict, httpresp.CodeNoCandidate, "all candidates are at review capacity")
+			case errors.Is(err, serviceerrors.ErrNoCandidates):
+				httpresp.WriteError(w, r, http.StatusConflict, httpresp.CodeNoCandidate, "not enough active reviewers in team")
+			default:
+				logger.Error("failed to reopen PR", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := prReopenResponse{PullRequest: pullRequestItem{
+			PullRequest: pullrequestitem.New(pullreq),
+			ClosedAt:    pullreq.ClosedAt,
+		}}
+
+		logger.Info("pr reopened", slog.String("prID", resp.PullRequest.PullRequestID))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
@@ -0,0 +1,95 @@
+package pullrequesthandlerreview
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	pullrequestitem "github.com/hihikaAAa/PRManager/internal/http-server/handlers/pullrequest/item"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	"github.com/hihikaAAa/PRManager/internal/lib/auth"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type PrReviewer interface {
+	Review(ctx context.Context, prID, userID string, state pullrequest.ReviewState) (*pullrequest.PullRequest, error)
+}
+
+type TeamChecker interface {
+	SameTeam(ctx context.Context, userID, otherID string) (bool, error)
+}
+
+type prReviewRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+	State         string `json:"state"`
+}
+
+type prReviewResponse struct {
+	PullRequest pullrequestitem.PullRequest `json:"pr"`
+}
+
+// New serves /pullRequest/review. Members review only as themselves, team leads
+// also for a member of their own team, admins for anyone.
+func New(log *slog.Logger, reviewer PrReviewer, teams TeamChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.pull-request.review"
+
+		logger := log.With(slog.String("op", op))
+
+		var req prReviewRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid json")
+			return
+		}
+		if req.PullRequestID == "" || req.UserID == "" || req.State == "" {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "pull_request_id, user_id and state are required")
+			return
+		}
+		if id, ok := auth.FromContext(r.Context()); ok && id.UserID != req.UserID && id.Role != auth.RoleAdmin {
+			if id.Role == auth.RoleMember {
+				httpresp.WriteError(w, r, http.StatusForbidden, httpresp.CodeForbidden, "members can only review as themselves")
+				return
+			}
+			same, err := teams.SameTeam(r.Context(), id.UserID, req.UserID)
+			if err != nil {
+				logger.Error("failed to check team", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+				return
+			}
+			if !same {
+				httpresp.WriteError(w, r, http.StatusForbidden, httpresp.CodeForbidden, "can only review for members of your team")
+				return
+			}
+		}
+
+		pullreq, err := reviewer.Review(r.Context(), req.PullRequestID, req.UserID, pullrequest.ReviewState(req.State))
+		if err != nil {
+			switch {
+			case errors.Is(err, pullrequest.ErrInvalidReviewState):
+				httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "state must be APPROVED, CHANGES_REQUESTED or COMMENTED")
+			case errors.Is(err, repo_errors.ErrPRNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "pr not found")
+			case errors.Is(err, serviceerrors.ErrPRMerged):
+				httpresp.WriteError(w, r, http.StatusConflict, httpresp.CodePRMerged, "cannot review merged PR")
+			case errors.Is(err, serviceerrors.ErrReviewerNotFound):
+				httpresp.WriteError(w, r, http.StatusConflict, httpresp.CodeNotAssigned, "reviewer is not assigned to this PR")
+			default:
+				logger.Error("failed to submit review", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := prReviewResponse{PullRequest: pullrequestitem.New(pullreq)}
+
+		logger.Info("pr review submitted", slog.String("prID", pullreq.ID), slog.String("userID", req.UserID), slog.String("state", req.State))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
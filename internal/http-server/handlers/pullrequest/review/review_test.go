@@ -0,0 +1,185 @@
+package pullrequesthandlerreview
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	"github.com/hihikaAAa/PRManager/internal/lib/auth"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type prReviewerMock struct {
+	pr          *pullrequest.PullRequest
+	err         error
+	calledState pullrequest.ReviewState
+}
+
+func (m *prReviewerMock) Review(ctx context.Context, prID, userID string, state pullrequest.ReviewState) (*pullrequest.PullRequest, error) {
+	m.calledState = state
+	return m.pr, m.err
+}
+
+// teamsMock puts u1-u3 in one team; everyone else is in another.
+type teamsMock struct{}
+
+func (teamsMock) SameTeam(ctx context.Context, userID, otherID string) (bool, error) {
+	team := func(id string) string {
+		if id == "u1" || id == "u2" || id == "u3" {
+			return "backend"
+		}
+		return "frontend"
+	}
+	return team(userID) == team(otherID), nil
+}
+
+func newTestLogger() *slog.Logger {
+	return slogdiscard.NewDiscardLogger()
+}
+
+func TestReview_Success(t *testing.T) {
+	log := newTestLogger()
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock := &prReviewerMock{
+		pr: &pullrequest.PullRequest{
+			ID:        "pr-1",
+			Name:      "Add search",
+			AuthorID:  "u1",
+			Status:    pullrequest.StatusOpen,
+			Reviewers: []string{"u2", "u3"},
+			Reviews: []pullrequest.Review{
+				{UserID: "u2", State: pullrequest.ReviewApproved, UpdatedAt: &now},
+				{UserID: "u3", State: pullrequest.ReviewPending},
+			},
+		},
+	}
+	h := New(log, mock, teamsMock{})
+
+	body := []byte(`{"pull_request_id":"pr-1","user_id":"u2","state":"APPROVED"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/review", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if mock.calledState != pullrequest.ReviewApproved {
+		t.Fatalf("expected state APPROVED, got %q", mock.calledState)
+	}
+	got := rr.Body.String()
+	if !strings.Contains(got, `"state":"APPROVED"`) || !strings.Contains(got, `"state":"PENDING"`) {
+		t.Fatalf("unexpected body: %s", got)
+	}
+}
+
+func TestReview_MissingFields(t *testing.T) {
+	log := newTestLogger()
+	h := New(log, &prReviewerMock{}, teamsMock{})
+
+	body := []byte(`{"pull_request_id":"pr-1","user_id":"u2"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/review", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+}
+
+func TestReview_InvalidState(t *testing.T) {
+	log := newTestLogger()
+	h := New(log, &prReviewerMock{err: pullrequest.ErrInvalidReviewState}, teamsMock{})
+
+	body := []byte(`{"pull_request_id":"pr-1","user_id":"u2","state":"LGTM"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/review", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+}
+
+func TestReview_NotAssigned(t *testing.T) {
+	log := newTestLogger()
+	h := New(log, &prReviewerMock{err: serviceerrors.ErrReviewerNotFound}, teamsMock{})
+
+	body := []byte(`{"pull_request_id":"pr-1","user_id":"u9","state":"APPROVED"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/review", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), string(httpresp.CodeNotAssigned)) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestReview_Merged(t *testing.T) {
+	log := newTestLogger()
+	h := New(log, &prReviewerMock{err: serviceerrors.ErrPRMerged}, teamsMock{})
+
+	body := []byte(`{"pull_request_id":"pr-1","user_id":"u2","state":"APPROVED"}`)
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/review", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), string(httpresp.CodePRMerged)) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestReview_Access(t *testing.T) {
+	tests := []struct {
+		name       string
+		identity   auth.Identity
+		userID     string
+		wantStatus int
+	}{
+		{name: "member reviews as self", identity: auth.Identity{UserID: "u2", Role: auth.RoleMember}, userID: "u2", wantStatus: http.StatusOK},
+		{name: "member reviews as other", identity: auth.Identity{UserID: "u3", Role: auth.RoleMember}, userID: "u2", wantStatus: http.StatusForbidden},
+		{name: "team lead reviews for own team", identity: auth.Identity{UserID: "u1", Role: auth.RoleTeamLead}, userID: "u2", wantStatus: http.StatusOK},
+		{name: "team lead reviews for other team", identity: auth.Identity{UserID: "u1", Role: auth.RoleTeamLead}, userID: "u9", wantStatus: http.StatusForbidden},
+		{name: "admin reviews for own team", identity: auth.Identity{UserID: "u1", Role: auth.RoleAdmin}, userID: "u3", wantStatus: http.StatusOK},
+		{name: "admin reviews for other team", identity: auth.Identity{UserID: "u1", Role: auth.RoleAdmin}, userID: "u9", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &prReviewerMock{pr: &pullrequest.PullRequest{ID: "pr-1", Status: pullrequest.StatusOpen}}
+			h := New(newTestLogger(), mock, teamsMock{})
+
+			body := []byte(`{"pull_request_id":"pr-1","user_id":"` + tt.userID + `","state":"APPROVED"}`)
+			req := httptest.NewRequest(http.MethodPost, "/pullRequest/review", bytes.NewReader(body))
+			req = req.WithContext(auth.WithIdentity(req.Context(), tt.identity))
+			rr := httptest.NewRecorder()
+
+			h(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if called := mock.calledState != ""; called != (tt.wantStatus == http.StatusOK) {
+				t.Fatalf("unexpected service call: %v", called)
+			}
+		})
+	}
+}
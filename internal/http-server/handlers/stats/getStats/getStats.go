@@ -1,16 +1,19 @@
 package statshandler
 
 import (
+    "errors"
     "log/slog"
     "net/http"
 	"context"
+    "time"
 
     httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
 	statsservice "github.com/hihikaAAa/PRManager/internal/services/statsservice"
 )
 
 type StatsGetter interface {
-    GetStats(ctx context.Context) (statsservice.Stats, error)
+    GetStats(ctx context.Context, f statsservice.Filter) (statsservice.Stats, error)
 }
 
 func New(log *slog.Logger, s StatsGetter) http.HandlerFunc {
@@ -18,10 +21,31 @@ func New(log *slog.Logger, s StatsGetter) http.HandlerFunc {
         const op = "internal.http-server.handlers.stats.get"
         logger := log.With(slog.String("op", op))
 
-        st, err := s.GetStats(r.Context())
+        q := r.URL.Query()
+        f := statsservice.Filter{TeamName: q.Get("team_name")}
+        var err error
+        if f.From, err = parseTime(q.Get("from")); err != nil {
+            httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid from")
+            return
+        }
+        if f.To, err = parseTime(q.Get("to")); err != nil {
+            httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid to")
+            return
+        }
+        if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+            httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "from must be before to")
+            return
+        }
+
+        st, err := s.GetStats(r.Context(), f)
         if err != nil {
-            logger.Error("failed to get stats", slog.Any("err", err))
-            httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+            switch {
+            case errors.Is(err, serviceerrors.ErrTeamNotFound):
+                httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "team not found")
+            default:
+                logger.Error("failed to get stats", slog.Any("err", err))
+                httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+            }
             return
         }
 
@@ -29,3 +53,15 @@ func New(log *slog.Logger, s StatsGetter) http.HandlerFunc {
         httpresp.WriteOK(w, r, st)
     }
 }
+
+// parseTime accepts RFC 3339 timestamps and dates, which mean midnight UTC.
+// An empty value is the zero time, i.e. no bound.
+func parseTime(s string) (time.Time, error) {
+    if s == "" {
+        return time.Time{}, nil
+    }
+    if t, err := time.Parse(time.DateOnly, s); err == nil {
+        return t, nil
+    }
+    return time.Parse(time.RFC3339, s)
+}
@@ -7,18 +7,22 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
 	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
 	statsservice "github.com/hihikaAAa/PRManager/internal/services/statsservice"
 )
 
 type statsGetterMock struct {
 	stats statsservice.Stats
 	err error
+	filter statsservice.Filter
 }
 
-func (m *statsGetterMock) GetStats(ctx context.Context) (statsservice.Stats, error) {
+func (m *statsGetterMock) GetStats(ctx context.Context, f statsservice.Filter) (statsservice.Stats, error) {
+	m.filter = f
 	return m.stats, m.err
 }
 
@@ -70,3 +74,47 @@ func TestStatsHandler_Error(t *testing.T) {
 		t.Fatalf("unexpected body: %s", rr.Body.String())
 	}
 }
+
+func TestStatsHandler_Filter(t *testing.T) {
+	mock := &statsGetterMock{}
+	h := New(newTestLogger(), mock)
+	req := httptest.NewRequest(http.MethodGet, "/stats?from=2025-01-01&to=2025-02-01T12:00:00%2B03:00&team_name=backend", nil)
+	rr := httptest.NewRecorder()
+	h(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	want := statsservice.Filter{
+		From:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC),
+		TeamName: "backend",
+	}
+	if !mock.filter.From.Equal(want.From) || !mock.filter.To.Equal(want.To) || mock.filter.TeamName != want.TeamName {
+		t.Fatalf("expected filter %+v, got %+v", want, mock.filter)
+	}
+}
+
+func TestStatsHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		err        error
+		wantStatus int
+	}{
+		{name: "invalid from", query: "?from=yesterday", wantStatus: http.StatusBadRequest},
+		{name: "invalid to", query: "?to=2025-13-01", wantStatus: http.StatusBadRequest},
+		{name: "empty period", query: "?from=2025-02-01&to=2025-01-01", wantStatus: http.StatusBadRequest},
+		{name: "team not found", query: "?team_name=ghost", err: serviceerrors.ErrTeamNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(newTestLogger(), &statsGetterMock{err: tt.err})
+			rr := httptest.NewRecorder()
+			h(rr, httptest.NewRequest(http.MethodGet, "/stats"+tt.query, nil))
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, rr.Code)
+			}
+		})
+	}
+}
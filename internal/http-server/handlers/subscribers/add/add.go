@@ -0,0 +1,82 @@
+package subscriberhandleradd
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	"github.com/hihikaAAa/PRManager/internal/domain/subscriber"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+)
+
+type SubscriberAdder interface {
+	Add(ctx context.Context, sub subscriber.Subscriber) (subscriber.Subscriber, error)
+}
+
+type addSubscriberRequest struct {
+	URL    string       `json:"url"`
+	Secret string       `json:"secret"`
+	Events []event.Type `json:"events"`
+}
+
+type addSubscriberResponse struct {
+	Subscriber subscriberItem `json:"subscriber"`
+}
+
+type subscriberItem struct {
+	ID        int64        `json:"id"`
+	URL       string       `json:"url"`
+	Events    []event.Type `json:"events"`
+	IsActive  bool         `json:"is_active"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+func New(log *slog.Logger, adder SubscriberAdder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.subscribers.add"
+
+		logger := log.With(slog.String("op", op))
+
+		var req addSubscriberRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid json")
+			return
+		}
+		if req.URL == "" || req.Secret == "" {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "url and secret are required")
+			return
+		}
+
+		sub, err := adder.Add(r.Context(), subscriber.Subscriber{URL: req.URL, Secret: req.Secret, Events: req.Events})
+		if err != nil {
+			switch {
+			case errors.Is(err, subscriber.ErrInvalidSubscriber):
+				httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid url or unknown event type")
+			default:
+				logger.Error("failed to add subscriber", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := addSubscriberResponse{Subscriber: subscriberItem{
+			ID:        sub.ID,
+			URL:       sub.URL,
+			Events:    sub.Events,
+			IsActive:  sub.IsActive,
+			CreatedAt: sub.CreatedAt,
+		}}
+		if resp.Subscriber.Events == nil {
+			resp.Subscriber.Events = []event.Type{}
+		}
+
+		logger.Info("subscriber added", slog.Int64("id", sub.ID))
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, resp)
+	}
+}
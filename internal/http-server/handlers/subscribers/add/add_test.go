@@ -0,0 +1,82 @@
+package subscriberhandleradd
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/subscriber"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+)
+
+type subscriberAdderMock struct {
+	got subscriber.Subscriber
+	err error
+}
+
+func (m *subscriberAdderMock) Add(ctx context.Context, sub subscriber.Subscriber) (subscriber.Subscriber, error) {
+	m.got = sub
+	if m.err != nil {
+		return subscriber.Subscriber{}, m.err
+	}
+	sub.ID = 1
+	sub.IsActive = true
+	return sub, nil
+}
+
+func newTestLogger() *slog.Logger {
+	return slogdiscard.NewDiscardLogger()
+}
+
+func TestAddSubscriber_Success(t *testing.T) {
+	mock := &subscriberAdderMock{}
+	h := New(newTestLogger(), mock)
+
+	body := `{"url":"https://hooks.example.com/pr","secret":"s3cr3t","events":["reviewers.assigned"]}`
+	req := httptest.NewRequest(http.MethodPost, "/subscribers/add", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rr.Code)
+	}
+	if mock.got.Secret != "s3cr3t" || len(mock.got.Events) != 1 {
+		t.Fatalf("unexpected subscriber passed to service: %+v", mock.got)
+	}
+	if strings.Contains(rr.Body.String(), "s3cr3t") {
+		t.Fatalf("secret must not be returned: %s", rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), `"id":1`) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestAddSubscriber_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+	}{
+		{name: "missing secret", body: `{"url":"https://hooks.example.com"}`},
+		{name: "invalid subscriber", body: `{"url":"ftp://x","secret":"s"}`, err: subscriber.ErrInvalidSubscriber},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(newTestLogger(), &subscriberAdderMock{err: tt.err})
+
+			req := httptest.NewRequest(http.MethodPost, "/subscribers/add", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			h(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", rr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package subscriberhandlerdelete
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type SubscriberDeleter interface {
+	Delete(ctx context.Context, id int64) error
+}
+
+type deleteSubscriberRequest struct {
+	ID int64 `json:"id"`
+}
+
+type deleteSubscriberResponse struct {
+	ID int64 `json:"id"`
+}
+
+func New(log *slog.Logger, deleter SubscriberDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.subscribers.delete"
+
+		logger := log.With(slog.String("op", op))
+
+		var req deleteSubscriberRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid json")
+			return
+		}
+		if req.ID <= 0 {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "id is required")
+			return
+		}
+
+		if err := deleter.Delete(r.Context(), req.ID); err != nil {
+			switch {
+			case errors.Is(err, repo_errors.ErrSubscriberNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "subscriber not found")
+			default:
+				logger.Error("failed to delete subscriber", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		logger.Info("subscriber deleted", slog.Int64("id", req.ID))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, deleteSubscriberResponse{ID: req.ID})
+	}
+}
@@ -0,0 +1,48 @@
+package subscriberhandlerdelete
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type subscriberDeleterMock struct {
+	err error
+}
+
+func (m *subscriberDeleterMock) Delete(ctx context.Context, id int64) error {
+	return m.err
+}
+
+func TestDeleteSubscriber(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+	}{
+		{name: "success", body: `{"id":1}`, code: http.StatusOK},
+		{name: "missing id", body: `{}`, code: http.StatusBadRequest},
+		{name: "not found", body: `{"id":9}`, err: repo_errors.ErrSubscriberNotFound, code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(slogdiscard.NewDiscardLogger(), &subscriberDeleterMock{err: tt.err})
+
+			req := httptest.NewRequest(http.MethodPost, "/subscribers/delete", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			h(rr, req)
+
+			if rr.Code != tt.code {
+				t.Fatalf("expected %d, got %d", tt.code, rr.Code)
+			}
+		})
+	}
+}
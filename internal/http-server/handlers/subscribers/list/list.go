@@ -0,0 +1,57 @@
+package subscriberhandlerlist
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	"github.com/hihikaAAa/PRManager/internal/domain/subscriber"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+)
+
+type SubscriberLister interface {
+	List(ctx context.Context) ([]subscriber.Subscriber, error)
+}
+
+type listSubscribersResponse struct {
+	Subscribers []subscriberItem `json:"subscribers"`
+}
+
+type subscriberItem struct {
+	ID        int64        `json:"id"`
+	URL       string       `json:"url"`
+	Events    []event.Type `json:"events"`
+	IsActive  bool         `json:"is_active"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+func New(log *slog.Logger, lister SubscriberLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.subscribers.list"
+
+		logger := log.With(slog.String("op", op))
+
+		subs, err := lister.List(r.Context())
+		if err != nil {
+			logger.Error("failed to list subscribers", slog.Any("err", err))
+			httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			return
+		}
+
+		resp := listSubscribersResponse{Subscribers: make([]subscriberItem, 0, len(subs))}
+		for _, s := range subs {
+			item := subscriberItem{ID: s.ID, URL: s.URL, Events: s.Events, IsActive: s.IsActive, CreatedAt: s.CreatedAt}
+			if item.Events == nil {
+				item.Events = []event.Type{}
+			}
+			resp.Subscribers = append(resp.Subscribers, item)
+		}
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
@@ -0,0 +1,45 @@
+package subscriberhandlerlist
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	"github.com/hihikaAAa/PRManager/internal/domain/subscriber"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+)
+
+type subscriberListerMock struct {
+	subs []subscriber.Subscriber
+}
+
+func (m *subscriberListerMock) List(ctx context.Context) ([]subscriber.Subscriber, error) {
+	return m.subs, nil
+}
+
+func TestListSubscribers(t *testing.T) {
+	mock := &subscriberListerMock{subs: []subscriber.Subscriber{
+		{ID: 1, URL: "https://a.example.com", Secret: "top-secret", IsActive: true},
+		{ID: 2, URL: "https://b.example.com", Secret: "top-secret", IsActive: true, Events: []event.Type{event.TypePRMerged}},
+	}}
+	h := New(slogdiscard.NewDiscardLogger(), mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/subscribers/list", nil)
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if strings.Contains(body, "top-secret") {
+		t.Fatalf("secrets must not be returned: %s", body)
+	}
+	if !strings.Contains(body, `"events":[]`) || !strings.Contains(body, `"events":["pull_request.merged"]`) {
+		t.Fatalf("unexpected body: %s", body)
+	}
+}
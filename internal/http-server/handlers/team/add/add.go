@@ -14,7 +14,7 @@ import (
 )
 
 type TeamAdder interface{
-	AddTeam (ctx context.Context, teamName string, members []*user.User) error
+	AddTeam (ctx context.Context, teamName, parent string, members []*user.User) error
 }
 
 type teamMemberRequest struct{
@@ -25,12 +25,14 @@ type teamMemberRequest struct{
 
 type addTeamRequest struct {
 	TeamName string `json:"team_name"`
+	ParentTeam string `json:"parent_team"`
 	Members []teamMemberRequest `json:"members"`
 }
 
 type addTeamResponse struct {
 	Team struct {
 		TeamName string `json:"team_name"`
+		ParentTeam string `json:"parent_team,omitempty"`
 		Members []teamMemberRequest `json:"members"`
 	} `json:"team"`
 }
@@ -61,11 +63,13 @@ func New(log *slog.Logger, teamAdder TeamAdder) http.HandlerFunc{
 			})
 		}
 		
-		err := teamAdder.AddTeam(r.Context(), req.TeamName, members)
+		err := teamAdder.AddTeam(r.Context(), req.TeamName, req.ParentTeam, members)
 		if err != nil{
 			switch{
 			case errors.Is(err, serviceerrors.ErrTeamExists):
 				httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeTeamExists, "team_name already exists")
+			case errors.Is(err, serviceerrors.ErrParentTeamNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "parent team not found")
 			default:
 				logger.Error("failed to add team", slog.Any("err", err))
 				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
@@ -74,6 +78,7 @@ func New(log *slog.Logger, teamAdder TeamAdder) http.HandlerFunc{
 		}
 		resp := addTeamResponse{}
 		resp.Team.TeamName = req.TeamName
+		resp.Team.ParentTeam = req.ParentTeam
 		resp.Team.Members = req.Members
 
 		logger.Info("team created", slog.String("team_name", req.TeamName))
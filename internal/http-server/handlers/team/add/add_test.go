@@ -16,12 +16,14 @@ import (
 
 type teamAdderMock struct {
 	lastTeamName string
+	lastParent string
 	lastMembers []*user.User
 	err error
 }
 
-func (m *teamAdderMock) AddTeam(ctx context.Context, teamName string, members []*user.User) error {
+func (m *teamAdderMock) AddTeam(ctx context.Context, teamName, parent string, members []*user.User) error {
 	m.lastTeamName = teamName
+	m.lastParent = parent
 	m.lastMembers = members
 	return m.err
 }
@@ -55,6 +57,47 @@ func TestAddTeam_Success(t *testing.T) {
 	if len(mock.lastMembers) != 1 || mock.lastMembers[0].ID != "u1" {
 		t.Fatalf("unexpected members: %#v", mock.lastMembers)
 	}
+	if mock.lastParent != "" {
+		t.Fatalf("expected no parent, got %q", mock.lastParent)
+	}
+}
+
+func TestAddTeam_WithParent(t *testing.T) {
+	log := newTestLogger()
+	mock := &teamAdderMock{}
+	h := New(log, mock)
+
+	body := []byte(`{"team_name":"backend","parent_team":"platform","members":[]}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/add", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", rr.Code)
+	}
+	if mock.lastParent != "platform" {
+		t.Fatalf("expected parent=platform, got %q", mock.lastParent)
+	}
+	if !bytes.Contains(rr.Body.Bytes(), []byte(`"parent_team":"platform"`)) {
+		t.Fatalf("response must contain the parent, got %s", rr.Body.String())
+	}
+}
+
+func TestAddTeam_ParentNotFound(t *testing.T) {
+	log := newTestLogger()
+	mock := &teamAdderMock{err: serviceerrors.ErrParentTeamNotFound}
+	h := New(log, mock)
+
+	body := []byte(`{"team_name":"backend","parent_team":"nope","members":[]}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/add", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
 }
 
 func TestAddTeam_TeamExists(t *testing.T) {
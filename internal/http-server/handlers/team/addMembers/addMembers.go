@@ -0,0 +1,96 @@
+package teamhandleraddmembers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+	"github.com/hihikaAAa/PRManager/internal/services/teamservice"
+)
+
+type MemberAdder interface {
+	AddMembers(ctx context.Context, teamName string, members []*user.User) (teamservice.MembershipResult, error)
+}
+
+type memberRequest struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+}
+
+type addMembersRequest struct {
+	TeamName string          `json:"team_name"`
+	Members  []memberRequest `json:"members"`
+}
+
+type addMembersResponse struct {
+	TeamName        string   `json:"team_name"`
+	Added           []string `json:"added"`
+	ReassignedCount int      `json:"reassigned_count"`
+	RemovedCount    int      `json:"removed_count"`
+	FallbackCount   int      `json:"fallback_count"`
+	UnderstaffedPRs []string `json:"understaffed_prs,omitempty"`
+}
+
+func New(log *slog.Logger, svc MemberAdder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.team.addMembers"
+
+		logger := log.With(slog.String("op", op))
+
+		var req addMembersRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid json")
+			return
+		}
+		if req.TeamName == "" || len(req.Members) == 0 {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "team_name and members are required")
+			return
+		}
+
+		members := make([]*user.User, 0, len(req.Members))
+		for _, m := range req.Members {
+			if m.UserID == "" {
+				httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "user_id is required")
+				return
+			}
+			members = append(members, &user.User{ID: m.UserID, Name: m.Username, IsActive: m.IsActive, TeamName: req.TeamName})
+		}
+
+		res, err := svc.AddMembers(r.Context(), req.TeamName, members)
+		if err != nil {
+			switch {
+			case errors.Is(err, serviceerrors.ErrTeamNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "team not found")
+			default:
+				logger.Error("failed to add team members", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := addMembersResponse{
+			TeamName:        res.TeamName,
+			Added:           res.Added,
+			ReassignedCount: res.ReassignedCount,
+			RemovedCount:    res.RemovedCount,
+			FallbackCount:   res.FallbackCount,
+			UnderstaffedPRs: res.UnderstaffedPRs,
+		}
+
+		logger.Info("team members added",
+			slog.String("team_name", resp.TeamName),
+			slog.Int("added", len(resp.Added)),
+			slog.Int("reassigned", resp.ReassignedCount),
+		)
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
@@ -0,0 +1,81 @@
+package teamhandleraddmembers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+	"github.com/hihikaAAa/PRManager/internal/services/teamservice"
+)
+
+type adderMock struct {
+	result  teamservice.MembershipResult
+	err     error
+	called  bool
+	members []*user.User
+}
+
+func (m *adderMock) AddMembers(ctx context.Context, teamName string, members []*user.User) (teamservice.MembershipResult, error) {
+	m.called = true
+	m.members = members
+	return m.result, m.err
+}
+
+func TestAddMembers_OK(t *testing.T) {
+	mock := &adderMock{result: teamservice.MembershipResult{
+		TeamName: "backend", Added: []string{"u5"},
+		Reassignment: teamservice.Reassignment{ReassignedCount: 1},
+	}}
+	h := New(slogdiscard.NewDiscardLogger(), mock)
+
+	body := []byte(`{"team_name":"backend","members":[{"user_id":"u5","username":"Eve","is_active":true}]}`)
+	rr := httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodPost, "/team/addMembers", bytes.NewReader(body)))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(mock.members) != 1 || mock.members[0].ID != "u5" || mock.members[0].Name != "Eve" || !mock.members[0].IsActive {
+		t.Fatalf("unexpected members: %+v", mock.members)
+	}
+	var resp addMembersResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.TeamName != "backend" || len(resp.Added) != 1 || resp.ReassignedCount != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAddMembers_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+		msg  string
+	}{
+		{"invalid json", `{"team_name":`, nil, http.StatusBadRequest, "invalid json"},
+		{"no members", `{"team_name":"backend","members":[]}`, nil, http.StatusBadRequest, "team_name and members are required"},
+		{"no user id", `{"team_name":"backend","members":[{"username":"Eve"}]}`, nil, http.StatusBadRequest, "user_id is required"},
+		{"unknown team", `{"team_name":"nope","members":[{"user_id":"u5"}]}`, serviceerrors.ErrTeamNotFound, http.StatusNotFound, "team not found"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := New(slogdiscard.NewDiscardLogger(), &adderMock{err: tc.err})
+			rr := httptest.NewRecorder()
+			h(rr, httptest.NewRequest(http.MethodPost, "/team/addMembers", strings.NewReader(tc.body)))
+
+			if rr.Code != tc.code || !strings.Contains(rr.Body.String(), tc.msg) {
+				t.Fatalf("got %d %s, want %d %q", rr.Code, rr.Body.String(), tc.code, tc.msg)
+			}
+		})
+	}
+}
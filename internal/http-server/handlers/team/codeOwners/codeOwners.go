@@ -0,0 +1,64 @@
+package teamhandlercodeowners
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type CodeOwnersGetter interface {
+	GetCodeOwners(ctx context.Context, teamName string) (team.CodeOwners, error)
+}
+
+type ruleItem struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+}
+
+type codeOwnersResponse struct {
+	TeamName string     `json:"team_name"`
+	Rules    []ruleItem `json:"rules"`
+}
+
+func New(log *slog.Logger, getter CodeOwnersGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.team.codeOwners"
+
+		logger := log.With(slog.String("op", op))
+
+		teamName := r.URL.Query().Get("team_name")
+		if teamName == "" {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "team_name is required")
+			return
+		}
+
+		c, err := getter.GetCodeOwners(r.Context(), teamName)
+		if err != nil {
+			switch {
+			case errors.Is(err, serviceerrors.ErrTeamNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "team not found")
+			default:
+				logger.Error("failed to get code owners", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := codeOwnersResponse{TeamName: teamName, Rules: make([]ruleItem, 0, len(c.Rules))}
+		for _, rule := range c.Rules {
+			resp.Rules = append(resp.Rules, ruleItem{Pattern: rule.Pattern, Owners: rule.Owners})
+		}
+
+		logger.Info("code owners fetched", slog.String("team_name", teamName))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
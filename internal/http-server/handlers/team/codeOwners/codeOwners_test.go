@@ -0,0 +1,97 @@
+package teamhandlercodeowners
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type codeOwnersGetterMock struct {
+	owners     team.CodeOwners
+	err        error
+	calledTeam string
+}
+
+func (m *codeOwnersGetterMock) GetCodeOwners(ctx context.Context, teamName string) (team.CodeOwners, error) {
+	m.calledTeam = teamName
+	return m.owners, m.err
+}
+
+func newTestLogger() *slog.Logger {
+	return slogdiscard.NewDiscardLogger()
+}
+
+func TestCodeOwners_Success(t *testing.T) {
+	log := newTestLogger()
+	mock := &codeOwnersGetterMock{owners: team.CodeOwners{TeamName: "backend", Rules: []team.OwnershipRule{
+		{Pattern: "/internal/db/", Owners: []string{"u1", "u2"}},
+	}}}
+	h := New(log, mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/team/codeOwners?team_name=backend", nil)
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if mock.calledTeam != "backend" {
+		t.Fatalf("expected team backend, got %q", mock.calledTeam)
+	}
+	if !strings.Contains(rr.Body.String(), `"rules":[{"pattern":"/internal/db/","owners":["u1","u2"]}]`) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestCodeOwners_Empty(t *testing.T) {
+	log := newTestLogger()
+	mock := &codeOwnersGetterMock{owners: team.CodeOwners{TeamName: "backend"}}
+	h := New(log, mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/team/codeOwners?team_name=backend", nil)
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `"rules":[]`) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestCodeOwners_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		err        error
+		wantStatus int
+	}{
+		{name: "missing team_name", url: "/team/codeOwners", wantStatus: http.StatusBadRequest},
+		{name: "team not found", url: "/team/codeOwners?team_name=x", err: serviceerrors.ErrTeamNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(newTestLogger(), &codeOwnersGetterMock{err: tt.err})
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rr := httptest.NewRecorder()
+
+			h(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, rr.Code)
+			}
+		})
+	}
+}
@@ -27,6 +27,8 @@ type deactivateResponse struct {
 	Deactivated []string `json:"deactivated"`
 	ReassignedCount int `json:"reassigned_count"`
 	RemovedCount int `json:"removed_count"`
+	FallbackCount int `json:"fallback_count"`
+	UnderstaffedPRs []string `json:"understaffed_prs,omitempty"`
 }
 
 func New(log *slog.Logger, svc TeamDeactivator) http.HandlerFunc {
@@ -64,6 +66,8 @@ func New(log *slog.Logger, svc TeamDeactivator) http.HandlerFunc {
 			Deactivated:     res.Deactivated,
 			ReassignedCount: res.ReassignedCount,
 			RemovedCount:    res.RemovedCount,
+			FallbackCount:   res.FallbackCount,
+			UnderstaffedPRs: res.UnderstaffedPRs,
 		}
 
 		logger.Info("team users deactivated and reassigned",
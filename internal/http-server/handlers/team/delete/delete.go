@@ -0,0 +1,96 @@
+package teamhandlerdelete
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+	"github.com/hihikaAAa/PRManager/internal/services/teamservice"
+)
+
+type TeamDeleter interface {
+	DeleteTeam(ctx context.Context, name, target string, force bool) (teamservice.DeleteResult, error)
+}
+
+type deleteRequest struct {
+	TeamName   string `json:"team_name"`
+	TargetTeam string `json:"target_team"`
+	Force      bool   `json:"force"`
+}
+
+type deleteResponse struct {
+	TeamName        string   `json:"team_name"`
+	TargetTeam      string   `json:"target_team,omitempty"`
+	Moved           []string `json:"moved,omitempty"`
+	Deactivated     []string `json:"deactivated,omitempty"`
+	ReassignedCount int      `json:"reassigned_count"`
+	RemovedCount    int      `json:"removed_count"`
+	FallbackCount   int      `json:"fallback_count"`
+	UnderstaffedPRs []string `json:"understaffed_prs,omitempty"`
+}
+
+func New(log *slog.Logger, svc TeamDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.team.delete"
+
+		logger := log.With(slog.String("op", op))
+
+		var req deleteRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid json")
+			return
+		}
+		if req.TeamName == "" {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "team_name is required")
+			return
+		}
+		if req.TargetTeam != "" && req.Force {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "target_team and force are mutually exclusive")
+			return
+		}
+
+		res, err := svc.DeleteTeam(r.Context(), req.TeamName, req.TargetTeam, req.Force)
+		if err != nil {
+			switch {
+			case errors.Is(err, serviceerrors.ErrTeamNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "team not found")
+			case errors.Is(err, serviceerrors.ErrTargetTeamNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "target team not found")
+			case errors.Is(err, serviceerrors.ErrSameTeam):
+				httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "target_team must differ from team_name")
+			case errors.Is(err, serviceerrors.ErrTeamNotEmpty):
+				httpresp.WriteError(w, r, http.StatusConflict, httpresp.CodeTeamNotEmpty, "team has members: set target_team or force")
+			default:
+				logger.Error("failed to delete team", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := deleteResponse{
+			TeamName:        res.TeamName,
+			TargetTeam:      res.TargetTeam,
+			Moved:           res.Moved,
+			Deactivated:     res.Deactivated,
+			ReassignedCount: res.ReassignedCount,
+			RemovedCount:    res.RemovedCount,
+			FallbackCount:   res.FallbackCount,
+			UnderstaffedPRs: res.UnderstaffedPRs,
+		}
+
+		logger.Info("team deleted",
+			slog.String("team_name", resp.TeamName),
+			slog.String("target_team", resp.TargetTeam),
+			slog.Int("moved", len(resp.Moved)),
+			slog.Int("deactivated", len(resp.Deactivated)),
+		)
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
@@ -0,0 +1,84 @@
+package teamhandlerdelete
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+	"github.com/hihikaAAa/PRManager/internal/services/teamservice"
+)
+
+type deleterMock struct {
+	result teamservice.DeleteResult
+	err    error
+	called bool
+	target string
+	force  bool
+}
+
+func (m *deleterMock) DeleteTeam(ctx context.Context, name, target string, force bool) (teamservice.DeleteResult, error) {
+	m.called, m.target, m.force = true, target, force
+	return m.result, m.err
+}
+
+func TestDelete_OK(t *testing.T) {
+	mock := &deleterMock{result: teamservice.DeleteResult{
+		TeamName: "backend", Deactivated: []string{"u1", "u2"},
+		Reassignment: teamservice.Reassignment{ReassignedCount: 3, FallbackCount: 3},
+	}}
+	h := New(slogdiscard.NewDiscardLogger(), mock)
+
+	rr := httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodPost, "/team/delete", strings.NewReader(`{"team_name":"backend","force":true}`)))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !mock.force || mock.target != "" {
+		t.Fatalf("unexpected call: %+v", mock)
+	}
+	var resp deleteResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Deactivated) != 2 || resp.ReassignedCount != 3 || resp.FallbackCount != 3 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestDelete_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+		msg  string
+	}{
+		{"invalid json", `{"team_name":`, nil, http.StatusBadRequest, "invalid json"},
+		{"no team", `{"force":true}`, nil, http.StatusBadRequest, "team_name is required"},
+		{"both modes", `{"team_name":"backend","target_team":"frontend","force":true}`, nil, http.StatusBadRequest, "mutually exclusive"},
+		{"not empty", `{"team_name":"backend"}`, serviceerrors.ErrTeamNotEmpty, http.StatusConflict, "TEAM_NOT_EMPTY"},
+		{"unknown target", `{"team_name":"backend","target_team":"x"}`, serviceerrors.ErrTargetTeamNotFound, http.StatusNotFound, "target team not found"},
+		{"unknown team", `{"team_name":"x","force":true}`, serviceerrors.ErrTeamNotFound, http.StatusNotFound, "team not found"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := &deleterMock{err: tc.err}
+			h := New(slogdiscard.NewDiscardLogger(), mock)
+			rr := httptest.NewRecorder()
+			h(rr, httptest.NewRequest(http.MethodPost, "/team/delete", strings.NewReader(tc.body)))
+
+			if rr.Code != tc.code || !strings.Contains(rr.Body.String(), tc.msg) {
+				t.Fatalf("got %d %s, want %d %q", rr.Code, rr.Body.String(), tc.code, tc.msg)
+			}
+			if tc.err == nil && mock.called {
+				t.Fatal("service must not be called on a bad request")
+			}
+		})
+	}
+}
@@ -10,7 +10,7 @@ import (
 
 	"github.com/hihikaAAa/PRManager/internal/domain/team"
 	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
-	"github.com/hihikaAAa/PRManager/internal/repository/postgres/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
 )
 
 type TeamGetter interface{
@@ -25,6 +25,7 @@ type teamMemberResponse struct {
 
 type getTeamResponse struct {
 	TeamName string `json:"team_name"`
+	ParentTeam string `json:"parent_team,omitempty"`
 	Members []teamMemberResponse `json:"members"`
 }
 
@@ -54,6 +55,7 @@ func New(log *slog.Logger, teamGetter TeamGetter)http.HandlerFunc{
 
 		resp := getTeamResponse{
 			TeamName: t.TeamName,
+			ParentTeam: t.ParentTeam,
 			Members: make([]teamMemberResponse, 0, len(t.Members)),
 		}
 
@@ -12,7 +12,7 @@ import (
 	"github.com/hihikaAAa/PRManager/internal/domain/user"
 	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
 	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
-	"github.com/hihikaAAa/PRManager/internal/repository/postgres/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
 )
 
 type teamGetterMock struct {
@@ -0,0 +1,71 @@
+package teamhandlergetsettings
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type SettingsGetter interface {
+	GetSettings(ctx context.Context, teamName string) (team.Settings, error)
+}
+
+type settingsResponse struct {
+	TeamName          string   `json:"team_name"`
+	ReviewersRequired int      `json:"reviewers_required"`
+	MinReviewers      int      `json:"min_reviewers"`
+	Strategy          string   `json:"strategy"`
+	FallbackTeams     []string `json:"fallback_teams"`
+	RequiredApprovals int      `json:"required_approvals"`
+	SearchParents     bool     `json:"search_parents"`
+	InheritedFrom     string   `json:"inherited_from,omitempty"`
+}
+
+func New(log *slog.Logger, getter SettingsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.team.getSettings"
+
+		logger := log.With(slog.String("op", op))
+
+		teamName := r.URL.Query().Get("team_name")
+		if teamName == "" {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "team_name is required")
+			return
+		}
+
+		s, err := getter.GetSettings(r.Context(), teamName)
+		if err != nil {
+			switch {
+			case errors.Is(err, serviceerrors.ErrTeamNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "team not found")
+			default:
+				logger.Error("failed to get team settings", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := settingsResponse{
+			TeamName:          s.TeamName,
+			ReviewersRequired: s.ReviewersRequired,
+			MinReviewers:      s.MinReviewers,
+			Strategy:          s.Strategy,
+			FallbackTeams:     s.FallbackTeams,
+			RequiredApprovals: s.RequiredApprovals,
+			SearchParents:     s.SearchParents,
+			InheritedFrom:     s.InheritedFrom,
+		}
+
+		logger.Info("team settings fetched", slog.String("team_name", teamName))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
@@ -0,0 +1,104 @@
+package teamhandlergetsettings
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type settingsGetterMock struct {
+	settings   team.Settings
+	err        error
+	calledTeam string
+}
+
+func (m *settingsGetterMock) GetSettings(ctx context.Context, teamName string) (team.Settings, error) {
+	m.calledTeam = teamName
+	return m.settings, m.err
+}
+
+func newTestLogger() *slog.Logger {
+	return slogdiscard.NewDiscardLogger()
+}
+
+func TestGetSettings_Success(t *testing.T) {
+	log := newTestLogger()
+	mock := &settingsGetterMock{settings: team.Settings{TeamName: "security", ReviewersRequired: 3, MinReviewers: 2, Strategy: "least_loaded"}}
+	h := New(log, mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/team/settings?team_name=security", nil)
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if mock.calledTeam != "security" {
+		t.Fatalf("expected team security, got %q", mock.calledTeam)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, `"reviewers_required":3`) || !strings.Contains(body, `"strategy":"least_loaded"`) {
+		t.Fatalf("unexpected body: %s", body)
+	}
+}
+
+func TestGetSettings_TeamNotFound(t *testing.T) {
+	log := newTestLogger()
+	mock := &settingsGetterMock{err: serviceerrors.ErrTeamNotFound}
+	h := New(log, mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/team/settings?team_name=unknown", nil)
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), string(httpresp.CodeNotFound)) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestGetSettings_MissingQuery(t *testing.T) {
+	log := newTestLogger()
+	mock := &settingsGetterMock{}
+	h := New(log, mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/team/settings", nil)
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+}
+
+func TestGetSettings_Inherited(t *testing.T) {
+	log := newTestLogger()
+	mock := &settingsGetterMock{settings: team.Settings{TeamName: "backend", ReviewersRequired: 2, SearchParents: true, InheritedFrom: "platform"}}
+	h := New(log, mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/team/settings?team_name=backend", nil)
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, `"search_parents":true`) || !strings.Contains(body, `"inherited_from":"platform"`) {
+		t.Fatalf("unexpected body: %s", body)
+	}
+}
@@ -0,0 +1,83 @@
+package teamhandlerremovemembers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+	"github.com/hihikaAAa/PRManager/internal/services/teamservice"
+)
+
+type MemberRemover interface {
+	RemoveMembers(ctx context.Context, teamName string, userIDs []string) (teamservice.MembershipResult, error)
+}
+
+type removeMembersRequest struct {
+	TeamName string   `json:"team_name"`
+	UserIDs  []string `json:"user_ids"`
+}
+
+type removeMembersResponse struct {
+	TeamName        string   `json:"team_name"`
+	Removed         []string `json:"removed"`
+	ReassignedCount int      `json:"reassigned_count"`
+	RemovedCount    int      `json:"removed_count"`
+	FallbackCount   int      `json:"fallback_count"`
+	UnderstaffedPRs []string `json:"understaffed_prs,omitempty"`
+}
+
+func New(log *slog.Logger, svc MemberRemover) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.team.removeMembers"
+
+		logger := log.With(slog.String("op", op))
+
+		var req removeMembersRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid json")
+			return
+		}
+		if req.TeamName == "" || len(req.UserIDs) == 0 {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "team_name and user_ids are required")
+			return
+		}
+
+		res, err := svc.RemoveMembers(r.Context(), req.TeamName, req.UserIDs)
+		if err != nil {
+			switch {
+			case errors.Is(err, serviceerrors.ErrTeamNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "team not found")
+			case errors.Is(err, serviceerrors.ErrUserNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "user not found")
+			default:
+				logger.Error("failed to remove team members", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := removeMembersResponse{
+			TeamName:        res.TeamName,
+			Removed:         res.Removed,
+			ReassignedCount: res.ReassignedCount,
+			RemovedCount:    res.RemovedCount,
+			FallbackCount:   res.FallbackCount,
+			UnderstaffedPRs: res.UnderstaffedPRs,
+		}
+
+		logger.Info("team members removed",
+			slog.String("team_name", resp.TeamName),
+			slog.Int("removed_members", len(resp.Removed)),
+			slog.Int("reassigned", resp.ReassignedCount),
+			slog.Int("removed", resp.RemovedCount),
+		)
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
@@ -0,0 +1,73 @@
+package teamhandlerremovemembers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+	"github.com/hihikaAAa/PRManager/internal/services/teamservice"
+)
+
+type removerMock struct {
+	result teamservice.MembershipResult
+	err    error
+	users  []string
+}
+
+func (m *removerMock) RemoveMembers(ctx context.Context, teamName string, userIDs []string) (teamservice.MembershipResult, error) {
+	m.users = userIDs
+	return m.result, m.err
+}
+
+func TestRemoveMembers_OK(t *testing.T) {
+	mock := &removerMock{result: teamservice.MembershipResult{
+		TeamName: "backend", Removed: []string{"u2"},
+		Reassignment: teamservice.Reassignment{RemovedCount: 1, UnderstaffedPRs: []string{"pr-1"}},
+	}}
+	h := New(slogdiscard.NewDiscardLogger(), mock)
+
+	rr := httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodPost, "/team/removeMembers", strings.NewReader(`{"team_name":"backend","user_ids":["u2"]}`)))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp removeMembersResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Removed) != 1 || resp.RemovedCount != 1 || len(resp.UnderstaffedPRs) != 1 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestRemoveMembers_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+		msg  string
+	}{
+		{"invalid json", `{"team_name":`, nil, http.StatusBadRequest, "invalid json"},
+		{"no users", `{"team_name":"backend","user_ids":[]}`, nil, http.StatusBadRequest, "team_name and user_ids are required"},
+		{"unknown team", `{"team_name":"nope","user_ids":["u2"]}`, serviceerrors.ErrTeamNotFound, http.StatusNotFound, "team not found"},
+		{"unknown user", `{"team_name":"backend","user_ids":["x"]}`, serviceerrors.ErrUserNotFound, http.StatusNotFound, "user not found"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := New(slogdiscard.NewDiscardLogger(), &removerMock{err: tc.err})
+			rr := httptest.NewRecorder()
+			h(rr, httptest.NewRequest(http.MethodPost, "/team/removeMembers", strings.NewReader(tc.body)))
+
+			if rr.Code != tc.code || !strings.Contains(rr.Body.String(), tc.msg) {
+				t.Fatalf("got %d %s, want %d %q", rr.Code, rr.Body.String(), tc.code, tc.msg)
+			}
+		})
+	}
+}
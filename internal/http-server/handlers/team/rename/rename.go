@@ -0,0 +1,82 @@
+package teamhandlerrename
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type TeamRenamer interface {
+	RenameTeam(ctx context.Context, oldName, newName string) (*team.Team, error)
+}
+
+type renameRequest struct {
+	TeamName    string `json:"team_name"`
+	NewTeamName string `json:"new_team_name"`
+}
+
+type memberItem struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+}
+
+type renameResponse struct {
+	Team struct {
+		TeamName string       `json:"team_name"`
+		Members  []memberItem `json:"members"`
+	} `json:"team"`
+}
+
+func New(log *slog.Logger, svc TeamRenamer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.team.rename"
+
+		logger := log.With(slog.String("op", op))
+
+		var req renameRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid json")
+			return
+		}
+		if req.TeamName == "" || req.NewTeamName == "" {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "team_name and new_team_name are required")
+			return
+		}
+
+		t, err := svc.RenameTeam(r.Context(), req.TeamName, req.NewTeamName)
+		if err != nil {
+			switch {
+			case errors.Is(err, serviceerrors.ErrTeamNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "team not found")
+			case errors.Is(err, serviceerrors.ErrTeamExists):
+				httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeTeamExists, "new_team_name already exists")
+			case errors.Is(err, serviceerrors.ErrSameTeam):
+				httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "new_team_name must differ from team_name")
+			default:
+				logger.Error("failed to rename team", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := renameResponse{}
+		resp.Team.TeamName = t.TeamName
+		resp.Team.Members = make([]memberItem, 0, len(t.Members))
+		for _, m := range t.Members {
+			resp.Team.Members = append(resp.Team.Members, memberItem{UserID: m.ID, Username: m.Name, IsActive: m.IsActive})
+		}
+
+		logger.Info("team renamed", slog.String("old_team_name", req.TeamName), slog.String("team_name", t.TeamName))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
@@ -0,0 +1,75 @@
+package teamhandlerrename
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type renamerMock struct {
+	team     *team.Team
+	err      error
+	old, new string
+}
+
+func (m *renamerMock) RenameTeam(ctx context.Context, oldName, newName string) (*team.Team, error) {
+	m.old, m.new = oldName, newName
+	return m.team, m.err
+}
+
+func TestRename_OK(t *testing.T) {
+	mock := &renamerMock{team: &team.Team{TeamName: "platform", Members: []*user.User{{ID: "u1", Name: "Alice", IsActive: true}}}}
+	h := New(slogdiscard.NewDiscardLogger(), mock)
+
+	rr := httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodPost, "/team/rename", strings.NewReader(`{"team_name":"backend","new_team_name":"platform"}`)))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if mock.old != "backend" || mock.new != "platform" {
+		t.Fatalf("unexpected call: %+v", mock)
+	}
+	var resp renameResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Team.TeamName != "platform" || len(resp.Team.Members) != 1 || resp.Team.Members[0].UserID != "u1" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestRename_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+		msg  string
+	}{
+		{"invalid json", `{"team_name":`, nil, http.StatusBadRequest, "invalid json"},
+		{"no new name", `{"team_name":"backend"}`, nil, http.StatusBadRequest, "team_name and new_team_name are required"},
+		{"unknown team", `{"team_name":"nope","new_team_name":"x"}`, serviceerrors.ErrTeamNotFound, http.StatusNotFound, "team not found"},
+		{"name taken", `{"team_name":"backend","new_team_name":"frontend"}`, serviceerrors.ErrTeamExists, http.StatusBadRequest, "TEAM_EXISTS"},
+		{"same name", `{"team_name":"backend","new_team_name":"backend"}`, serviceerrors.ErrSameTeam, http.StatusBadRequest, "must differ"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := New(slogdiscard.NewDiscardLogger(), &renamerMock{err: tc.err})
+			rr := httptest.NewRecorder()
+			h(rr, httptest.NewRequest(http.MethodPost, "/team/rename", strings.NewReader(tc.body)))
+
+			if rr.Code != tc.code || !strings.Contains(rr.Body.String(), tc.msg) {
+				t.Fatalf("got %d %s, want %d %q", rr.Code, rr.Body.String(), tc.code, tc.msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package teamhandlersetcodeowners
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type CodeOwnersSetter interface {
+	SetCodeOwners(ctx context.Context, c team.CodeOwners) (team.CodeOwners, error)
+}
+
+type ruleItem struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+}
+
+type setCodeOwnersRequest struct {
+	TeamName string     `json:"team_name"`
+	Rules    []ruleItem `json:"rules"`
+}
+
+type codeOwnersResponse struct {
+	TeamName string     `json:"team_name"`
+	Rules    []ruleItem `json:"rules"`
+}
+
+func New(log *slog.Logger, setter CodeOwnersSetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.team.setCodeOwners"
+
+		logger := log.With(slog.String("op", op))
+
+		var req setCodeOwnersRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid json")
+			return
+		}
+		if req.TeamName == "" {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "team_name is required")
+			return
+		}
+
+		in := team.CodeOwners{TeamName: req.TeamName, Rules: make([]team.OwnershipRule, 0, len(req.Rules))}
+		for _, rule := range req.Rules {
+			in.Rules = append(in.Rules, team.OwnershipRule{Pattern: rule.Pattern, Owners: rule.Owners})
+		}
+
+		c, err := setter.SetCodeOwners(r.Context(), in)
+		if err != nil {
+			switch {
+			case errors.Is(err, serviceerrors.ErrTeamNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "team not found")
+			case errors.Is(err, team.ErrInvalidCodeOwners):
+				httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid pattern or empty owners")
+			case errors.Is(err, serviceerrors.ErrOwnerNotInTeam):
+				httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "code owner is not a team member")
+			default:
+				logger.Error("failed to set code owners", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := codeOwnersResponse{TeamName: c.TeamName, Rules: make([]ruleItem, 0, len(c.Rules))}
+		for _, rule := range c.Rules {
+			resp.Rules = append(resp.Rules, ruleItem{Pattern: rule.Pattern, Owners: rule.Owners})
+		}
+
+		logger.Info("code owners updated", slog.String("team_name", resp.TeamName), slog.Int("rules", len(resp.Rules)))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
@@ -0,0 +1,99 @@
+package teamhandlersetcodeowners
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type codeOwnersSetterMock struct {
+	called *team.CodeOwners
+	err    error
+}
+
+func (m *codeOwnersSetterMock) SetCodeOwners(ctx context.Context, c team.CodeOwners) (team.CodeOwners, error) {
+	m.called = &c
+	return c, m.err
+}
+
+func newTestLogger() *slog.Logger {
+	return slogdiscard.NewDiscardLogger()
+}
+
+func TestSetCodeOwners_Success(t *testing.T) {
+	log := newTestLogger()
+	mock := &codeOwnersSetterMock{}
+	h := New(log, mock)
+
+	body := []byte(`{"team_name":"backend","rules":[{"pattern":"*.sql","owners":["u1"]},{"pattern":"/docs/","owners":["u2","u3"]}]}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/setCodeOwners", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if mock.called == nil || mock.called.TeamName != "backend" || len(mock.called.Rules) != 2 || mock.called.Rules[1].Owners[1] != "u3" {
+		t.Fatalf("unexpected code owners passed to service: %#v", mock.called)
+	}
+	if !strings.Contains(rr.Body.String(), `{"pattern":"*.sql","owners":["u1"]}`) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestSetCodeOwners_MissingTeamName(t *testing.T) {
+	log := newTestLogger()
+	mock := &codeOwnersSetterMock{}
+	h := New(log, mock)
+
+	body := []byte(`{"rules":[]}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/setCodeOwners", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+	if mock.called != nil {
+		t.Fatalf("service must not be called on validation error")
+	}
+}
+
+func TestSetCodeOwners_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "team not found", err: serviceerrors.ErrTeamNotFound, wantStatus: http.StatusNotFound},
+		{name: "invalid rules", err: team.ErrInvalidCodeOwners, wantStatus: http.StatusBadRequest},
+		{name: "owner not in team", err: serviceerrors.ErrOwnerNotInTeam, wantStatus: http.StatusBadRequest},
+		{name: "internal", err: context.DeadlineExceeded, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(newTestLogger(), &codeOwnersSetterMock{err: tt.err})
+
+			body := []byte(`{"team_name":"backend","rules":[{"pattern":"*","owners":["u1"]}]}`)
+			req := httptest.NewRequest(http.MethodPost, "/team/setCodeOwners", bytes.NewReader(body))
+			rr := httptest.NewRecorder()
+
+			h(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, rr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package teamhandlersetparent
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type ParentSetter interface {
+	SetParent(ctx context.Context, teamName, parent string) (*team.Team, error)
+}
+
+type setParentRequest struct {
+	TeamName   string `json:"team_name"`
+	ParentTeam string `json:"parent_team"`
+}
+
+type setParentResponse struct {
+	Team struct {
+		TeamName   string `json:"team_name"`
+		ParentTeam string `json:"parent_team,omitempty"`
+	} `json:"team"`
+}
+
+func New(log *slog.Logger, svc ParentSetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.team.setParent"
+
+		logger := log.With(slog.String("op", op))
+
+		var req setParentRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid json")
+			return
+		}
+		if req.TeamName == "" {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "team_name is required")
+			return
+		}
+
+		t, err := svc.SetParent(r.Context(), req.TeamName, req.ParentTeam)
+		if err != nil {
+			switch {
+			case errors.Is(err, serviceerrors.ErrTeamNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "team not found")
+			case errors.Is(err, serviceerrors.ErrParentTeamNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "parent team not found")
+			case errors.Is(err, team.ErrInvalidParent):
+				httpresp.WriteError(w, r, http.StatusConflict, httpresp.CodeInvalidParent, "parent_team is the team itself or one of its sub-teams")
+			default:
+				logger.Error("failed to set parent team", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := setParentResponse{}
+		resp.Team.TeamName = t.TeamName
+		resp.Team.ParentTeam = t.ParentTeam
+
+		logger.Info("parent team set", slog.String("team_name", t.TeamName), slog.String("parent_team", t.ParentTeam))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
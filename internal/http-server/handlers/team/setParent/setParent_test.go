@@ -0,0 +1,71 @@
+package teamhandlersetparent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type setterMock struct {
+	err          error
+	name, parent string
+}
+
+func (m *setterMock) SetParent(ctx context.Context, teamName, parent string) (*team.Team, error) {
+	m.name, m.parent = teamName, parent
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &team.Team{TeamName: teamName, ParentTeam: parent}, nil
+}
+
+func TestSetParent_OK(t *testing.T) {
+	mock := &setterMock{}
+	h := New(slogdiscard.NewDiscardLogger(), mock)
+
+	rr := httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodPost, "/team/setParent", strings.NewReader(`{"team_name":"backend","parent_team":"platform"}`)))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if mock.name != "backend" || mock.parent != "platform" {
+		t.Fatalf("unexpected call: %+v", mock)
+	}
+	if !strings.Contains(rr.Body.String(), `"parent_team":"platform"`) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestSetParent_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+		msg  string
+	}{
+		{"invalid json", `{"team_name":`, nil, http.StatusBadRequest, "invalid json"},
+		{"no team", `{"parent_team":"platform"}`, nil, http.StatusBadRequest, "team_name is required"},
+		{"unknown team", `{"team_name":"nope"}`, serviceerrors.ErrTeamNotFound, http.StatusNotFound, "team not found"},
+		{"unknown parent", `{"team_name":"backend","parent_team":"nope"}`, serviceerrors.ErrParentTeamNotFound, http.StatusNotFound, "parent team not found"},
+		{"cycle", `{"team_name":"platform","parent_team":"backend"}`, team.ErrInvalidParent, http.StatusConflict, "INVALID_PARENT"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := New(slogdiscard.NewDiscardLogger(), &setterMock{err: tc.err})
+			rr := httptest.NewRecorder()
+			h(rr, httptest.NewRequest(http.MethodPost, "/team/setParent", strings.NewReader(tc.body)))
+
+			if rr.Code != tc.code || !strings.Contains(rr.Body.String(), tc.msg) {
+				t.Fatalf("got %d %s, want %d %q", rr.Code, rr.Body.String(), tc.code, tc.msg)
+			}
+		})
+	}
+}
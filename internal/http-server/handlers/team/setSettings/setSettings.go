@@ -0,0 +1,97 @@
+package teamhandlersetsettings
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type SettingsSetter interface {
+	SetSettings(ctx context.Context, settings team.Settings) (team.Settings, error)
+}
+
+type setSettingsRequest struct {
+	TeamName          string   `json:"team_name"`
+	ReviewersRequired *int     `json:"reviewers_required"`
+	MinReviewers      int      `json:"min_reviewers"`
+	Strategy          string   `json:"strategy"`
+	FallbackTeams     []string `json:"fallback_teams"`
+	RequiredApprovals int      `json:"required_approvals"`
+	SearchParents     bool     `json:"search_parents"`
+}
+
+type settingsResponse struct {
+	TeamName          string   `json:"team_name"`
+	ReviewersRequired int      `json:"reviewers_required"`
+	MinReviewers      int      `json:"min_reviewers"`
+	Strategy          string   `json:"strategy"`
+	FallbackTeams     []string `json:"fallback_teams"`
+	RequiredApprovals int      `json:"required_approvals"`
+	SearchParents     bool     `json:"search_parents"`
+}
+
+func New(log *slog.Logger, setter SettingsSetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.team.setSettings"
+
+		logger := log.With(slog.String("op", op))
+
+		var req setSettingsRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid json")
+			return
+		}
+		if req.TeamName == "" || req.ReviewersRequired == nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "team_name and reviewers_required are required")
+			return
+		}
+
+		s, err := setter.SetSettings(r.Context(), team.Settings{
+			TeamName:          req.TeamName,
+			ReviewersRequired: *req.ReviewersRequired,
+			MinReviewers:      req.MinReviewers,
+			Strategy:          req.Strategy,
+			FallbackTeams:     req.FallbackTeams,
+			RequiredApprovals: req.RequiredApprovals,
+			SearchParents:     req.SearchParents,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, serviceerrors.ErrTeamNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "team not found")
+			case errors.Is(err, team.ErrInvalidSettings):
+				httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid reviewer counts, required_approvals or fallback_teams")
+			case errors.Is(err, serviceerrors.ErrFallbackTeamNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "fallback team not found")
+			case errors.Is(err, serviceerrors.ErrUnknownStrategy):
+				httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "unknown strategy")
+			default:
+				logger.Error("failed to set team settings", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := settingsResponse{
+			TeamName:          s.TeamName,
+			ReviewersRequired: s.ReviewersRequired,
+			MinReviewers:      s.MinReviewers,
+			Strategy:          s.Strategy,
+			FallbackTeams:     s.FallbackTeams,
+			RequiredApprovals: s.RequiredApprovals,
+			SearchParents:     s.SearchParents,
+		}
+
+		logger.Info("team settings updated", slog.String("team_name", resp.TeamName))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
@@ -0,0 +1,200 @@
+package teamhandlersetsettings
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type settingsSetterMock struct {
+	called *team.Settings
+	err    error
+}
+
+func (m *settingsSetterMock) SetSettings(ctx context.Context, settings team.Settings) (team.Settings, error) {
+	m.called = &settings
+	return settings, m.err
+}
+
+func newTestLogger() *slog.Logger {
+	return slogdiscard.NewDiscardLogger()
+}
+
+func TestSetSettings_Success(t *testing.T) {
+	log := newTestLogger()
+	mock := &settingsSetterMock{}
+	h := New(log, mock)
+
+	body := []byte(`{"team_name":"docs","reviewers_required":1,"min_reviewers":1,"strategy":"random"}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/setSettings", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if mock.called == nil || mock.called.TeamName != "docs" || mock.called.ReviewersRequired != 1 || mock.called.MinReviewers != 1 {
+		t.Fatalf("unexpected settings passed to service: %#v", mock.called)
+	}
+	if !strings.Contains(rr.Body.String(), `"reviewers_required":1`) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestSetSettings_MissingReviewersRequired(t *testing.T) {
+	log := newTestLogger()
+	mock := &settingsSetterMock{}
+	h := New(log, mock)
+
+	body := []byte(`{"team_name":"docs"}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/setSettings", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+	if mock.called != nil {
+		t.Fatalf("service must not be called on validation error")
+	}
+}
+
+func TestSetSettings_InvalidSettings(t *testing.T) {
+	log := newTestLogger()
+	mock := &settingsSetterMock{err: team.ErrInvalidSettings}
+	h := New(log, mock)
+
+	body := []byte(`{"team_name":"docs","reviewers_required":1,"min_reviewers":2}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/setSettings", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+}
+
+func TestSetSettings_UnknownStrategy(t *testing.T) {
+	log := newTestLogger()
+	mock := &settingsSetterMock{err: serviceerrors.ErrUnknownStrategy}
+	h := New(log, mock)
+
+	body := []byte(`{"team_name":"docs","reviewers_required":1,"strategy":"round_robin"}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/setSettings", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+}
+
+func TestSetSettings_TeamNotFound(t *testing.T) {
+	log := newTestLogger()
+	mock := &settingsSetterMock{err: serviceerrors.ErrTeamNotFound}
+	h := New(log, mock)
+
+	body := []byte(`{"team_name":"ghost","reviewers_required":2}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/setSettings", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+}
+
+func TestSetSettings_FallbackTeams(t *testing.T) {
+	log := newTestLogger()
+	mock := &settingsSetterMock{}
+	h := New(log, mock)
+
+	body := []byte(`{"team_name":"docs","reviewers_required":1,"fallback_teams":["backend","frontend"]}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/setSettings", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if mock.called == nil || len(mock.called.FallbackTeams) != 2 || mock.called.FallbackTeams[0] != "backend" {
+		t.Fatalf("unexpected settings passed to service: %#v", mock.called)
+	}
+	if !strings.Contains(rr.Body.String(), `"fallback_teams":["backend","frontend"]`) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestSetSettings_FallbackTeamNotFound(t *testing.T) {
+	log := newTestLogger()
+	mock := &settingsSetterMock{err: serviceerrors.ErrFallbackTeamNotFound}
+	h := New(log, mock)
+
+	body := []byte(`{"team_name":"docs","reviewers_required":1,"fallback_teams":["ghost"]}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/setSettings", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+}
+
+func TestSetSettings_RequiredApprovals(t *testing.T) {
+	log := newTestLogger()
+	mock := &settingsSetterMock{}
+	h := New(log, mock)
+
+	body := []byte(`{"team_name":"security","reviewers_required":3,"required_approvals":2}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/setSettings", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if mock.called == nil || mock.called.RequiredApprovals != 2 {
+		t.Fatalf("unexpected settings passed to service: %#v", mock.called)
+	}
+	if !strings.Contains(rr.Body.String(), `"required_approvals":2`) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestSetSettings_SearchParents(t *testing.T) {
+	log := newTestLogger()
+	mock := &settingsSetterMock{}
+	h := New(log, mock)
+
+	body := []byte(`{"team_name":"backend","reviewers_required":2,"search_parents":true}`)
+	req := httptest.NewRequest(http.MethodPost, "/team/setSettings", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if mock.called == nil || !mock.called.SearchParents {
+		t.Fatalf("unexpected settings passed to service: %#v", mock.called)
+	}
+	if !strings.Contains(rr.Body.String(), `"search_parents":true`) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
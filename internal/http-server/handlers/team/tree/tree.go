@@ -0,0 +1,63 @@
+package teamhandlertree
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type TreeGetter interface {
+	Tree(ctx context.Context, root string) ([]*team.Node, error)
+}
+
+type nodeResponse struct {
+	TeamName string         `json:"team_name"`
+	Children []nodeResponse `json:"children"`
+}
+
+type treeResponse struct {
+	Teams []nodeResponse `json:"teams"`
+}
+
+func New(log *slog.Logger, svc TreeGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.team.tree"
+
+		logger := log.With(slog.String("op", op))
+
+		root := r.URL.Query().Get("team_name")
+		nodes, err := svc.Tree(r.Context(), root)
+		if err != nil {
+			switch {
+			case errors.Is(err, serviceerrors.ErrTeamNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "team not found")
+			default:
+				logger.Error("failed to get team tree", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := treeResponse{Teams: toResponse(nodes)}
+
+		logger.Info("team tree fetched", slog.String("team_name", root))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
+
+func toResponse(nodes []*team.Node) []nodeResponse {
+	res := make([]nodeResponse, 0, len(nodes))
+	for _, n := range nodes {
+		res = append(res, nodeResponse{TeamName: n.TeamName, Children: toResponse(n.Children)})
+	}
+	return res
+}
@@ -0,0 +1,73 @@
+package teamhandlertree
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type treeMock struct {
+	nodes []*team.Node
+	err   error
+	root  string
+}
+
+func (m *treeMock) Tree(ctx context.Context, root string) ([]*team.Node, error) {
+	m.root = root
+	return m.nodes, m.err
+}
+
+func TestTree_OK(t *testing.T) {
+	mock := &treeMock{nodes: []*team.Node{
+		{TeamName: "platform", Children: []*team.Node{{TeamName: "backend"}, {TeamName: "frontend"}}},
+		{TeamName: "sales"},
+	}}
+	h := New(slogdiscard.NewDiscardLogger(), mock)
+
+	rr := httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodGet, "/team/tree", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp treeResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Teams) != 2 || len(resp.Teams[0].Children) != 2 || resp.Teams[0].Children[1].TeamName != "frontend" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if !strings.Contains(rr.Body.String(), `{"team_name":"sales","children":[]}`) {
+		t.Fatalf("leaves must have empty children: %s", rr.Body.String())
+	}
+}
+
+func TestTree_Subtree(t *testing.T) {
+	mock := &treeMock{nodes: []*team.Node{{TeamName: "platform"}}}
+	h := New(slogdiscard.NewDiscardLogger(), mock)
+
+	rr := httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodGet, "/team/tree?team_name=platform", nil))
+
+	if rr.Code != http.StatusOK || mock.root != "platform" {
+		t.Fatalf("got %d, root %q", rr.Code, mock.root)
+	}
+}
+
+func TestTree_TeamNotFound(t *testing.T) {
+	h := New(slogdiscard.NewDiscardLogger(), &treeMock{err: serviceerrors.ErrTeamNotFound})
+
+	rr := httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodGet, "/team/tree?team_name=nope", nil))
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,74 @@
+package userhandlerabsences
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type AbsenceLister interface {
+	List(ctx context.Context, userID string) ([]user.Absence, error)
+}
+
+type absenceItem struct {
+	ID        int64      `json:"id"`
+	StartsAt  time.Time  `json:"starts_at"`
+	EndsAt    time.Time  `json:"ends_at"`
+	Reason    string     `json:"reason"`
+	HandledAt *time.Time `json:"handled_at,omitempty"`
+}
+
+type absencesResponse struct {
+	UserID   string        `json:"user_id"`
+	Absences []absenceItem `json:"absences"`
+}
+
+func New(log *slog.Logger, lister AbsenceLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.user.absences"
+
+		logger := log.With(slog.String("op", op))
+
+		userID := r.URL.Query().Get("user_id")
+		if userID == "" {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "user_id is required")
+			return
+		}
+
+		list, err := lister.List(r.Context(), userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, serviceerrors.ErrUserNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "user not found")
+			default:
+				logger.Error("failed to list absences", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := absencesResponse{UserID: userID, Absences: make([]absenceItem, 0, len(list))}
+		for _, a := range list {
+			resp.Absences = append(resp.Absences, absenceItem{
+				ID:        a.ID,
+				StartsAt:  a.StartsAt,
+				EndsAt:    a.EndsAt,
+				Reason:    a.Reason,
+				HandledAt: a.HandledAt,
+			})
+		}
+
+		logger.Info("absences fetched", slog.String("user_id", userID), slog.Int("count", len(resp.Absences)))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
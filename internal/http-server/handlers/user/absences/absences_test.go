@@ -0,0 +1,74 @@
+package userhandlerabsences
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type listerMock struct {
+	list   []user.Absence
+	err    error
+	userID string
+}
+
+func (m *listerMock) List(ctx context.Context, userID string) ([]user.Absence, error) {
+	m.userID = userID
+	return m.list, m.err
+}
+
+func TestAbsences_OK(t *testing.T) {
+	start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	mock := &listerMock{list: []user.Absence{
+		{ID: 1, UserID: "u2", StartsAt: start, EndsAt: start.Add(24 * time.Hour), Reason: "vacation", HandledAt: &start},
+		{ID: 2, UserID: "u2", StartsAt: start.Add(48 * time.Hour), EndsAt: start.Add(72 * time.Hour)},
+	}}
+	h := New(slogdiscard.NewDiscardLogger(), mock)
+
+	rr := httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodGet, "/users/absences?user_id=u2", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if mock.userID != "u2" {
+		t.Fatalf("expected user u2, got %q", mock.userID)
+	}
+	var resp absencesResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Absences) != 2 || resp.Absences[0].HandledAt == nil || resp.Absences[1].HandledAt != nil {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAbsences_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		err  error
+		code int
+	}{
+		{"no user_id", "/users/absences", nil, http.StatusBadRequest},
+		{"unknown user", "/users/absences?user_id=nope", serviceerrors.ErrUserNotFound, http.StatusNotFound},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := New(slogdiscard.NewDiscardLogger(), &listerMock{err: tc.err})
+			rr := httptest.NewRecorder()
+			h(rr, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if rr.Code != tc.code {
+				t.Fatalf("expected %d, got %d: %s", tc.code, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
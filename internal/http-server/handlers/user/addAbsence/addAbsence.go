@@ -0,0 +1,93 @@
+package userhandleraddabsence
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	"github.com/hihikaAAa/PRManager/internal/lib/auth"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type AbsenceAdder interface {
+	Add(ctx context.Context, a user.Absence) (user.Absence, error)
+}
+
+type addAbsenceRequest struct {
+	UserID   string    `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason"`
+}
+
+type absenceItem struct {
+	ID       int64     `json:"id"`
+	UserID   string    `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason"`
+}
+
+type addAbsenceResponse struct {
+	Absence absenceItem `json:"absence"`
+}
+
+func New(log *slog.Logger, adder AbsenceAdder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.user.addAbsence"
+
+		logger := log.With(slog.String("op", op))
+
+		var req addAbsenceRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid json")
+			return
+		}
+		if req.UserID == "" || req.StartsAt.IsZero() || req.EndsAt.IsZero() {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "user_id, starts_at and ends_at are required")
+			return
+		}
+		if id, ok := auth.FromContext(r.Context()); ok && id.Role == auth.RoleMember && id.UserID != req.UserID {
+			httpresp.WriteError(w, r, http.StatusForbidden, httpresp.CodeForbidden, "members can only manage their own absences")
+			return
+		}
+
+		a, err := adder.Add(r.Context(), user.Absence{
+			UserID:   req.UserID,
+			StartsAt: req.StartsAt,
+			EndsAt:   req.EndsAt,
+			Reason:   req.Reason,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, serviceerrors.ErrUserNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "user not found")
+			case errors.Is(err, user.ErrInvalidAbsence):
+				httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "ends_at must be after starts_at")
+			default:
+				logger.Error("failed to add absence", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := addAbsenceResponse{Absence: absenceItem{
+			ID:       a.ID,
+			UserID:   a.UserID,
+			StartsAt: a.StartsAt,
+			EndsAt:   a.EndsAt,
+			Reason:   a.Reason,
+		}}
+
+		logger.Info("absence added", slog.String("user_id", a.UserID), slog.Int64("id", a.ID))
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, resp)
+	}
+}
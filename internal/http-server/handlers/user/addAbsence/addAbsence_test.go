@@ -0,0 +1,107 @@
+package userhandleraddabsence
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/lib/auth"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type adderMock struct {
+	got user.Absence
+	err error
+}
+
+func (m *adderMock) Add(ctx context.Context, a user.Absence) (user.Absence, error) {
+	m.got = a
+	a.ID = 7
+	return a, m.err
+}
+
+func TestAddAbsence_OK(t *testing.T) {
+	mock := &adderMock{}
+	h := New(slogdiscard.NewDiscardLogger(), mock)
+
+	body := `{"user_id":"u2","starts_at":"2026-07-01T00:00:00Z","ends_at":"2026-07-15T00:00:00Z","reason":"vacation"}`
+	rr := httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodPost, "/users/addAbsence", strings.NewReader(body)))
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if mock.got.UserID != "u2" || mock.got.Reason != "vacation" || mock.got.EndsAt.Sub(mock.got.StartsAt).Hours() != 14*24 {
+		t.Fatalf("unexpected absence passed to service: %+v", mock.got)
+	}
+	var resp addAbsenceResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Absence.ID != 7 || resp.Absence.UserID != "u2" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestAddAbsence_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+		msg  string
+	}{
+		{"invalid json", `{"user_id":`, nil, http.StatusBadRequest, "invalid json"},
+		{"bad time", `{"user_id":"u2","starts_at":"tomorrow","ends_at":"2026-07-15T00:00:00Z"}`, nil, http.StatusBadRequest, "invalid json"},
+		{"no end", `{"user_id":"u2","starts_at":"2026-07-01T00:00:00Z"}`, nil, http.StatusBadRequest, "are required"},
+		{"unknown user", `{"user_id":"nope","starts_at":"2026-07-01T00:00:00Z","ends_at":"2026-07-15T00:00:00Z"}`, serviceerrors.ErrUserNotFound, http.StatusNotFound, "user not found"},
+		{"ends before start", `{"user_id":"u2","starts_at":"2026-07-15T00:00:00Z","ends_at":"2026-07-01T00:00:00Z"}`, user.ErrInvalidAbsence, http.StatusBadRequest, "ends_at must be after starts_at"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := New(slogdiscard.NewDiscardLogger(), &adderMock{err: tc.err})
+			rr := httptest.NewRecorder()
+			h(rr, httptest.NewRequest(http.MethodPost, "/users/addAbsence", strings.NewReader(tc.body)))
+
+			if rr.Code != tc.code || !strings.Contains(rr.Body.String(), tc.msg) {
+				t.Fatalf("got %d %s, want %d %q", rr.Code, rr.Body.String(), tc.code, tc.msg)
+			}
+		})
+	}
+}
+
+func TestAddAbsence_Access(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity auth.Identity
+		code     int
+	}{
+		{"member for self", auth.Identity{UserID: "u2", Role: auth.RoleMember}, http.StatusCreated},
+		{"member for other", auth.Identity{UserID: "u3", Role: auth.RoleMember}, http.StatusForbidden},
+		{"team lead for other", auth.Identity{UserID: "u1", Role: auth.RoleTeamLead}, http.StatusCreated},
+		{"admin for other", auth.Identity{UserID: "admin", Role: auth.RoleAdmin}, http.StatusCreated},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := &adderMock{}
+			h := New(slogdiscard.NewDiscardLogger(), mock)
+			body := `{"user_id":"u2","starts_at":"2026-07-01T00:00:00Z","ends_at":"2026-07-15T00:00:00Z"}`
+			req := httptest.NewRequest(http.MethodPost, "/users/addAbsence", strings.NewReader(body))
+			req = req.WithContext(auth.WithIdentity(req.Context(), tc.identity))
+			rr := httptest.NewRecorder()
+			h(rr, req)
+
+			if rr.Code != tc.code {
+				t.Fatalf("expected %d, got %d: %s", tc.code, rr.Code, rr.Body.String())
+			}
+			if called := mock.got.UserID != ""; called != (tc.code == http.StatusCreated) {
+				t.Fatalf("unexpected service call: %v", called)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package userhandlerdeleteabsence
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	"github.com/hihikaAAa/PRManager/internal/lib/auth"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type AbsenceDeleter interface {
+	Get(ctx context.Context, id int64) (user.Absence, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type deleteAbsenceRequest struct {
+	ID int64 `json:"id"`
+}
+
+type deleteAbsenceResponse struct {
+	ID      int64 `json:"id"`
+	Deleted bool  `json:"deleted"`
+}
+
+func New(log *slog.Logger, deleter AbsenceDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.user.deleteAbsence"
+
+		logger := log.With(slog.String("op", op))
+
+		var req deleteAbsenceRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid json")
+			return
+		}
+		if req.ID <= 0 {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "id is required")
+			return
+		}
+
+		writeErr := func(err error) {
+			switch {
+			case errors.Is(err, serviceerrors.ErrAbsenceNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "absence not found")
+			default:
+				logger.Error("failed to delete absence", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+		}
+
+		if id, ok := auth.FromContext(r.Context()); ok && id.Role == auth.RoleMember {
+			a, err := deleter.Get(r.Context(), req.ID)
+			if err != nil {
+				writeErr(err)
+				return
+			}
+			if a.UserID != id.UserID {
+				httpresp.WriteError(w, r, http.StatusForbidden, httpresp.CodeForbidden, "members can only manage their own absences")
+				return
+			}
+		}
+
+		if err := deleter.Delete(r.Context(), req.ID); err != nil {
+			writeErr(err)
+			return
+		}
+
+		logger.Info("absence deleted", slog.Int64("id", req.ID))
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, deleteAbsenceResponse{ID: req.ID, Deleted: true})
+	}
+}
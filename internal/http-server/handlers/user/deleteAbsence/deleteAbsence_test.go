@@ -0,0 +1,90 @@
+package userhandlerdeleteabsence
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/lib/auth"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type deleterMock struct {
+	id  int64
+	err error
+}
+
+// Get reports every absence as belonging to u2.
+func (m *deleterMock) Get(ctx context.Context, id int64) (user.Absence, error) {
+	return user.Absence{ID: id, UserID: "u2"}, m.err
+}
+
+func (m *deleterMock) Delete(ctx context.Context, id int64) error {
+	m.id = id
+	return m.err
+}
+
+func TestDeleteAbsence(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+	}{
+		{"ok", `{"id":3}`, nil, http.StatusOK},
+		{"invalid json", `{"id":`, nil, http.StatusBadRequest},
+		{"no id", `{}`, nil, http.StatusBadRequest},
+		{"unknown", `{"id":9}`, serviceerrors.ErrAbsenceNotFound, http.StatusNotFound},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := &deleterMock{err: tc.err}
+			h := New(slogdiscard.NewDiscardLogger(), mock)
+			rr := httptest.NewRecorder()
+			h(rr, httptest.NewRequest(http.MethodPost, "/users/deleteAbsence", strings.NewReader(tc.body)))
+
+			if rr.Code != tc.code {
+				t.Fatalf("expected %d, got %d: %s", tc.code, rr.Code, rr.Body.String())
+			}
+			if tc.code == http.StatusOK && (mock.id != 3 || !strings.Contains(rr.Body.String(), `"deleted":true`)) {
+				t.Fatalf("unexpected call %d, body %s", mock.id, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestDeleteAbsence_Access(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity auth.Identity
+		err      error
+		code     int
+	}{
+		{"member own", auth.Identity{UserID: "u2", Role: auth.RoleMember}, nil, http.StatusOK},
+		{"member other", auth.Identity{UserID: "u3", Role: auth.RoleMember}, nil, http.StatusForbidden},
+		{"member unknown", auth.Identity{UserID: "u3", Role: auth.RoleMember}, serviceerrors.ErrAbsenceNotFound, http.StatusNotFound},
+		{"team lead other", auth.Identity{UserID: "u1", Role: auth.RoleTeamLead}, nil, http.StatusOK},
+		{"admin other", auth.Identity{UserID: "admin", Role: auth.RoleAdmin}, nil, http.StatusOK},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := &deleterMock{err: tc.err}
+			h := New(slogdiscard.NewDiscardLogger(), mock)
+			req := httptest.NewRequest(http.MethodPost, "/users/deleteAbsence", strings.NewReader(`{"id":3}`))
+			req = req.WithContext(auth.WithIdentity(req.Context(), tc.identity))
+			rr := httptest.NewRecorder()
+			h(rr, req)
+
+			if rr.Code != tc.code {
+				t.Fatalf("expected %d, got %d: %s", tc.code, rr.Code, rr.Body.String())
+			}
+			if called := mock.id != 0; called != (tc.code == http.StatusOK) {
+				t.Fatalf("unexpected service call: %v", called)
+			}
+		})
+	}
+}
@@ -11,7 +11,7 @@ import (
 	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
 	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
 	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
-	"github.com/hihikaAAa/PRManager/internal/repository/postgres/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
 )
 
 type userReviewGetterMock struct {
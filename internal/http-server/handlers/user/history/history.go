@@ -0,0 +1,77 @@
+package userhandlerhistory
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type UserHistoryGetter interface {
+	History(ctx context.Context, userID string) ([]assignment.Change, error)
+}
+
+type userHistoryResponse struct {
+	UserID string      `json:"user_id"`
+	Events []eventItem `json:"events"`
+}
+
+type eventItem struct {
+	ID            int64     `json:"id"`
+	PullRequestID string    `json:"pull_request_id"`
+	OldReviewerID string    `json:"old_reviewer_id,omitempty"`
+	NewReviewerID string    `json:"new_reviewer_id,omitempty"`
+	Reason        string    `json:"reason"`
+	FromFallback  bool      `json:"from_fallback,omitempty"`
+	Actor         string    `json:"actor"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func New(log *slog.Logger, svc UserHistoryGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.user.history"
+
+		logger := log.With(slog.String("op", op))
+
+		userID := r.URL.Query().Get("user_id")
+		if userID == "" {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "user_id is required")
+			return
+		}
+
+		changes, err := svc.History(r.Context(), userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, repo_errors.ErrUserNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "user not found")
+			default:
+				logger.Error("failed to fetch user history", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := userHistoryResponse{UserID: userID, Events: make([]eventItem, 0, len(changes))}
+		for _, c := range changes {
+			resp.Events = append(resp.Events, eventItem{
+				ID:            c.ID,
+				PullRequestID: c.PullRequestID,
+				OldReviewerID: c.OldReviewerID,
+				NewReviewerID: c.NewReviewerID,
+				Reason:        c.Reason,
+				FromFallback:  c.FromFallback,
+				Actor:         c.Actor,
+				CreatedAt:     c.CreatedAt,
+			})
+		}
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
@@ -0,0 +1,69 @@
+package userhandlerhistory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type historyGetterMock struct {
+	changes []assignment.Change
+	err     error
+}
+
+func (m *historyGetterMock) History(ctx context.Context, userID string) ([]assignment.Change, error) {
+	return m.changes, m.err
+}
+
+func TestHistory_Success(t *testing.T) {
+	mock := &historyGetterMock{changes: []assignment.Change{
+		{ID: 7, PullRequestID: "pr-42", OldReviewerID: "u2", NewReviewerID: "u5", Reason: "manual_reassign", Actor: "u1", CreatedAt: time.Now()},
+	}}
+	h := New(slogdiscard.NewDiscardLogger(), mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/history?user_id=u2", nil)
+	rr := httptest.NewRecorder()
+	h(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var resp userHistoryResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.UserID != "u2" || len(resp.Events) != 1 || resp.Events[0].PullRequestID != "pr-42" || resp.Events[0].Actor != "u1" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestHistory_MissingUserID(t *testing.T) {
+	h := New(slogdiscard.NewDiscardLogger(), &historyGetterMock{})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/history", nil)
+	rr := httptest.NewRecorder()
+	h(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+}
+
+func TestHistory_UserNotFound(t *testing.T) {
+	h := New(slogdiscard.NewDiscardLogger(), &historyGetterMock{err: repo_errors.ErrUserNotFound})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/history?user_id=nobody", nil)
+	rr := httptest.NewRecorder()
+	h(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rr.Code)
+	}
+}
@@ -9,7 +9,7 @@ import (
 	"github.com/go-chi/render"
 	"github.com/hihikaAAa/PRManager/internal/domain/user"
 	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
-	"github.com/hihikaAAa/PRManager/internal/repository/postgres/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
 )
 
 type UserSetIsActive interface{
@@ -0,0 +1,69 @@
+package userhandlerlinkidentity
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type IdentityLinker interface {
+	LinkIdentity(ctx context.Context, userID, provider, login string) (*user.User, error)
+}
+
+type linkIdentityRequest struct {
+	UserID   string `json:"user_id"`
+	Provider string `json:"provider"`
+	Login    string `json:"login"`
+}
+
+type linkIdentityResponse struct {
+	UserID   string `json:"user_id"`
+	Provider string `json:"provider"`
+	Login    string `json:"login"`
+}
+
+func New(log *slog.Logger, linker IdentityLinker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.user.linkIdentity"
+
+		logger := log.With(slog.String("op", op))
+
+		var req linkIdentityRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid json")
+			return
+		}
+		if req.UserID == "" || req.Provider == "" || req.Login == "" {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "user_id, provider and login are required")
+			return
+		}
+
+		usr, err := linker.LinkIdentity(r.Context(), req.UserID, req.Provider, req.Login)
+		if err != nil {
+			switch {
+			case errors.Is(err, repo_errors.ErrUserNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "user not found")
+			case errors.Is(err, serviceerrors.ErrUnknownProvider):
+				httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "unknown provider")
+			default:
+				logger.Error("failed to link identity", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := linkIdentityResponse{UserID: usr.ID, Provider: req.Provider, Login: req.Login}
+
+		logger.Info("identity linked", slog.String("userID", resp.UserID), slog.String("provider", resp.Provider))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
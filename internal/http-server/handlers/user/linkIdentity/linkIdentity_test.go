@@ -0,0 +1,75 @@
+package userhandlerlinkidentity
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type identityLinkerMock struct {
+	user *user.User
+	err  error
+}
+
+func (m *identityLinkerMock) LinkIdentity(ctx context.Context, userID, provider, login string) (*user.User, error) {
+	return m.user, m.err
+}
+
+func newTestLogger() *slog.Logger {
+	return slogdiscard.NewDiscardLogger()
+}
+
+func TestLinkIdentity_Success(t *testing.T) {
+	mock := &identityLinkerMock{user: &user.User{ID: "u1", Name: "Alice", TeamName: "backend", IsActive: true}}
+	h := New(newTestLogger(), mock)
+
+	body := []byte(`{"user_id":"u1","provider":"github","login":"alice-gh"}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/linkIdentity", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `"login":"alice-gh"`) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestLinkIdentity_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+	}{
+		{name: "missing login", body: `{"user_id":"u1","provider":"github"}`, code: http.StatusBadRequest},
+		{name: "unknown provider", body: `{"user_id":"u1","provider":"svn","login":"a"}`, err: serviceerrors.ErrUnknownProvider, code: http.StatusBadRequest},
+		{name: "user not found", body: `{"user_id":"u9","provider":"github","login":"a"}`, err: repo_errors.ErrUserNotFound, code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(newTestLogger(), &identityLinkerMock{err: tt.err})
+
+			req := httptest.NewRequest(http.MethodPost, "/users/linkIdentity", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			h(rr, req)
+
+			if rr.Code != tt.code {
+				t.Fatalf("expected %d, got %d", tt.code, rr.Code)
+			}
+		})
+	}
+}
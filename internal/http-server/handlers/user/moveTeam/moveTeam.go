@@ -0,0 +1,95 @@
+package userhandlermoveteam
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+	"github.com/hihikaAAa/PRManager/internal/services/teamservice"
+)
+
+type MemberMover interface {
+	MoveMember(ctx context.Context, userID, teamName string) (teamservice.MoveResult, error)
+}
+
+type moveTeamRequest struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
+
+type moveTeamResponse struct {
+	User            userItem `json:"user"`
+	FromTeam        string   `json:"from_team,omitempty"`
+	ReassignedCount int      `json:"reassigned_count"`
+	RemovedCount    int      `json:"removed_count"`
+	FallbackCount   int      `json:"fallback_count"`
+	UnderstaffedPRs []string `json:"understaffed_prs,omitempty"`
+}
+
+type userItem struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive bool   `json:"is_active"`
+}
+
+func New(log *slog.Logger, svc MemberMover) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.user.moveTeam"
+
+		logger := log.With(slog.String("op", op))
+
+		var req moveTeamRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid json")
+			return
+		}
+		if req.UserID == "" || req.TeamName == "" {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "user_id and team_name are required")
+			return
+		}
+
+		res, err := svc.MoveMember(r.Context(), req.UserID, req.TeamName)
+		if err != nil {
+			switch {
+			case errors.Is(err, serviceerrors.ErrTeamNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "team not found")
+			case errors.Is(err, serviceerrors.ErrUserNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "user not found")
+			default:
+				logger.Error("failed to move user", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := moveTeamResponse{
+			User: userItem{
+				UserID:   res.User.ID,
+				Username: res.User.Name,
+				TeamName: res.User.TeamName,
+				IsActive: res.User.IsActive,
+			},
+			FromTeam:        res.FromTeam,
+			ReassignedCount: res.ReassignedCount,
+			RemovedCount:    res.RemovedCount,
+			FallbackCount:   res.FallbackCount,
+			UnderstaffedPRs: res.UnderstaffedPRs,
+		}
+
+		logger.Info("user moved to another team",
+			slog.String("user_id", resp.User.UserID),
+			slog.String("from_team", resp.FromTeam),
+			slog.String("team_name", resp.User.TeamName),
+			slog.Int("reassigned", resp.ReassignedCount),
+		)
+
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
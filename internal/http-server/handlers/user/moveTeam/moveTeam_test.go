@@ -0,0 +1,78 @@
+package userhandlermoveteam
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+	"github.com/hihikaAAa/PRManager/internal/services/teamservice"
+)
+
+type moverMock struct {
+	result       teamservice.MoveResult
+	err          error
+	userID, team string
+}
+
+func (m *moverMock) MoveMember(ctx context.Context, userID, teamName string) (teamservice.MoveResult, error) {
+	m.userID, m.team = userID, teamName
+	return m.result, m.err
+}
+
+func TestMoveTeam_OK(t *testing.T) {
+	mock := &moverMock{result: teamservice.MoveResult{
+		User:         &user.User{ID: "u2", Name: "Bob", TeamName: "frontend", IsActive: true},
+		FromTeam:     "backend",
+		Reassignment: teamservice.Reassignment{ReassignedCount: 2},
+	}}
+	h := New(slogdiscard.NewDiscardLogger(), mock)
+
+	rr := httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodPost, "/users/moveTeam", strings.NewReader(`{"user_id":"u2","team_name":"frontend"}`)))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if mock.userID != "u2" || mock.team != "frontend" {
+		t.Fatalf("unexpected call: %+v", mock)
+	}
+	var resp moveTeamResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.User.TeamName != "frontend" || resp.FromTeam != "backend" || resp.ReassignedCount != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestMoveTeam_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+		msg  string
+	}{
+		{"invalid json", `{"user_id":`, nil, http.StatusBadRequest, "invalid json"},
+		{"no team", `{"user_id":"u2"}`, nil, http.StatusBadRequest, "user_id and team_name are required"},
+		{"unknown team", `{"user_id":"u2","team_name":"nope"}`, serviceerrors.ErrTeamNotFound, http.StatusNotFound, "team not found"},
+		{"unknown user", `{"user_id":"x","team_name":"frontend"}`, serviceerrors.ErrUserNotFound, http.StatusNotFound, "user not found"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := New(slogdiscard.NewDiscardLogger(), &moverMock{err: tc.err})
+			rr := httptest.NewRecorder()
+			h(rr, httptest.NewRequest(http.MethodPost, "/users/moveTeam", strings.NewReader(tc.body)))
+
+			if rr.Code != tc.code || !strings.Contains(rr.Body.String(), tc.msg) {
+				t.Fatalf("got %d %s, want %d %q", rr.Code, rr.Body.String(), tc.code, tc.msg)
+			}
+		})
+	}
+}
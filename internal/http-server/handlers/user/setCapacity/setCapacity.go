@@ -0,0 +1,76 @@
+package userhandlersetcapacity
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type CapacitySetter interface {
+	SetCapacity(ctx context.Context, userID string, maxOpenReviews int) (*user.User, error)
+}
+
+type setCapacityRequest struct {
+	UserID         string `json:"user_id"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+}
+
+type setCapacityResponse struct {
+	User userCapacityItem `json:"user"`
+}
+
+type userCapacityItem struct {
+	UserID         string `json:"user_id"`
+	Username       string `json:"username"`
+	TeamName       string `json:"team_name"`
+	IsActive       bool   `json:"is_active"`
+	MaxOpenReviews int    `json:"max_open_reviews"`
+}
+
+func New(log *slog.Logger, setter CapacitySetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.user.setCapacity"
+
+		logger := log.With(slog.String("op", op))
+
+		var req setCapacityRequest
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid json")
+			return
+		}
+		if req.UserID == "" || req.MaxOpenReviews == nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "user_id and max_open_reviews are required")
+			return
+		}
+
+		usr, err := setter.SetCapacity(r.Context(), req.UserID, *req.MaxOpenReviews)
+		if err != nil {
+			switch {
+			case errors.Is(err, user.ErrInvalidCapacity):
+				httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "max_open_reviews must not be negative")
+			case errors.Is(err, repo_errors.ErrUserNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "user not found")
+			default:
+				logger.Error("failed to set review capacity", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp := setCapacityResponse{User: userCapacityItem{
+			UserID: usr.ID, Username: usr.Name, TeamName: usr.TeamName,
+			IsActive: usr.IsActive, MaxOpenReviews: usr.MaxOpenReviews,
+		}}
+
+		logger.Info("review capacity updated", slog.String("userID", usr.ID), slog.Int("max_open_reviews", usr.MaxOpenReviews))
+		render.Status(r, http.StatusOK)
+		render.JSON(w, r, resp)
+	}
+}
@@ -0,0 +1,77 @@
+package userhandlersetcapacity
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type capacitySetterMock struct {
+	lastUserID string
+	lastMax    int
+	err        error
+}
+
+func (m *capacitySetterMock) SetCapacity(ctx context.Context, userID string, maxOpenReviews int) (*user.User, error) {
+	m.lastUserID, m.lastMax = userID, maxOpenReviews
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &user.User{ID: userID, Name: "Alice", TeamName: "backend", IsActive: true, MaxOpenReviews: maxOpenReviews}, nil
+}
+
+func TestSetCapacity_Success(t *testing.T) {
+	mock := &capacitySetterMock{}
+	h := New(slogdiscard.NewDiscardLogger(), mock)
+
+	body := []byte(`{"user_id":"u1","max_open_reviews":3}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/setCapacity", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if mock.lastUserID != "u1" || mock.lastMax != 3 {
+		t.Fatalf("unexpected call: %+v", mock)
+	}
+	if !strings.Contains(rr.Body.String(), `"max_open_reviews":3`) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestSetCapacity_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+	}{
+		{name: "bad json", body: `{`, code: http.StatusBadRequest},
+		{name: "no user", body: `{"max_open_reviews":1}`, code: http.StatusBadRequest},
+		{name: "no limit", body: `{"user_id":"u1"}`, code: http.StatusBadRequest},
+		{name: "negative", body: `{"user_id":"u1","max_open_reviews":-1}`, err: user.ErrInvalidCapacity, code: http.StatusBadRequest},
+		{name: "unknown user", body: `{"user_id":"u9","max_open_reviews":1}`, err: repo_errors.ErrUserNotFound, code: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(slogdiscard.NewDiscardLogger(), &capacitySetterMock{err: tt.err})
+			req := httptest.NewRequest(http.MethodPost, "/users/setCapacity", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			h(rr, req)
+
+			if rr.Code != tt.code {
+				t.Fatalf("expected %d, got %d", tt.code, rr.Code)
+			}
+		})
+	}
+}
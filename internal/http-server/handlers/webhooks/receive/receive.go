@@ -0,0 +1,112 @@
+package webhookhandlerreceive
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+	"github.com/hihikaAAa/PRManager/internal/services/webhookservice"
+	"github.com/hihikaAAa/PRManager/internal/webhooks"
+)
+
+// maxBodyBytes matches the largest payload cap among supported providers (GitHub, 25 MB).
+const maxBodyBytes = 25 << 20
+
+const (
+	resultApplied   = "applied"
+	resultDuplicate = "duplicate"
+	resultIgnored   = "ignored"
+)
+
+type EventApplier interface {
+	Apply(ctx context.Context, ev webhooks.Event) (webhookservice.Result, error)
+}
+
+type webhookResponse struct {
+	Provider      string `json:"provider"`
+	Action        string `json:"action,omitempty"`
+	PullRequestID string `json:"pull_request_id,omitempty"`
+	Status        string `json:"status,omitempty"`
+	Result        string `json:"result"`
+}
+
+// New serves deliveries of a single provider; register one route per provider.
+func New(log *slog.Logger, provider webhooks.Provider, applier EventApplier) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "internal.http-server.handlers.webhooks.receive"
+
+		logger := log.With(
+			slog.String("op", op),
+			slog.String("provider", provider.Name()),
+		)
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+		if err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "cannot read body")
+			return
+		}
+
+		if err := provider.Authenticate(r.Header, body); err != nil {
+			logger.Warn("webhook authentication failed", slog.Any("err", err))
+			httpresp.WriteError(w, r, http.StatusUnauthorized, httpresp.CodeUnauthorized, "invalid signature or token")
+			return
+		}
+
+		resp := webhookResponse{Provider: provider.Name(), Result: resultIgnored}
+
+		ev, ok, err := provider.Parse(r.Header, body)
+		if err != nil {
+			httpresp.WriteError(w, r, http.StatusBadRequest, httpresp.CodeNotFound, "invalid payload")
+			return
+		}
+		if !ok {
+			httpresp.WriteOK(w, r, resp)
+			return
+		}
+		resp.Action = string(ev.Action)
+		resp.PullRequestID = ev.PullRequestID
+
+		res, err := applier.Apply(r.Context(), ev)
+		if err != nil {
+			switch {
+			case errors.Is(err, serviceerrors.ErrIdentityNotLinked):
+				httpresp.WriteError(w, r, http.StatusUnprocessableEntity, httpresp.CodeNotFound, "author login is not linked to a user")
+			case errors.Is(err, serviceerrors.ErrUserNotFound), errors.Is(err, repo_errors.ErrUserNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "author not found")
+			case errors.Is(err, repo_errors.ErrPRNotFound):
+				httpresp.WriteError(w, r, http.StatusNotFound, httpresp.CodeNotFound, "pr is not found")
+			case errors.Is(err, serviceerrors.ErrCandidatesAtCapacity):
+				httpresp.WriteError(w, r, http.StatusConflict, httpresp.CodeNoCandidate, "all candidates are at review capacity")
+			case errors.Is(err, serviceerrors.ErrNoCandidates):
+				httpresp.WriteError(w, r, http.StatusConflict, httpresp.CodeNoCandidate, "not enough reviewers available")
+			case errors.Is(err, pullrequest.ErrInvalidTransition):
+				httpresp.WriteError(w, r, http.StatusConflict, httpresp.CodeInvalidTransition, "transition is not allowed in current status")
+			default:
+				logger.Error("failed to apply webhook event", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusInternalServerError, httpresp.CodeNotFound, "internal error")
+			}
+			return
+		}
+
+		resp.Result = resultDuplicate
+		if res.Applied {
+			resp.Result = resultApplied
+		}
+		if res.PullRequest != nil {
+			resp.Status = string(res.PullRequest.Status)
+		}
+
+		logger.Info("webhook event handled",
+			slog.String("action", resp.Action),
+			slog.String("prID", resp.PullRequestID),
+			slog.String("result", resp.Result),
+		)
+		httpresp.WriteOK(w, r, resp)
+	}
+}
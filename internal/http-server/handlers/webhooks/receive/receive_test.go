@@ -0,0 +1,198 @@
+package webhookhandlerreceive
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+	"github.com/hihikaAAa/PRManager/internal/services/webhookservice"
+	"github.com/hihikaAAa/PRManager/internal/webhooks"
+	"github.com/hihikaAAa/PRManager/internal/webhooks/github"
+	"github.com/hihikaAAa/PRManager/internal/webhooks/gitlab"
+)
+
+const testSecret = "s3cr3t"
+
+const openedPayload = `{
+  "action": "opened",
+  "number": 42,
+  "pull_request": {"title": "Add search", "draft": false, "merged": false, "user": {"login": "Octocat"}},
+  "repository": {"full_name": "octo-org/api"}
+}`
+
+type applierMock struct {
+	got    *webhooks.Event
+	result webhookservice.Result
+	err    error
+}
+
+func (m *applierMock) Apply(ctx context.Context, ev webhooks.Event) (webhookservice.Result, error) {
+	m.got = &ev
+	return m.result, m.err
+}
+
+func newTestLogger() *slog.Logger {
+	return slogdiscard.NewDiscardLogger()
+}
+
+const mergeRequestPayload = `{
+  "object_kind": "merge_request",
+  "user": {"username": "Octocat"},
+  "project": {"path_with_namespace": "octo-group/api"},
+  "object_attributes": {"iid": 7, "title": "Add search", "action": "merge", "draft": false}
+}`
+
+func newRequest(event, body, signature string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(body))
+	req.Header.Set(github.HeaderEvent, event)
+	req.Header.Set(github.HeaderSignature, signature)
+	return req
+}
+
+func TestGitHubWebhook_Opened(t *testing.T) {
+	mock := &applierMock{result: webhookservice.Result{
+		Applied:     true,
+		PullRequest: &pullrequest.PullRequest{ID: "octo-org/api#42", Status: pullrequest.StatusOpen},
+	}}
+	h := New(newTestLogger(), github.NewProvider(testSecret), mock)
+
+	rr := httptest.NewRecorder()
+	h(rr, newRequest(github.EventPullRequest, openedPayload, github.Sign([]byte(testSecret), []byte(openedPayload))))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if mock.got == nil || mock.got.Action != webhooks.ActionOpened || mock.got.PullRequestID != "octo-org/api#42" {
+		t.Fatalf("unexpected event: %+v", mock.got)
+	}
+	if !strings.Contains(rr.Body.String(), `"result":"applied"`) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestGitHubWebhook_InvalidSignature(t *testing.T) {
+	mock := &applierMock{}
+	h := New(newTestLogger(), github.NewProvider(testSecret), mock)
+
+	rr := httptest.NewRecorder()
+	h(rr, newRequest(github.EventPullRequest, openedPayload, github.Sign([]byte("other"), []byte(openedPayload))))
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rr.Code)
+	}
+	if mock.got != nil {
+		t.Fatalf("event must not be applied")
+	}
+}
+
+func TestGitHubWebhook_Ping(t *testing.T) {
+	mock := &applierMock{}
+	h := New(newTestLogger(), github.NewProvider(testSecret), mock)
+
+	body := `{"zen":"Keep it logically awesome."}`
+	rr := httptest.NewRecorder()
+	h(rr, newRequest(github.EventPing, body, github.Sign([]byte(testSecret), []byte(body))))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if mock.got != nil {
+		t.Fatalf("ping must not be applied")
+	}
+}
+
+func TestGitHubWebhook_IgnoredAction(t *testing.T) {
+	mock := &applierMock{}
+	h := New(newTestLogger(), github.NewProvider(testSecret), mock)
+
+	body := strings.Replace(openedPayload, `"opened"`, `"labeled"`, 1)
+	rr := httptest.NewRecorder()
+	h(rr, newRequest(github.EventPullRequest, body, github.Sign([]byte(testSecret), []byte(body))))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if mock.got != nil || !strings.Contains(rr.Body.String(), `"result":"ignored"`) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestGitHubWebhook_UnlinkedAuthor(t *testing.T) {
+	mock := &applierMock{err: serviceerrors.ErrIdentityNotLinked}
+	h := New(newTestLogger(), github.NewProvider(testSecret), mock)
+
+	rr := httptest.NewRecorder()
+	h(rr, newRequest(github.EventPullRequest, openedPayload, github.Sign([]byte(testSecret), []byte(openedPayload))))
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), string(httpresp.CodeNotFound)) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func newGitLabRequest(event, body, token string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/gitlab", strings.NewReader(body))
+	req.Header.Set(gitlab.HeaderEvent, event)
+	req.Header.Set(gitlab.HeaderToken, token)
+	return req
+}
+
+func TestGitLabWebhook_Merged(t *testing.T) {
+	mock := &applierMock{result: webhookservice.Result{
+		Applied:     true,
+		PullRequest: &pullrequest.PullRequest{ID: "octo-group/api!7", Status: pullrequest.StatusMerged},
+	}}
+	h := New(newTestLogger(), gitlab.NewProvider(testSecret), mock)
+
+	rr := httptest.NewRecorder()
+	h(rr, newGitLabRequest(gitlab.EventMergeRequest, mergeRequestPayload, testSecret))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if mock.got == nil || mock.got.Action != webhooks.ActionMerged || mock.got.Provider != webhooks.ProviderGitLab {
+		t.Fatalf("unexpected event: %+v", mock.got)
+	}
+	if !strings.Contains(rr.Body.String(), `"status":"MERGED"`) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestGitLabWebhook_InvalidToken(t *testing.T) {
+	mock := &applierMock{}
+	h := New(newTestLogger(), gitlab.NewProvider(testSecret), mock)
+
+	rr := httptest.NewRecorder()
+	h(rr, newGitLabRequest(gitlab.EventMergeRequest, mergeRequestPayload, "wrong"))
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rr.Code)
+	}
+	if mock.got != nil {
+		t.Fatalf("event must not be applied")
+	}
+}
+
+func TestGitLabWebhook_InvalidTransition(t *testing.T) {
+	mock := &applierMock{err: pullrequest.ErrInvalidTransition}
+	h := New(newTestLogger(), gitlab.NewProvider(testSecret), mock)
+
+	rr := httptest.NewRecorder()
+	h(rr, newGitLabRequest(gitlab.EventMergeRequest, mergeRequestPayload, testSecret))
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), string(httpresp.CodeInvalidTransition)) {
+		t.Fatalf("unexpected body: %s", rr.Body.String())
+	}
+}
@@ -0,0 +1,22 @@
+package actor
+
+import (
+	"net/http"
+
+	"github.com/hihikaAAa/PRManager/internal/lib/actor"
+)
+
+// Header carries the id of whoever makes the request. It is recorded in the assignment history.
+const Header = "X-Actor-ID"
+
+func New() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if id := r.Header.Get(Header); id != "" {
+				r = r.WithContext(actor.WithActor(r.Context(), id))
+			}
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
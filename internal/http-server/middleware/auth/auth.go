@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	httpresp "github.com/hihikaAAa/PRManager/internal/lib/api/response"
+	"github.com/hihikaAAa/PRManager/internal/lib/actor"
+	"github.com/hihikaAAa/PRManager/internal/lib/auth"
+)
+
+type Authenticator interface {
+	Authenticate(token string) (auth.Identity, error)
+}
+
+// New rejects requests without a valid "Authorization: Bearer <token>" header.
+// The authenticated user becomes the actor of the request, replacing X-Actor-ID.
+func New(log *slog.Logger, authenticator Authenticator) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(slog.String("component", "middleware/auth"))
+
+		log.Info("auth middleware enabled")
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				httpresp.WriteError(w, r, http.StatusUnauthorized, httpresp.CodeUnauthorized, "missing bearer token")
+				return
+			}
+			id, err := authenticator.Authenticate(token)
+			if err != nil {
+				log.Debug("authentication failed", slog.Any("err", err))
+				httpresp.WriteError(w, r, http.StatusUnauthorized, httpresp.CodeUnauthorized, "invalid token")
+				return
+			}
+
+			ctx := auth.WithIdentity(r.Context(), id)
+			ctx = actor.WithActor(ctx, id.UserID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// RequireRole lets through only identities with one of the roles. It must run after New.
+func RequireRole(roles ...auth.Role) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			id, ok := auth.FromContext(r.Context())
+			if !ok || !id.HasRole(roles...) {
+				httpresp.WriteError(w, r, http.StatusForbidden, httpresp.CodeForbidden, "insufficient role")
+				return
+			}
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	h := r.Header.Get("Authorization")
+	scheme, token, ok := strings.Cut(h, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/lib/actor"
+	"github.com/hihikaAAa/PRManager/internal/lib/auth"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+)
+
+type authenticatorMock struct {
+	ids map[string]auth.Identity
+}
+
+func (m *authenticatorMock) Authenticate(token string) (auth.Identity, error) {
+	id, ok := m.ids[token]
+	if !ok {
+		return auth.Identity{}, auth.ErrInvalidToken
+	}
+	return id, nil
+}
+
+func newHandler() (http.Handler, *string) {
+	var gotActor string
+	mock := &authenticatorMock{ids: map[string]auth.Identity{
+		"admin":  {UserID: "root", Role: auth.RoleAdmin},
+		"lead":   {UserID: "u1", Role: auth.RoleTeamLead},
+		"member": {UserID: "u2", Role: auth.RoleMember},
+	}}
+	final := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotActor = actor.FromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+	h := New(slogdiscard.NewDiscardLogger(), mock)(RequireRole(auth.RoleAdmin, auth.RoleTeamLead)(final))
+	return h, &gotActor
+}
+
+func TestAuth(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+		wantActor  string
+	}{
+		{name: "no header", wantStatus: http.StatusUnauthorized},
+		{name: "not bearer", header: "Basic lead", wantStatus: http.StatusUnauthorized},
+		{name: "unknown token", header: "Bearer nope", wantStatus: http.StatusUnauthorized},
+		{name: "member", header: "Bearer member", wantStatus: http.StatusForbidden},
+		{name: "team lead", header: "Bearer lead", wantStatus: http.StatusOK, wantActor: "u1"},
+		{name: "admin", header: "bearer admin", wantStatus: http.StatusOK, wantActor: "root"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, gotActor := newHandler()
+
+			req := httptest.NewRequest(http.MethodPost, "/team/deactivate", nil)
+			req.Header.Set("X-Actor-ID", "spoofed")
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rr := httptest.NewRecorder()
+
+			h.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, rr.Code)
+			}
+			if *gotActor != tt.wantActor {
+				t.Fatalf("expected actor %q, got %q", tt.wantActor, *gotActor)
+			}
+		})
+	}
+}
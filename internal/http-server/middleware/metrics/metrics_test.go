@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chi "github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/hihikaAAa/PRManager/internal/lib/metrics"
+)
+
+func TestMetrics_LabelsByRoutePattern(t *testing.T) {
+	router := chi.NewRouter()
+	router.Use(New())
+	router.Route("/team", func(r chi.Router) {
+		r.Get("/get", func(w http.ResponseWriter, r *http.Request) {})
+		r.Post("/add", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+		})
+	})
+
+	ok := metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/team/get", "200")
+	conflict := metrics.HTTPRequests.WithLabelValues(http.MethodPost, "/team/add", "409")
+	unmatched := metrics.HTTPRequests.WithLabelValues(http.MethodGet, unmatchedRoute, "404")
+	before := []float64{testutil.ToFloat64(ok), testutil.ToFloat64(conflict), testutil.ToFloat64(unmatched)}
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/team/get?team_name=backend", nil),
+		httptest.NewRequest(http.MethodGet, "/team/get?team_name=frontend", nil),
+		httptest.NewRequest(http.MethodPost, "/team/add", nil),
+		httptest.NewRequest(http.MethodGet, "/no/such/path", nil),
+	} {
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if got := testutil.ToFloat64(ok) - before[0]; got != 2 {
+		t.Fatalf("expected 2 GET /team/get requests, got %v", got)
+	}
+	if got := testutil.ToFloat64(conflict) - before[1]; got != 1 {
+		t.Fatalf("expected 1 POST /team/add 409 request, got %v", got)
+	}
+	if got := testutil.ToFloat64(unmatched) - before[2]; got != 1 {
+		t.Fatalf("expected 1 unmatched request, got %v", got)
+	}
+}
@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chi "github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+)
+
+func newRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return sr
+}
+
+func newRouter() chi.Router {
+	router := chi.NewRouter()
+	router.Use(New())
+	router.Route("/team", func(r chi.Router) {
+		r.Post("/deactivate", func(w http.ResponseWriter, r *http.Request) {
+			_, span := tracing.Start(r.Context(), "internal.services.teamservice.DeactivateAndReassign")
+			span.End()
+		})
+		r.Get("/get", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+	})
+	return router
+}
+
+func TestTracing_SpanPerRequest(t *testing.T) {
+	sr := newRecorder(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/team/deactivate", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	newRouter().ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	service, server := spans[0], spans[1]
+	if server.Name() != "POST /team/deactivate" || server.SpanKind() != trace.SpanKindServer {
+		t.Fatalf("unexpected server span %q (%v)", server.Name(), server.SpanKind())
+	}
+	if got := server.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("trace context was not propagated, trace id %s", got)
+	}
+	if got := server.Parent().SpanID().String(); got != "00f067aa0ba902b7" || !server.Parent().IsRemote() {
+		t.Fatalf("unexpected parent %s", got)
+	}
+	if service.Parent().SpanID() != server.SpanContext().SpanID() {
+		t.Fatal("service span is not a child of the request span")
+	}
+	if server.Status().Code == codes.Error {
+		t.Fatal("successful request marked as an error")
+	}
+}
+
+func TestTracing_ServerErrorStatus(t *testing.T) {
+	sr := newRecorder(t)
+
+	newRouter().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/team/get", nil))
+
+	spans := sr.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Name() != "GET /team/get" || spans[0].Status().Code != codes.Error {
+		t.Fatalf("unexpected span %q with status %v", spans[0].Name(), spans[0].Status())
+	}
+	if spans[0].Parent().IsValid() {
+		t.Fatal("request without traceparent must start a new trace")
+	}
+}
@@ -0,0 +1,20 @@
+package actor
+
+import "context"
+
+// System is the actor of changes made by the service itself (background jobs, automatic assignment).
+const System = "system"
+
+type ctxKey struct{}
+
+func WithActor(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the actor stored in ctx or System.
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(ctxKey{}).(string); ok && id != "" {
+		return id
+	}
+	return System
+}
@@ -16,6 +16,13 @@ const (
 	CodeNotAssigned ErrorCode = "NOT_ASSIGNED"
 	CodeNoCandidate ErrorCode = "NO_CANDIDATE"
 	CodeNotFound ErrorCode = "NOT_FOUND"
+	CodeNotApproved ErrorCode = "NOT_APPROVED"
+	CodeInvalidTransition ErrorCode = "INVALID_TRANSITION"
+	CodeUnauthorized ErrorCode = "UNAUTHORIZED"
+	CodeInvalidReviewers ErrorCode = "INVALID_REVIEWERS"
+	CodeTeamNotEmpty ErrorCode = "TEAM_NOT_EMPTY"
+	CodeInvalidParent ErrorCode = "INVALID_PARENT"
+	CodeForbidden ErrorCode = "FORBIDDEN"
 )
 
 type SuccessResponse struct {
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+type Role string
+
+const (
+	RoleAdmin    Role = "admin"
+	RoleTeamLead Role = "team-lead"
+	RoleMember   Role = "member"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrUnknownRole  = errors.New("unknown role")
+)
+
+func ParseRole(s string) (Role, error) {
+	switch r := Role(s); r {
+	case RoleAdmin, RoleTeamLead, RoleMember:
+		return r, nil
+	}
+	return "", ErrUnknownRole
+}
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	UserID string
+	Role   Role
+}
+
+// HasRole reports whether the identity has one of the roles.
+func (i Identity) HasRole(roles ...Role) bool {
+	for _, r := range roles {
+		if i.Role == r {
+			return true
+		}
+	}
+	return false
+}
+
+type ctxKey struct{}
+
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the identity stored in ctx. ok is false when authentication is disabled.
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(ctxKey{}).(Identity)
+	return id, ok
+}
@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testSecret = []byte("s3cr3t")
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, c jwt.MapClaims) string {
+	t.Helper()
+	s, err := jwt.NewWithClaims(method, c).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{"sub": "u1", "role": "team-lead", "exp": time.Now().Add(time.Hour).Unix()}
+}
+
+func TestAuthenticate_StaticToken(t *testing.T) {
+	a, err := NewAuthenticator(Config{Tokens: []StaticToken{{Token: "ci-token", UserID: "ci", Role: RoleAdmin}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := a.Authenticate("ci-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.UserID != "ci" || id.Role != RoleAdmin {
+		t.Fatalf("unexpected identity: %+v", id)
+	}
+	if _, err := a.Authenticate("other"); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+	if _, err := a.Authenticate(sign(t, jwt.SigningMethodHS256, testSecret, validClaims())); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("JWT must be rejected without keys, got %v", err)
+	}
+}
+
+func TestAuthenticate_HS256(t *testing.T) {
+	a, err := NewAuthenticator(Config{HS256Secret: testSecret, Issuer: "sso"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := validClaims()
+	c["iss"] = "sso"
+	id, err := a.Authenticate(sign(t, jwt.SigningMethodHS256, testSecret, c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.UserID != "u1" || id.Role != RoleTeamLead {
+		t.Fatalf("unexpected identity: %+v", id)
+	}
+
+	tests := []struct {
+		name  string
+		token func() string
+	}{
+		{name: "wrong secret", token: func() string { return sign(t, jwt.SigningMethodHS256, []byte("other"), c) }},
+		{name: "expired", token: func() string {
+			e := validClaims()
+			e["iss"] = "sso"
+			e["exp"] = time.Now().Add(-time.Minute).Unix()
+			return sign(t, jwt.SigningMethodHS256, testSecret, e)
+		}},
+		{name: "no expiry", token: func() string {
+			return sign(t, jwt.SigningMethodHS256, testSecret, jwt.MapClaims{"sub": "u1", "role": "admin", "iss": "sso"})
+		}},
+		{name: "wrong issuer", token: func() string { return sign(t, jwt.SigningMethodHS256, testSecret, validClaims()) }},
+		{name: "unknown role", token: func() string {
+			e := validClaims()
+			e["iss"] = "sso"
+			e["role"] = "root"
+			return sign(t, jwt.SigningMethodHS256, testSecret, e)
+		}},
+		{name: "no subject", token: func() string {
+			e := validClaims()
+			e["iss"] = "sso"
+			delete(e, "sub")
+			return sign(t, jwt.SigningMethodHS256, testSecret, e)
+		}},
+		{name: "garbage", token: func() string { return "a.b.c" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := a.Authenticate(tt.token()); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("expected ErrInvalidToken, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAuthenticate_RS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	a, err := NewAuthenticator(Config{RS256PublicKey: pubPEM, Audience: "pr-manager"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := validClaims()
+	c["aud"] = "pr-manager"
+	c["role"] = "member"
+	id, err := a.Authenticate(sign(t, jwt.SigningMethodRS256, key, c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.UserID != "u1" || id.Role != RoleMember {
+		t.Fatalf("unexpected identity: %+v", id)
+	}
+
+	// A token signed with HS256 using the public key as the secret must not pass.
+	if _, err := a.Authenticate(sign(t, jwt.SigningMethodHS256, pubPEM, c)); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+	delete(c, "aud")
+	if _, err := a.Authenticate(sign(t, jwt.SigningMethodRS256, key, c)); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken for a missing audience, got %v", err)
+	}
+}
+
+func TestNewAuthenticator_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "empty", cfg: Config{}},
+		{name: "unknown role", cfg: Config{Tokens: []StaticToken{{Token: "t", UserID: "u1", Role: "root"}}}},
+		{name: "no user", cfg: Config{Tokens: []StaticToken{{Token: "t", Role: RoleAdmin}}}},
+		{name: "bad key", cfg: Config{RS256PublicKey: []byte("not a key")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAuthenticator(tt.cfg); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
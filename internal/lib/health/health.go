@@ -0,0 +1,57 @@
+// Package health tracks the state of background workers for the readiness probe.
+package health
+
+import (
+	"sync"
+	"time"
+)
+
+// WorkerStatus is a snapshot of a polling loop.
+type WorkerStatus struct {
+	Running   bool       `json:"running"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+}
+
+// WorkerState records the progress of a polling loop. The zero value is a stopped worker.
+type WorkerState struct {
+	mu      sync.Mutex
+	running bool
+	lastRun time.Time
+	lastErr error
+}
+
+func (s *WorkerState) Started() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = true
+}
+
+func (s *WorkerState) Stopped() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = false
+}
+
+// Polled records the outcome of a poll; a nil err clears the previous error.
+func (s *WorkerState) Polled(at time.Time, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastRun = at
+	s.lastErr = err
+}
+
+func (s *WorkerState) Status() WorkerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := WorkerStatus{Running: s.running}
+	if !s.lastRun.IsZero() {
+		at := s.lastRun
+		st.LastRunAt = &at
+	}
+	if s.lastErr != nil {
+		st.LastError = s.lastErr.Error()
+	}
+	return st
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "prmanager"
+
+// Registry holds every metric of the service; it is served by Handler.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, chi route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and chi route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	PRsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "prs_created_total",
+		Help:      "Pull requests created, drafts and imports included.",
+	})
+
+	PRsMerged = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "prs_merged_total",
+		Help:      "Pull requests merged.",
+	})
+
+	Reassignments = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reassignments_total",
+		Help:      "Reviewers replaced on open PRs by reason.",
+	}, []string{"reason"})
+
+	ReviewersRemoved = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reviewers_removed_total",
+		Help:      "Reviewers removed from open PRs because nobody could replace them, by reason.",
+	}, []string{"reason"})
+
+	NoCandidate = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "no_candidate_total",
+		Help:      "Requests failed with NO_CANDIDATE by operation.",
+	}, []string{"operation"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests, HTTPDuration,
+		PRsCreated, PRsMerged, Reassignments, ReviewersRemoved, NoCandidate,
+	)
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// RegisterDB exposes the connection pool stats of db.
+func RegisterDB(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+type OpenReviewsCounter interface {
+	OpenReviewsByTeam(ctx context.Context) (map[string]int, error)
+}
+
+// RegisterOpenReviews exposes the number of open reviews per team, queried on every scrape.
+func RegisterOpenReviews(counter OpenReviewsCounter) error {
+	return Registry.Register(&openReviewsCollector{counter: counter})
+}
+
+var openReviewsDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "open_reviews"),
+	"Reviews of OPEN pull requests by the team of the reviewer.",
+	[]string{"team"}, nil,
+)
+
+// scrapeTimeout bounds the open reviews query of a single scrape.
+const scrapeTimeout = 5 * time.Second
+
+type openReviewsCollector struct {
+	counter OpenReviewsCounter
+}
+
+func (c *openReviewsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- openReviewsDesc
+}
+
+func (c *openReviewsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+
+	counts, err := c.counter.OpenReviewsByTeam(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(openReviewsDesc, err)
+		return
+	}
+	for teamName, n := range counts {
+		ch <- prometheus.MustNewConstMetric(openReviewsDesc, prometheus.GaugeValue, float64(n), teamName)
+	}
+}
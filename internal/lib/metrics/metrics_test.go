@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type openReviewsCounterMock struct {
+	counts map[string]int
+	err    error
+}
+
+func (m *openReviewsCounterMock) OpenReviewsByTeam(ctx context.Context) (map[string]int, error) {
+	return m.counts, m.err
+}
+
+func TestOpenReviewsCollector(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(&openReviewsCollector{counter: &openReviewsCounterMock{counts: map[string]int{"backend": 3, "frontend": 1}}})
+
+	want := `
+# HELP prmanager_open_reviews Reviews of OPEN pull requests by the team of the reviewer.
+# TYPE prmanager_open_reviews gauge
+prmanager_open_reviews{team="backend"} 3
+prmanager_open_reviews{team="frontend"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "prmanager_open_reviews"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpenReviewsCollector_Error(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(&openReviewsCollector{counter: &openReviewsCounterMock{err: errors.New("db is down")}})
+
+	if _, err := reg.Gather(); err == nil || !strings.Contains(err.Error(), "db is down") {
+		t.Fatalf("expected the query error, got %v", err)
+	}
+}
@@ -0,0 +1,411 @@
+// Package migrator applies embedded SQL migrations.
+//
+// Files are named NNN_description.up.sql / NNN_description.down.sql. The applied
+// version is kept in schema_migrations (version, dirty) in the format of
+// golang-migrate, so databases migrated by the migrate/migrate tool keep working.
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrDirty       = errors.New("database is dirty, fix the schema and the schema_migrations row manually")
+	ErrNoVersion   = errors.New("no migrations applied")
+	ErrNoMigration = errors.New("migration file not found")
+)
+
+// Dialect holds the database specific parts of the migrator.
+type Dialect struct {
+	Name string
+	// Lock and Unlock serialize migrators across processes. They run on the
+	// connection used for the whole run and may be nil.
+	Lock   func(ctx context.Context, conn *sql.Conn) error
+	Unlock func(ctx context.Context, conn *sql.Conn) error
+	// HasTable reports whether schema_migrations exists, without creating it.
+	HasTable func(ctx context.Context, conn *sql.Conn) (bool, error)
+}
+
+// lockKey is the postgres advisory lock key shared by all replicas.
+var lockKey = int64(crc32.ChecksumIEEE([]byte("prmanager:schema_migrations")))
+
+// Postgres takes a session advisory lock, so replicas starting at once wait for
+// the first one and then find the schema up to date.
+var Postgres = Dialect{
+	Name: "postgres",
+	Lock: func(ctx context.Context, conn *sql.Conn) error {
+		_, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey)
+		return err
+	},
+	Unlock: func(ctx context.Context, conn *sql.Conn) error {
+		_, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, lockKey)
+		return err
+	},
+	HasTable: func(ctx context.Context, conn *sql.Conn) (bool, error) {
+		var ok bool
+		err := conn.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&ok)
+		return ok, err
+	},
+}
+
+// SQLite needs no lock: the database file has a single writer and every
+// migration runs in its own transaction.
+var SQLite = Dialect{
+	Name: "sqlite",
+	HasTable: func(ctx context.Context, conn *sql.Conn) (bool, error) {
+		var ok bool
+		err := conn.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations')`).Scan(&ok)
+		return ok, err
+	},
+}
+
+// Migration is a pair of up/down files with the same version.
+type Migration struct {
+	Version int
+	Name    string
+	up      string
+	down    string
+}
+
+// Status describes the database schema relative to the known migrations.
+type Status struct {
+	Version int // 0 if nothing is applied
+	Dirty   bool
+	// NotMigrated is set when schema_migrations does not exist: no migrator ever ran.
+	NotMigrated bool
+	Migrations  []Migration
+}
+
+// Pending returns the migrations newer than the applied version.
+func (s Status) Pending() []Migration {
+	var res []Migration
+	for _, m := range s.Migrations {
+		if m.Version > s.Version {
+			res = append(res, m)
+		}
+	}
+	return res
+}
+
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+}
+
+// New reads the migrations from the root of fsys.
+func New(db *sql.DB, fsys fs.FS, dialect Dialect) (*Migrator, error) {
+	const op = "internal.migrator.New"
+
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+func load(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, f := range files {
+		var direction string
+		switch {
+		case strings.HasSuffix(f, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(f, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("%s: expected .up.sql or .down.sql", f)
+		}
+
+		prefix, name, ok := strings.Cut(strings.TrimSuffix(f, "."+direction+".sql"), "_")
+		version, err := strconv.Atoi(prefix)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("%s: expected NNN_name.%s.sql", f, direction)
+		}
+
+		body, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("version %d has two names: %s and %s", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.up = string(body)
+		} else {
+			m.down = string(body)
+		}
+	}
+
+	res := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" {
+			return nil, fmt.Errorf("version %d: %w: up", m.Version, ErrNoMigration)
+		}
+		res = append(res, *m)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+	return res, nil
+}
+
+// Up applies all pending migrations and returns the versions it applied.
+func (m *Migrator) Up(ctx context.Context) ([]int, error) {
+	const op = "internal.migrator.Up"
+
+	var applied []int
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		version, err := currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mg := range m.migrations {
+			if mg.Version <= version {
+				continue
+			}
+			if err := apply(ctx, conn, mg.up, mg.Version); err != nil {
+				return fmt.Errorf("%03d_%s up: %w", mg.Version, mg.Name, err)
+			}
+			applied = append(applied, mg.Version)
+		}
+		return nil
+	})
+	if err != nil {
+		return applied, fmt.Errorf("%s: %w", op, err)
+	}
+	return applied, nil
+}
+
+// Down rolls back the last steps applied migrations and returns the versions it reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]int, error) {
+	const op = "internal.migrator.Down"
+
+	var reverted []int
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		version, err := currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for ; steps > 0; steps-- {
+			if version == 0 {
+				if len(reverted) == 0 {
+					return ErrNoVersion
+				}
+				return nil
+			}
+			i := m.index(version)
+			if i < 0 || m.migrations[i].down == "" {
+				return fmt.Errorf("version %d: %w: down", version, ErrNoMigration)
+			}
+			prev := 0
+			if i > 0 {
+				prev = m.migrations[i-1].Version
+			}
+
+			mg := m.migrations[i]
+			if err := apply(ctx, conn, mg.down, prev); err != nil {
+				return fmt.Errorf("%03d_%s down: %w", mg.Version, mg.Name, err)
+			}
+			reverted = append(reverted, mg.Version)
+			version = prev
+		}
+		return nil
+	})
+	if err != nil {
+		return reverted, fmt.Errorf("%s: %w", op, err)
+	}
+	return reverted, nil
+}
+
+// Status reports the applied version without taking the lock. It only reads,
+// so it is safe for probes and read-only roles.
+func (m *Migrator) Status(ctx context.Context) (Status, error) {
+	const op = "internal.migrator.Status"
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return Status{}, fmt.Errorf("%s, Conn: %w", op, err)
+	}
+	defer conn.Close()
+
+	exists, err := m.dialect.HasTable(ctx, conn)
+	if err != nil {
+		return Status{}, fmt.Errorf("%s, HasTable: %w", op, err)
+	}
+	if !exists {
+		return Status{NotMigrated: true, Migrations: m.migrations}, nil
+	}
+	version, dirty, err := readVersion(ctx, conn)
+	if err != nil {
+		return Status{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return Status{Version: version, Dirty: dirty, Migrations: m.migrations}, nil
+}
+
+// Latest returns the newest known migration version.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+func (m *Migrator) index(version int) int {
+	for i, mg := range m.migrations {
+		if mg.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// locked runs fn on a single connection holding the dialect lock.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("Conn: %w", err)
+	}
+	defer conn.Close()
+
+	if m.dialect.Lock != nil {
+		if err := m.dialect.Lock(ctx, conn); err != nil {
+			return fmt.Errorf("lock: %w", err)
+		}
+		defer func() {
+			// The session lock must not outlive the run even if ctx is already done.
+			_ = m.dialect.Unlock(context.WithoutCancel(ctx), conn)
+		}()
+	}
+
+	if err := createTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func createTable(ctx context.Context, conn *sql.Conn) error {
+	const q = `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`
+
+	if _, err := conn.ExecContext(ctx, q); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+func readVersion(ctx context.Context, conn *sql.Conn) (int, bool, error) {
+	const q = `SELECT version, dirty FROM schema_migrations LIMIT 1`
+
+	var (
+		version int
+		dirty   bool
+	)
+	err := conn.QueryRowContext(ctx, q).Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	return version, dirty, nil
+}
+
+func currentVersion(ctx context.Context, conn *sql.Conn) (int, error) {
+	version, dirty, err := readVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("version %d: %w", version, ErrDirty)
+	}
+	return version, nil
+}
+
+// apply runs a migration body and records the resulting version.
+//
+// Bodies without transaction control run in one transaction together with the
+// version update, so a failed migration leaves no trace. Files that open their own
+// transaction (BEGIN; ... COMMIT;, as written for golang-migrate) run as they are,
+// between marking the version dirty and clean, which is what golang-migrate does.
+func apply(ctx context.Context, conn *sql.Conn, body string, version int) error {
+	if !ownsTransaction(body) {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("BeginTx: %w", err)
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.ExecContext(ctx, body); err != nil {
+			return err
+		}
+		if err := setVersion(ctx, tx, version, false); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	if err := setVersion(ctx, conn, version, true); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, body); err != nil {
+		// Leave the connection usable; the version stays dirty.
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), `ROLLBACK`)
+		return err
+	}
+	return setVersion(ctx, conn, version, false)
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// setVersion replaces the schema_migrations row. A clean version 0 is stored as no row.
+func setVersion(ctx context.Context, db execer, version int, dirty bool) error {
+	if _, err := db.ExecContext(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return fmt.Errorf("clear schema_migrations: %w", err)
+	}
+	if version == 0 && !dirty {
+		return nil
+	}
+	// Both values are generated here, and the two drivers disagree on placeholders.
+	q := fmt.Sprintf(`INSERT INTO schema_migrations (version, dirty) VALUES (%d, %t)`, version, dirty)
+	if _, err := db.ExecContext(ctx, q); err != nil {
+		return fmt.Errorf("write schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// ownsTransaction reports whether the body starts with BEGIN, skipping blank lines,
+// comments and PRAGMA statements: SQLite ignores some pragmas (foreign_keys) inside
+// a transaction, so files that need them set them before their own BEGIN.
+func ownsTransaction(body string) bool {
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "--") {
+			continue
+		}
+		word := strings.TrimRight(strings.ToUpper(strings.Fields(line)[0]), ";")
+		if word == "PRAGMA" {
+			continue
+		}
+		return word == "BEGIN"
+	}
+	return false
+}
@@ -0,0 +1,284 @@
+package migrator_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/hihikaAAa/PRManager/internal/migrator"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"001_teams.up.sql":   {Data: []byte(`CREATE TABLE teams (name TEXT PRIMARY KEY);`)},
+		"001_teams.down.sql": {Data: []byte(`DROP TABLE teams;`)},
+		"002_users.up.sql":   {Data: []byte(`CREATE TABLE users (id TEXT PRIMARY KEY); CREATE INDEX idx_users ON users(id);`)},
+		"002_users.down.sql": {Data: []byte(`DROP TABLE users;`)},
+	}
+}
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", "file::memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newMigrator(t *testing.T, db *sql.DB, fsys fstest.MapFS) *migrator.Migrator {
+	t.Helper()
+
+	m, err := migrator.New(db, fsys, migrator.SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, name).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n > 0
+}
+
+func TestUpAppliesPendingOnce(t *testing.T) {
+	db := openDB(t)
+	m := newMigrator(t, db, testFS())
+	ctx := context.Background()
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(applied, []int{1, 2}) {
+		t.Fatalf("applied = %v", applied)
+	}
+	if !tableExists(t, db, "teams") || !tableExists(t, db, "users") {
+		t.Fatal("tables were not created")
+	}
+
+	applied, err = m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 0 {
+		t.Fatalf("second run applied %v", applied)
+	}
+
+	st, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Version != 2 || st.Dirty || len(st.Pending()) != 0 {
+		t.Fatalf("status = %+v", st)
+	}
+}
+
+func TestStatusIsReadOnly(t *testing.T) {
+	db := openDB(t)
+	m := newMigrator(t, db, testFS())
+	ctx := context.Background()
+
+	st, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !st.NotMigrated || st.Version != 0 || len(st.Pending()) != 2 {
+		t.Fatalf("status = %+v", st)
+	}
+	if tableExists(t, db, "schema_migrations") {
+		t.Fatal("Status created schema_migrations")
+	}
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	st, err = m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.NotMigrated || st.Version != 2 {
+		t.Fatalf("status = %+v", st)
+	}
+}
+
+func TestUpAppliesNewMigrations(t *testing.T) {
+	db := openDB(t)
+	fsys := testFS()
+	delete(fsys, "002_users.up.sql")
+	delete(fsys, "002_users.down.sql")
+	ctx := context.Background()
+
+	if _, err := newMigrator(t, db, fsys).Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newMigrator(t, db, testFS())
+	st, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Version != 1 || len(st.Pending()) != 1 || st.Pending()[0].Version != 2 {
+		t.Fatalf("status = %+v", st)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(applied, []int{2}) {
+		t.Fatalf("applied = %v", applied)
+	}
+}
+
+func TestFailedMigrationIsRolledBack(t *testing.T) {
+	db := openDB(t)
+	fsys := testFS()
+	fsys["003_broken.up.sql"] = &fstest.MapFile{Data: []byte(`CREATE TABLE broken (id TEXT); INSERT INTO missing VALUES (1);`)}
+	m := newMigrator(t, db, fsys)
+	ctx := context.Background()
+
+	applied, err := m.Up(ctx)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !reflect.DeepEqual(applied, []int{1, 2}) {
+		t.Fatalf("applied = %v", applied)
+	}
+	if tableExists(t, db, "broken") {
+		t.Fatal("failed migration left a table")
+	}
+
+	st, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Version != 2 || st.Dirty {
+		t.Fatalf("status = %+v", st)
+	}
+}
+
+func TestSelfManagedTransactions(t *testing.T) {
+	db := openDB(t)
+	fsys := testFS()
+	fsys["003_tags.up.sql"] = &fstest.MapFile{Data: []byte("-- golang-migrate style\nBEGIN;\nCREATE TABLE tags (id TEXT);\nCOMMIT;\n")}
+	fsys["004_pragma.up.sql"] = &fstest.MapFile{Data: []byte("PRAGMA foreign_keys = OFF;\nBEGIN;\nCREATE TABLE labels (id TEXT);\nCOMMIT;\nPRAGMA foreign_keys = ON;\n")}
+	fsys["005_broken.up.sql"] = &fstest.MapFile{Data: []byte("BEGIN;\nCREATE TABLE broken (id TEXT);\nINSERT INTO missing VALUES (1);\nCOMMIT;\n")}
+	m := newMigrator(t, db, fsys)
+	ctx := context.Background()
+
+	applied, err := m.Up(ctx)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !reflect.DeepEqual(applied, []int{1, 2, 3, 4}) || !tableExists(t, db, "tags") || !tableExists(t, db, "labels") {
+		t.Fatalf("applied = %v", applied)
+	}
+	if tableExists(t, db, "broken") {
+		t.Fatal("failed migration was committed")
+	}
+
+	// Like golang-migrate, a failed file that controls its own transaction leaves the version dirty.
+	st, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Version != 5 || !st.Dirty {
+		t.Fatalf("status = %+v", st)
+	}
+	if _, err := m.Up(ctx); !errors.Is(err, migrator.ErrDirty) {
+		t.Fatalf("err = %v, want ErrDirty", err)
+	}
+}
+
+func TestDown(t *testing.T) {
+	db := openDB(t)
+	m := newMigrator(t, db, testFS())
+	ctx := context.Background()
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	reverted, err := m.Down(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reverted, []int{2}) || tableExists(t, db, "users") || !tableExists(t, db, "teams") {
+		t.Fatalf("reverted = %v", reverted)
+	}
+
+	reverted, err = m.Down(ctx, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reverted, []int{1}) || tableExists(t, db, "teams") {
+		t.Fatalf("reverted = %v", reverted)
+	}
+
+	if _, err := m.Down(ctx, 1); !errors.Is(err, migrator.ErrNoVersion) {
+		t.Fatalf("err = %v, want ErrNoVersion", err)
+	}
+
+	st, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Version != 0 || len(st.Pending()) != 2 {
+		t.Fatalf("status = %+v", st)
+	}
+}
+
+func TestDirtyDatabaseIsRefused(t *testing.T) {
+	db := openDB(t)
+	m := newMigrator(t, db, testFS())
+	ctx := context.Background()
+
+	// The state a failed golang-migrate run leaves behind.
+	if _, err := db.Exec(`CREATE TABLE schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO schema_migrations VALUES (1, TRUE)`); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Up(ctx); !errors.Is(err, migrator.ErrDirty) {
+		t.Fatalf("err = %v, want ErrDirty", err)
+	}
+	if tableExists(t, db, "users") {
+		t.Fatal("migration applied to a dirty database")
+	}
+}
+
+func TestNewRejectsBadFiles(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"no version":   {"teams.up.sql": {Data: []byte(`SELECT 1;`)}},
+		"no direction": {"001_teams.sql": {Data: []byte(`SELECT 1;`)}},
+		"down only":    {"001_teams.down.sql": {Data: []byte(`SELECT 1;`)}},
+		"two names": {
+			"001_teams.up.sql": {Data: []byte(`SELECT 1;`)},
+			"001_users.up.sql": {Data: []byte(`SELECT 1;`)},
+		},
+	}
+
+	for name, fsys := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := migrator.New(openDB(t), fsys, migrator.SQLite); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
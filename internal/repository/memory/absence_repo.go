@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type AbsenceRepository struct {
+	s *Storage
+}
+
+func NewAbsenceRepository(s *Storage) *AbsenceRepository {
+	return &AbsenceRepository{s: s}
+}
+
+func (r *AbsenceRepository) Create(ctx context.Context, a user.Absence) (user.Absence, error) {
+	const op = "internal.repository.memory.absence_repo.Create"
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[a.UserID]; !ok {
+		return user.Absence{}, fmt.Errorf("%s: %w", op, repo_errors.ErrUserNotFound)
+	}
+	r.s.absenceSeq++
+	a.ID = r.s.absenceSeq
+	a.HandledAt, a.Attempts, a.NextAttemptAt = nil, 0, nil
+	r.s.absences[a.ID] = a
+	return a, nil
+}
+
+func (r *AbsenceRepository) Get(ctx context.Context, id int64) (user.Absence, error) {
+	const op = "internal.repository.memory.absence_repo.Get"
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	a, ok := r.s.absences[id]
+	if !ok {
+		return user.Absence{}, fmt.Errorf("%s: %w", op, repo_errors.ErrAbsenceNotFound)
+	}
+	a.HandledAt, a.NextAttemptAt = copyTime(a.HandledAt), copyTime(a.NextAttemptAt)
+	return a, nil
+}
+
+// ListByUser returns the user's absences ordered by start.
+func (r *AbsenceRepository) ListByUser(ctx context.Context, userID string) ([]user.Absence, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	res := make([]user.Absence, 0)
+	for _, a := range r.s.absences {
+		if a.UserID == userID {
+			a.HandledAt, a.NextAttemptAt = copyTime(a.HandledAt), copyTime(a.NextAttemptAt)
+			res = append(res, a)
+		}
+	}
+	sortAbsences(res)
+	return res, nil
+}
+
+func (r *AbsenceRepository) Delete(ctx context.Context, id int64) error {
+	const op = "internal.repository.memory.absence_repo.Delete"
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.absences[id]; !ok {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrAbsenceNotFound)
+	}
+	delete(r.s.absences, id)
+	return nil
+}
+
+func (r *AbsenceRepository) ListStarted(ctx context.Context, now time.Time, limit int) ([]user.Absence, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var res []user.Absence
+	for _, a := range r.s.absences {
+		if a.HandledAt == nil && a.ActiveAt(now) && (a.NextAttemptAt == nil || !a.NextAttemptAt.After(now)) {
+			a.NextAttemptAt = copyTime(a.NextAttemptAt)
+			res = append(res, a)
+		}
+	}
+	sortAbsences(res)
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+func (r *AbsenceRepository) MarkHandled(ctx context.Context, id int64, now time.Time) error {
+	const op = "internal.repository.memory.absence_repo.MarkHandled"
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	a, ok := r.s.absences[id]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrAbsenceNotFound)
+	}
+	a.HandledAt = &now
+	r.s.absences[id] = a
+	return nil
+}
+
+func (r *AbsenceRepository) MarkFailed(ctx context.Context, id int64, next time.Time) error {
+	const op = "internal.repository.memory.absence_repo.MarkFailed"
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	a, ok := r.s.absences[id]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrAbsenceNotFound)
+	}
+	a.Attempts++
+	a.NextAttemptAt = &next
+	r.s.absences[id] = a
+	return nil
+}
+
+func sortAbsences(res []user.Absence) {
+	sort.Slice(res, func(i, j int) bool {
+		if !res[i].StartsAt.Equal(res[j].StartsAt) {
+			return res[i].StartsAt.Before(res[j].StartsAt)
+		}
+		return res[i].ID < res[j].ID
+	})
+}
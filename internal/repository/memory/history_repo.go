@@ -0,0 +1,28 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
+)
+
+func (r *PRRepository) HistoryByPR(ctx context.Context, prID string) ([]assignment.Change, error) {
+	return r.history(func(c assignment.Change) bool { return c.PullRequestID == prID }), nil
+}
+
+func (r *PRRepository) HistoryByUser(ctx context.Context, userID string) ([]assignment.Change, error) {
+	return r.history(func(c assignment.Change) bool { return c.OldReviewerID == userID || c.NewReviewerID == userID }), nil
+}
+
+func (r *PRRepository) history(match func(assignment.Change) bool) []assignment.Change {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	res := []assignment.Change{}
+	for _, c := range r.s.history {
+		if match(c) {
+			res = append(res, c)
+		}
+	}
+	return res
+}
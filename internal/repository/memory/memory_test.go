@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/repository"
+	"github.com/hihikaAAa/PRManager/internal/repository/repotest"
+)
+
+func TestContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repository.Repositories {
+		return NewStorage().Repositories()
+	})
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	repos := NewStorage().Repositories()
+
+	if err := repos.Teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	users := []*user.User{{ID: "author", IsActive: true}}
+	for i := 0; i < 10; i++ {
+		users = append(users, &user.User{ID: fmt.Sprintf("u%d", i), IsActive: true})
+	}
+	if err := repos.Users.UpsertManyForTeam(ctx, "backend", users); err != nil {
+		t.Fatal(err)
+	}
+
+	const workers = 20
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			id := fmt.Sprintf("pr-%d", w)
+			ev := event.New(ctx, event.TypeReviewersAssigned, event.ReviewersAssigned{
+				PullRequestID: id, Reviewers: []string{"u0"}, Reason: event.ReasonAutoCreate,
+			})
+			pr := pullrequest.PullRequest{ID: id, AuthorID: "author", Status: pullrequest.StatusOpen, CreatedAt: time.Now(), Reviewers: []string{"u0"}}
+			if err := repos.PRs.CreateWithReviewers(ctx, pr, ev); err != nil {
+				t.Error(err)
+				return
+			}
+			if err := repos.PRs.ReplaceReviewers(ctx, id, "u0", fmt.Sprintf("u%d", w%9+1)); err != nil {
+				t.Error(err)
+			}
+			if _, err := repos.PRs.CountOpenReviews(ctx, []string{"u0"}); err != nil {
+				t.Error(err)
+			}
+			if _, err := repos.Outbox.ClaimPending(ctx, 5, time.Minute); err != nil {
+				t.Error(err)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	loads, err := repos.PRs.CountOpenReviews(ctx, []string{"u0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loads["u0"] != 0 {
+		t.Fatalf("expected u0 to be replaced everywhere, got %d open reviews", loads["u0"])
+	}
+	stats, err := repos.PRs.GetStats(ctx, repository.StatsFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.OpenPR != workers {
+		t.Fatalf("expected %d open PRs, got %d", workers, stats.OpenPR)
+	}
+}
+
+func TestReturnedValuesAreCopies(t *testing.T) {
+	ctx := context.Background()
+	repos := NewStorage().Repositories()
+
+	if err := repos.Teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Users.UpsertManyForTeam(ctx, "backend", []*user.User{{ID: "u1", IsActive: true}, {ID: "u2", IsActive: true}}); err != nil {
+		t.Fatal(err)
+	}
+	pr := pullrequest.PullRequest{ID: "pr-1", AuthorID: "u1", Status: pullrequest.StatusOpen, CreatedAt: time.Now(), Reviewers: []string{"u2"}}
+	if err := repos.PRs.CreateWithReviewers(ctx, pr); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repos.PRs.GetWithReviewers(ctx, "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Reviewers[0] = "mutated"
+	got.Reviews[0].State = pullrequest.ReviewApproved
+	got.Status = pullrequest.StatusMerged
+
+	again, err := repos.PRs.GetWithReviewers(ctx, "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Reviewers[0] != "u2" || again.Reviews[0].State != pullrequest.ReviewPending || again.Status != pullrequest.StatusOpen {
+		t.Fatalf("stored PR was changed through a returned value: %+v", again)
+	}
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+)
+
+type OutboxRepository struct {
+	s *Storage
+}
+
+func NewOutboxRepository(s *Storage) *OutboxRepository {
+	return &OutboxRepository{s: s}
+}
+
+// ClaimPending locks up to limit unpublished events for the lease duration.
+// Events whose lease expired are claimed again. Data is the raw JSON payload, as in postgres.
+func (r *OutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]event.Event, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := r.s.now()
+	var events []event.Event
+	for _, row := range r.s.outbox {
+		if len(events) >= limit {
+			break
+		}
+		if row.published || row.dead || row.lockedUntil.After(now) {
+			continue
+		}
+		row.lockedUntil = now.Add(lease)
+
+		ev := row.ev
+		ev.Data = json.RawMessage(append([]byte(nil), row.payload...))
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+func (r *OutboxRepository) MarkPublished(ctx context.Context, eventIDs []string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ids := make(map[string]struct{}, len(eventIDs))
+	for _, id := range eventIDs {
+		ids[id] = struct{}{}
+	}
+	for _, row := range r.s.outbox {
+		if _, ok := ids[row.ev.ID]; ok {
+			row.published = true
+			row.publishedAt = r.s.now()
+			row.lockedUntil = time.Time{}
+		}
+	}
+	return nil
+}
+
+// MarkFailed records the failure and keeps the lease, so the event is retried after it expires.
+// The failure that reaches maxAttempts dead-letters the event instead.
+func (r *OutboxRepository) MarkFailed(ctx context.Context, eventID string, cause error, maxAttempts int) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, row := range r.s.outbox {
+		if row.ev.ID == eventID {
+			row.attempts++
+			row.lastError = cause.Error()
+			if row.attempts >= maxAttempts {
+				row.dead = true
+				row.lockedUntil = time.Time{}
+			}
+			return row.dead, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *OutboxRepository) PrunePublished(ctx context.Context, retention time.Duration, limit int) (int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	cutoff := r.s.now().Add(-retention)
+	kept := r.s.outbox[:0]
+	n := 0
+	for _, row := range r.s.outbox {
+		if n < limit && row.published && row.publishedAt.Before(cutoff) {
+			n++
+			continue
+		}
+		kept = append(kept, row)
+	}
+	r.s.outbox = kept
+	return n, nil
+}
@@ -0,0 +1,451 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type PRRepository struct {
+	s *Storage
+}
+
+func NewPRRepository(s *Storage) *PRRepository {
+	return &PRRepository{s: s}
+}
+
+func (r *PRRepository) CreateWithReviewers(ctx context.Context, pr pullrequest.PullRequest, events ...event.Event) error {
+	const op = "internal.repository.memory.pr_repo.CreateWithReviewers"
+
+	if err := r.createMany([]pullrequest.PullRequest{pr}, events); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *PRRepository) CreateManyWithReviewers(ctx context.Context, prs []pullrequest.PullRequest, events ...event.Event) error {
+	const op = "internal.repository.memory.pr_repo.CreateManyWithReviewers"
+
+	if err := r.createMany(prs, events); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// createMany validates every PR before storing any of them.
+func (r *PRRepository) createMany(prs []pullrequest.PullRequest, events []event.Event) error {
+	rows, err := prepareEvents(events)
+	if err != nil {
+		return err
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	batch := make(map[string]struct{}, len(prs))
+	for _, pr := range prs {
+		if _, ok := r.s.prs[pr.ID]; ok {
+			return fmt.Errorf("pull request %q already exists", pr.ID)
+		}
+		if _, ok := batch[pr.ID]; ok {
+			return fmt.Errorf("pull request %q is listed twice", pr.ID)
+		}
+		batch[pr.ID] = struct{}{}
+		if _, ok := r.s.users[pr.AuthorID]; !ok {
+			return fmt.Errorf("author %q does not exist", pr.AuthorID)
+		}
+		if err := r.s.checkReviewers(pr.Reviewers); err != nil {
+			return err
+		}
+	}
+
+	for _, pr := range prs {
+		r.s.prSeq++
+		row := &prRow{seq: r.s.prSeq, pr: pullrequest.PullRequest{
+			ID: pr.ID, Name: pr.Name, AuthorID: pr.AuthorID, Status: pr.Status,
+			CreatedAt: pr.CreatedAt, MergedAt: copyTime(pr.MergedAt),
+			ChangedFiles: append([]string(nil), pr.ChangedFiles...),
+		}}
+		insertReviewers(row, pr.Reviewers)
+		r.s.prs[pr.ID] = row
+	}
+	r.s.recordEvents(rows)
+	return nil
+}
+
+func (r *PRRepository) GetWithReviewers(ctx context.Context, id string) (*pullrequest.PullRequest, error) {
+	const op = "internal.repository.memory.pr_repo.GetWithReviewers"
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	row, ok := r.s.prs[id]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, repo_errors.ErrPRNotFound)
+	}
+	return row.snapshot(), nil
+}
+
+// Merge moves an OPEN PR to MERGED. The events are stored only when the
+// status actually changed, so repeated merges do not emit them again.
+func (r *PRRepository) Merge(ctx context.Context, id string, now time.Time, events ...event.Event) (*pullrequest.PullRequest, bool, error) {
+	const op = "internal.repository.memory.pr_repo.Merge"
+
+	rows, err := prepareEvents(events)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	row, ok := r.s.prs[id]
+	if !ok {
+		return nil, false, fmt.Errorf("%s: %w", op, repo_errors.ErrPRNotFound)
+	}
+	switch row.pr.Status {
+	case pullrequest.StatusMerged:
+		return row.snapshot(), false, nil
+	case pullrequest.StatusOpen:
+	default:
+		return nil, false, fmt.Errorf("%s: %w", op, repo_errors.ErrPRStatusChanged)
+	}
+	row.pr.Status = pullrequest.StatusMerged
+	if row.pr.MergedAt == nil {
+		row.pr.MergedAt = &now
+	}
+	r.s.recordEvents(rows)
+	return row.snapshot(), true, nil
+}
+
+func (r *PRRepository) ReplaceReviewers(ctx context.Context, prID, oldRevID, newRevID string, events ...event.Event) error {
+	const op = "internal.repository.memory.pr_repo.ReplaceReviewers"
+
+	rows, err := prepareEvents(events)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	row, err := r.s.assignedRow(prID, oldRevID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if row.reviewIndex(newRevID) >= 0 {
+		return fmt.Errorf("%s: reviewer %q is already assigned", op, newRevID)
+	}
+	if err := r.s.checkReviewers([]string{newRevID}); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	i := row.reviewIndex(oldRevID)
+	row.reviews = append(row.reviews[:i], row.reviews[i+1:]...)
+	insertReviewers(row, []string{newRevID})
+	r.s.recordEvents(rows)
+	return nil
+}
+
+func (r *PRRepository) RemoveReviewer(ctx context.Context, prID, revID string, events ...event.Event) error {
+	const op = "internal.repository.memory.pr_repo.RemoveReviewer"
+
+	rows, err := prepareEvents(events)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	row, err := r.s.assignedRow(prID, revID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	i := row.reviewIndex(revID)
+	row.reviews = append(row.reviews[:i], row.reviews[i+1:]...)
+	r.s.recordEvents(rows)
+	return nil
+}
+
+// HandOver applies the prepared handovers and stores their events, all or nothing.
+func (r *PRRepository) HandOver(ctx context.Context, handovers []assignment.Handover) error {
+	const op = "internal.repository.memory.pr_repo.HandOver"
+
+	rows, err := prepareHandoverEvents(handovers)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := r.s.checkHandovers(handovers); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	r.s.applyHandovers(handovers, rows)
+	return nil
+}
+
+func prepareHandoverEvents(handovers []assignment.Handover) ([]*outboxRow, error) {
+	events := make([]event.Event, 0, len(handovers))
+	for _, h := range handovers {
+		events = append(events, h.Event)
+	}
+	return prepareEvents(events)
+}
+
+// checkHandovers reports whether every handover applies after the ones before it,
+// so that applyHandovers changes nothing unless all of them go through.
+func (s *Storage) checkHandovers(handovers []assignment.Handover) error {
+	assigned := make(map[string]map[string]bool)
+	for _, h := range handovers {
+		set, ok := assigned[h.PullRequestID]
+		if !ok {
+			row, ok := s.prs[h.PullRequestID]
+			if !ok {
+				return repo_errors.ErrPRNotFound
+			}
+			if row.pr.Status == pullrequest.StatusMerged {
+				return repo_errors.ErrPRMerged
+			}
+			set = make(map[string]bool, len(row.reviews))
+			for _, rv := range row.reviews {
+				set[rv.UserID] = true
+			}
+			assigned[h.PullRequestID] = set
+		}
+		if !set[h.ReviewerID] {
+			return repo_errors.ErrReviewersNotFound
+		}
+		delete(set, h.ReviewerID)
+		if h.NewReviewerID == "" {
+			continue
+		}
+		if set[h.NewReviewerID] {
+			return fmt.Errorf("reviewer %q is already assigned", h.NewReviewerID)
+		}
+		if err := s.checkReviewers([]string{h.NewReviewerID}); err != nil {
+			return err
+		}
+		set[h.NewReviewerID] = true
+	}
+	return nil
+}
+
+func (s *Storage) applyHandovers(handovers []assignment.Handover, rows []*outboxRow) {
+	for _, h := range handovers {
+		row := s.prs[h.PullRequestID]
+		i := row.reviewIndex(h.ReviewerID)
+		row.reviews = append(row.reviews[:i], row.reviews[i+1:]...)
+		if h.NewReviewerID != "" {
+			insertReviewers(row, []string{h.NewReviewerID})
+		}
+	}
+	s.recordEvents(rows)
+}
+
+// assignedRow returns a not merged PR that has revID among its reviewers.
+func (s *Storage) assignedRow(prID, revID string) (*prRow, error) {
+	row, ok := s.prs[prID]
+	if !ok {
+		return nil, repo_errors.ErrPRNotFound
+	}
+	if row.pr.Status == pullrequest.StatusMerged {
+		return nil, repo_errors.ErrPRMerged
+	}
+	if row.reviewIndex(revID) < 0 {
+		return nil, repo_errors.ErrReviewersNotFound
+	}
+	return row, nil
+}
+
+func (s *Storage) checkReviewers(ids []string) error {
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := s.users[id]; !ok {
+			return fmt.Errorf("reviewer %q does not exist", id)
+		}
+		if _, dup := seen[id]; dup {
+			return fmt.Errorf("reviewer %q is listed twice", id)
+		}
+		seen[id] = struct{}{}
+	}
+	return nil
+}
+
+// sortedPRs returns the PRs in creation order.
+func (s *Storage) sortedPRs() []*prRow {
+	rows := make([]*prRow, 0, len(s.prs))
+	for _, row := range s.prs {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].seq < rows[j].seq })
+	return rows
+}
+
+func (r *PRRepository) FindShortByReviewer(ctx context.Context, userID string) ([]pullrequest.PullRequestShort, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var result []pullrequest.PullRequestShort
+	for _, row := range r.s.sortedPRs() {
+		if row.reviewIndex(userID) < 0 {
+			continue
+		}
+		result = append(result, pullrequest.PullRequestShort{
+			ID: row.pr.ID, Name: row.pr.Name, AuthorID: row.pr.AuthorID, Status: row.pr.Status,
+		})
+	}
+	return result, nil
+}
+
+func (r *PRRepository) GetOpenPRIDsByReviewer(ctx context.Context, userID string) ([]string, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var ids []string
+	for _, row := range r.s.sortedPRs() {
+		if row.pr.Status == pullrequest.StatusOpen && row.reviewIndex(userID) >= 0 {
+			ids = append(ids, row.pr.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *PRRepository) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	wanted := make(map[string]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		wanted[id] = struct{}{}
+	}
+
+	loads := make(map[string]int, len(userIDs))
+	for _, row := range r.s.prs {
+		if row.pr.Status != pullrequest.StatusOpen {
+			continue
+		}
+		for _, rv := range row.reviews {
+			if _, ok := wanted[rv.UserID]; ok {
+				loads[rv.UserID]++
+			}
+		}
+	}
+	return loads, nil
+}
+
+func (r *PRRepository) OpenReviewsByTeam(ctx context.Context) (map[string]int, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, row := range r.s.prs {
+		if row.pr.Status != pullrequest.StatusOpen {
+			continue
+		}
+		for _, rv := range row.reviews {
+			if teamName := r.s.users[rv.UserID].TeamName; teamName != "" {
+				counts[teamName]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+func (r *PRRepository) SetReviewState(ctx context.Context, prID, userID string, state pullrequest.ReviewState, now time.Time) error {
+	const op = "internal.repository.memory.pr_repo.SetReviewState"
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	row, err := r.s.assignedRow(prID, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	i := row.reviewIndex(userID)
+	row.reviews[i].State = state
+	row.reviews[i].UpdatedAt = &now
+	return nil
+}
+
+func (r *PRRepository) MarkReady(ctx context.Context, prID string, reviewers []string, events ...event.Event) error {
+	const op = "internal.repository.memory.pr_repo.MarkReady"
+
+	return r.transition(op, prID, events, []pullrequest.Status{pullrequest.StatusDraft}, func(row *prRow) error {
+		if err := r.s.checkReviewers(reviewers); err != nil {
+			return err
+		}
+		row.pr.Status = pullrequest.StatusOpen
+		insertReviewers(row, reviewers)
+		return nil
+	})
+}
+
+func (r *PRRepository) Close(ctx context.Context, prID string, now time.Time, events ...event.Event) error {
+	const op = "internal.repository.memory.pr_repo.Close"
+
+	from := []pullrequest.Status{pullrequest.StatusOpen, pullrequest.StatusDraft}
+	return r.transition(op, prID, events, from, func(row *prRow) error {
+		row.pr.Status = pullrequest.StatusClosed
+		row.pr.ClosedAt = &now
+		row.reviews = nil
+		return nil
+	})
+}
+
+func (r *PRRepository) Reopen(ctx context.Context, prID string, reviewers []string, events ...event.Event) error {
+	const op = "internal.repository.memory.pr_repo.Reopen"
+
+	return r.transition(op, prID, events, []pullrequest.Status{pullrequest.StatusClosed}, func(row *prRow) error {
+		if err := r.s.checkReviewers(reviewers); err != nil {
+			return err
+		}
+		row.pr.Status = pullrequest.StatusOpen
+		row.pr.ClosedAt = nil
+		insertReviewers(row, reviewers)
+		return nil
+	})
+}
+
+// transition applies a status change to a PR currently in one of the from statuses.
+// It reports ErrPRNotFound or ErrPRStatusChanged otherwise. apply must validate before mutating.
+func (r *PRRepository) transition(op, prID string, events []event.Event, from []pullrequest.Status, apply func(*prRow) error) error {
+	rows, err := prepareEvents(events)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	row, ok := r.s.prs[prID]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrPRNotFound)
+	}
+	allowed := false
+	for _, st := range from {
+		if row.pr.Status == st {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrPRStatusChanged)
+	}
+
+	if err := apply(row); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	r.s.recordEvents(rows)
+	return nil
+}
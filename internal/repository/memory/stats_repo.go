@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	"github.com/hihikaAAa/PRManager/internal/repository"
+)
+
+func (r *PRRepository) GetStats(ctx context.Context, f repository.StatsFilter) (repository.Stats, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	stats := repository.Stats{}
+	teams := make(map[string]*repository.TeamStats)
+	var merged []time.Duration
+	mergedByTeam := make(map[string][]time.Duration)
+	reviewers := make(map[string]*repository.ReviewerStat)
+
+	inPeriod := func(t time.Time) bool {
+		return (f.From.IsZero() || !t.Before(f.From)) && (f.To.IsZero() || t.Before(f.To))
+	}
+
+	for _, row := range r.s.prs {
+		pr := row.pr
+		if !inPeriod(pr.CreatedAt) {
+			continue
+		}
+		teamName := r.s.users[pr.AuthorID].TeamName
+		if f.TeamName != "" && teamName != f.TeamName {
+			continue
+		}
+
+		stats.AddStatus(pr.Status, 1)
+		if teamName != "" {
+			if teams[teamName] == nil {
+				teams[teamName] = &repository.TeamStats{TeamName: teamName}
+			}
+			teams[teamName].AddStatus(pr.Status, 1)
+		}
+		if pr.Status == pullrequest.StatusMerged && pr.MergedAt != nil {
+			d := pr.MergedAt.Sub(pr.CreatedAt)
+			merged = append(merged, d)
+			mergedByTeam[teamName] = append(mergedByTeam[teamName], d)
+		}
+
+		for _, rv := range row.reviews {
+			rs := reviewers[rv.UserID]
+			if rs == nil {
+				rs = &repository.ReviewerStat{UserID: rv.UserID}
+				reviewers[rv.UserID] = rs
+			}
+			rs.Count++
+			switch pr.Status {
+			case pullrequest.StatusOpen:
+				rs.Open++
+			case pullrequest.StatusMerged:
+				rs.Completed++
+			}
+		}
+	}
+
+	// Reassignments are counted by when they happened, on PRs of any age.
+	for _, c := range r.s.history {
+		if c.OldReviewerID == "" || c.NewReviewerID == "" || !inPeriod(c.CreatedAt) {
+			continue
+		}
+		row, ok := r.s.prs[c.PullRequestID]
+		if !ok {
+			continue
+		}
+		teamName := r.s.users[row.pr.AuthorID].TeamName
+		if f.TeamName != "" && teamName != f.TeamName {
+			continue
+		}
+		stats.Reassignments++
+		if teamName != "" {
+			if teams[teamName] == nil {
+				teams[teamName] = &repository.TeamStats{TeamName: teamName}
+			}
+			teams[teamName].Reassignments++
+		}
+	}
+
+	stats.MedianTimeToMerge = repository.MedianDuration(merged)
+	for name, ts := range teams {
+		ts.MedianTimeToMerge = repository.MedianDuration(mergedByTeam[name])
+		stats.Teams = append(stats.Teams, *ts)
+	}
+	sort.Slice(stats.Teams, func(i, j int) bool { return stats.Teams[i].TeamName < stats.Teams[j].TeamName })
+
+	for _, rs := range reviewers {
+		stats.Reviewers = append(stats.Reviewers, *rs)
+	}
+	sort.Slice(stats.Reviewers, func(i, j int) bool { return stats.Reviewers[i].UserID < stats.Reviewers[j].UserID })
+	return stats, nil
+}
@@ -0,0 +1,170 @@
+// Package memory is a thread-safe in-memory storage backend.
+// It keeps the semantics of the postgres repositories (errors, ordering, atomic
+// event recording) and is meant for tests and demos: nothing survives a restart.
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	"github.com/hihikaAAa/PRManager/internal/domain/subscriber"
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/repository"
+)
+
+// Storage holds all tables behind a single lock, so every repository method is one transaction.
+type Storage struct {
+	mu sync.RWMutex
+
+	teams      map[string]*teamRow
+	users      map[string]user.User
+	identities map[identityKey]string
+
+	absences   map[int64]user.Absence
+	absenceSeq int64
+
+	prs   map[string]*prRow
+	prSeq int64
+
+	subscribers  map[int64]subscriber.Subscriber
+	subscriberID int64
+	deadLetters  []subscriber.DeadLetter
+	retries      []subscriber.Retry // ordered by id
+	retrySeq     int64
+
+	outbox    []*outboxRow
+	outboxSeq int64
+	history   []assignment.Change
+
+	now func() time.Time
+}
+
+type teamRow struct {
+	parent     string
+	settings   *team.Settings
+	codeOwners []team.OwnershipRule
+}
+
+type identityKey struct {
+	provider string
+	login    string
+}
+
+type prRow struct {
+	seq     int64
+	pr      pullrequest.PullRequest
+	reviews []pullrequest.Review
+}
+
+type outboxRow struct {
+	id          int64
+	ev          event.Event
+	payload     []byte
+	attempts    int
+	lastError   string
+	lockedUntil time.Time
+	published   bool
+	publishedAt time.Time
+	dead        bool
+}
+
+func NewStorage() *Storage {
+	return &Storage{
+		teams:       make(map[string]*teamRow),
+		users:       make(map[string]user.User),
+		identities:  make(map[identityKey]string),
+		absences:    make(map[int64]user.Absence),
+		prs:         make(map[string]*prRow),
+		subscribers: make(map[int64]subscriber.Subscriber),
+		now:         func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Repositories returns all repositories backed by s.
+func (s *Storage) Repositories() repository.Repositories {
+	return repository.Repositories{
+		PRs:         NewPRRepository(s),
+		Users:       NewUserRepository(s),
+		Teams:       NewTeamRepository(s),
+		Subscribers: NewSubscriberRepository(s),
+		Outbox:      NewOutboxRepository(s),
+		Absences:    NewAbsenceRepository(s),
+	}
+}
+
+// prepareEvents encodes the events before anything is changed, so a failure leaves the storage intact.
+func prepareEvents(events []event.Event) ([]*outboxRow, error) {
+	rows := make([]*outboxRow, 0, len(events))
+	for _, ev := range events {
+		payload, err := json.Marshal(ev.Data)
+		if err != nil {
+			return nil, fmt.Errorf("Marshal outbox payload: %w", err)
+		}
+		rows = append(rows, &outboxRow{ev: ev, payload: payload})
+	}
+	return rows, nil
+}
+
+// recordEvents appends prepared events to the outbox and the assignment history.
+func (s *Storage) recordEvents(rows []*outboxRow) {
+	for _, row := range rows {
+		s.outboxSeq++
+		row.id = s.outboxSeq
+		s.outbox = append(s.outbox, row)
+
+		for _, c := range assignment.FromEvent(row.ev) {
+			c.ID = int64(len(s.history) + 1)
+			s.history = append(s.history, c)
+		}
+	}
+}
+
+// insertReviewers adds pending reviews. Reviewers added together are ordered by id,
+// matching the (created_at, user_id) order of the postgres backend.
+func insertReviewers(row *prRow, reviewers []string) {
+	batch := append([]string(nil), reviewers...)
+	sort.Strings(batch)
+	for _, id := range batch {
+		row.reviews = append(row.reviews, pullrequest.Review{UserID: id, State: pullrequest.ReviewPending})
+	}
+}
+
+func (r *prRow) snapshot() *pullrequest.PullRequest {
+	pr := r.pr
+	pr.MergedAt = copyTime(r.pr.MergedAt)
+	pr.ClosedAt = copyTime(r.pr.ClosedAt)
+	pr.ChangedFiles = append([]string(nil), r.pr.ChangedFiles...)
+	pr.FallbackReviewers = nil
+	pr.Reviews = make([]pullrequest.Review, 0, len(r.reviews))
+	pr.Reviewers = make([]string, 0, len(r.reviews))
+	for _, rv := range r.reviews {
+		rv.UpdatedAt = copyTime(rv.UpdatedAt)
+		pr.Reviews = append(pr.Reviews, rv)
+		pr.Reviewers = append(pr.Reviewers, rv.UserID)
+	}
+	return &pr
+}
+
+func (r *prRow) reviewIndex(userID string) int {
+	for i, rv := range r.reviews {
+		if rv.UserID == userID {
+			return i
+		}
+	}
+	return -1
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := *t
+	return &v
+}
@@ -0,0 +1,209 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	"github.com/hihikaAAa/PRManager/internal/domain/subscriber"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type SubscriberRepository struct {
+	s *Storage
+}
+
+func NewSubscriberRepository(s *Storage) *SubscriberRepository {
+	return &SubscriberRepository{s: s}
+}
+
+func (r *SubscriberRepository) Create(ctx context.Context, sub subscriber.Subscriber) (subscriber.Subscriber, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.subscriberID++
+	sub.ID = r.s.subscriberID
+	sub.IsActive = true
+	sub.CreatedAt = r.s.now()
+	sub.Events = append([]event.Type(nil), sub.Events...)
+	r.s.subscribers[sub.ID] = sub
+	return sub, nil
+}
+
+// List returns all subscribers ordered by id, secrets included.
+func (r *SubscriberRepository) List(ctx context.Context) ([]subscriber.Subscriber, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var res []subscriber.Subscriber
+	for _, sub := range r.s.subscribers {
+		sub.Events = append([]event.Type(nil), sub.Events...)
+		res = append(res, sub)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res, nil
+}
+
+func (r *SubscriberRepository) Delete(ctx context.Context, id int64) error {
+	const op = "internal.repository.memory.subscriber_repo.Delete"
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.subscribers[id]; !ok {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrSubscriberNotFound)
+	}
+	delete(r.s.subscribers, id)
+
+	kept := r.s.deadLetters[:0]
+	for _, dl := range r.s.deadLetters {
+		if dl.SubscriberID != id {
+			kept = append(kept, dl)
+		}
+	}
+	r.s.deadLetters = kept
+
+	keptRetries := r.s.retries[:0]
+	for _, rt := range r.s.retries {
+		if rt.SubscriberID != id {
+			keptRetries = append(keptRetries, rt)
+		}
+	}
+	r.s.retries = keptRetries
+	return nil
+}
+
+func (r *SubscriberRepository) SaveDeadLetter(ctx context.Context, dl subscriber.DeadLetter) error {
+	const op = "internal.repository.memory.subscriber_repo.SaveDeadLetter"
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.subscribers[dl.SubscriberID]; !ok {
+		return fmt.Errorf("%s: subscriber %d does not exist", op, dl.SubscriberID)
+	}
+	dl.ID = int64(len(r.s.deadLetters) + 1)
+	dl.CreatedAt = r.s.now()
+	dl.Payload = append([]byte(nil), dl.Payload...)
+	r.s.deadLetters = append(r.s.deadLetters, dl)
+	return nil
+}
+
+func (r *SubscriberRepository) ScheduleRetry(ctx context.Context, rt subscriber.Retry) error {
+	const op = "internal.repository.memory.subscriber_repo.ScheduleRetry"
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.subscribers[rt.SubscriberID]; !ok {
+		return fmt.Errorf("%s: subscriber %d does not exist", op, rt.SubscriberID)
+	}
+	for _, queued := range r.s.retries {
+		if queued.SubscriberID == rt.SubscriberID && queued.EventID == rt.EventID {
+			return nil
+		}
+	}
+	r.s.retrySeq++
+	rt.ID = r.s.retrySeq
+	rt.Payload = append([]byte(nil), rt.Payload...)
+	r.s.retries = append(r.s.retries, rt)
+	return nil
+}
+
+func (r *SubscriberRepository) RetryingSubscribers(ctx context.Context) ([]int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	seen := make(map[int64]struct{})
+	var ids []int64
+	for _, rt := range r.s.retries {
+		if _, ok := seen[rt.SubscriberID]; !ok {
+			seen[rt.SubscriberID] = struct{}{}
+			ids = append(ids, rt.SubscriberID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func (r *SubscriberRepository) ClaimDueRetries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]subscriber.Retry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var res []subscriber.Retry
+	blocked := make(map[int64]bool)
+	for i := range r.s.retries {
+		if len(res) >= limit {
+			break
+		}
+		rt := &r.s.retries[i]
+		if blocked[rt.SubscriberID] {
+			continue
+		}
+		if rt.NextAttemptAt.After(now) {
+			blocked[rt.SubscriberID] = true
+			continue
+		}
+		rt.NextAttemptAt = now.Add(lease)
+
+		claimed := *rt
+		claimed.Payload = append([]byte(nil), rt.Payload...)
+		res = append(res, claimed)
+	}
+	return res, nil
+}
+
+func (r *SubscriberRepository) RescheduleRetry(ctx context.Context, rt subscriber.Retry) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for i := range r.s.retries {
+		if queued := &r.s.retries[i]; queued.ID == rt.ID {
+			queued.Attempts = rt.Attempts
+			queued.LastError = rt.LastError
+			queued.NextAttemptAt = rt.NextAttemptAt
+		}
+	}
+	return nil
+}
+
+func (r *SubscriberRepository) DeleteRetry(ctx context.Context, id int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.deleteRetry(id)
+	return nil
+}
+
+func (r *SubscriberRepository) DeadLetterRetry(ctx context.Context, rt subscriber.Retry) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	queued, ok := r.s.deleteRetry(rt.ID)
+	if !ok {
+		return nil
+	}
+	r.s.deadLetters = append(r.s.deadLetters, subscriber.DeadLetter{
+		ID:           int64(len(r.s.deadLetters) + 1),
+		SubscriberID: queued.SubscriberID,
+		EventID:      queued.EventID,
+		EventType:    queued.EventType,
+		Payload:      queued.Payload,
+		Attempts:     rt.Attempts,
+		LastError:    rt.LastError,
+		CreatedAt:    r.s.now(),
+	})
+	return nil
+}
+
+func (s *Storage) deleteRetry(id int64) (subscriber.Retry, bool) {
+	for i, rt := range s.retries {
+		if rt.ID == id {
+			s.retries = append(s.retries[:i], s.retries[i+1:]...)
+			return rt, true
+		}
+	}
+	return subscriber.Retry{}, false
+}
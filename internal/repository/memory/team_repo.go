@@ -0,0 +1,308 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type TeamRepository struct {
+	s *Storage
+}
+
+func NewTeamRepository(s *Storage) *TeamRepository {
+	return &TeamRepository{s: s}
+}
+
+func (r *TeamRepository) CreateTeam(ctx context.Context, name string) error {
+	const op = "internal.repository.memory.team_repo.CreateTeam"
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.teams[name]; ok {
+		return fmt.Errorf("%s: team %q already exists", op, name)
+	}
+	r.s.teams[name] = &teamRow{}
+	return nil
+}
+
+func (r *TeamRepository) Exists(ctx context.Context, name string) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	_, ok := r.s.teams[name]
+	return ok, nil
+}
+
+func (r *TeamRepository) GetWithMembers(ctx context.Context, name string) (*team.Team, error) {
+	const op = "internal.repository.memory.team_repo.GetWithMembers"
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	row, ok := r.s.teams[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, repo_errors.ErrTeamNotFound)
+	}
+	return &team.Team{TeamName: name, ParentTeam: row.parent, Members: r.s.membersOf(name)}, nil
+}
+
+func (r *TeamRepository) List(ctx context.Context) ([]team.Team, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	teams := make([]team.Team, 0, len(r.s.teams))
+	for name, row := range r.s.teams {
+		teams = append(teams, team.Team{TeamName: name, ParentTeam: row.parent})
+	}
+	sort.Slice(teams, func(i, j int) bool { return teams[i].TeamName < teams[j].TeamName })
+	return teams, nil
+}
+
+func (r *TeamRepository) SetParent(ctx context.Context, name, parent string) error {
+	const op = "internal.repository.memory.team_repo.SetParent"
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	row, ok := r.s.teams[name]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrTeamNotFound)
+	}
+	if parent != "" {
+		p, ok := r.s.teams[parent]
+		if !ok {
+			return fmt.Errorf("%s: %w", op, repo_errors.ErrParentTeamNotFound)
+		}
+		if parent == name || slices.Contains(r.s.ancestorsOf(p), name) {
+			return fmt.Errorf("%s: %w", op, team.ErrInvalidParent)
+		}
+	}
+	row.parent = parent
+	return nil
+}
+
+func (r *TeamRepository) Ancestors(ctx context.Context, name string) ([]string, error) {
+	const op = "internal.repository.memory.team_repo.Ancestors"
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	row, ok := r.s.teams[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, repo_errors.ErrTeamNotFound)
+	}
+	return r.s.ancestorsOf(row), nil
+}
+
+// ancestorsOf returns the parent chain of the team, nearest first. The caller holds the lock.
+func (s *Storage) ancestorsOf(row *teamRow) []string {
+	var res []string
+	seen := make(map[string]struct{})
+	for row.parent != "" {
+		if _, ok := seen[row.parent]; ok {
+			break
+		}
+		seen[row.parent] = struct{}{}
+		res = append(res, row.parent)
+		next, ok := s.teams[row.parent]
+		if !ok {
+			break
+		}
+		row = next
+	}
+	return res
+}
+
+func (r *TeamRepository) GetSettings(ctx context.Context, name string) (team.Settings, error) {
+	const op = "internal.repository.memory.team_repo.GetSettings"
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	row, ok := r.s.teams[name]
+	if !ok {
+		return team.Settings{}, fmt.Errorf("%s: %w", op, repo_errors.ErrTeamNotFound)
+	}
+	src := row.settings
+	if src == nil {
+		for _, parent := range r.s.ancestorsOf(row) {
+			if p := r.s.teams[parent]; p != nil && p.settings != nil {
+				src = p.settings
+				break
+			}
+		}
+	}
+	if src == nil {
+		return team.DefaultSettings(name), nil
+	}
+
+	s := *src
+	s.FallbackTeams = append([]string(nil), src.FallbackTeams...)
+	if src.TeamName != name {
+		s.TeamName, s.InheritedFrom = name, src.TeamName
+	}
+	return s, nil
+}
+
+func (r *TeamRepository) UpsertSettings(ctx context.Context, s team.Settings) error {
+	const op = "internal.repository.memory.team_repo.UpsertSettings"
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	row, ok := r.s.teams[s.TeamName]
+	if !ok {
+		return fmt.Errorf("%s: team %q does not exist", op, s.TeamName)
+	}
+	for _, fb := range s.FallbackTeams {
+		if _, ok := r.s.teams[fb]; !ok {
+			return fmt.Errorf("%s: fallback team %q does not exist", op, fb)
+		}
+	}
+
+	stored := s
+	stored.InheritedFrom = ""
+	stored.FallbackTeams = append([]string(nil), s.FallbackTeams...)
+	row.settings = &stored
+	return nil
+}
+
+func (r *TeamRepository) GetCodeOwners(ctx context.Context, name string) (team.CodeOwners, error) {
+	const op = "internal.repository.memory.team_repo.GetCodeOwners"
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	row, ok := r.s.teams[name]
+	if !ok {
+		return team.CodeOwners{}, fmt.Errorf("%s: %w", op, repo_errors.ErrTeamNotFound)
+	}
+	return team.CodeOwners{TeamName: name, Rules: copyRules(row.codeOwners)}, nil
+}
+
+func (r *TeamRepository) SetCodeOwners(ctx context.Context, c team.CodeOwners) error {
+	const op = "internal.repository.memory.team_repo.SetCodeOwners"
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	row, ok := r.s.teams[c.TeamName]
+	if !ok {
+		return fmt.Errorf("%s: team %q does not exist", op, c.TeamName)
+	}
+	row.codeOwners = copyRules(c.Rules)
+	return nil
+}
+
+func copyRules(rules []team.OwnershipRule) []team.OwnershipRule {
+	var out []team.OwnershipRule
+	for _, r := range rules {
+		out = append(out, team.OwnershipRule{Pattern: r.Pattern, Owners: append([]string(nil), r.Owners...)})
+	}
+	return out
+}
+
+func (r *TeamRepository) Rename(ctx context.Context, oldName, newName string) error {
+	const op = "internal.repository.memory.team_repo.Rename"
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	row, ok := r.s.teams[oldName]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrTeamNotFound)
+	}
+	if _, ok := r.s.teams[newName]; ok {
+		return fmt.Errorf("%s: team %q already exists", op, newName)
+	}
+
+	delete(r.s.teams, oldName)
+	r.s.teams[newName] = row
+	if row.settings != nil {
+		row.settings.TeamName = newName
+	}
+	for _, t := range r.s.teams {
+		if t.parent == oldName {
+			t.parent = newName
+		}
+	}
+	for id, u := range r.s.users {
+		if u.TeamName == oldName {
+			u.TeamName = newName
+			r.s.users[id] = u
+		}
+	}
+	for _, t := range r.s.teams {
+		if t.settings == nil {
+			continue
+		}
+		for i, fb := range t.settings.FallbackTeams {
+			if fb == oldName {
+				t.settings.FallbackTeams[i] = newName
+			}
+		}
+	}
+	return nil
+}
+
+func (r *TeamRepository) Delete(ctx context.Context, name, target string, handovers ...assignment.Handover) ([]string, error) {
+	const op = "internal.repository.memory.team_repo.Delete"
+
+	rows, err := prepareHandoverEvents(handovers)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	deleted, ok := r.s.teams[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, repo_errors.ErrTeamNotFound)
+	}
+	if _, ok := r.s.teams[target]; target != "" && !ok {
+		return nil, fmt.Errorf("%s: team %q does not exist", op, target)
+	}
+	if err := r.s.checkHandovers(handovers); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	r.s.applyHandovers(handovers, rows)
+
+	var ids []string
+	for id, u := range r.s.users {
+		if u.TeamName != name {
+			continue
+		}
+		u.TeamName = target
+		if target == "" {
+			u.IsActive = false
+		}
+		r.s.users[id] = u
+		ids = append(ids, id)
+	}
+	delete(r.s.teams, name)
+	for _, t := range r.s.teams {
+		if t.parent == name {
+			t.parent = deleted.parent
+		}
+		if t.settings == nil {
+			continue
+		}
+		kept := t.settings.FallbackTeams[:0]
+		for _, fb := range t.settings.FallbackTeams {
+			if fb != name {
+				kept = append(kept, fb)
+			}
+		}
+		t.settings.FallbackTeams = kept
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
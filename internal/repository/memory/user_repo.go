@@ -0,0 +1,195 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type UserRepository struct {
+	s *Storage
+}
+
+func NewUserRepository(s *Storage) *UserRepository {
+	return &UserRepository{s: s}
+}
+
+func (r *UserRepository) UpsertManyForTeam(ctx context.Context, teamName string, users []*user.User) error {
+	const op = "internal.repository.memory.user_repo.UpsertManyForTeam"
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.teams[teamName]; !ok {
+		return fmt.Errorf("%s: team %q does not exist", op, teamName)
+	}
+	for _, u := range users {
+		prev := r.s.users[u.ID]
+		r.s.users[u.ID] = user.User{ID: u.ID, Name: u.Name, TeamName: teamName, IsActive: u.IsActive, MaxOpenReviews: prev.MaxOpenReviews}
+	}
+	return nil
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*user.User, error) {
+	const op = "internal.repository.memory.user_repo.GetByID"
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	u, ok := r.s.users[id]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, repo_errors.ErrUserNotFound)
+	}
+	return &u, nil
+}
+
+func (r *UserRepository) SetIsActive(ctx context.Context, id string, active bool) (*user.User, error) {
+	const op = "internal.repository.memory.user_repo.SetIsActive"
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[id]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, repo_errors.ErrUserNotFound)
+	}
+	u.IsActive = active
+	r.s.users[id] = u
+	return &u, nil
+}
+
+func (r *UserRepository) Deactivate(ctx context.Context, userIDs []string, handovers ...assignment.Handover) error {
+	const op = "internal.repository.memory.user_repo.Deactivate"
+
+	rows, err := prepareHandoverEvents(handovers)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, id := range userIDs {
+		if _, ok := r.s.users[id]; !ok {
+			return fmt.Errorf("%s: %w", op, repo_errors.ErrUserNotFound)
+		}
+	}
+	if err := r.s.checkHandovers(handovers); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	for _, id := range userIDs {
+		u := r.s.users[id]
+		u.IsActive = false
+		r.s.users[id] = u
+	}
+	r.s.applyHandovers(handovers, rows)
+	return nil
+}
+
+func (r *UserRepository) SetMaxOpenReviews(ctx context.Context, id string, max int) (*user.User, error) {
+	const op = "internal.repository.memory.user_repo.SetMaxOpenReviews"
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[id]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, repo_errors.ErrUserNotFound)
+	}
+	u.MaxOpenReviews = max
+	r.s.users[id] = u
+	return &u, nil
+}
+
+func (r *UserRepository) SetTeam(ctx context.Context, id, teamName string) (*user.User, error) {
+	const op = "internal.repository.memory.user_repo.SetTeam"
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	u, ok := r.s.users[id]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, repo_errors.ErrUserNotFound)
+	}
+	if _, ok := r.s.teams[teamName]; teamName != "" && !ok {
+		return nil, fmt.Errorf("%s: team %q does not exist", op, teamName)
+	}
+	u.TeamName = teamName
+	r.s.users[id] = u
+	return &u, nil
+}
+
+func (r *UserRepository) FindActiveByTeamExcept(ctx context.Context, teamName string, excluded []string) ([]*user.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	skip := make(map[string]struct{}, len(excluded))
+	for _, id := range excluded {
+		skip[id] = struct{}{}
+	}
+
+	now := r.s.now()
+	for _, a := range r.s.absences {
+		if a.ActiveAt(now) {
+			skip[a.UserID] = struct{}{}
+		}
+	}
+
+	cands := make([]*user.User, 0)
+	for _, u := range r.s.users {
+		if u.TeamName != teamName || !u.IsActive {
+			continue
+		}
+		if _, ok := skip[u.ID]; ok {
+			continue
+		}
+		u := u
+		cands = append(cands, &u)
+	}
+	sort.Slice(cands, func(i, j int) bool { return cands[i].ID < cands[j].ID })
+	return cands, nil
+}
+
+// membersOf returns the team members ordered by id. The caller holds the lock.
+func (s *Storage) membersOf(teamName string) []*user.User {
+	var members []*user.User
+	for _, u := range s.users {
+		if u.TeamName == teamName {
+			u := u
+			members = append(members, &u)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].ID < members[j].ID })
+	return members
+}
+
+func (r *UserRepository) LinkIdentity(ctx context.Context, provider, login, userID string) error {
+	const op = "internal.repository.memory.user_repo.LinkIdentity"
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[userID]; !ok {
+		return fmt.Errorf("%s: user %q does not exist", op, userID)
+	}
+	r.s.identities[identityKey{provider: provider, login: login}] = userID
+	return nil
+}
+
+func (r *UserRepository) FindByIdentity(ctx context.Context, provider, login string) (*user.User, error) {
+	const op = "internal.repository.memory.user_repo.FindByIdentity"
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	id, ok := r.s.identities[identityKey{provider: provider, login: login}]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, repo_errors.ErrIdentityNotFound)
+	}
+	u := r.s.users[id]
+	return &u, nil
+}
@@ -0,0 +1,73 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/lib/pq"
+
+	"github.com/hihikaAAa/PRManager/internal/repository"
+	"github.com/hihikaAAa/PRManager/internal/repository/postgres"
+	"github.com/hihikaAAa/PRManager/internal/repository/repotest"
+)
+
+// The contract suite needs a disposable database: TEST_POSTGRES_DSN=postgres://.../prmanager_test?sslmode=disable.
+// All tables are truncated before each case.
+const envDSN = "TEST_POSTGRES_DSN"
+
+func TestContract(t *testing.T) {
+	dsn := os.Getenv(envDSN)
+	if dsn == "" {
+		t.Skipf("%s is not set", envDSN)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	migrate(t, db)
+
+	repotest.Run(t, func(t *testing.T) repository.Repositories {
+		truncate(t, db)
+		return postgres.Repositories(db)
+	})
+}
+
+func migrate(t *testing.T, db *sql.DB) {
+	t.Helper()
+
+	m, err := postgres.Migrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func truncate(t *testing.T, db *sql.DB) {
+	t.Helper()
+
+	const q = `
+	TRUNCATE assignment_events, outbox, webhook_dead_letters, webhook_subscribers, user_identities,
+		pull_request_reviewers, pull_requests, team_code_owners, team_fallbacks, team_settings, user_absences, users, teams
+	RESTART IDENTITY CASCADE;
+	`
+	if _, err := db.Exec(q); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigrationsAreEmbedded(t *testing.T) {
+	m, err := postgres.Migrator(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Latest() == 0 {
+		t.Fatal("no migrations embedded")
+	}
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+)
+
+// insertAssignmentChanges appends the reviewer changes described by events to assignment_events.
+func insertAssignmentChanges(ctx context.Context, tx *sql.Tx, events []event.Event) error {
+	const q = `
+	INSERT INTO assignment_events (pull_request_id, old_reviewer_id, new_reviewer_id, reason, from_fallback, actor, event_id, created_at)
+	VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, $5, $6, $7, $8);
+	`
+
+	for _, ev := range events {
+		for _, c := range assignment.FromEvent(ev) {
+			_, err := tx.ExecContext(ctx, q, c.PullRequestID, c.OldReviewerID, c.NewReviewerID, c.Reason, c.FromFallback, c.Actor, ev.ID, c.CreatedAt)
+			if err != nil {
+				return fmt.Errorf("Exec insert assignment event: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *PRRepository) HistoryByPR(ctx context.Context, prID string) ([]assignment.Change, error) {
+	const op = "internal.repository.postgres.history_repo.HistoryByPR"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	SELECT id, pull_request_id, COALESCE(old_reviewer_id, ''), COALESCE(new_reviewer_id, ''), reason, from_fallback, actor, created_at
+	FROM assignment_events
+	WHERE pull_request_id = $1
+	ORDER BY id;
+	`
+
+	res, err := r.queryHistory(ctx, q, prID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return res, nil
+}
+
+func (r *PRRepository) HistoryByUser(ctx context.Context, userID string) ([]assignment.Change, error) {
+	const op = "internal.repository.postgres.history_repo.HistoryByUser"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	SELECT id, pull_request_id, COALESCE(old_reviewer_id, ''), COALESCE(new_reviewer_id, ''), reason, from_fallback, actor, created_at
+	FROM assignment_events
+	WHERE old_reviewer_id = $1 OR new_reviewer_id = $1
+	ORDER BY id;
+	`
+
+	res, err := r.queryHistory(ctx, q, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return res, nil
+}
+
+func (r *PRRepository) queryHistory(ctx context.Context, q string, arg string) ([]assignment.Change, error) {
+	rows, err := r.db.QueryContext(ctx, q, arg)
+	if err != nil {
+		return nil, fmt.Errorf("QueryContext: %w", err)
+	}
+	defer rows.Close()
+
+	res := []assignment.Change{}
+	for rows.Next() {
+		var c assignment.Change
+		if err := rows.Scan(&c.ID, &c.PullRequestID, &c.OldReviewerID, &c.NewReviewerID, &c.Reason, &c.FromFallback, &c.Actor, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("Scan: %w", err)
+		}
+		res = append(res, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/hihikaAAa/PRManager/internal/migrator"
+	"github.com/hihikaAAa/PRManager/internal/repository"
+	"github.com/hihikaAAa/PRManager/migrations"
+)
+
+// Migrator returns the migrator for the PostgreSQL schema in /migrations.
+func Migrator(db *sql.DB) (*migrator.Migrator, error) {
+	return migrator.New(db, migrations.FS, migrator.Postgres)
+}
+
+// Repositories returns all repositories sharing db.
+func Repositories(db *sql.DB) repository.Repositories {
+	return repository.Repositories{
+		PRs:         New(db),
+		Users:       NewUserRepository(db),
+		Teams:       NewTeamRepository(db),
+		Subscribers: NewSubscriberRepository(db),
+		Outbox:      NewOutboxRepository(db),
+		Absences:    NewAbsenceRepository(db),
+	}
+}
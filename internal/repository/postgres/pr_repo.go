@@ -6,8 +6,13 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/lib/pq"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
 	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
-	"github.com/hihikaAAa/PRManager/internal/repository/postgres/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
 )
 
 type PRRepository struct {
@@ -18,63 +23,88 @@ func New(db *sql.DB) *PRRepository{
 	return &PRRepository{db: db}
 }
 
-func (r *PRRepository) CreateWithReviewers(ctx context.Context, pr pullrequest.PullRequest) error{
+// CreateWithReviewers stores the PR with its reviewers and the events in one transaction.
+func (r *PRRepository) CreateWithReviewers(ctx context.Context, pr pullrequest.PullRequest, events ...event.Event) error{
 	const op = "internal.repository.postgres.pr_repo.CreateWithReviewers"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := r.createMany(ctx, []pullrequest.PullRequest{pr}, events); err != nil{
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// CreateManyWithReviewers stores the PRs with their reviewers and the events in one transaction.
+func (r *PRRepository) CreateManyWithReviewers(ctx context.Context, prs []pullrequest.PullRequest, events ...event.Event) error{
+	const op = "internal.repository.postgres.pr_repo.CreateManyWithReviewers"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := r.createMany(ctx, prs, events); err != nil{
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *PRRepository) createMany(ctx context.Context, prs []pullrequest.PullRequest, events []event.Event) error{
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil{
-		return fmt.Errorf("%s, BeginTx: %w", op ,err)
+		return fmt.Errorf("BeginTx: %w", err)
 	}
 	defer tx.Rollback()
 
 	const qPR = `
-	INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, merged_at)
-	VALUES ($1, $2, $3, $4, $5, $6)
-	`
-
-	if _, err := tx.ExecContext(ctx, qPR, pr.ID, pr.Name, pr.AuthorID, pr.Status, pr.CreatedAt, pr.MergedAt); err != nil{
-		return fmt.Errorf("%s, ExecContextPr: %w", op, err)
-	}
-
-	const qRev = `
-	INSERT INTO pull_request_reviewers (pull_request_id, user_id)
-	VALUES ($1, $2)
+	INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, merged_at, changed_files)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
-	for _, reviewerID := range pr.Reviewers{
-		if _, err := tx.ExecContext(ctx, qRev, pr.ID, reviewerID); err != nil{
-			return fmt.Errorf("%s, ExecContextReviewer: %w", op, err)
+	for _, pr := range prs{
+		files := pr.ChangedFiles
+		if files == nil{
+			files = []string{}
+		}
+		if _, err := tx.ExecContext(ctx, qPR, pr.ID, pr.Name, pr.AuthorID, pr.Status, pr.CreatedAt, pr.MergedAt, pq.Array(files)); err != nil{
+			return fmt.Errorf("ExecContextPr %s: %w", pr.ID, err)
 		}
+		if err := insertReviewers(ctx, tx, pr.ID, pr.Reviewers); err != nil{
+			return fmt.Errorf("%s: %w", pr.ID, err)
+		}
+	}
+	if err := recordEvents(ctx, tx, events); err != nil{
+		return err
 	}
 	if err := tx.Commit(); err != nil{
-		return fmt.Errorf("%s, Commit: %w", op, err)
+		return fmt.Errorf("Commit: %w", err)
 	}
-
 	return nil
 }
 
 func (r *PRRepository) GetWithReviewers(ctx context.Context, id string)(*pullrequest.PullRequest, error){
 	const op = "internal.repository.postgres.pr_repo.GetWithReviewers"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	const qPR = `
-	SELECT pull_request_id, pull_request_name, author_id, status, created_at,merged_at
+	SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, closed_at, changed_files
 	FROM pull_requests 
 	WHERE pull_request_id = $1;
 	`
 
 	pr := &pullrequest.PullRequest{}
-	if err := r.db.QueryRowContext(ctx, qPR, id).Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt); err != nil{
+	if err := r.db.QueryRowContext(ctx, qPR, id).Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt, pq.Array(&pr.ChangedFiles)); err != nil{
 		if err == sql.ErrNoRows{
 			return nil, fmt.Errorf("%s: %w", op, repo_errors.ErrPRNotFound)
 		}
 		return nil , fmt.Errorf("%s, QueryRow: %w", op, err)
 	}
 
-	rev,err := r.GetReviewers(ctx,id)
-	if err != nil{
+	if err := r.loadReviews(ctx, pr); err != nil{
 		return nil, fmt.Errorf("%s:%w",op,err)
 	}
-	pr.Reviewers = rev
 	return pr, nil
 }
 
@@ -107,55 +137,122 @@ func(r *PRRepository) GetReviewers(ctx context.Context, prID string)([]string,er
 	return rev, nil
 }
 
-func (r *PRRepository) Merge(ctx context.Context, id string, now time.Time) (*pullrequest.PullRequest, error) {
+func (r *PRRepository) GetReviews(ctx context.Context, prID string) ([]pullrequest.Review, error) {
+	const op = "internal.repository.postgres.pr_repo.GetReviews"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	SELECT user_id, state, state_updated_at
+	FROM pull_request_reviewers
+	WHERE pull_request_id = $1
+	ORDER BY created_at, user_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, q, prID)
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	reviews := []pullrequest.Review{}
+	for rows.Next() {
+		var rv pullrequest.Review
+		if err := rows.Scan(&rv.UserID, &rv.State, &rv.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		reviews = append(reviews, rv)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s, rows.Err: %w", op, err)
+	}
+	return reviews, nil
+}
+
+func (r *PRRepository) loadReviews(ctx context.Context, pr *pullrequest.PullRequest) error {
+	reviews, err := r.GetReviews(ctx, pr.ID)
+	if err != nil {
+		return err
+	}
+
+	pr.Reviews = reviews
+	pr.Reviewers = make([]string, 0, len(reviews))
+	for _, rv := range reviews {
+		pr.Reviewers = append(pr.Reviewers, rv.UserID)
+	}
+	return nil
+}
+
+// Merge moves an OPEN PR to MERGED. The events are stored only when the
+// status actually changed, so repeated merges do not emit them again.
+// A PR that was moved to another status concurrently gives ErrPRStatusChanged.
+func (r *PRRepository) Merge(ctx context.Context, id string, now time.Time, events ...event.Event) (*pullrequest.PullRequest, bool, error) {
 	const op = "internal.repository.postgres.pr_repo.Merge"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
 	const qUpdate = `
 		UPDATE pull_requests
 		SET status = $2, merged_at = COALESCE(merged_at, $3)
 		WHERE pull_request_id = $1 AND status = 'OPEN'
-		RETURNING pull_request_id, pull_request_name, author_id, status, created_at, merged_at;
+		RETURNING pull_request_id;
 	`
-	pr := &pullrequest.PullRequest{}
 
-	err := r.db.QueryRowContext(ctx, qUpdate, id, pullrequest.StatusMerged, now).Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt)
-	if err == nil {
-		rev,err := r.GetReviewers(ctx,pr.ID)
-		if err != nil{
-			return nil, fmt.Errorf("%s:%w",op,err)
+	var mergedID string
+	err = tx.QueryRowContext(ctx, qUpdate, id, pullrequest.StatusMerged, now).Scan(&mergedID)
+	merged := err == nil
+	switch {
+	case merged:
+		if err := recordEvents(ctx, tx, events); err != nil {
+			return nil, false, fmt.Errorf("%s: %w", op, err)
 		}
-		pr.Reviewers = rev
-		return pr, nil
+	case err != sql.ErrNoRows:
+		return nil, false, fmt.Errorf("%s, QueryRowContext: %w", op, err)
 	}
-	if err != sql.ErrNoRows {
-		return nil, fmt.Errorf("%s, QueryRowContext: %w", op, err)
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("%s: Commit: %w", op, err)
 	}
+
 	const qSelect = `
-		SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at
+		SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, closed_at
 		FROM pull_requests
 		WHERE pull_request_id = $1;
 	`
 
-	pr = &pullrequest.PullRequest{}
-	if err = r.db.QueryRowContext(ctx, qSelect, id).Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt); err != nil{
+	pr := &pullrequest.PullRequest{}
+	if err = r.db.QueryRowContext(ctx, qSelect, id).Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt); err != nil{
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("%s: %w", op, repo_errors.ErrPRNotFound)
+			return nil, false, fmt.Errorf("%s: %w", op, repo_errors.ErrPRNotFound)
 		}
-		return nil, fmt.Errorf("%s, QueryRowContext: %w", op, err)
+		return nil, false, fmt.Errorf("%s, QueryRowContext: %w", op, err)
 	}
-	rev,err := r.GetReviewers(ctx,pr.ID)
-	if err != nil{
-		return nil, fmt.Errorf("%s:%w",op,err)
+	if !merged && pr.Status != pullrequest.StatusMerged{
+		return nil, false, fmt.Errorf("%s: %w", op, repo_errors.ErrPRStatusChanged)
+	}
+	if err := r.loadReviews(ctx, pr); err != nil{
+		return nil, false, fmt.Errorf("%s:%w",op,err)
 	}
-	pr.Reviewers = rev
 
-	return pr, nil
+	return pr, merged, nil
 }
 
 
-func (r *PRRepository) ReplaceReviewers(ctx context.Context, prID, oldRevID, newRevID string) error {
+func (r *PRRepository) ReplaceReviewers(ctx context.Context, prID, oldRevID, newRevID string, events ...event.Event) error {
 	const op = "internal.repository.postgres.pr_repo.ReplaceReviewers"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("%s, BeginTx: %w", op, err)
@@ -206,6 +303,10 @@ func (r *PRRepository) ReplaceReviewers(ctx context.Context, prID, oldRevID, new
 		return fmt.Errorf("%s: Exec insert: %w", op, err)
 	}
 
+	if err := recordEvents(ctx, tx, events); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("%s: Commit: %w", op, err)
 	}
@@ -216,6 +317,9 @@ func (r *PRRepository) ReplaceReviewers(ctx context.Context, prID, oldRevID, new
 func (r *PRRepository) FindShortByReviewer(ctx context.Context, userID string)([]pullrequest.PullRequestShort, error){
 	const op = "internal.repository.postgres.pr_repo.FindShortByReviewer"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	const q = `
 	SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status
 	FROM pull_requests pr INNER JOIN pull_request_reviewers r 
@@ -249,6 +353,9 @@ func (r *PRRepository) FindShortByReviewer(ctx context.Context, userID string)([
 func (r *PRRepository) GetOpenPRIDsByReviewer(ctx context.Context, userID string) ([]string, error) {
 	const op = "internal.repository.postgres.pr_repo.GetOpenPRIDsByReviewer"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	const q = `
 		SELECT pr.pull_request_id
 		FROM pull_requests pr
@@ -279,9 +386,12 @@ func (r *PRRepository) GetOpenPRIDsByReviewer(ctx context.Context, userID string
 	return ids, nil
 }
 
-func (r *PRRepository) RemoveReviewer(ctx context.Context, prID, revID string) error {
+func (r *PRRepository) RemoveReviewer(ctx context.Context, prID, revID string, events ...event.Event) error {
 	const op = "internal.repository.postgres.pr_repo.RemoveReviewer"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("%s, BeginTx: %w", op, err)
@@ -324,9 +434,401 @@ func (r *PRRepository) RemoveReviewer(ctx context.Context, prID, revID string) e
 		return fmt.Errorf("%s: %w", op, repo_errors.ErrReviewersNotFound)
 	}
 
+	if err := recordEvents(ctx, tx, events); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: Commit: %w", op, err)
+	}
+
+	return nil
+}
+func (r *PRRepository) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	const op = "internal.repository.postgres.pr_repo.CountOpenReviews"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+		SELECT r.user_id, COUNT(*)
+		FROM pull_request_reviewers r
+		JOIN pull_requests pr
+			ON pr.pull_request_id = r.pull_request_id
+		WHERE r.user_id = ANY($1) AND pr.status = 'OPEN'
+		GROUP BY r.user_id;
+	`
+
+	rows, err := r.db.QueryContext(ctx, q, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	loads := make(map[string]int, len(userIDs))
+	for rows.Next() {
+		var uid string
+		var cnt int
+		if err := rows.Scan(&uid, &cnt); err != nil {
+			return nil, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		loads[uid] = cnt
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s, rows.Err: %w", op, err)
+	}
+
+	return loads, nil
+}
+
+func (r *PRRepository) OpenReviewsByTeam(ctx context.Context) (map[string]int, error) {
+	const op = "internal.repository.postgres.pr_repo.OpenReviewsByTeam"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+		SELECT u.team_name, COUNT(*)
+		FROM pull_request_reviewers r
+		JOIN pull_requests pr
+			ON pr.pull_request_id = r.pull_request_id
+		JOIN users u
+			ON u.user_id = r.user_id
+		WHERE pr.status = 'OPEN' AND u.team_name <> ''
+		GROUP BY u.team_name;
+	`
+
+	rows, err := r.db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var teamName string
+		var cnt int
+		if err := rows.Scan(&teamName, &cnt); err != nil {
+			return nil, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		counts[teamName] = cnt
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s, rows.Err: %w", op, err)
+	}
+
+	return counts, nil
+}
+
+func (r *PRRepository) SetReviewState(ctx context.Context, prID, userID string, state pullrequest.ReviewState, now time.Time) error {
+	const op = "internal.repository.postgres.pr_repo.SetReviewState"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	const qPR = `
+		SELECT status
+		FROM pull_requests
+		WHERE pull_request_id = $1
+		FOR UPDATE;
+	`
+
+	var status pullrequest.Status
+	if err := tx.QueryRowContext(ctx, qPR, prID).Scan(&status); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%s: %w", op, repo_errors.ErrPRNotFound)
+		}
+		return fmt.Errorf("%s, QueryRow PR: %w", op, err)
+	}
+
+	if status == pullrequest.StatusMerged {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrPRMerged)
+	}
+
+	const qUpd = `
+		UPDATE pull_request_reviewers
+		SET state = $3, state_updated_at = $4
+		WHERE pull_request_id = $1 AND user_id = $2;
+	`
+
+	res, err := tx.ExecContext(ctx, qUpd, prID, userID, state, now)
+	if err != nil {
+		return fmt.Errorf("%s, Exec update: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s, RowsAffected update: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrReviewersNotFound)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: Commit: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *PRRepository) MarkReady(ctx context.Context, prID string, reviewers []string, events ...event.Event) error {
+	const op = "internal.repository.postgres.pr_repo.MarkReady"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	const qUpd = `
+		UPDATE pull_requests
+		SET status = 'OPEN'
+		WHERE pull_request_id = $1 AND status = 'DRAFT';
+	`
+
+	if err := execTransition(ctx, tx, qUpd, prID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := insertReviewers(ctx, tx, prID, reviewers); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := recordEvents(ctx, tx, events); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: Commit: %w", op, err)
+	}
+	return nil
+}
+
+func (r *PRRepository) Close(ctx context.Context, prID string, now time.Time, events ...event.Event) error {
+	const op = "internal.repository.postgres.pr_repo.Close"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	const qUpd = `
+		UPDATE pull_requests
+		SET status = 'CLOSED', closed_at = $2
+		WHERE pull_request_id = $1 AND status IN ('OPEN', 'DRAFT');
+	`
+
+	if err := execTransition(ctx, tx, qUpd, prID, now); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	const qDel = `
+		DELETE FROM pull_request_reviewers
+		WHERE pull_request_id = $1;
+	`
+
+	if _, err := tx.ExecContext(ctx, qDel, prID); err != nil {
+		return fmt.Errorf("%s, Exec delete: %w", op, err)
+	}
+
+	if err := recordEvents(ctx, tx, events); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: Commit: %w", op, err)
+	}
+	return nil
+}
+
+func (r *PRRepository) Reopen(ctx context.Context, prID string, reviewers []string, events ...event.Event) error {
+	const op = "internal.repository.postgres.pr_repo.Reopen"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	const qUpd = `
+		UPDATE pull_requests
+		SET status = 'OPEN', closed_at = NULL
+		WHERE pull_request_id = $1 AND status = 'CLOSED';
+	`
+
+	if err := execTransition(ctx, tx, qUpd, prID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := insertReviewers(ctx, tx, prID, reviewers); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := recordEvents(ctx, tx, events); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("%s: Commit: %w", op, err)
 	}
+	return nil
+}
+
+// execTransition runs a conditional status update and reports
+// ErrPRNotFound or ErrPRStatusChanged when no row matched.
+func execTransition(ctx context.Context, tx *sql.Tx, q string, prID string, args ...any) error {
+	res, err := tx.ExecContext(ctx, q, append([]any{prID}, args...)...)
+	if err != nil {
+		return fmt.Errorf("Exec update: %w", err)
+	}
 
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("RowsAffected update: %w", err)
+	}
+	if affected > 0 {
+		return nil
+	}
+
+	const qExists = `SELECT 1 FROM pull_requests WHERE pull_request_id = $1`
+
+	var dummy int
+	if err := tx.QueryRowContext(ctx, qExists, prID).Scan(&dummy); err != nil {
+		if err == sql.ErrNoRows {
+			return repo_errors.ErrPRNotFound
+		}
+		return fmt.Errorf("QueryRow exists: %w", err)
+	}
+	return repo_errors.ErrPRStatusChanged
+}
+
+func insertReviewers(ctx context.Context, tx *sql.Tx, prID string, reviewers []string) error {
+	const qRev = `
+	INSERT INTO pull_request_reviewers (pull_request_id, user_id)
+	VALUES ($1, $2)
+	`
+
+	for _, reviewerID := range reviewers {
+		if _, err := tx.ExecContext(ctx, qRev, prID, reviewerID); err != nil {
+			return fmt.Errorf("Exec insert reviewer: %w", err)
+		}
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+// HandOver applies the prepared handovers and stores their events in one transaction.
+func (r *PRRepository) HandOver(ctx context.Context, handovers []assignment.Handover) error {
+	const op = "internal.repository.postgres.pr_repo.HandOver"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if len(handovers) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := applyHandovers(ctx, tx, handovers); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s, Commit: %w", op, err)
+	}
+	return nil
+}
+
+// applyHandovers applies prepared handovers in the caller's transaction. The PRs are
+// locked in id order first, so concurrent handovers of the same PRs cannot deadlock.
+func applyHandovers(ctx context.Context, tx *sql.Tx, handovers []assignment.Handover) error {
+	if len(handovers) == 0 {
+		return nil
+	}
+
+	prIDs := make([]string, 0, len(handovers))
+	for _, h := range handovers {
+		prIDs = append(prIDs, h.PullRequestID)
+	}
+	const qLock = `
+	SELECT pull_request_id, status
+	FROM pull_requests
+	WHERE pull_request_id = ANY($1)
+	ORDER BY pull_request_id
+	FOR UPDATE;
+	`
+	rows, err := tx.QueryContext(ctx, qLock, pq.Array(prIDs))
+	if err != nil {
+		return fmt.Errorf("Query lock PRs: %w", err)
+	}
+	statuses := make(map[string]pullrequest.Status, len(prIDs))
+	for rows.Next() {
+		var (
+			id     string
+			status pullrequest.Status
+		)
+		if err := rows.Scan(&id, &status); err != nil {
+			rows.Close()
+			return fmt.Errorf("Scan PR: %w", err)
+		}
+		statuses[id] = status
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows.Err: %w", err)
+	}
+
+	const qDel = `
+	DELETE FROM pull_request_reviewers
+	WHERE pull_request_id = $1 AND user_id = $2;
+	`
+	events := make([]event.Event, 0, len(handovers))
+	for _, h := range handovers {
+		status, ok := statuses[h.PullRequestID]
+		if !ok {
+			return repo_errors.ErrPRNotFound
+		}
+		if status == pullrequest.StatusMerged {
+			return repo_errors.ErrPRMerged
+		}
+		res, err := tx.ExecContext(ctx, qDel, h.PullRequestID, h.ReviewerID)
+		if err != nil {
+			return fmt.Errorf("Exec delete reviewer: %w", err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("RowsAffected delete reviewer: %w", err)
+		}
+		if affected == 0 {
+			return repo_errors.ErrReviewersNotFound
+		}
+		if h.NewReviewerID != "" {
+			if err := insertReviewers(ctx, tx, h.PullRequestID, []string{h.NewReviewerID}); err != nil {
+				return err
+			}
+		}
+		events = append(events, h.Event)
+	}
+	return recordEvents(ctx, tx, events)
+}
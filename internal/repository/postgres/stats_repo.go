@@ -2,84 +2,186 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"sort"
+	"time"
 
 	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	"github.com/hihikaAAa/PRManager/internal/repository"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
 )
 
-type PRStats struct{
-	TotalPR int
-	OpenPR int
-	MergedPR int
-}
-
-type ReviewerStat struct{
-	UserID string
-	Count int
-}
-
-func (r *PRRepository) GetStats(ctx context.Context) (PRStats, []ReviewerStat, error){
+// statsPRs selects the PRs matching the stats filter ($1 from, $2 to, $3 team)
+// with the current team of their author.
+const statsPRs = `
+	WITH prs AS (
+		SELECT p.pull_request_id, p.status, p.created_at, p.merged_at, COALESCE(u.team_name, '') AS team_name
+		FROM pull_requests p
+		JOIN users u ON u.user_id = p.author_id
+		WHERE ($1::timestamptz IS NULL OR p.created_at >= $1)
+		  AND ($2::timestamptz IS NULL OR p.created_at < $2)
+		  AND ($3::text = '' OR u.team_name = $3)
+	)
+`
+
+func (r *PRRepository) GetStats(ctx context.Context, f repository.StatsFilter) (repository.Stats, error){
 	const op = "internal.repository.postgres.stats_repo.GetStats"
 
-	stats := PRStats{}
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	args := []any{
+		sql.NullTime{Time: f.From, Valid: !f.From.IsZero()},
+		sql.NullTime{Time: f.To, Valid: !f.To.IsZero()},
+		f.TeamName,
+	}
+	stats := repository.Stats{}
+	teams := make(map[string]*repository.TeamStats)
+	team := func(name string) *repository.TeamStats{
+		if name == ""{
+			return nil
+		}
+		if teams[name] == nil{
+			teams[name] = &repository.TeamStats{TeamName: name}
+		}
+		return teams[name]
+	}
 
-	const qStatus = `
-	SELECT status, COUNT(*)
-	FROM pull_requests 
-	GROUP BY status;
+	const qStatus = statsPRs + `
+	SELECT team_name, status, COUNT(*)
+	FROM prs
+	GROUP BY team_name, status;
 	`
 
-	rows, err := r.db.QueryContext(ctx,qStatus)
+	rows, err := r.db.QueryContext(ctx, qStatus, args...)
 	if err != nil{
-		return stats, nil, fmt.Errorf("%s, QueryContext status: %w", op, err)
+		return stats, fmt.Errorf("%s, QueryContext status: %w", op, err)
 	}
 
 	defer rows.Close()
 
 	for rows.Next(){
+		var teamName string
 		var status pullrequest.Status
 		var cnt int
-		if err := rows.Scan(&status, &cnt); err != nil{
-			return stats, nil, fmt.Errorf("%s, Scan status: %w", op, err)
+		if err := rows.Scan(&teamName, &status, &cnt); err != nil{
+			return stats, fmt.Errorf("%s, Scan status: %w", op, err)
 		}
-		stats.TotalPR += cnt
-		switch status{
-			case pullrequest.StatusOpen:
-				stats.OpenPR = cnt
-			case pullrequest.StatusMerged:
-				stats.MergedPR = cnt
+		stats.AddStatus(status, cnt)
+		if ts := team(teamName); ts != nil{
+			ts.AddStatus(status, cnt)
 		}
 	}
 
 	if err := rows.Err(); err != nil{
-		return stats, nil, fmt.Errorf("%s, rowsErr status: %w", op, err)
+		return stats, fmt.Errorf("%s, rowsErr status: %w", op, err)
+	}
+
+	// The row with GROUPING(team_name) = 1 holds the median over all teams.
+	const qMedian = statsPRs + `
+	SELECT COALESCE(team_name, ''), GROUPING(team_name) = 1,
+		percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM merged_at - created_at)::float8)
+	FROM prs
+	WHERE status = 'MERGED' AND merged_at IS NOT NULL
+	GROUP BY GROUPING SETS ((team_name), ());
+	`
+
+	mRows, err := r.db.QueryContext(ctx, qMedian, args...)
+	if err != nil{
+		return stats, fmt.Errorf("%s, QueryContext median: %w", op, err)
+	}
+
+	defer mRows.Close()
+
+	for mRows.Next(){
+		var teamName string
+		var total bool
+		var seconds sql.NullFloat64
+		if err := mRows.Scan(&teamName, &total, &seconds); err != nil{
+			return stats, fmt.Errorf("%s, Scan median: %w", op, err)
+		}
+		median := time.Duration(seconds.Float64 * float64(time.Second))
+		if total{
+			stats.MedianTimeToMerge = median
+		} else if ts := team(teamName); ts != nil{
+			ts.MedianTimeToMerge = median
+		}
+	}
+
+	if err := mRows.Err(); err != nil{
+		return stats, fmt.Errorf("%s, rowsErr median: %w", op, err)
+	}
+
+	// Reassignments are counted by when they happened, on PRs of any age.
+	const qReassignments = `
+	SELECT COALESCE(u.team_name, ''), COUNT(*)
+	FROM assignment_events e
+	JOIN pull_requests p ON p.pull_request_id = e.pull_request_id
+	JOIN users u ON u.user_id = p.author_id
+	WHERE e.old_reviewer_id IS NOT NULL AND e.new_reviewer_id IS NOT NULL
+	  AND ($1::timestamptz IS NULL OR e.created_at >= $1)
+	  AND ($2::timestamptz IS NULL OR e.created_at < $2)
+	  AND ($3::text = '' OR u.team_name = $3)
+	GROUP BY 1;
+	`
+
+	aRows, err := r.db.QueryContext(ctx, qReassignments, args...)
+	if err != nil{
+		return stats, fmt.Errorf("%s, QueryContext reassignments: %w", op, err)
+	}
+
+	defer aRows.Close()
+
+	for aRows.Next(){
+		var teamName string
+		var cnt int
+		if err := aRows.Scan(&teamName, &cnt); err != nil{
+			return stats, fmt.Errorf("%s, Scan reassignments: %w", op, err)
+		}
+		stats.Reassignments += cnt
+		if ts := team(teamName); ts != nil{
+			ts.Reassignments = cnt
+		}
 	}
 
-	const qReviewers = `
-	SELECT user_id, COUNT(*)
-	FROM pull_request_reviewers
-	GROUP BY user_id
+	if err := aRows.Err(); err != nil{
+		return stats, fmt.Errorf("%s, rowsErr reassignments: %w", op, err)
+	}
+
+	for _, ts := range teams{
+		stats.Teams = append(stats.Teams, *ts)
+	}
+	sort.Slice(stats.Teams, func(i, j int) bool { return stats.Teams[i].TeamName < stats.Teams[j].TeamName })
+
+	const qReviewers = statsPRs + `
+	SELECT r.user_id, COUNT(*),
+		COUNT(*) FILTER (WHERE prs.status = 'OPEN'),
+		COUNT(*) FILTER (WHERE prs.status = 'MERGED')
+	FROM pull_request_reviewers r
+	JOIN prs ON prs.pull_request_id = r.pull_request_id
+	GROUP BY r.user_id
+	ORDER BY r.user_id;
 	`
 
-	rRows, err := r.db.QueryContext(ctx,qReviewers)
+	rRows, err := r.db.QueryContext(ctx, qReviewers, args...)
 	if err != nil{
-		return stats, nil, fmt.Errorf("%s, QueryContext reviewers: %w", op, err)
+		return stats, fmt.Errorf("%s, QueryContext reviewers: %w", op, err)
 	}
 
 	defer rRows.Close()
 
-	var reviewers []ReviewerStat
 	for rRows.Next(){
-		var s ReviewerStat
-		if err := rRows.Scan(&s.UserID, &s.Count); err != nil{
-			return stats, nil, fmt.Errorf("%s, Scan stats: %w", op, err)
+		var s repository.ReviewerStat
+		if err := rRows.Scan(&s.UserID, &s.Count, &s.Open, &s.Completed); err != nil{
+			return stats, fmt.Errorf("%s, Scan reviewers: %w", op, err)
 		}
-		reviewers = append(reviewers, s)
+		stats.Reviewers = append(stats.Reviewers, s)
 	}
 
 	if err := rRows.Err(); err != nil{
-		return stats, nil, fmt.Errorf("%s, rowsErr stats: %w", op, err)
+		return stats, fmt.Errorf("%s, rowsErr reviewers: %w", op, err)
 	}
 
-	return stats, reviewers, nil
-}
\ No newline at end of file
+	return stats, nil
+}
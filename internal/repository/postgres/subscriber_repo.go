@@ -0,0 +1,297 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	"github.com/hihikaAAa/PRManager/internal/domain/subscriber"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type SubscriberRepository struct {
+	db *sql.DB
+}
+
+func NewSubscriberRepository(db *sql.DB) *SubscriberRepository {
+	return &SubscriberRepository{db: db}
+}
+
+func (r *SubscriberRepository) Create(ctx context.Context, s subscriber.Subscriber) (subscriber.Subscriber, error) {
+	const op = "internal.repository.postgres.subscriber_repo.Create"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	INSERT INTO webhook_subscribers (url, secret, events, is_active)
+	VALUES ($1, $2, $3, TRUE)
+	RETURNING id, is_active, created_at;
+	`
+
+	err := r.db.QueryRowContext(ctx, q, s.URL, s.Secret, pq.Array(typesToStrings(s.Events))).
+		Scan(&s.ID, &s.IsActive, &s.CreatedAt)
+	if err != nil {
+		return subscriber.Subscriber{}, fmt.Errorf("%s, QueryRow: %w", op, err)
+	}
+	return s, nil
+}
+
+// List returns all subscribers ordered by id, secrets included.
+func (r *SubscriberRepository) List(ctx context.Context) ([]subscriber.Subscriber, error) {
+	const op = "internal.repository.postgres.subscriber_repo.List"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	SELECT id, url, secret, events, is_active, created_at
+	FROM webhook_subscribers
+	ORDER BY id;
+	`
+
+	rows, err := r.db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	var res []subscriber.Subscriber
+	for rows.Next() {
+		var (
+			s      subscriber.Subscriber
+			events []string
+		)
+		if err := rows.Scan(&s.ID, &s.URL, &s.Secret, pq.Array(&events), &s.IsActive, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		for _, e := range events {
+			s.Events = append(s.Events, event.Type(e))
+		}
+		res = append(res, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s, rows: %w", op, err)
+	}
+	return res, nil
+}
+
+func (r *SubscriberRepository) Delete(ctx context.Context, id int64) error {
+	const op = "internal.repository.postgres.subscriber_repo.Delete"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `DELETE FROM webhook_subscribers WHERE id = $1`
+
+	res, err := r.db.ExecContext(ctx, q, id)
+	if err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s, RowsAffected: %w", op, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrSubscriberNotFound)
+	}
+	return nil
+}
+
+func (r *SubscriberRepository) SaveDeadLetter(ctx context.Context, dl subscriber.DeadLetter) error {
+	const op = "internal.repository.postgres.subscriber_repo.SaveDeadLetter"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	INSERT INTO webhook_dead_letters (subscriber_id, event_id, event_type, payload, attempts, last_error)
+	VALUES ($1, $2, $3, $4, $5, $6);
+	`
+
+	_, err := r.db.ExecContext(ctx, q, dl.SubscriberID, dl.EventID, string(dl.EventType), dl.Payload, dl.Attempts, dl.LastError)
+	if err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	return nil
+}
+
+func (r *SubscriberRepository) ScheduleRetry(ctx context.Context, rt subscriber.Retry) error {
+	const op = "internal.repository.postgres.subscriber_repo.ScheduleRetry"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	INSERT INTO webhook_retries (subscriber_id, event_id, event_type, payload, attempts, last_error, next_attempt_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (subscriber_id, event_id) DO NOTHING;
+	`
+
+	_, err := r.db.ExecContext(ctx, q, rt.SubscriberID, rt.EventID, string(rt.EventType), rt.Payload, rt.Attempts, rt.LastError, rt.NextAttemptAt)
+	if err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	return nil
+}
+
+func (r *SubscriberRepository) RetryingSubscribers(ctx context.Context) ([]int64, error) {
+	const op = "internal.repository.postgres.subscriber_repo.RetryingSubscribers"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT DISTINCT subscriber_id FROM webhook_retries ORDER BY subscriber_id;`)
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s, rows: %w", op, err)
+	}
+	return ids, nil
+}
+
+func (r *SubscriberRepository) ClaimDueRetries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]subscriber.Retry, error) {
+	const op = "internal.repository.postgres.subscriber_repo.ClaimDueRetries"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	// Locking the subscribers keeps a concurrent claim from taking a later delivery
+	// of a subscriber whose earlier one is being claimed here.
+	const q = `
+	UPDATE webhook_retries
+	SET next_attempt_at = $3
+	WHERE id IN (
+		SELECT r.id
+		FROM webhook_retries r
+		WHERE r.next_attempt_at <= $1
+		  AND NOT EXISTS (
+			SELECT 1
+			FROM webhook_retries e
+			WHERE e.subscriber_id = r.subscriber_id AND e.id < r.id AND e.next_attempt_at > $1
+		  )
+		  AND r.subscriber_id IN (
+			SELECT s.id
+			FROM webhook_subscribers s
+			WHERE s.id IN (SELECT subscriber_id FROM webhook_retries WHERE next_attempt_at <= $1)
+			FOR UPDATE SKIP LOCKED
+		  )
+		ORDER BY r.id
+		LIMIT $2
+		FOR UPDATE OF r SKIP LOCKED
+	)
+	RETURNING id, subscriber_id, event_id, event_type, payload, attempts, last_error, next_attempt_at;
+	`
+
+	rows, err := r.db.QueryContext(ctx, q, now, limit, now.Add(lease))
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	var res []subscriber.Retry
+	for rows.Next() {
+		var (
+			rt  subscriber.Retry
+			typ string
+		)
+		if err := rows.Scan(&rt.ID, &rt.SubscriberID, &rt.EventID, &typ, &rt.Payload, &rt.Attempts, &rt.LastError, &rt.NextAttemptAt); err != nil {
+			return nil, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		rt.EventType = event.Type(typ)
+		res = append(res, rt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s, rows: %w", op, err)
+	}
+
+	// RETURNING does not keep the subquery order.
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res, nil
+}
+
+func (r *SubscriberRepository) RescheduleRetry(ctx context.Context, rt subscriber.Retry) error {
+	const op = "internal.repository.postgres.subscriber_repo.RescheduleRetry"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	UPDATE webhook_retries
+	SET attempts = $2, last_error = $3, next_attempt_at = $4
+	WHERE id = $1;
+	`
+
+	if _, err := r.db.ExecContext(ctx, q, rt.ID, rt.Attempts, rt.LastError, rt.NextAttemptAt); err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	return nil
+}
+
+func (r *SubscriberRepository) DeleteRetry(ctx context.Context, id int64) error {
+	const op = "internal.repository.postgres.subscriber_repo.DeleteRetry"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM webhook_retries WHERE id = $1;`, id); err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	return nil
+}
+
+func (r *SubscriberRepository) DeadLetterRetry(ctx context.Context, rt subscriber.Retry) error {
+	const op = "internal.repository.postgres.subscriber_repo.DeadLetterRetry"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	const qInsert = `
+	INSERT INTO webhook_dead_letters (subscriber_id, event_id, event_type, payload, attempts, last_error)
+	SELECT subscriber_id, event_id, event_type, payload, $2, $3
+	FROM webhook_retries
+	WHERE id = $1;
+	`
+
+	if _, err := tx.ExecContext(ctx, qInsert, rt.ID, rt.Attempts, rt.LastError); err != nil {
+		return fmt.Errorf("%s, Exec insert: %w", op, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_retries WHERE id = $1;`, rt.ID); err != nil {
+		return fmt.Errorf("%s, Exec delete: %w", op, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s, Commit: %w", op, err)
+	}
+	return nil
+}
+
+func typesToStrings(types []event.Type) []string {
+	out := make([]string, 0, len(types))
+	for _, t := range types {
+		out = append(out, string(t))
+	}
+	return out
+}
@@ -5,8 +5,12 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/lib/pq"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
 	"github.com/hihikaAAa/PRManager/internal/domain/user"
-	"github.com/hihikaAAa/PRManager/internal/repository/postgres/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
 )
 
 type UserRepository struct{
@@ -20,6 +24,9 @@ func NewUserRepository(db *sql.DB) *UserRepository{
 func (r *UserRepository) UpsertManyForTeam(ctx context.Context,teamName string, users []*user.User) error{
 	const op = "internal.repository.postgres.user_repo.UpsertManyForTeam"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	tx, err := r.db.BeginTx(ctx,nil)
 	if err != nil{
 		return fmt.Errorf("%s, BeginTx: %w", op,err)
@@ -56,14 +63,17 @@ func (r *UserRepository) UpsertManyForTeam(ctx context.Context,teamName string,
 func (r *UserRepository) GetByID(ctx context.Context, id string)(*user.User, error){
 	const op = "internal.repository.postgres.user_repo.GetByID"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	const q = `
-	SELECT user_id, username, team_name, is_active
+	SELECT user_id, username, COALESCE(team_name, ''), is_active, max_open_reviews
 	FROM users
 	WHERE user_id = $1;
 	`
 
 	u := &user.User{}
-	err := r.db.QueryRowContext(ctx,q,id).Scan(&u.ID,&u.Name,&u.TeamName,&u.IsActive)
+	err := r.db.QueryRowContext(ctx,q,id).Scan(&u.ID,&u.Name,&u.TeamName,&u.IsActive,&u.MaxOpenReviews)
 	if err == sql.ErrNoRows{
 		return nil, fmt.Errorf("%s: %w",op,repo_errors.ErrUserNotFound)
 	}
@@ -76,15 +86,104 @@ func (r *UserRepository) GetByID(ctx context.Context, id string)(*user.User, err
 func (r *UserRepository) SetIsActive(ctx context.Context, id string, active bool)(*user.User, error){
 	const op = "internal.repository.postgres.user_repo.SetIsActive"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	const q = `
 		UPDATE users
 		SET is_active = $2, updated_at = now()
 		WHERE user_id = $1
-		RETURNING user_id,username,team_name,is_active;
+		RETURNING user_id,username,COALESCE(team_name, ''),is_active,max_open_reviews;
 	`
 
 	u := &user.User{}
-	err  := r.db.QueryRowContext(ctx,q,id,active).Scan(&u.ID,&u.Name,&u.TeamName,&u.IsActive)
+	err  := r.db.QueryRowContext(ctx,q,id,active).Scan(&u.ID,&u.Name,&u.TeamName,&u.IsActive,&u.MaxOpenReviews)
+	if err == sql.ErrNoRows{
+		return nil, fmt.Errorf("%s: %w",op,repo_errors.ErrUserNotFound)
+	}
+	if err !=nil{
+		return nil, fmt.Errorf("%s, QueryRow: %w", op, err)
+	}
+	return u, nil
+}
+
+func (r *UserRepository) Deactivate(ctx context.Context, userIDs []string, handovers ...assignment.Handover) error {
+	const op = "internal.repository.postgres.user_repo.Deactivate"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	const q = `
+	UPDATE users
+	SET is_active = false, updated_at = now()
+	WHERE user_id = ANY($1);
+	`
+
+	res, err := tx.ExecContext(ctx, q, pq.Array(userIDs))
+	if err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s, RowsAffected: %w", op, err)
+	}
+	if int(affected) < countDistinct(userIDs) {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrUserNotFound)
+	}
+	if err := applyHandovers(ctx, tx, handovers); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s, Commit: %w", op, err)
+	}
+	return nil
+}
+
+func (r *UserRepository) SetMaxOpenReviews(ctx context.Context, id string, max int)(*user.User, error){
+	const op = "internal.repository.postgres.user_repo.SetMaxOpenReviews"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+		UPDATE users
+		SET max_open_reviews = $2, updated_at = now()
+		WHERE user_id = $1
+		RETURNING user_id,username,COALESCE(team_name, ''),is_active,max_open_reviews;
+	`
+
+	u := &user.User{}
+	err  := r.db.QueryRowContext(ctx,q,id,max).Scan(&u.ID,&u.Name,&u.TeamName,&u.IsActive,&u.MaxOpenReviews)
+	if err == sql.ErrNoRows{
+		return nil, fmt.Errorf("%s: %w",op,repo_errors.ErrUserNotFound)
+	}
+	if err !=nil{
+		return nil, fmt.Errorf("%s, QueryRow: %w", op, err)
+	}
+	return u, nil
+}
+
+func (r *UserRepository) SetTeam(ctx context.Context, id, teamName string)(*user.User, error){
+	const op = "internal.repository.postgres.user_repo.SetTeam"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+		UPDATE users
+		SET team_name = NULLIF($2, ''), updated_at = now()
+		WHERE user_id = $1
+		RETURNING user_id,username,COALESCE(team_name, ''),is_active,max_open_reviews;
+	`
+
+	u := &user.User{}
+	err  := r.db.QueryRowContext(ctx,q,id,teamName).Scan(&u.ID,&u.Name,&u.TeamName,&u.IsActive,&u.MaxOpenReviews)
 	if err == sql.ErrNoRows{
 		return nil, fmt.Errorf("%s: %w",op,repo_errors.ErrUserNotFound)
 	}
@@ -97,10 +196,17 @@ func (r *UserRepository) SetIsActive(ctx context.Context, id string, active bool
 func (r *UserRepository) FindActiveByTeamExcept(ctx context.Context, teamName string, excluded []string ) ([]*user.User,error){
 	const op = "internal.repository.postgres.user_repo.FindActiveByTeamExceptAuthor"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	const q = `
-	SELECT user_id, username, team_name, is_active
-	FROM users
+	SELECT user_id, username, team_name, is_active, max_open_reviews
+	FROM users u
 	WHERE team_name = $1 AND is_active = $2
+	AND NOT EXISTS (
+		SELECT 1 FROM user_absences a
+		WHERE a.user_id = u.user_id AND a.starts_at <= now() AND a.ends_at > now()
+	)
 	`
 
 	rows, err := r.db.QueryContext(ctx, q, teamName, true)
@@ -112,7 +218,7 @@ func (r *UserRepository) FindActiveByTeamExcept(ctx context.Context, teamName st
 	cands := make([]*user.User,0)
 	for rows.Next(){
 		user := &user.User{}
-		err := rows.Scan(&user.ID, &user.Name, &user.TeamName, &user.IsActive)
+		err := rows.Scan(&user.ID, &user.Name, &user.TeamName, &user.IsActive, &user.MaxOpenReviews)
 		if err != nil{
 			return nil, fmt.Errorf("%s, Scan: %w", op, err)
 		}
@@ -136,4 +242,55 @@ func checkIfExcluded(ID string, excluded []string) bool{
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+func (r *UserRepository) LinkIdentity(ctx context.Context, provider, login, userID string) error {
+	const op = "internal.repository.postgres.user_repo.LinkIdentity"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+		INSERT INTO user_identities (provider, login, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (provider, login)
+		DO UPDATE SET user_id = EXCLUDED.user_id;
+	`
+
+	if _, err := r.db.ExecContext(ctx, q, provider, login, userID); err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	return nil
+}
+
+func (r *UserRepository) FindByIdentity(ctx context.Context, provider, login string) (*user.User, error) {
+	const op = "internal.repository.postgres.user_repo.FindByIdentity"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	SELECT u.user_id, u.username, COALESCE(u.team_name, ''), u.is_active, u.max_open_reviews
+	FROM user_identities i
+	JOIN users u ON u.user_id = i.user_id
+	WHERE i.provider = $1 AND i.login = $2;
+	`
+
+	u := &user.User{}
+	err := r.db.QueryRowContext(ctx, q, provider, login).Scan(&u.ID, &u.Name, &u.TeamName, &u.IsActive, &u.MaxOpenReviews)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%s: %w", op, repo_errors.ErrIdentityNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryRow: %w", op, err)
+	}
+	return u, nil
+}
+
+// countDistinct returns the number of distinct ids.
+func countDistinct(ids []string) int {
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		seen[id] = struct{}{}
+	}
+	return len(seen)
+}
@@ -0,0 +1,16 @@
+package repo_errors
+
+import "errors"
+
+var (
+	ErrUserNotFound = errors.New("users not found")
+	ErrTeamNotFound = errors.New("team not found")
+	ErrParentTeamNotFound = errors.New("parent team not found")
+	ErrReviewersNotFound = errors.New("reviewers not found")
+	ErrPRNotFound = errors.New("pr not found")
+	ErrPRMerged = errors.New("pull request already merged")
+	ErrIdentityNotFound = errors.New("identity not found")
+	ErrSubscriberNotFound = errors.New("subscriber not found")
+	ErrPRStatusChanged = errors.New("pull request status changed concurrently")
+	ErrAbsenceNotFound = errors.New("absence not found")
+)
\ No newline at end of file
@@ -0,0 +1,220 @@
+// Package repository declares the storage contract implemented by every backend
+// (postgres, sqlite, memory). Services depend on narrower interfaces of their own.
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	"github.com/hihikaAAa/PRManager/internal/domain/subscriber"
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+)
+
+// PRRepository stores pull requests with their reviewers.
+// Methods taking events store them (outbox and assignment history) atomically with the change.
+type PRRepository interface {
+	CreateWithReviewers(ctx context.Context, pr pullrequest.PullRequest, events ...event.Event) error
+	// CreateManyWithReviewers stores all the PRs in one transaction, or none of them.
+	CreateManyWithReviewers(ctx context.Context, prs []pullrequest.PullRequest, events ...event.Event) error
+	GetWithReviewers(ctx context.Context, id string) (*pullrequest.PullRequest, error)
+	// Merge moves an OPEN PR to MERGED and reports whether this call did it. A PR
+	// that is already MERGED is returned as is; any other status gives ErrPRStatusChanged.
+	Merge(ctx context.Context, id string, now time.Time, events ...event.Event) (*pullrequest.PullRequest, bool, error)
+	ReplaceReviewers(ctx context.Context, prID, oldRevID, newRevID string, events ...event.Event) error
+	RemoveReviewer(ctx context.Context, prID, revID string, events ...event.Event) error
+	// HandOver applies the prepared reviewer handovers with their events in one transaction.
+	// It fails with ErrReviewersNotFound when a reviewer was unassigned meanwhile.
+	HandOver(ctx context.Context, handovers []assignment.Handover) error
+	FindShortByReviewer(ctx context.Context, userID string) ([]pullrequest.PullRequestShort, error)
+	GetOpenPRIDsByReviewer(ctx context.Context, userID string) ([]string, error)
+	CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error)
+	// OpenReviewsByTeam counts the reviews of OPEN PRs by the team of the reviewer.
+	OpenReviewsByTeam(ctx context.Context) (map[string]int, error)
+	SetReviewState(ctx context.Context, prID, userID string, state pullrequest.ReviewState, now time.Time) error
+	MarkReady(ctx context.Context, prID string, reviewers []string, events ...event.Event) error
+	Close(ctx context.Context, prID string, now time.Time, events ...event.Event) error
+	Reopen(ctx context.Context, prID string, reviewers []string, events ...event.Event) error
+	HistoryByPR(ctx context.Context, prID string) ([]assignment.Change, error)
+	HistoryByUser(ctx context.Context, userID string) ([]assignment.Change, error)
+	// GetStats aggregates the PRs selected by the filter.
+	GetStats(ctx context.Context, f StatsFilter) (Stats, error)
+}
+
+type UserRepository interface {
+	// UpsertManyForTeam keeps the review capacity of existing users.
+	UpsertManyForTeam(ctx context.Context, teamName string, users []*user.User) error
+	GetByID(ctx context.Context, id string) (*user.User, error)
+	SetIsActive(ctx context.Context, id string, active bool) (*user.User, error)
+	// Deactivate deactivates the users and applies the handovers of their reviews
+	// (see PRRepository.HandOver) in one transaction.
+	Deactivate(ctx context.Context, userIDs []string, handovers ...assignment.Handover) error
+	// SetMaxOpenReviews sets the user's review capacity; 0 removes the limit.
+	SetMaxOpenReviews(ctx context.Context, id string, max int) (*user.User, error)
+	// SetTeam moves the user to the team; an empty name leaves the user without a team.
+	SetTeam(ctx context.Context, id, teamName string) (*user.User, error)
+	// FindActiveByTeamExcept returns the active team members who are not away right now.
+	FindActiveByTeamExcept(ctx context.Context, teamName string, excluded []string) ([]*user.User, error)
+	LinkIdentity(ctx context.Context, provider, login, userID string) error
+	FindByIdentity(ctx context.Context, provider, login string) (*user.User, error)
+}
+
+type TeamRepository interface {
+	CreateTeam(ctx context.Context, name string) error
+	Exists(ctx context.Context, name string) (bool, error)
+	GetWithMembers(ctx context.Context, name string) (*team.Team, error)
+	GetSettings(ctx context.Context, name string) (team.Settings, error)
+	UpsertSettings(ctx context.Context, s team.Settings) error
+	// GetCodeOwners returns the team's ownership rules in order.
+	GetCodeOwners(ctx context.Context, name string) (team.CodeOwners, error)
+	// SetCodeOwners replaces the team's ownership rules.
+	SetCodeOwners(ctx context.Context, c team.CodeOwners) error
+	// List returns all teams with their parents, without members, ordered by name.
+	List(ctx context.Context) ([]team.Team, error)
+	// SetParent moves the team under parent; an empty parent makes it a root.
+	// The cycle check and the move are atomic: a parent that is the team or one of
+	// its sub-teams gives team.ErrInvalidParent, a missing one ErrParentTeamNotFound.
+	SetParent(ctx context.Context, name, parent string) error
+	// Ancestors returns the parent chain of the team, nearest first.
+	Ancestors(ctx context.Context, name string) ([]string, error)
+	// Rename changes the team name everywhere it is referenced, in one transaction.
+	Rename(ctx context.Context, oldName, newName string) error
+	// Delete removes the team, its settings and the fallback references to it in one
+	// transaction. Members move to target, or are left without a team and deactivated
+	// when target is empty. The handovers are applied in the same transaction; it fails
+	// with ErrReviewersNotFound if one of them no longer applies. It returns the ids of
+	// the affected members.
+	Delete(ctx context.Context, name, target string, handovers ...assignment.Handover) ([]string, error)
+}
+
+type SubscriberRepository interface {
+	Create(ctx context.Context, s subscriber.Subscriber) (subscriber.Subscriber, error)
+	List(ctx context.Context) ([]subscriber.Subscriber, error)
+	Delete(ctx context.Context, id int64) error
+	SaveDeadLetter(ctx context.Context, dl subscriber.DeadLetter) error
+	// ScheduleRetry queues a delivery; a delivery already queued for the subscriber is kept.
+	ScheduleRetry(ctx context.Context, rt subscriber.Retry) error
+	// RetryingSubscribers returns the ids of subscribers with queued deliveries.
+	RetryingSubscribers(ctx context.Context) ([]int64, error)
+	// ClaimDueRetries returns up to limit deliveries due at now, oldest first,
+	// and moves their next attempt lease past now so no other worker claims them.
+	// A delivery is not claimed while an earlier one of its subscriber is not due,
+	// so that every subscriber gets its events in order.
+	ClaimDueRetries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]subscriber.Retry, error)
+	// RescheduleRetry stores Attempts, LastError and NextAttemptAt of a queued delivery.
+	RescheduleRetry(ctx context.Context, rt subscriber.Retry) error
+	DeleteRetry(ctx context.Context, id int64) error
+	// DeadLetterRetry moves a queued delivery to the dead letters with its Attempts and LastError.
+	DeadLetterRetry(ctx context.Context, rt subscriber.Retry) error
+}
+
+// AbsenceRepository stores out-of-office windows of users.
+type AbsenceRepository interface {
+	Create(ctx context.Context, a user.Absence) (user.Absence, error)
+	Get(ctx context.Context, id int64) (user.Absence, error)
+	ListByUser(ctx context.Context, userID string) ([]user.Absence, error)
+	Delete(ctx context.Context, id int64) error
+	// ListStarted returns up to limit absences in progress at now whose reviews were not handed over yet,
+	// leaving out those whose failed handover is not due again.
+	ListStarted(ctx context.Context, now time.Time, limit int) ([]user.Absence, error)
+	MarkHandled(ctx context.Context, id int64, now time.Time) error
+	// MarkFailed counts a failed handover and puts the next one off until next.
+	MarkFailed(ctx context.Context, id int64, next time.Time) error
+}
+
+// OutboxRepository hands stored events to the relay.
+type OutboxRepository interface {
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]event.Event, error)
+	MarkPublished(ctx context.Context, eventIDs []string) error
+	// MarkFailed records a failed attempt. Once the event failed maxAttempts times it is
+	// dead-lettered: kept in the outbox but never claimed again; dead reports that.
+	MarkFailed(ctx context.Context, eventID string, cause error, maxAttempts int) (dead bool, err error)
+	// PrunePublished deletes up to limit events published more than retention ago.
+	PrunePublished(ctx context.Context, retention time.Duration, limit int) (int, error)
+}
+
+// Repositories is a complete storage backend.
+type Repositories struct {
+	PRs         PRRepository
+	Users       UserRepository
+	Teams       TeamRepository
+	Subscribers SubscriberRepository
+	Outbox      OutboxRepository
+	Absences    AbsenceRepository
+}
+
+// StatsFilter selects the PRs created in [From, To) by a member of TeamName, and the
+// reassignments made in [From, To) on PRs of members of TeamName. Zero values do not restrict.
+type StatsFilter struct {
+	From     time.Time
+	To       time.Time
+	TeamName string
+}
+
+type Stats struct {
+	PRStats
+	// Teams breaks the PRs down by the current team of the author; PRs of authors
+	// without a team count only in the totals.
+	Teams     []TeamStats
+	Reviewers []ReviewerStat
+}
+
+type PRStats struct {
+	TotalPR  int
+	OpenPR   int
+	MergedPR int
+	DraftPR  int
+	ClosedPR int
+	// MedianTimeToMerge is taken over the merged PRs; zero if there are none.
+	MedianTimeToMerge time.Duration
+	// Reassignments counts reviewers replaced by another one, whatever the reason,
+	// within the period of the filter whenever the PR was created.
+	Reassignments int
+}
+
+// AddStatus counts n PRs in the status.
+func (s *PRStats) AddStatus(status pullrequest.Status, n int) {
+	s.TotalPR += n
+	switch status {
+	case pullrequest.StatusOpen:
+		s.OpenPR += n
+	case pullrequest.StatusMerged:
+		s.MergedPR += n
+	case pullrequest.StatusDraft:
+		s.DraftPR += n
+	case pullrequest.StatusClosed:
+		s.ClosedPR += n
+	}
+}
+
+type TeamStats struct {
+	TeamName string
+	PRStats
+}
+
+// ReviewerStat counts the reviews of the selected PRs. Reviews of OPEN PRs are open,
+// reviews of MERGED PRs are completed; closed PRs keep no reviewers.
+type ReviewerStat struct {
+	UserID    string
+	Count     int
+	Open      int
+	Completed int
+}
+
+// MedianDuration returns the median of ds, the mean of the middle two for an even
+// count, and zero for none. ds is sorted in place.
+func MedianDuration(ds []time.Duration) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+	mid := len(ds) / 2
+	if len(ds)%2 == 1 {
+		return ds[mid]
+	}
+	return ds[mid-1] + (ds[mid]-ds[mid-1])/2
+}
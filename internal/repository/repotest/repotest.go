@@ -0,0 +1,1480 @@
+// Package repotest is the contract test suite every storage backend must pass.
+package repotest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	"github.com/hihikaAAa/PRManager/internal/domain/subscriber"
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/lib/actor"
+	"github.com/hihikaAAa/PRManager/internal/repository"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+// Factory returns repositories over empty storage. It is called once per test case.
+type Factory func(t *testing.T) repository.Repositories
+
+// Run runs the contract suite against the backend.
+func Run(t *testing.T, newRepos Factory) {
+	cases := []struct {
+		name string
+		fn   func(t *testing.T, r repository.Repositories)
+	}{
+		{"Teams", testTeams},
+		{"TeamSettings", testTeamSettings},
+		{"RenameAndDeleteTeam", testRenameAndDeleteTeam},
+		{"TeamHierarchy", testTeamHierarchy},
+		{"CodeOwners", testCodeOwners},
+		{"Users", testUsers},
+		{"SetTeam", testSetTeam},
+		{"Identities", testIdentities},
+		{"ReviewCapacity", testReviewCapacity},
+		{"Absences", testAbsences},
+		{"CreateAndGetPR", testCreateAndGetPR},
+		{"CreateMany", testCreateMany},
+		{"ReviewerQueries", testReviewerQueries},
+		{"ReplaceAndRemoveReviewers", testReplaceAndRemoveReviewers},
+		{"HandOver", testHandOver},
+		{"ReviewState", testReviewState},
+		{"Merge", testMerge},
+		{"Lifecycle", testLifecycle},
+		{"History", testHistory},
+		{"FailedChangeRecordsNothing", testFailedChangeRecordsNothing},
+		{"Stats", testStats},
+		{"StatsFilter", testStatsFilter},
+		{"Outbox", testOutbox},
+		{"Subscribers", testSubscribers},
+		{"SubscriberRetries", testSubscriberRetries},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.fn(t, newRepos(t))
+		})
+	}
+}
+
+// now is truncated to what every backend can store.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// seed creates team "backend" (u1-u4 active, u5 inactive) and team "frontend" (f1).
+func seed(t *testing.T, r repository.Repositories) {
+	t.Helper()
+	ctx := context.Background()
+
+	mustNoErr(t, r.Teams.CreateTeam(ctx, "backend"))
+	mustNoErr(t, r.Teams.CreateTeam(ctx, "frontend"))
+	mustNoErr(t, r.Users.UpsertManyForTeam(ctx, "backend", []*user.User{
+		{ID: "u1", Name: "Alice", IsActive: true},
+		{ID: "u2", Name: "Bob", IsActive: true},
+		{ID: "u3", Name: "Carol", IsActive: true},
+		{ID: "u4", Name: "Dave", IsActive: true},
+		{ID: "u5", Name: "Eve", IsActive: false},
+	}))
+	mustNoErr(t, r.Users.UpsertManyForTeam(ctx, "frontend", []*user.User{
+		{ID: "f1", Name: "Frank", IsActive: true},
+	}))
+}
+
+func createPR(t *testing.T, r repository.Repositories, id string, status pullrequest.Status, reviewers ...string) {
+	t.Helper()
+	pr := pullrequest.PullRequest{ID: id, Name: "PR " + id, AuthorID: "u1", Status: status, CreatedAt: now(), Reviewers: reviewers}
+	mustNoErr(t, r.PRs.CreateWithReviewers(context.Background(), pr))
+}
+
+func testTeams(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+
+	ok, err := r.Teams.Exists(ctx, "backend")
+	mustNoErr(t, err)
+	if ok {
+		t.Fatal("team exists in empty storage")
+	}
+	if _, err := r.Teams.GetWithMembers(ctx, "backend"); !errors.Is(err, repo_errors.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+
+	seed(t, r)
+
+	ok, err = r.Teams.Exists(ctx, "backend")
+	mustNoErr(t, err)
+	if !ok {
+		t.Fatal("created team does not exist")
+	}
+	if err := r.Teams.CreateTeam(ctx, "backend"); err == nil {
+		t.Fatal("expected an error creating a duplicate team")
+	}
+
+	tm, err := r.Teams.GetWithMembers(ctx, "backend")
+	mustNoErr(t, err)
+	if tm.TeamName != "backend" {
+		t.Fatalf("unexpected team name %q", tm.TeamName)
+	}
+	ids := make([]string, 0, len(tm.Members))
+	for _, m := range tm.Members {
+		ids = append(ids, m.ID)
+		if m.TeamName != "backend" {
+			t.Fatalf("member %s has team %q", m.ID, m.TeamName)
+		}
+	}
+	assertSet(t, "members", ids, []string{"u1", "u2", "u3", "u4", "u5"})
+}
+
+func testTeamSettings(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+
+	if _, err := r.Teams.GetSettings(ctx, "backend"); !errors.Is(err, repo_errors.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+
+	seed(t, r)
+	mustNoErr(t, r.Teams.CreateTeam(ctx, "docs"))
+
+	s, err := r.Teams.GetSettings(ctx, "backend")
+	mustNoErr(t, err)
+	if s.TeamName != "backend" || s.ReviewersRequired != team.DefaultReviewersRequired || s.MinReviewers != team.DefaultMinReviewers ||
+		s.Strategy != "" || s.RequiredApprovals != 0 || len(s.FallbackTeams) != 0 {
+		t.Fatalf("unexpected default settings: %+v", s)
+	}
+
+	want := team.Settings{
+		TeamName: "backend", ReviewersRequired: 3, MinReviewers: 1, Strategy: "least_loaded",
+		FallbackTeams: []string{"frontend", "docs"}, RequiredApprovals: 2,
+	}
+	mustNoErr(t, r.Teams.UpsertSettings(ctx, want))
+	s, err = r.Teams.GetSettings(ctx, "backend")
+	mustNoErr(t, err)
+	if s.ReviewersRequired != 3 || s.MinReviewers != 1 || s.Strategy != "least_loaded" || s.RequiredApprovals != 2 {
+		t.Fatalf("unexpected settings: %+v", s)
+	}
+	assertOrdered(t, "fallback teams", s.FallbackTeams, []string{"frontend", "docs"})
+
+	want.FallbackTeams = []string{"docs"}
+	mustNoErr(t, r.Teams.UpsertSettings(ctx, want))
+	s, err = r.Teams.GetSettings(ctx, "backend")
+	mustNoErr(t, err)
+	assertOrdered(t, "fallback teams", s.FallbackTeams, []string{"docs"})
+}
+
+func testRenameAndDeleteTeam(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+
+	if err := r.Teams.Rename(ctx, "backend", "platform"); !errors.Is(err, repo_errors.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+	if _, err := r.Teams.Delete(ctx, "backend", ""); !errors.Is(err, repo_errors.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+
+	seed(t, r)
+	createPR(t, r, "pr-1", pullrequest.StatusOpen, "u2")
+	backend := team.DefaultSettings("backend")
+	backend.ReviewersRequired, backend.FallbackTeams = 3, []string{"frontend"}
+	mustNoErr(t, r.Teams.UpsertSettings(ctx, backend))
+	frontend := team.DefaultSettings("frontend")
+	frontend.FallbackTeams = []string{"backend"}
+	mustNoErr(t, r.Teams.UpsertSettings(ctx, frontend))
+
+	if err := r.Teams.Rename(ctx, "backend", "frontend"); err == nil {
+		t.Fatal("expected an error renaming to an existing team")
+	}
+	mustNoErr(t, r.Teams.Rename(ctx, "backend", "platform"))
+
+	ok, err := r.Teams.Exists(ctx, "backend")
+	mustNoErr(t, err)
+	if ok {
+		t.Fatal("old team still exists")
+	}
+	tm, err := r.Teams.GetWithMembers(ctx, "platform")
+	mustNoErr(t, err)
+	if len(tm.Members) != 5 {
+		t.Fatalf("expected 5 members, got %d", len(tm.Members))
+	}
+	s, err := r.Teams.GetSettings(ctx, "platform")
+	mustNoErr(t, err)
+	if s.TeamName != "platform" || s.ReviewersRequired != 3 {
+		t.Fatalf("settings were not kept: %+v", s)
+	}
+	assertOrdered(t, "fallback teams", s.FallbackTeams, []string{"frontend"})
+	s, err = r.Teams.GetSettings(ctx, "frontend")
+	mustNoErr(t, err)
+	assertOrdered(t, "fallback teams", s.FallbackTeams, []string{"platform"})
+
+	// Members move to the target team, references to the deleted team disappear.
+	ids, err := r.Teams.Delete(ctx, "platform", "frontend")
+	mustNoErr(t, err)
+	assertOrdered(t, "moved", ids, []string{"u1", "u2", "u3", "u4", "u5"})
+	if _, err := r.Teams.GetSettings(ctx, "platform"); !errors.Is(err, repo_errors.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+	s, err = r.Teams.GetSettings(ctx, "frontend")
+	mustNoErr(t, err)
+	if len(s.FallbackTeams) != 0 {
+		t.Fatalf("fallback to the deleted team was kept: %v", s.FallbackTeams)
+	}
+	u, err := r.Users.GetByID(ctx, "u2")
+	mustNoErr(t, err)
+	if u.TeamName != "frontend" || !u.IsActive {
+		t.Fatalf("unexpected moved user: %+v", u)
+	}
+
+	// Without a target the members are left without a team and deactivated, in the
+	// same transaction as the handovers of their reviews.
+	handover := func(reviewerID string) assignment.Handover {
+		return assignment.Handover{PullRequestID: "pr-1", ReviewerID: reviewerID,
+			Event: event.New(ctx, event.TypeReviewerRemoved, event.ReviewerRemoved{
+				PullRequestID: "pr-1", ReviewerID: reviewerID, Reason: event.ReasonTeamDeactivation,
+			}),
+		}
+	}
+	if _, err := r.Teams.Delete(ctx, "frontend", "", handover("u3")); !errors.Is(err, repo_errors.ErrReviewersNotFound) {
+		t.Fatalf("expected ErrReviewersNotFound, got %v", err)
+	}
+	ok, err = r.Teams.Exists(ctx, "frontend")
+	mustNoErr(t, err)
+	if !ok {
+		t.Fatal("a failed delete removed the team")
+	}
+	ids, err = r.Teams.Delete(ctx, "frontend", "", handover("u2"))
+	mustNoErr(t, err)
+	assertOrdered(t, "deactivated", ids, []string{"f1", "u1", "u2", "u3", "u4", "u5"})
+	u, err = r.Users.GetByID(ctx, "f1")
+	mustNoErr(t, err)
+	if u.TeamName != "" || u.IsActive {
+		t.Fatalf("unexpected user of a deleted team: %+v", u)
+	}
+	pr, err := r.PRs.GetWithReviewers(ctx, "pr-1")
+	mustNoErr(t, err)
+	assertOrdered(t, "reviewers", pr.Reviewers, nil)
+}
+
+func testTeamHierarchy(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+
+	if err := r.Teams.SetParent(ctx, "backend", ""); !errors.Is(err, repo_errors.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+	if _, err := r.Teams.Ancestors(ctx, "backend"); !errors.Is(err, repo_errors.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+
+	seed(t, r)
+	mustNoErr(t, r.Teams.CreateTeam(ctx, "org"))
+	mustNoErr(t, r.Teams.CreateTeam(ctx, "platform"))
+	mustNoErr(t, r.Teams.SetParent(ctx, "platform", "org"))
+	mustNoErr(t, r.Teams.SetParent(ctx, "backend", "platform"))
+	mustNoErr(t, r.Teams.SetParent(ctx, "frontend", "platform"))
+
+	// A move that would close a cycle, or under a missing parent, changes nothing.
+	for _, parent := range []string{"org", "backend"} {
+		if err := r.Teams.SetParent(ctx, "org", parent); !errors.Is(err, team.ErrInvalidParent) {
+			t.Fatalf("expected ErrInvalidParent for parent %q, got %v", parent, err)
+		}
+	}
+	if err := r.Teams.SetParent(ctx, "org", "missing"); !errors.Is(err, repo_errors.ErrParentTeamNotFound) {
+		t.Fatalf("expected ErrParentTeamNotFound, got %v", err)
+	}
+
+	tm, err := r.Teams.GetWithMembers(ctx, "backend")
+	mustNoErr(t, err)
+	if tm.ParentTeam != "platform" {
+		t.Fatalf("expected parent platform, got %q", tm.ParentTeam)
+	}
+	chain, err := r.Teams.Ancestors(ctx, "backend")
+	mustNoErr(t, err)
+	assertOrdered(t, "ancestors", chain, []string{"platform", "org"})
+	chain, err = r.Teams.Ancestors(ctx, "org")
+	mustNoErr(t, err)
+	assertOrdered(t, "ancestors", chain, nil)
+
+	teams, err := r.Teams.List(ctx)
+	mustNoErr(t, err)
+	got := make([]string, 0, len(teams))
+	for _, tm := range teams {
+		got = append(got, tm.TeamName+"<"+tm.ParentTeam)
+	}
+	assertOrdered(t, "teams", got, []string{"backend<platform", "frontend<platform", "org<", "platform<org"})
+
+	// Settings come from the nearest ancestor that has them.
+	s, err := r.Teams.GetSettings(ctx, "backend")
+	mustNoErr(t, err)
+	if s.InheritedFrom != "" || s.ReviewersRequired != team.DefaultSettings("").ReviewersRequired {
+		t.Fatalf("expected defaults, got %+v", s)
+	}
+	org := team.DefaultSettings("org")
+	org.ReviewersRequired, org.SearchParents, org.FallbackTeams = 3, true, []string{"frontend"}
+	mustNoErr(t, r.Teams.UpsertSettings(ctx, org))
+	s, err = r.Teams.GetSettings(ctx, "backend")
+	mustNoErr(t, err)
+	if s.TeamName != "backend" || s.InheritedFrom != "org" || s.ReviewersRequired != 3 || !s.SearchParents {
+		t.Fatalf("settings were not inherited: %+v", s)
+	}
+	assertOrdered(t, "fallback teams", s.FallbackTeams, []string{"frontend"})
+
+	platform := team.DefaultSettings("platform")
+	mustNoErr(t, r.Teams.UpsertSettings(ctx, platform))
+	s, err = r.Teams.GetSettings(ctx, "backend")
+	mustNoErr(t, err)
+	if s.InheritedFrom != "platform" || s.SearchParents || len(s.FallbackTeams) != 0 {
+		t.Fatalf("expected the platform settings, got %+v", s)
+	}
+	s, err = r.Teams.GetSettings(ctx, "platform")
+	mustNoErr(t, err)
+	if s.InheritedFrom != "" {
+		t.Fatalf("own settings reported as inherited: %+v", s)
+	}
+
+	// Renaming keeps the parent and the sub-teams; deleting moves the sub-teams up.
+	mustNoErr(t, r.Teams.Rename(ctx, "platform", "infra"))
+	tm, err = r.Teams.GetWithMembers(ctx, "infra")
+	mustNoErr(t, err)
+	if tm.ParentTeam != "org" {
+		t.Fatalf("expected parent org, got %q", tm.ParentTeam)
+	}
+	chain, err = r.Teams.Ancestors(ctx, "frontend")
+	mustNoErr(t, err)
+	assertOrdered(t, "ancestors", chain, []string{"infra", "org"})
+
+	_, err = r.Teams.Delete(ctx, "infra", "")
+	mustNoErr(t, err)
+	chain, err = r.Teams.Ancestors(ctx, "backend")
+	mustNoErr(t, err)
+	assertOrdered(t, "ancestors", chain, []string{"org"})
+
+	mustNoErr(t, r.Teams.SetParent(ctx, "backend", ""))
+	chain, err = r.Teams.Ancestors(ctx, "backend")
+	mustNoErr(t, err)
+	assertOrdered(t, "ancestors", chain, nil)
+}
+
+func testCodeOwners(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+
+	if _, err := r.Teams.GetCodeOwners(ctx, "backend"); !errors.Is(err, repo_errors.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+
+	seed(t, r)
+
+	c, err := r.Teams.GetCodeOwners(ctx, "backend")
+	mustNoErr(t, err)
+	if c.TeamName != "backend" || len(c.Rules) != 0 {
+		t.Fatalf("unexpected code owners: %+v", c)
+	}
+
+	mustNoErr(t, r.Teams.SetCodeOwners(ctx, team.CodeOwners{TeamName: "backend", Rules: []team.OwnershipRule{
+		{Pattern: "*", Owners: []string{"u1"}},
+		{Pattern: "/internal/db/", Owners: []string{"u3", "u2"}},
+	}}))
+	c, err = r.Teams.GetCodeOwners(ctx, "backend")
+	mustNoErr(t, err)
+	if len(c.Rules) != 2 || c.Rules[0].Pattern != "*" || c.Rules[1].Pattern != "/internal/db/" {
+		t.Fatalf("unexpected rules: %+v", c.Rules)
+	}
+	assertOrdered(t, "owners", c.Rules[1].Owners, []string{"u3", "u2"})
+
+	// The rules follow the team when it is renamed and are replaced as a whole.
+	mustNoErr(t, r.Teams.Rename(ctx, "backend", "core"))
+	c, err = r.Teams.GetCodeOwners(ctx, "core")
+	mustNoErr(t, err)
+	if c.TeamName != "core" || len(c.Rules) != 2 {
+		t.Fatalf("rules were not renamed: %+v", c)
+	}
+	mustNoErr(t, r.Teams.SetCodeOwners(ctx, team.CodeOwners{TeamName: "core"}))
+	c, err = r.Teams.GetCodeOwners(ctx, "core")
+	mustNoErr(t, err)
+	if len(c.Rules) != 0 {
+		t.Fatalf("rules were not cleared: %+v", c.Rules)
+	}
+}
+
+func testUsers(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+
+	if _, err := r.Users.GetByID(ctx, "u1"); !errors.Is(err, repo_errors.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+	if _, err := r.Users.SetIsActive(ctx, "u1", false); !errors.Is(err, repo_errors.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+	seed(t, r)
+
+	u, err := r.Users.GetByID(ctx, "u2")
+	mustNoErr(t, err)
+	if u.ID != "u2" || u.Name != "Bob" || u.TeamName != "backend" || !u.IsActive {
+		t.Fatalf("unexpected user: %+v", u)
+	}
+
+	u, err = r.Users.SetIsActive(ctx, "u2", false)
+	mustNoErr(t, err)
+	if u.IsActive {
+		t.Fatal("SetIsActive returned an active user")
+	}
+
+	cands, err := r.Users.FindActiveByTeamExcept(ctx, "backend", []string{"u1"})
+	mustNoErr(t, err)
+	assertSet(t, "candidates", userIDs(cands), []string{"u3", "u4"})
+
+	cands, err = r.Users.FindActiveByTeamExcept(ctx, "nobody", nil)
+	mustNoErr(t, err)
+	if cands == nil || len(cands) != 0 {
+		t.Fatalf("expected an empty non-nil slice, got %#v", cands)
+	}
+
+	// Upserting into another team moves the user and updates the fields.
+	mustNoErr(t, r.Users.UpsertManyForTeam(ctx, "frontend", []*user.User{{ID: "u3", Name: "Caroline", IsActive: true}}))
+	u, err = r.Users.GetByID(ctx, "u3")
+	mustNoErr(t, err)
+	if u.TeamName != "frontend" || u.Name != "Caroline" {
+		t.Fatalf("user was not moved: %+v", u)
+	}
+	cands, err = r.Users.FindActiveByTeamExcept(ctx, "frontend", nil)
+	mustNoErr(t, err)
+	assertSet(t, "frontend candidates", userIDs(cands), []string{"f1", "u3"})
+}
+
+func testSetTeam(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+
+	if _, err := r.Users.SetTeam(ctx, "u1", ""); !errors.Is(err, repo_errors.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+	seed(t, r)
+	createPR(t, r, "pr-1", pullrequest.StatusOpen, "u4")
+
+	u, err := r.Users.SetTeam(ctx, "u3", "frontend")
+	mustNoErr(t, err)
+	if u.TeamName != "frontend" || u.Name != "Carol" || !u.IsActive {
+		t.Fatalf("unexpected moved user: %+v", u)
+	}
+
+	// A user without a team keeps the reviews and is no longer anyone's candidate.
+	u, err = r.Users.SetTeam(ctx, "u4", "")
+	mustNoErr(t, err)
+	if u.TeamName != "" {
+		t.Fatalf("user still has a team: %+v", u)
+	}
+	u, err = r.Users.GetByID(ctx, "u4")
+	mustNoErr(t, err)
+	if u.TeamName != "" || u.Name != "Dave" {
+		t.Fatalf("unexpected user without a team: %+v", u)
+	}
+	tm, err := r.Teams.GetWithMembers(ctx, "backend")
+	mustNoErr(t, err)
+	ids := make([]string, 0, len(tm.Members))
+	for _, m := range tm.Members {
+		ids = append(ids, m.ID)
+	}
+	assertSet(t, "members", ids, []string{"u1", "u2", "u5"})
+	cands, err := r.Users.FindActiveByTeamExcept(ctx, "backend", nil)
+	mustNoErr(t, err)
+	assertSet(t, "candidates", userIDs(cands), []string{"u1", "u2"})
+
+	handedOver := event.New(ctx, event.TypeReviewerReassigned, event.ReviewerReassigned{
+		PullRequestID: "pr-1", OldReviewerID: "u4", NewReviewerID: "u2", Reason: event.ReasonTeamChange,
+	})
+	mustNoErr(t, r.PRs.ReplaceReviewers(ctx, "pr-1", "u4", "u2", handedOver))
+	hist, err := r.PRs.HistoryByUser(ctx, "u4")
+	mustNoErr(t, err)
+	if len(hist) != 1 || hist[0].Reason != event.ReasonTeamChange {
+		t.Fatalf("unexpected history: %+v", hist)
+	}
+
+	// Upserting brings the user back into a team.
+	mustNoErr(t, r.Users.UpsertManyForTeam(ctx, "backend", []*user.User{{ID: "u4", Name: "Dave", IsActive: true}}))
+	u, err = r.Users.GetByID(ctx, "u4")
+	mustNoErr(t, err)
+	if u.TeamName != "backend" {
+		t.Fatalf("user was not added back: %+v", u)
+	}
+}
+
+func testIdentities(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+	seed(t, r)
+
+	if _, err := r.Users.FindByIdentity(ctx, "github", "bob"); !errors.Is(err, repo_errors.ErrIdentityNotFound) {
+		t.Fatalf("expected ErrIdentityNotFound, got %v", err)
+	}
+
+	mustNoErr(t, r.Users.LinkIdentity(ctx, "github", "bob", "u2"))
+	u, err := r.Users.FindByIdentity(ctx, "github", "bob")
+	mustNoErr(t, err)
+	if u.ID != "u2" {
+		t.Fatalf("expected u2, got %s", u.ID)
+	}
+	if _, err := r.Users.FindByIdentity(ctx, "gitlab", "bob"); !errors.Is(err, repo_errors.ErrIdentityNotFound) {
+		t.Fatalf("identities must be per provider, got %v", err)
+	}
+
+	mustNoErr(t, r.Users.LinkIdentity(ctx, "github", "bob", "u3"))
+	u, err = r.Users.FindByIdentity(ctx, "github", "bob")
+	mustNoErr(t, err)
+	if u.ID != "u3" {
+		t.Fatalf("relinking must replace the user, got %s", u.ID)
+	}
+}
+
+func testReviewCapacity(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+
+	if _, err := r.Users.SetMaxOpenReviews(ctx, "u1", 1); !errors.Is(err, repo_errors.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+	seed(t, r)
+
+	u, err := r.Users.SetMaxOpenReviews(ctx, "u2", 3)
+	mustNoErr(t, err)
+	if u.ID != "u2" || u.MaxOpenReviews != 3 || !u.IsActive {
+		t.Fatalf("unexpected user: %+v", u)
+	}
+
+	// The capacity survives re-upserting the team and is returned with the candidates.
+	mustNoErr(t, r.Users.UpsertManyForTeam(ctx, "backend", []*user.User{{ID: "u2", Name: "Bobby", IsActive: true}}))
+	u, err = r.Users.GetByID(ctx, "u2")
+	mustNoErr(t, err)
+	if u.Name != "Bobby" || u.MaxOpenReviews != 3 {
+		t.Fatalf("capacity was not kept: %+v", u)
+	}
+	cands, err := r.Users.FindActiveByTeamExcept(ctx, "backend", []string{"u1", "u3", "u4"})
+	mustNoErr(t, err)
+	if len(cands) != 1 || cands[0].MaxOpenReviews != 3 {
+		t.Fatalf("unexpected candidates: %+v", cands)
+	}
+
+	u, err = r.Users.SetMaxOpenReviews(ctx, "u2", 0)
+	mustNoErr(t, err)
+	if u.MaxOpenReviews != 0 {
+		t.Fatalf("limit was not removed: %+v", u)
+	}
+}
+
+func testAbsences(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+	start := now()
+
+	if _, err := r.Absences.Create(ctx, user.Absence{UserID: "u2", StartsAt: start, EndsAt: start.Add(time.Hour)}); !errors.Is(err, repo_errors.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+	if err := r.Absences.Delete(ctx, 1); !errors.Is(err, repo_errors.ErrAbsenceNotFound) {
+		t.Fatalf("expected ErrAbsenceNotFound, got %v", err)
+	}
+	if _, err := r.Absences.Get(ctx, 1); !errors.Is(err, repo_errors.ErrAbsenceNotFound) {
+		t.Fatalf("expected ErrAbsenceNotFound, got %v", err)
+	}
+
+	seed(t, r)
+	current, err := r.Absences.Create(ctx, user.Absence{UserID: "u2", StartsAt: start.Add(-time.Hour), EndsAt: start.Add(time.Hour), Reason: "vacation"})
+	mustNoErr(t, err)
+	if current.ID == 0 || current.HandledAt != nil {
+		t.Fatalf("unexpected absence: %+v", current)
+	}
+	got, err := r.Absences.Get(ctx, current.ID)
+	mustNoErr(t, err)
+	if got.UserID != "u2" || got.Reason != "vacation" || !got.StartsAt.Equal(current.StartsAt) {
+		t.Fatalf("unexpected absence: %+v", got)
+	}
+	future, err := r.Absences.Create(ctx, user.Absence{UserID: "u2", StartsAt: start.Add(24 * time.Hour), EndsAt: start.Add(48 * time.Hour)})
+	mustNoErr(t, err)
+	_, err = r.Absences.Create(ctx, user.Absence{UserID: "u3", StartsAt: start.Add(-2 * time.Hour), EndsAt: start.Add(-time.Hour)})
+	mustNoErr(t, err)
+
+	list, err := r.Absences.ListByUser(ctx, "u2")
+	mustNoErr(t, err)
+	if len(list) != 2 || list[0].ID != current.ID || list[1].ID != future.ID || list[0].Reason != "vacation" {
+		t.Fatalf("unexpected absences: %+v", list)
+	}
+	if !list[0].StartsAt.Equal(current.StartsAt) || !list[0].EndsAt.Equal(current.EndsAt) {
+		t.Fatalf("times were not kept: %+v", list[0])
+	}
+
+	// Only users away right now are skipped; past and future absences do not matter.
+	cands, err := r.Users.FindActiveByTeamExcept(ctx, "backend", []string{"u1"})
+	mustNoErr(t, err)
+	assertSet(t, "candidates", userIDs(cands), []string{"u3", "u4"})
+
+	started, err := r.Absences.ListStarted(ctx, start, 10)
+	mustNoErr(t, err)
+	if len(started) != 1 || started[0].ID != current.ID || started[0].UserID != "u2" {
+		t.Fatalf("unexpected started absences: %+v", started)
+	}
+	// A failed handover is not listed again before its next attempt.
+	mustNoErr(t, r.Absences.MarkFailed(ctx, current.ID, start.Add(time.Minute)))
+	started, err = r.Absences.ListStarted(ctx, start, 10)
+	mustNoErr(t, err)
+	if len(started) != 0 {
+		t.Fatalf("failed absence listed before its next attempt: %+v", started)
+	}
+	started, err = r.Absences.ListStarted(ctx, start.Add(time.Minute), 10)
+	mustNoErr(t, err)
+	if len(started) != 1 || started[0].Attempts != 1 || started[0].NextAttemptAt == nil || !started[0].NextAttemptAt.Equal(start.Add(time.Minute)) {
+		t.Fatalf("unexpected started absences: %+v", started)
+	}
+	mustNoErr(t, r.Absences.MarkHandled(ctx, current.ID, start))
+	started, err = r.Absences.ListStarted(ctx, start, 10)
+	mustNoErr(t, err)
+	if len(started) != 0 {
+		t.Fatalf("handled absence listed again: %+v", started)
+	}
+	started, err = r.Absences.ListStarted(ctx, start.Add(25*time.Hour), 10)
+	mustNoErr(t, err)
+	if len(started) != 1 || started[0].ID != future.ID {
+		t.Fatalf("unexpected started absences: %+v", started)
+	}
+	list, err = r.Absences.ListByUser(ctx, "u2")
+	mustNoErr(t, err)
+	if list[0].HandledAt == nil || !list[0].HandledAt.Equal(start) {
+		t.Fatalf("handled_at was not kept: %+v", list[0])
+	}
+
+	mustNoErr(t, r.Absences.Delete(ctx, current.ID))
+	cands, err = r.Users.FindActiveByTeamExcept(ctx, "backend", []string{"u1"})
+	mustNoErr(t, err)
+	assertSet(t, "candidates", userIDs(cands), []string{"u2", "u3", "u4"})
+}
+
+func testCreateAndGetPR(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+	seed(t, r)
+
+	if _, err := r.PRs.GetWithReviewers(ctx, "pr-1"); !errors.Is(err, repo_errors.ErrPRNotFound) {
+		t.Fatalf("expected ErrPRNotFound, got %v", err)
+	}
+
+	created := now()
+	pr := pullrequest.PullRequest{ID: "pr-1", Name: "Add search", AuthorID: "u1", Status: pullrequest.StatusOpen, CreatedAt: created, Reviewers: []string{"u3", "u2"},
+		ChangedFiles: []string{"internal/search/search.go", "README.md"}}
+	mustNoErr(t, r.PRs.CreateWithReviewers(ctx, pr))
+	if err := r.PRs.CreateWithReviewers(ctx, pr); err == nil {
+		t.Fatal("expected an error creating a duplicate PR")
+	}
+
+	got, err := r.PRs.GetWithReviewers(ctx, "pr-1")
+	mustNoErr(t, err)
+	if got.ID != "pr-1" || got.Name != "Add search" || got.AuthorID != "u1" || got.Status != pullrequest.StatusOpen {
+		t.Fatalf("unexpected PR: %+v", got)
+	}
+	if !got.CreatedAt.Equal(created) || got.MergedAt != nil || got.ClosedAt != nil {
+		t.Fatalf("unexpected timestamps: %+v", got)
+	}
+	// Reviewers assigned together are ordered by id.
+	assertOrdered(t, "reviewers", got.Reviewers, []string{"u2", "u3"})
+	assertOrdered(t, "changed files", got.ChangedFiles, []string{"internal/search/search.go", "README.md"})
+	for _, rv := range got.Reviews {
+		if rv.State != pullrequest.ReviewPending || rv.UpdatedAt != nil {
+			t.Fatalf("unexpected review: %+v", rv)
+		}
+	}
+
+	createPR(t, r, "pr-2", pullrequest.StatusDraft)
+	got, err = r.PRs.GetWithReviewers(ctx, "pr-2")
+	mustNoErr(t, err)
+	if got.Status != pullrequest.StatusDraft || len(got.Reviewers) != 0 || got.Reviews == nil || len(got.ChangedFiles) != 0 {
+		t.Fatalf("unexpected draft PR: %+v", got)
+	}
+}
+
+func testCreateMany(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+	seed(t, r)
+	createPR(t, r, "pr-0", pullrequest.StatusOpen)
+
+	imported := event.New(ctx, event.TypeReviewersAssigned, event.ReviewersAssigned{
+		PullRequestID: "pr-1", AuthorID: "u1", Reviewers: []string{"u5"}, Reason: event.ReasonImport,
+	})
+	batch := []pullrequest.PullRequest{
+		{ID: "pr-1", Name: "One", AuthorID: "u1", Status: pullrequest.StatusOpen, CreatedAt: now(), Reviewers: []string{"u5"}},
+		{ID: "pr-2", Name: "Two", AuthorID: "u2", Status: pullrequest.StatusDraft, CreatedAt: now()},
+		{ID: "pr-0", Name: "Zero", AuthorID: "u1", Status: pullrequest.StatusOpen, CreatedAt: now()},
+	}
+
+	// One conflicting PR fails the whole batch.
+	if err := r.PRs.CreateManyWithReviewers(ctx, batch, imported); err == nil {
+		t.Fatal("expected an error for a batch with an existing PR")
+	}
+	if _, err := r.PRs.GetWithReviewers(ctx, "pr-1"); !errors.Is(err, repo_errors.ErrPRNotFound) {
+		t.Fatalf("failed batch stored pr-1: %v", err)
+	}
+	hist, err := r.PRs.HistoryByPR(ctx, "pr-1")
+	mustNoErr(t, err)
+	if len(hist) != 0 {
+		t.Fatalf("failed batch stored history: %+v", hist)
+	}
+
+	mustNoErr(t, r.PRs.CreateManyWithReviewers(ctx, batch[:2], imported))
+
+	got, err := r.PRs.GetWithReviewers(ctx, "pr-1")
+	mustNoErr(t, err)
+	assertOrdered(t, "reviewers", got.Reviewers, []string{"u5"})
+	got, err = r.PRs.GetWithReviewers(ctx, "pr-2")
+	mustNoErr(t, err)
+	if got.Status != pullrequest.StatusDraft || got.AuthorID != "u2" {
+		t.Fatalf("unexpected PR: %+v", got)
+	}
+
+	hist, err = r.PRs.HistoryByPR(ctx, "pr-1")
+	mustNoErr(t, err)
+	if len(hist) != 1 || hist[0].NewReviewerID != "u5" || hist[0].Reason != event.ReasonImport {
+		t.Fatalf("unexpected history: %+v", hist)
+	}
+
+	mustNoErr(t, r.PRs.CreateManyWithReviewers(ctx, nil))
+}
+
+func testReviewerQueries(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+	seed(t, r)
+
+	createPR(t, r, "pr-1", pullrequest.StatusOpen, "u2", "u3")
+	createPR(t, r, "pr-2", pullrequest.StatusOpen, "u2")
+	createPR(t, r, "pr-3", pullrequest.StatusOpen, "u2", "u4")
+	_, _, err := r.PRs.Merge(ctx, "pr-3", now())
+	mustNoErr(t, err)
+
+	short, err := r.PRs.FindShortByReviewer(ctx, "u2")
+	mustNoErr(t, err)
+	ids := make([]string, 0, len(short))
+	for _, s := range short {
+		ids = append(ids, s.ID)
+		if s.ID == "pr-3" && s.Status != pullrequest.StatusMerged {
+			t.Fatalf("unexpected status of pr-3: %s", s.Status)
+		}
+	}
+	assertSet(t, "reviewed PRs", ids, []string{"pr-1", "pr-2", "pr-3"})
+
+	short, err = r.PRs.FindShortByReviewer(ctx, "f1")
+	mustNoErr(t, err)
+	if len(short) != 0 {
+		t.Fatalf("expected no PRs, got %+v", short)
+	}
+
+	open, err := r.PRs.GetOpenPRIDsByReviewer(ctx, "u2")
+	mustNoErr(t, err)
+	assertSet(t, "open PRs", open, []string{"pr-1", "pr-2"})
+
+	loads, err := r.PRs.CountOpenReviews(ctx, []string{"u2", "u3", "u4", "f1"})
+	mustNoErr(t, err)
+	if loads["u2"] != 2 || loads["u3"] != 1 || loads["u4"] != 0 || loads["f1"] != 0 {
+		t.Fatalf("unexpected loads: %v", loads)
+	}
+
+	createPR(t, r, "pr-4", pullrequest.StatusOpen, "f1")
+	byTeam, err := r.PRs.OpenReviewsByTeam(ctx)
+	mustNoErr(t, err)
+	if len(byTeam) != 2 || byTeam["backend"] != 3 || byTeam["frontend"] != 1 {
+		t.Fatalf("unexpected open reviews by team: %v", byTeam)
+	}
+}
+
+func testReplaceAndRemoveReviewers(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+	seed(t, r)
+	createPR(t, r, "pr-1", pullrequest.StatusOpen, "u2", "u3")
+
+	if err := r.PRs.ReplaceReviewers(ctx, "missing", "u2", "u4"); !errors.Is(err, repo_errors.ErrPRNotFound) {
+		t.Fatalf("expected ErrPRNotFound, got %v", err)
+	}
+	if err := r.PRs.ReplaceReviewers(ctx, "pr-1", "u4", "f1"); !errors.Is(err, repo_errors.ErrReviewersNotFound) {
+		t.Fatalf("expected ErrReviewersNotFound, got %v", err)
+	}
+
+	mustNoErr(t, r.PRs.ReplaceReviewers(ctx, "pr-1", "u2", "u4"))
+	got, err := r.PRs.GetWithReviewers(ctx, "pr-1")
+	mustNoErr(t, err)
+	assertSet(t, "reviewers", got.Reviewers, []string{"u3", "u4"})
+
+	if err := r.PRs.RemoveReviewer(ctx, "pr-1", "u2"); !errors.Is(err, repo_errors.ErrReviewersNotFound) {
+		t.Fatalf("expected ErrReviewersNotFound, got %v", err)
+	}
+	mustNoErr(t, r.PRs.RemoveReviewer(ctx, "pr-1", "u3"))
+	got, err = r.PRs.GetWithReviewers(ctx, "pr-1")
+	mustNoErr(t, err)
+	assertSet(t, "reviewers", got.Reviewers, []string{"u4"})
+
+	_, _, err = r.PRs.Merge(ctx, "pr-1", now())
+	mustNoErr(t, err)
+	if err := r.PRs.ReplaceReviewers(ctx, "pr-1", "u4", "u2"); !errors.Is(err, repo_errors.ErrPRMerged) {
+		t.Fatalf("expected ErrPRMerged, got %v", err)
+	}
+	if err := r.PRs.RemoveReviewer(ctx, "pr-1", "u4"); !errors.Is(err, repo_errors.ErrPRMerged) {
+		t.Fatalf("expected ErrPRMerged, got %v", err)
+	}
+}
+
+func testHandOver(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+	seed(t, r)
+	createPR(t, r, "pr-1", pullrequest.StatusOpen, "u2", "u3")
+	createPR(t, r, "pr-2", pullrequest.StatusOpen, "u2")
+
+	reassigned := event.New(ctx, event.TypeReviewerReassigned, event.ReviewerReassigned{
+		PullRequestID: "pr-1", OldReviewerID: "u2", NewReviewerID: "u4", Reason: event.ReasonTeamDeactivation,
+	})
+	removed := event.New(ctx, event.TypeReviewerRemoved, event.ReviewerRemoved{
+		PullRequestID: "pr-2", ReviewerID: "u2", Reason: event.ReasonTeamDeactivation,
+	})
+	handovers := []assignment.Handover{
+		{PullRequestID: "pr-1", ReviewerID: "u2", NewReviewerID: "u4", Event: reassigned},
+		{PullRequestID: "pr-2", ReviewerID: "u2", Event: removed},
+	}
+
+	// One stale handover fails the whole call.
+	stale := append(handovers, assignment.Handover{PullRequestID: "pr-1", ReviewerID: "u5",
+		Event: event.New(ctx, event.TypeReviewerRemoved, event.ReviewerRemoved{
+			PullRequestID: "pr-1", ReviewerID: "u5", Reason: event.ReasonTeamDeactivation,
+		}),
+	})
+	if err := r.Users.Deactivate(ctx, []string{"u2"}, stale...); !errors.Is(err, repo_errors.ErrReviewersNotFound) {
+		t.Fatalf("expected ErrReviewersNotFound, got %v", err)
+	}
+	if err := r.Users.Deactivate(ctx, []string{"u2", "nobody"}, handovers...); !errors.Is(err, repo_errors.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+	u, err := r.Users.GetByID(ctx, "u2")
+	mustNoErr(t, err)
+	if !u.IsActive {
+		t.Fatal("a failed deactivation must leave the user active")
+	}
+	pr, err := r.PRs.GetWithReviewers(ctx, "pr-1")
+	mustNoErr(t, err)
+	assertSet(t, "pr-1 reviewers", pr.Reviewers, []string{"u2", "u3"})
+	events, err := r.Outbox.ClaimPending(ctx, 10, time.Hour)
+	mustNoErr(t, err)
+	if len(events) != 0 {
+		t.Fatalf("failed handovers must not store events, got %+v", events)
+	}
+
+	mustNoErr(t, r.Users.Deactivate(ctx, []string{"u2"}, handovers...))
+	u, err = r.Users.GetByID(ctx, "u2")
+	mustNoErr(t, err)
+	if u.IsActive {
+		t.Fatal("u2 must be deactivated")
+	}
+	pr, err = r.PRs.GetWithReviewers(ctx, "pr-1")
+	mustNoErr(t, err)
+	assertSet(t, "pr-1 reviewers", pr.Reviewers, []string{"u3", "u4"})
+	pr, err = r.PRs.GetWithReviewers(ctx, "pr-2")
+	mustNoErr(t, err)
+	assertSet(t, "pr-2 reviewers", pr.Reviewers, nil)
+	hist, err := r.PRs.HistoryByUser(ctx, "u2")
+	mustNoErr(t, err)
+	if len(hist) != 2 {
+		t.Fatalf("expected 2 history entries, got %+v", hist)
+	}
+
+	if err := r.PRs.HandOver(ctx, handovers[1:]); !errors.Is(err, repo_errors.ErrReviewersNotFound) {
+		t.Fatalf("expected ErrReviewersNotFound, got %v", err)
+	}
+	mustNoErr(t, r.PRs.HandOver(ctx, []assignment.Handover{{PullRequestID: "pr-1", ReviewerID: "u4", NewReviewerID: "u1",
+		Event: event.New(ctx, event.TypeReviewerReassigned, event.ReviewerReassigned{
+			PullRequestID: "pr-1", OldReviewerID: "u4", NewReviewerID: "u1", Reason: event.ReasonTeamChange,
+		}),
+	}}))
+	pr, err = r.PRs.GetWithReviewers(ctx, "pr-1")
+	mustNoErr(t, err)
+	assertSet(t, "pr-1 reviewers", pr.Reviewers, []string{"u1", "u3"})
+}
+
+func testReviewState(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+	seed(t, r)
+	createPR(t, r, "pr-1", pullrequest.StatusOpen, "u2", "u3")
+
+	at := now()
+	mustNoErr(t, r.PRs.SetReviewState(ctx, "pr-1", "u2", pullrequest.ReviewApproved, at))
+	got, err := r.PRs.GetWithReviewers(ctx, "pr-1")
+	mustNoErr(t, err)
+	for _, rv := range got.Reviews {
+		switch rv.UserID {
+		case "u2":
+			if rv.State != pullrequest.ReviewApproved || rv.UpdatedAt == nil || !rv.UpdatedAt.Equal(at) {
+				t.Fatalf("unexpected review of u2: %+v", rv)
+			}
+		case "u3":
+			if rv.State != pullrequest.ReviewPending {
+				t.Fatalf("unexpected review of u3: %+v", rv)
+			}
+		}
+	}
+	if got.Approvals() != 1 {
+		t.Fatalf("expected 1 approval, got %d", got.Approvals())
+	}
+
+	if err := r.PRs.SetReviewState(ctx, "missing", "u2", pullrequest.ReviewApproved, at); !errors.Is(err, repo_errors.ErrPRNotFound) {
+		t.Fatalf("expected ErrPRNotFound, got %v", err)
+	}
+	if err := r.PRs.SetReviewState(ctx, "pr-1", "u4", pullrequest.ReviewApproved, at); !errors.Is(err, repo_errors.ErrReviewersNotFound) {
+		t.Fatalf("expected ErrReviewersNotFound, got %v", err)
+	}
+	_, _, err = r.PRs.Merge(ctx, "pr-1", at)
+	mustNoErr(t, err)
+	if err := r.PRs.SetReviewState(ctx, "pr-1", "u3", pullrequest.ReviewApproved, at); !errors.Is(err, repo_errors.ErrPRMerged) {
+		t.Fatalf("expected ErrPRMerged, got %v", err)
+	}
+}
+
+func testMerge(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+	seed(t, r)
+	createPR(t, r, "pr-1", pullrequest.StatusOpen, "u2")
+
+	if _, _, err := r.PRs.Merge(ctx, "missing", now()); !errors.Is(err, repo_errors.ErrPRNotFound) {
+		t.Fatalf("expected ErrPRNotFound, got %v", err)
+	}
+
+	first := now()
+	merged := event.New(ctx, event.TypePRMerged, event.PRMerged{PullRequestID: "pr-1", MergedAt: first})
+	pr, ok, err := r.PRs.Merge(ctx, "pr-1", first, merged)
+	mustNoErr(t, err)
+	if !ok || pr.Status != pullrequest.StatusMerged || pr.MergedAt == nil || !pr.MergedAt.Equal(first) {
+		t.Fatalf("unexpected merged PR: %+v", pr)
+	}
+	assertOrdered(t, "reviewers", pr.Reviewers, []string{"u2"})
+
+	again := event.New(ctx, event.TypePRMerged, event.PRMerged{PullRequestID: "pr-1"})
+	pr, ok, err = r.PRs.Merge(ctx, "pr-1", first.Add(time.Hour), again)
+	mustNoErr(t, err)
+	if ok || !pr.MergedAt.Equal(first) {
+		t.Fatalf("repeated merge changed merged_at: %v", pr.MergedAt)
+	}
+
+	events, err := r.Outbox.ClaimPending(ctx, 10, time.Hour)
+	mustNoErr(t, err)
+	if len(events) != 1 || events[0].ID != merged.ID {
+		t.Fatalf("expected only the first merge event, got %+v", events)
+	}
+
+	// Drafts and PRs closed concurrently are not merged.
+	createPR(t, r, "pr-2", pullrequest.StatusDraft)
+	if _, _, err := r.PRs.Merge(ctx, "pr-2", now()); !errors.Is(err, repo_errors.ErrPRStatusChanged) {
+		t.Fatalf("expected ErrPRStatusChanged for a draft, got %v", err)
+	}
+	createPR(t, r, "pr-3", pullrequest.StatusOpen, "u3")
+	mustNoErr(t, r.PRs.Close(ctx, "pr-3", now()))
+	if _, _, err := r.PRs.Merge(ctx, "pr-3", now()); !errors.Is(err, repo_errors.ErrPRStatusChanged) {
+		t.Fatalf("expected ErrPRStatusChanged for a closed PR, got %v", err)
+	}
+	pr, err = r.PRs.GetWithReviewers(ctx, "pr-3")
+	mustNoErr(t, err)
+	if pr.Status != pullrequest.StatusClosed || pr.MergedAt != nil {
+		t.Fatalf("closed PR was merged: %+v", pr)
+	}
+}
+
+func testLifecycle(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+	seed(t, r)
+	createPR(t, r, "pr-1", pullrequest.StatusDraft)
+
+	if err := r.PRs.MarkReady(ctx, "missing", nil); !errors.Is(err, repo_errors.ErrPRNotFound) {
+		t.Fatalf("expected ErrPRNotFound, got %v", err)
+	}
+	if err := r.PRs.Reopen(ctx, "pr-1", nil); !errors.Is(err, repo_errors.ErrPRStatusChanged) {
+		t.Fatalf("expected ErrPRStatusChanged, got %v", err)
+	}
+
+	mustNoErr(t, r.PRs.MarkReady(ctx, "pr-1", []string{"u3", "u2"}))
+	pr, err := r.PRs.GetWithReviewers(ctx, "pr-1")
+	mustNoErr(t, err)
+	if pr.Status != pullrequest.StatusOpen {
+		t.Fatalf("expected OPEN, got %s", pr.Status)
+	}
+	assertOrdered(t, "reviewers", pr.Reviewers, []string{"u2", "u3"})
+	if err := r.PRs.MarkReady(ctx, "pr-1", nil); !errors.Is(err, repo_errors.ErrPRStatusChanged) {
+		t.Fatalf("expected ErrPRStatusChanged, got %v", err)
+	}
+
+	closedAt := now()
+	mustNoErr(t, r.PRs.Close(ctx, "pr-1", closedAt))
+	pr, err = r.PRs.GetWithReviewers(ctx, "pr-1")
+	mustNoErr(t, err)
+	if pr.Status != pullrequest.StatusClosed || pr.ClosedAt == nil || !pr.ClosedAt.Equal(closedAt) || len(pr.Reviewers) != 0 {
+		t.Fatalf("unexpected closed PR: %+v", pr)
+	}
+	if err := r.PRs.Close(ctx, "pr-1", closedAt); !errors.Is(err, repo_errors.ErrPRStatusChanged) {
+		t.Fatalf("expected ErrPRStatusChanged, got %v", err)
+	}
+
+	mustNoErr(t, r.PRs.Reopen(ctx, "pr-1", []string{"u4"}))
+	pr, err = r.PRs.GetWithReviewers(ctx, "pr-1")
+	mustNoErr(t, err)
+	if pr.Status != pullrequest.StatusOpen || pr.ClosedAt != nil {
+		t.Fatalf("unexpected reopened PR: %+v", pr)
+	}
+	assertOrdered(t, "reviewers", pr.Reviewers, []string{"u4"})
+
+	_, _, err = r.PRs.Merge(ctx, "pr-1", now())
+	mustNoErr(t, err)
+	if err := r.PRs.Close(ctx, "pr-1", now()); !errors.Is(err, repo_errors.ErrPRStatusChanged) {
+		t.Fatalf("merged PR must not be closed, got %v", err)
+	}
+}
+
+func testHistory(t *testing.T, r repository.Repositories) {
+	ctx := actor.WithActor(context.Background(), "lead")
+	seed(t, r)
+
+	assigned := event.New(ctx, event.TypeReviewersAssigned, event.ReviewersAssigned{
+		PullRequestID: "pr-1", AuthorID: "u1", Reviewers: []string{"u2", "f1"}, Fallback: []string{"f1"}, Reason: event.ReasonAutoCreate,
+	})
+	pr := pullrequest.PullRequest{ID: "pr-1", Name: "Add search", AuthorID: "u1", Status: pullrequest.StatusOpen, CreatedAt: now(), Reviewers: []string{"u2", "f1"}}
+	mustNoErr(t, r.PRs.CreateWithReviewers(ctx, pr, assigned))
+
+	reassigned := event.New(ctx, event.TypeReviewerReassigned, event.ReviewerReassigned{
+		PullRequestID: "pr-1", OldReviewerID: "u2", NewReviewerID: "u3", Reason: event.ReasonManualReassign,
+	})
+	mustNoErr(t, r.PRs.ReplaceReviewers(ctx, "pr-1", "u2", "u3", reassigned))
+
+	removed := event.New(ctx, event.TypeReviewerRemoved, event.ReviewerRemoved{
+		PullRequestID: "pr-1", ReviewerID: "u3", Reason: event.ReasonTeamDeactivation,
+	})
+	mustNoErr(t, r.PRs.RemoveReviewer(ctx, "pr-1", "u3", removed))
+
+	hist, err := r.PRs.HistoryByPR(ctx, "pr-1")
+	mustNoErr(t, err)
+	if len(hist) != 4 {
+		t.Fatalf("expected 4 history entries, got %+v", hist)
+	}
+	want := []struct {
+		old, new, reason string
+		fallback         bool
+	}{
+		{"", "f1", event.ReasonFallback, true},
+		{"", "u2", event.ReasonAutoCreate, false},
+		{"u2", "u3", event.ReasonManualReassign, false},
+		{"u3", "", event.ReasonTeamDeactivation, false},
+	}
+	// Entries of one event may come in any order; sort the first two by new reviewer.
+	sort.SliceStable(hist[:2], func(i, j int) bool { return hist[i].NewReviewerID < hist[j].NewReviewerID })
+	for i, w := range want {
+		c := hist[i]
+		if c.PullRequestID != "pr-1" || c.OldReviewerID != w.old || c.NewReviewerID != w.new || c.Reason != w.reason || c.FromFallback != w.fallback {
+			t.Fatalf("entry %d: unexpected %+v", i, c)
+		}
+		if c.Actor != "lead" || c.ID == 0 || c.CreatedAt.IsZero() {
+			t.Fatalf("entry %d: missing metadata %+v", i, c)
+		}
+	}
+	if hist[1].ID >= hist[2].ID || hist[2].ID >= hist[3].ID {
+		t.Fatalf("history is not ordered by id: %+v", hist)
+	}
+
+	byUser, err := r.PRs.HistoryByUser(ctx, "u3")
+	mustNoErr(t, err)
+	if len(byUser) != 2 || byUser[0].Reason != event.ReasonManualReassign || byUser[1].Reason != event.ReasonTeamDeactivation {
+		t.Fatalf("unexpected history of u3: %+v", byUser)
+	}
+
+	empty, err := r.PRs.HistoryByPR(ctx, "missing")
+	mustNoErr(t, err)
+	if empty == nil || len(empty) != 0 {
+		t.Fatalf("expected an empty non-nil slice, got %#v", empty)
+	}
+}
+
+func testFailedChangeRecordsNothing(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+	seed(t, r)
+	createPR(t, r, "pr-1", pullrequest.StatusOpen, "u2")
+
+	ev := event.New(ctx, event.TypeReviewerReassigned, event.ReviewerReassigned{
+		PullRequestID: "pr-1", OldReviewerID: "u3", NewReviewerID: "u4", Reason: event.ReasonManualReassign,
+	})
+	if err := r.PRs.ReplaceReviewers(ctx, "pr-1", "u3", "u4", ev); !errors.Is(err, repo_errors.ErrReviewersNotFound) {
+		t.Fatalf("expected ErrReviewersNotFound, got %v", err)
+	}
+	if err := r.PRs.Reopen(ctx, "pr-1", []string{"u3"}, ev); !errors.Is(err, repo_errors.ErrPRStatusChanged) {
+		t.Fatalf("expected ErrPRStatusChanged, got %v", err)
+	}
+
+	events, err := r.Outbox.ClaimPending(ctx, 10, time.Hour)
+	mustNoErr(t, err)
+	if len(events) != 0 {
+		t.Fatalf("failed changes must not store events, got %+v", events)
+	}
+	hist, err := r.PRs.HistoryByPR(ctx, "pr-1")
+	mustNoErr(t, err)
+	if len(hist) != 0 {
+		t.Fatalf("failed changes must not store history, got %+v", hist)
+	}
+	pr, err := r.PRs.GetWithReviewers(ctx, "pr-1")
+	mustNoErr(t, err)
+	assertOrdered(t, "reviewers", pr.Reviewers, []string{"u2"})
+}
+
+func testStats(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+	seed(t, r)
+
+	stats, err := r.PRs.GetStats(ctx, repository.StatsFilter{})
+	mustNoErr(t, err)
+	if stats.PRStats != (repository.PRStats{}) || len(stats.Teams) != 0 || len(stats.Reviewers) != 0 {
+		t.Fatalf("expected empty stats, got %+v", stats)
+	}
+
+	createPR(t, r, "pr-1", pullrequest.StatusOpen, "u2", "u3")
+	createPR(t, r, "pr-2", pullrequest.StatusOpen, "u2")
+	createPR(t, r, "pr-3", pullrequest.StatusDraft)
+	createPR(t, r, "pr-4", pullrequest.StatusDraft)
+	_, _, err = r.PRs.Merge(ctx, "pr-2", now())
+	mustNoErr(t, err)
+	mustNoErr(t, r.PRs.Close(ctx, "pr-4", now()))
+
+	stats, err = r.PRs.GetStats(ctx, repository.StatsFilter{})
+	mustNoErr(t, err)
+	got := stats.PRStats
+	got.MedianTimeToMerge = 0
+	want := repository.PRStats{TotalPR: 4, OpenPR: 1, MergedPR: 1, DraftPR: 1, ClosedPR: 1}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, stats.PRStats)
+	}
+	if len(stats.Teams) != 1 || stats.Teams[0].TeamName != "backend" || stats.Teams[0].TotalPR != 4 {
+		t.Fatalf("unexpected team stats: %+v", stats.Teams)
+	}
+	wantReviewers := []repository.ReviewerStat{
+		{UserID: "u2", Count: 2, Open: 1, Completed: 1},
+		{UserID: "u3", Count: 1, Open: 1},
+	}
+	if !reflect.DeepEqual(stats.Reviewers, wantReviewers) {
+		t.Fatalf("expected reviewer stats %+v, got %+v", wantReviewers, stats.Reviewers)
+	}
+}
+
+func testStatsFilter(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+	seed(t, r)
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	create := func(id, author string, createdAt time.Time, reviewers ...string) {
+		t.Helper()
+		ev := event.New(ctx, event.TypeReviewersAssigned, event.ReviewersAssigned{
+			PullRequestID: id, AuthorID: author, Reviewers: reviewers, Reason: event.ReasonAutoCreate,
+		})
+		pr := pullrequest.PullRequest{ID: id, Name: "PR " + id, AuthorID: author, Status: pullrequest.StatusOpen, CreatedAt: createdAt, Reviewers: reviewers}
+		mustNoErr(t, r.PRs.CreateWithReviewers(ctx, pr, ev))
+	}
+	merge := func(id string, at time.Time) {
+		t.Helper()
+		_, _, err := r.PRs.Merge(ctx, id, at)
+		mustNoErr(t, err)
+	}
+
+	create("old", "u1", base.Add(-30*day), "u2")
+	create("b1", "u1", base, "u2", "u3")
+	merge("b1", base.Add(2*time.Hour))
+	create("b2", "u1", base.Add(day), "u2")
+	merge("b2", base.Add(day+4*time.Hour))
+	create("b3", "u1", base.Add(2*day), "u2", "u3")
+	removed := event.New(ctx, event.TypeReviewerRemoved, event.ReviewerRemoved{PullRequestID: "b3", ReviewerID: "u2", Reason: event.ReasonTeamDeactivation})
+	mustNoErr(t, r.PRs.RemoveReviewer(ctx, "b3", "u2", removed))
+	reassign := func(id, oldID, newID string, at time.Time) {
+		t.Helper()
+		ev := event.New(ctx, event.TypeReviewerReassigned, event.ReviewerReassigned{
+			PullRequestID: id, OldReviewerID: oldID, NewReviewerID: newID, Reason: event.ReasonManualReassign,
+		})
+		ev.OccurredAt = at
+		mustNoErr(t, r.PRs.ReplaceReviewers(ctx, id, oldID, newID, ev))
+	}
+	// Reassignments count by when they happened: the one on the old PR is in the period,
+	// the second one on b3 is after it.
+	reassign("old", "u2", "u3", base.Add(time.Hour))
+	reassign("b3", "u3", "u4", base.Add(2*day+time.Hour))
+	reassign("b3", "u4", "u3", base.Add(10*day))
+	create("f1", "f1", base.Add(day), "u2")
+	merge("f1", base.Add(day+10*time.Hour))
+
+	stats, err := r.PRs.GetStats(ctx, repository.StatsFilter{From: base, To: base.Add(3 * day)})
+	mustNoErr(t, err)
+	want := repository.Stats{
+		PRStats: repository.PRStats{TotalPR: 4, OpenPR: 1, MergedPR: 3, MedianTimeToMerge: 4 * time.Hour, Reassignments: 2},
+		Teams: []repository.TeamStats{
+			{TeamName: "backend", PRStats: repository.PRStats{TotalPR: 3, OpenPR: 1, MergedPR: 2, MedianTimeToMerge: 3 * time.Hour, Reassignments: 2}},
+			{TeamName: "frontend", PRStats: repository.PRStats{TotalPR: 1, MergedPR: 1, MedianTimeToMerge: 10 * time.Hour}},
+		},
+		Reviewers: []repository.ReviewerStat{
+			{UserID: "u2", Count: 3, Completed: 3},
+			{UserID: "u3", Count: 2, Open: 1, Completed: 1},
+		},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Fatalf("expected %+v, got %+v", want, stats)
+	}
+
+	stats, err = r.PRs.GetStats(ctx, repository.StatsFilter{From: base.Add(day), To: base.Add(2 * day)})
+	mustNoErr(t, err)
+	if stats.TotalPR != 2 || stats.MedianTimeToMerge != 7*time.Hour || stats.Reassignments != 0 {
+		t.Fatalf("unexpected stats for one day: %+v", stats.PRStats)
+	}
+
+	stats, err = r.PRs.GetStats(ctx, repository.StatsFilter{From: base.Add(5 * day)})
+	mustNoErr(t, err)
+	if stats.TotalPR != 0 || stats.Reassignments != 1 || len(stats.Teams) != 1 || stats.Teams[0].Reassignments != 1 {
+		t.Fatalf("reassignments must count by when they happened: %+v", stats)
+	}
+
+	stats, err = r.PRs.GetStats(ctx, repository.StatsFilter{To: base})
+	mustNoErr(t, err)
+	if stats.TotalPR != 1 || stats.OpenPR != 1 || stats.MedianTimeToMerge != 0 {
+		t.Fatalf("the upper bound must be exclusive: %+v", stats.PRStats)
+	}
+
+	stats, err = r.PRs.GetStats(ctx, repository.StatsFilter{TeamName: "frontend"})
+	mustNoErr(t, err)
+	if stats.TotalPR != 1 || stats.Reassignments != 0 || len(stats.Teams) != 1 || stats.Teams[0].TeamName != "frontend" {
+		t.Fatalf("unexpected frontend stats: %+v", stats)
+	}
+	if len(stats.Reviewers) != 1 || stats.Reviewers[0] != (repository.ReviewerStat{UserID: "u2", Count: 1, Completed: 1}) {
+		t.Fatalf("unexpected frontend reviewers: %+v", stats.Reviewers)
+	}
+}
+
+func testOutbox(t *testing.T, r repository.Repositories) {
+	ctx := actor.WithActor(context.Background(), "u1")
+	seed(t, r)
+
+	first := event.New(ctx, event.TypeReviewersAssigned, event.ReviewersAssigned{
+		PullRequestID: "pr-1", AuthorID: "u1", Reviewers: []string{"u2"}, Reason: event.ReasonAutoCreate,
+	})
+	pr := pullrequest.PullRequest{ID: "pr-1", Name: "Add search", AuthorID: "u1", Status: pullrequest.StatusOpen, CreatedAt: now(), Reviewers: []string{"u2"}}
+	mustNoErr(t, r.PRs.CreateWithReviewers(ctx, pr, first))
+	second := event.New(ctx, event.TypePRMerged, event.PRMerged{PullRequestID: "pr-1", AuthorID: "u1", Reviewers: []string{"u2"}})
+	_, _, err := r.PRs.Merge(ctx, "pr-1", now(), second)
+	mustNoErr(t, err)
+
+	claimed, err := r.Outbox.ClaimPending(ctx, 1, time.Hour)
+	mustNoErr(t, err)
+	if len(claimed) != 1 || claimed[0].ID != first.ID || claimed[0].Type != event.TypeReviewersAssigned || claimed[0].Actor != "u1" {
+		t.Fatalf("unexpected first claim: %+v", claimed)
+	}
+	if !claimed[0].OccurredAt.Truncate(time.Millisecond).Equal(first.OccurredAt.Truncate(time.Millisecond)) {
+		t.Fatalf("occurred_at changed: %v != %v", claimed[0].OccurredAt, first.OccurredAt)
+	}
+	raw, ok := claimed[0].Data.(json.RawMessage)
+	if !ok {
+		t.Fatalf("expected raw JSON payload, got %T", claimed[0].Data)
+	}
+	var payload event.ReviewersAssigned
+	mustNoErr(t, json.Unmarshal(raw, &payload))
+	if payload.PullRequestID != "pr-1" || len(payload.Reviewers) != 1 || payload.Reason != event.ReasonAutoCreate {
+		t.Fatalf("unexpected payload: %s", raw)
+	}
+
+	claimed, err = r.Outbox.ClaimPending(ctx, 10, time.Hour)
+	mustNoErr(t, err)
+	if len(claimed) != 1 || claimed[0].ID != second.ID {
+		t.Fatalf("leased events must not be claimed again, got %+v", claimed)
+	}
+
+	mustNoErr(t, r.Outbox.MarkPublished(ctx, []string{first.ID}))
+	dead, err := r.Outbox.MarkFailed(ctx, second.ID, errors.New("sink down"), 2)
+	mustNoErr(t, err)
+	if dead {
+		t.Fatalf("event dead-lettered before max attempts")
+	}
+	claimed, err = r.Outbox.ClaimPending(ctx, 10, time.Millisecond)
+	mustNoErr(t, err)
+	if len(claimed) != 0 {
+		t.Fatalf("failed events keep their lease, got %+v", claimed)
+	}
+
+	// The last allowed attempt dead-letters the event, it is not claimed any more.
+	dead, err = r.Outbox.MarkFailed(ctx, second.ID, errors.New("sink down"), 2)
+	mustNoErr(t, err)
+	if !dead {
+		t.Fatalf("expected event to be dead-lettered")
+	}
+	claimed, err = r.Outbox.ClaimPending(ctx, 10, time.Hour)
+	mustNoErr(t, err)
+	if len(claimed) != 0 {
+		t.Fatalf("dead-lettered events must not be claimed, got %+v", claimed)
+	}
+
+	// Only published events older than the retention are pruned.
+	pruned, err := r.Outbox.PrunePublished(ctx, time.Hour, 10)
+	mustNoErr(t, err)
+	if pruned != 0 {
+		t.Fatalf("pruned events within retention: %d", pruned)
+	}
+	time.Sleep(5 * time.Millisecond)
+	pruned, err = r.Outbox.PrunePublished(ctx, time.Millisecond, 10)
+	mustNoErr(t, err)
+	if pruned != 1 {
+		t.Fatalf("expected the published event to be pruned, got %d", pruned)
+	}
+}
+
+func testSubscribers(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+
+	list, err := r.Subscribers.List(ctx)
+	mustNoErr(t, err)
+	if len(list) != 0 {
+		t.Fatalf("expected no subscribers, got %+v", list)
+	}
+
+	a, err := r.Subscribers.Create(ctx, subscriber.Subscriber{URL: "https://a.example.com", Secret: "s1", Events: []event.Type{event.TypePRMerged}})
+	mustNoErr(t, err)
+	b, err := r.Subscribers.Create(ctx, subscriber.Subscriber{URL: "https://b.example.com", Secret: "s2"})
+	mustNoErr(t, err)
+	if a.ID == 0 || b.ID <= a.ID || !a.IsActive || a.CreatedAt.IsZero() {
+		t.Fatalf("unexpected created subscribers: %+v %+v", a, b)
+	}
+
+	list, err = r.Subscribers.List(ctx)
+	mustNoErr(t, err)
+	if len(list) != 2 || list[0].ID != a.ID || list[0].Secret != "s1" || len(list[0].Events) != 1 || list[0].Events[0] != event.TypePRMerged {
+		t.Fatalf("unexpected subscribers: %+v", list)
+	}
+	if len(list[1].Events) != 0 {
+		t.Fatalf("expected all events for b, got %v", list[1].Events)
+	}
+
+	mustNoErr(t, r.Subscribers.SaveDeadLetter(ctx, subscriber.DeadLetter{
+		SubscriberID: a.ID, EventID: "ev-1", EventType: event.TypePRMerged, Payload: []byte(`{}`), Attempts: 5, LastError: "timeout",
+	}))
+
+	mustNoErr(t, r.Subscribers.Delete(ctx, a.ID))
+	if err := r.Subscribers.Delete(ctx, a.ID); !errors.Is(err, repo_errors.ErrSubscriberNotFound) {
+		t.Fatalf("expected ErrSubscriberNotFound, got %v", err)
+	}
+	list, err = r.Subscribers.List(ctx)
+	mustNoErr(t, err)
+	if len(list) != 1 || list[0].ID != b.ID {
+		t.Fatalf("unexpected subscribers after delete: %+v", list)
+	}
+}
+
+func testSubscriberRetries(t *testing.T, r repository.Repositories) {
+	ctx := context.Background()
+	base := now()
+
+	a, err := r.Subscribers.Create(ctx, subscriber.Subscriber{URL: "https://a.example.com", Secret: "s1"})
+	mustNoErr(t, err)
+	b, err := r.Subscribers.Create(ctx, subscriber.Subscriber{URL: "https://b.example.com", Secret: "s2"})
+	mustNoErr(t, err)
+
+	ids, err := r.Subscribers.RetryingSubscribers(ctx)
+	mustNoErr(t, err)
+	if len(ids) != 0 {
+		t.Fatalf("expected no retrying subscribers, got %v", ids)
+	}
+
+	retry := func(sub int64, eventID string, attempts int, next time.Time) subscriber.Retry {
+		return subscriber.Retry{
+			SubscriberID: sub, EventID: eventID, EventType: event.TypePRMerged, Payload: []byte(`{"id":"` + eventID + `"}`),
+			Attempts: attempts, LastError: "timeout", NextAttemptAt: next,
+		}
+	}
+	mustNoErr(t, r.Subscribers.ScheduleRetry(ctx, retry(a.ID, "ev-1", 1, base)))
+	// A redelivered event does not queue the delivery twice.
+	mustNoErr(t, r.Subscribers.ScheduleRetry(ctx, retry(a.ID, "ev-1", 9, base)))
+	mustNoErr(t, r.Subscribers.ScheduleRetry(ctx, retry(a.ID, "ev-2", 0, base.Add(time.Hour))))
+	mustNoErr(t, r.Subscribers.ScheduleRetry(ctx, retry(b.ID, "ev-1", 1, base)))
+
+	ids, err = r.Subscribers.RetryingSubscribers(ctx)
+	mustNoErr(t, err)
+	if !reflect.DeepEqual(ids, []int64{a.ID, b.ID}) {
+		t.Fatalf("unexpected retrying subscribers: %v", ids)
+	}
+
+	due, err := r.Subscribers.ClaimDueRetries(ctx, base, 10, time.Minute)
+	mustNoErr(t, err)
+	if len(due) != 2 || due[0].SubscriberID != a.ID || due[1].SubscriberID != b.ID || due[0].ID >= due[1].ID {
+		t.Fatalf("unexpected due retries: %+v", due)
+	}
+	if due[0].EventID != "ev-1" || due[0].Attempts != 1 || due[0].LastError != "timeout" || string(due[0].Payload) != `{"id":"ev-1"}` {
+		t.Fatalf("retry was not kept: %+v", due[0])
+	}
+	claimed, err := r.Subscribers.ClaimDueRetries(ctx, base, 10, time.Minute)
+	mustNoErr(t, err)
+	if len(claimed) != 0 {
+		t.Fatalf("claimed retries keep their lease, got %+v", claimed)
+	}
+
+	first := due[0]
+	first.Attempts, first.LastError, first.NextAttemptAt = 2, "unexpected status 503", base.Add(3*time.Hour)
+	mustNoErr(t, r.Subscribers.RescheduleRetry(ctx, first))
+	mustNoErr(t, r.Subscribers.DeleteRetry(ctx, due[1].ID))
+
+	// ev-2 is due, but waits behind the earlier delivery of its subscriber.
+	claimed, err = r.Subscribers.ClaimDueRetries(ctx, base.Add(2*time.Hour), 10, time.Hour)
+	mustNoErr(t, err)
+	if len(claimed) != 0 {
+		t.Fatalf("claimed a delivery ahead of an earlier one: %+v", claimed)
+	}
+	claimed, err = r.Subscribers.ClaimDueRetries(ctx, base.Add(4*time.Hour), 10, time.Hour)
+	mustNoErr(t, err)
+	if len(claimed) != 2 || claimed[0].ID != first.ID || claimed[0].Attempts != 2 || claimed[0].LastError != "unexpected status 503" {
+		t.Fatalf("unexpected due retries: %+v", claimed)
+	}
+	if claimed[1].EventID != "ev-2" || claimed[1].Attempts != 0 {
+		t.Fatalf("unexpected due retries: %+v", claimed)
+	}
+
+	first.Attempts = 3
+	mustNoErr(t, r.Subscribers.DeadLetterRetry(ctx, first))
+	ids, err = r.Subscribers.RetryingSubscribers(ctx)
+	mustNoErr(t, err)
+	if !reflect.DeepEqual(ids, []int64{a.ID}) {
+		t.Fatalf("unexpected retrying subscribers: %v", ids)
+	}
+
+	// Retries go away with their subscriber.
+	mustNoErr(t, r.Subscribers.Delete(ctx, a.ID))
+	ids, err = r.Subscribers.RetryingSubscribers(ctx)
+	mustNoErr(t, err)
+	if len(ids) != 0 {
+		t.Fatalf("expected no retrying subscribers, got %v", ids)
+	}
+}
+
+func mustNoErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func userIDs(users []*user.User) []string {
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+func assertSet(t *testing.T, what string, got, want []string) {
+	t.Helper()
+	g := append([]string(nil), got...)
+	w := append([]string(nil), want...)
+	sort.Strings(g)
+	sort.Strings(w)
+	assertOrdered(t, what, g, w)
+}
+
+func assertOrdered(t *testing.T, what string, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: expected %v, got %v", what, want, got)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("%s: expected %v, got %v", what, want, got)
+		}
+	}
+}
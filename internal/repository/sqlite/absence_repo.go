@@ -0,0 +1,190 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type AbsenceRepository struct {
+	db *sql.DB
+}
+
+func NewAbsenceRepository(db *sql.DB) *AbsenceRepository {
+	return &AbsenceRepository{db: db}
+}
+
+func (r *AbsenceRepository) Create(ctx context.Context, a user.Absence) (user.Absence, error) {
+	const op = "internal.repository.sqlite.absence_repo.Create"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	INSERT INTO user_absences (user_id, starts_at, ends_at, reason, created_at)
+	SELECT user_id, ?2, ?3, ?4, ?5 FROM users WHERE user_id = ?1
+	RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx, q, a.UserID, encodeTime(a.StartsAt), encodeTime(a.EndsAt), a.Reason, encodeTime(time.Now())).Scan(&a.ID)
+	if err == sql.ErrNoRows {
+		return user.Absence{}, fmt.Errorf("%s: %w", op, repo_errors.ErrUserNotFound)
+	}
+	if err != nil {
+		return user.Absence{}, fmt.Errorf("%s, QueryRow: %w", op, err)
+	}
+	a.HandledAt, a.Attempts, a.NextAttemptAt = nil, 0, nil
+	return a, nil
+}
+
+func (r *AbsenceRepository) Get(ctx context.Context, id int64) (user.Absence, error) {
+	const op = "internal.repository.sqlite.absence_repo.Get"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	SELECT id, user_id, starts_at, ends_at, reason, handled_at, attempts, next_attempt_at
+	FROM user_absences
+	WHERE id = ?1
+	`
+
+	res, err := r.query(ctx, q, id)
+	if err != nil {
+		return user.Absence{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(res) == 0 {
+		return user.Absence{}, fmt.Errorf("%s: %w", op, repo_errors.ErrAbsenceNotFound)
+	}
+	return res[0], nil
+}
+
+// ListByUser returns the user's absences ordered by start.
+func (r *AbsenceRepository) ListByUser(ctx context.Context, userID string) ([]user.Absence, error) {
+	const op = "internal.repository.sqlite.absence_repo.ListByUser"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	SELECT id, user_id, starts_at, ends_at, reason, handled_at, attempts, next_attempt_at
+	FROM user_absences
+	WHERE user_id = ?1
+	ORDER BY starts_at, id
+	`
+
+	res, err := r.query(ctx, q, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return res, nil
+}
+
+func (r *AbsenceRepository) Delete(ctx context.Context, id int64) error {
+	const op = "internal.repository.sqlite.absence_repo.Delete"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	res, err := r.db.ExecContext(ctx, `DELETE FROM user_absences WHERE id = ?1`, id)
+	if err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("%s, RowsAffected: %w", op, err)
+	} else if n == 0 {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrAbsenceNotFound)
+	}
+	return nil
+}
+
+func (r *AbsenceRepository) ListStarted(ctx context.Context, now time.Time, limit int) ([]user.Absence, error) {
+	const op = "internal.repository.sqlite.absence_repo.ListStarted"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	SELECT id, user_id, starts_at, ends_at, reason, handled_at, attempts, next_attempt_at
+	FROM user_absences
+	WHERE handled_at IS NULL AND starts_at <= ?1 AND ends_at > ?1
+	  AND (next_attempt_at IS NULL OR next_attempt_at <= ?1)
+	ORDER BY starts_at, id
+	LIMIT ?2
+	`
+
+	res, err := r.query(ctx, q, encodeTime(now), limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return res, nil
+}
+
+func (r *AbsenceRepository) MarkHandled(ctx context.Context, id int64, now time.Time) error {
+	const op = "internal.repository.sqlite.absence_repo.MarkHandled"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	res, err := r.db.ExecContext(ctx, `UPDATE user_absences SET handled_at = ?2 WHERE id = ?1`, id, encodeTime(now))
+	if err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("%s, RowsAffected: %w", op, err)
+	} else if n == 0 {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrAbsenceNotFound)
+	}
+	return nil
+}
+
+func (r *AbsenceRepository) MarkFailed(ctx context.Context, id int64, next time.Time) error {
+	const op = "internal.repository.sqlite.absence_repo.MarkFailed"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	UPDATE user_absences
+	SET attempts = attempts + 1, next_attempt_at = ?2
+	WHERE id = ?1
+	`
+
+	res, err := r.db.ExecContext(ctx, q, id, encodeTime(next))
+	if err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("%s, RowsAffected: %w", op, err)
+	} else if n == 0 {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrAbsenceNotFound)
+	}
+	return nil
+}
+
+func (r *AbsenceRepository) query(ctx context.Context, q string, args ...any) ([]user.Absence, error) {
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("QueryContext: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]user.Absence, 0)
+	for rows.Next() {
+		var a user.Absence
+		err := rows.Scan(&a.ID, &a.UserID, timeValue{&a.StartsAt}, timeValue{&a.EndsAt}, &a.Reason, nullTimeValue{&a.HandledAt}, &a.Attempts, nullTimeValue{&a.NextAttemptAt})
+		if err != nil {
+			return nil, fmt.Errorf("Scan: %w", err)
+		}
+		res = append(res, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+	return res, nil
+}
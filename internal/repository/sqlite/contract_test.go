@@ -0,0 +1,61 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/repository"
+	"github.com/hihikaAAa/PRManager/internal/repository/repotest"
+	"github.com/hihikaAAa/PRManager/internal/repository/sqlite"
+)
+
+func TestContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repository.Repositories {
+		return sqlite.Repositories(open(t, filepath.Join(t.TempDir(), "prmanager.db")))
+	})
+}
+
+func TestMigrationsRoundTrip(t *testing.T) {
+	db := open(t, ":memory:")
+	m, err := sqlite.Migrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if _, err := m.Down(ctx, m.Latest()); err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	var tables int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name NOT IN ('schema_migrations', 'sqlite_sequence')`).Scan(&tables); err != nil {
+		t.Fatal(err)
+	}
+	if tables != 0 {
+		t.Fatalf("down left %d tables", tables)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("up again: %v", err)
+	}
+}
+
+// open returns a migrated database.
+func open(t *testing.T, path string) *sql.DB {
+	t.Helper()
+
+	db, err := sqlite.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	m, err := sqlite.Migrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS assignment_events;
+DROP TABLE IF EXISTS outbox;
+DROP TABLE IF EXISTS webhook_dead_letters;
+DROP TABLE IF EXISTS webhook_subscribers;
+DROP TABLE IF EXISTS user_identities;
+DROP TABLE IF EXISTS team_fallbacks;
+DROP TABLE IF EXISTS team_settings;
+DROP TABLE IF EXISTS pull_request_reviewers;
+DROP TABLE IF EXISTS pull_requests;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS teams;
@@ -0,0 +1,125 @@
+-- SQLite schema equivalent to the postgres migrations 001-009.
+-- Enums are TEXT with CHECK constraints, arrays are JSON text and timestamps are
+-- fixed-width RFC 3339 UTC text, so that they compare in chronological order.
+
+CREATE TABLE teams (
+    team_name TEXT PRIMARY KEY
+);
+
+CREATE TABLE users (
+    user_id TEXT PRIMARY KEY,
+    username TEXT NOT NULL,
+    team_name TEXT NOT NULL REFERENCES teams(team_name) ON DELETE RESTRICT,
+    is_active INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX idx_users_team_active ON users(team_name, is_active);
+
+CREATE TABLE pull_requests (
+    pull_request_id TEXT PRIMARY KEY,
+    pull_request_name TEXT NOT NULL,
+    author_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE RESTRICT,
+    status TEXT NOT NULL CHECK (status IN ('OPEN', 'MERGED', 'DRAFT', 'CLOSED')),
+    created_at TEXT NOT NULL,
+    merged_at TEXT,
+    closed_at TEXT
+);
+
+CREATE TABLE pull_request_reviewers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE RESTRICT,
+    state TEXT NOT NULL DEFAULT 'PENDING' CHECK (state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    state_updated_at TEXT,
+    UNIQUE (pull_request_id, user_id)
+);
+
+CREATE INDEX idx_pr_reviewers_user ON pull_request_reviewers(user_id);
+
+CREATE TABLE team_settings (
+    team_name TEXT PRIMARY KEY REFERENCES teams(team_name) ON DELETE CASCADE,
+    reviewers_required INTEGER NOT NULL DEFAULT 2 CHECK (reviewers_required >= 0),
+    min_reviewers INTEGER NOT NULL DEFAULT 0 CHECK (min_reviewers >= 0),
+    strategy TEXT NOT NULL DEFAULT '',
+    required_approvals INTEGER NOT NULL DEFAULT 0 CHECK (required_approvals >= 0),
+    CHECK (min_reviewers <= reviewers_required)
+);
+
+CREATE TABLE team_fallbacks (
+    team_name TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    fallback_team TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (team_name, fallback_team),
+    CHECK (team_name <> fallback_team)
+);
+
+CREATE TABLE user_identities (
+    provider TEXT NOT NULL,
+    login TEXT NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    PRIMARY KEY (provider, login)
+);
+
+CREATE TABLE webhook_subscribers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    events TEXT NOT NULL DEFAULT '[]',
+    is_active INTEGER NOT NULL DEFAULT 1,
+    created_at TEXT NOT NULL
+);
+
+CREATE TABLE webhook_dead_letters (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    subscriber_id INTEGER NOT NULL REFERENCES webhook_subscribers(id) ON DELETE CASCADE,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    last_error TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+CREATE TABLE outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id TEXT NOT NULL UNIQUE,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    occurred_at TEXT NOT NULL,
+    actor TEXT NOT NULL DEFAULT 'system',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    locked_until TEXT,
+    published_at TEXT
+);
+
+CREATE INDEX idx_outbox_pending ON outbox(id) WHERE published_at IS NULL;
+
+CREATE TABLE assignment_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id TEXT NOT NULL,
+    old_reviewer_id TEXT,
+    new_reviewer_id TEXT,
+    reason TEXT NOT NULL CHECK (reason IN ('auto_create', 'fallback', 'manual_reassign', 'team_deactivation', 'pr_closed')),
+    from_fallback INTEGER NOT NULL DEFAULT 0,
+    actor TEXT NOT NULL,
+    event_id TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    CHECK (old_reviewer_id IS NOT NULL OR new_reviewer_id IS NOT NULL)
+);
+
+CREATE INDEX idx_assignment_events_pr ON assignment_events(pull_request_id, id);
+CREATE INDEX idx_assignment_events_old ON assignment_events(old_reviewer_id, id);
+CREATE INDEX idx_assignment_events_new ON assignment_events(new_reviewer_id, id);
+
+CREATE TRIGGER assignment_events_no_update
+BEFORE UPDATE ON assignment_events
+BEGIN
+    SELECT RAISE(ABORT, 'assignment_events is append-only');
+END;
+
+CREATE TRIGGER assignment_events_no_delete
+BEFORE DELETE ON assignment_events
+BEGIN
+    SELECT RAISE(ABORT, 'assignment_events is append-only');
+END;
@@ -0,0 +1,55 @@
+-- Fails while there are users without a team or team_change events: history is
+-- append-only and they would violate the old constraints.
+PRAGMA foreign_keys = OFF;
+
+BEGIN;
+
+CREATE TABLE users_new (
+    user_id TEXT PRIMARY KEY,
+    username TEXT NOT NULL,
+    team_name TEXT NOT NULL REFERENCES teams(team_name) ON DELETE RESTRICT,
+    is_active INTEGER NOT NULL DEFAULT 1
+);
+
+INSERT INTO users_new SELECT * FROM users;
+DROP TABLE users;
+ALTER TABLE users_new RENAME TO users;
+
+CREATE INDEX idx_users_team_active ON users(team_name, is_active);
+
+CREATE TABLE assignment_events_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id TEXT NOT NULL,
+    old_reviewer_id TEXT,
+    new_reviewer_id TEXT,
+    reason TEXT NOT NULL CHECK (reason IN ('auto_create', 'fallback', 'manual_reassign', 'team_deactivation', 'pr_closed', 'import')),
+    from_fallback INTEGER NOT NULL DEFAULT 0,
+    actor TEXT NOT NULL,
+    event_id TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    CHECK (old_reviewer_id IS NOT NULL OR new_reviewer_id IS NOT NULL)
+);
+
+INSERT INTO assignment_events_new SELECT * FROM assignment_events;
+DROP TABLE assignment_events;
+ALTER TABLE assignment_events_new RENAME TO assignment_events;
+
+CREATE INDEX idx_assignment_events_pr ON assignment_events(pull_request_id, id);
+CREATE INDEX idx_assignment_events_old ON assignment_events(old_reviewer_id, id);
+CREATE INDEX idx_assignment_events_new ON assignment_events(new_reviewer_id, id);
+
+CREATE TRIGGER assignment_events_no_update
+BEFORE UPDATE ON assignment_events
+BEGIN
+    SELECT RAISE(ABORT, 'assignment_events is append-only');
+END;
+
+CREATE TRIGGER assignment_events_no_delete
+BEFORE DELETE ON assignment_events
+BEGIN
+    SELECT RAISE(ABORT, 'assignment_events is append-only');
+END;
+
+COMMIT;
+
+PRAGMA foreign_keys = ON;
@@ -0,0 +1,58 @@
+-- Users removed from their team (/team/removeMembers) keep their PRs and history but have
+-- no team, and reviews handed over when a member leaves a team get their own reason.
+-- SQLite cannot drop NOT NULL or alter a CHECK constraint, so both tables are rebuilt.
+-- Other tables reference users, so foreign keys are switched off around the rebuild;
+-- the pragma has no effect inside a transaction, hence the explicit one.
+PRAGMA foreign_keys = OFF;
+
+BEGIN;
+
+CREATE TABLE users_new (
+    user_id TEXT PRIMARY KEY,
+    username TEXT NOT NULL,
+    team_name TEXT REFERENCES teams(team_name) ON DELETE RESTRICT,
+    is_active INTEGER NOT NULL DEFAULT 1
+);
+
+INSERT INTO users_new SELECT * FROM users;
+DROP TABLE users;
+ALTER TABLE users_new RENAME TO users;
+
+CREATE INDEX idx_users_team_active ON users(team_name, is_active);
+
+CREATE TABLE assignment_events_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id TEXT NOT NULL,
+    old_reviewer_id TEXT,
+    new_reviewer_id TEXT,
+    reason TEXT NOT NULL CHECK (reason IN ('auto_create', 'fallback', 'manual_reassign', 'team_deactivation', 'pr_closed', 'import', 'team_change')),
+    from_fallback INTEGER NOT NULL DEFAULT 0,
+    actor TEXT NOT NULL,
+    event_id TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    CHECK (old_reviewer_id IS NOT NULL OR new_reviewer_id IS NOT NULL)
+);
+
+INSERT INTO assignment_events_new SELECT * FROM assignment_events;
+DROP TABLE assignment_events;
+ALTER TABLE assignment_events_new RENAME TO assignment_events;
+
+CREATE INDEX idx_assignment_events_pr ON assignment_events(pull_request_id, id);
+CREATE INDEX idx_assignment_events_old ON assignment_events(old_reviewer_id, id);
+CREATE INDEX idx_assignment_events_new ON assignment_events(new_reviewer_id, id);
+
+CREATE TRIGGER assignment_events_no_update
+BEFORE UPDATE ON assignment_events
+BEGIN
+    SELECT RAISE(ABORT, 'assignment_events is append-only');
+END;
+
+CREATE TRIGGER assignment_events_no_delete
+BEFORE DELETE ON assignment_events
+BEGIN
+    SELECT RAISE(ABORT, 'assignment_events is append-only');
+END;
+
+COMMIT;
+
+PRAGMA foreign_keys = ON;
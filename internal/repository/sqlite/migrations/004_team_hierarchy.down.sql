@@ -0,0 +1,21 @@
+-- SQLite cannot drop a column with a foreign key, so teams is rebuilt.
+-- Other tables reference teams, so foreign keys are switched off around the rebuild.
+PRAGMA foreign_keys = OFF;
+
+BEGIN;
+
+ALTER TABLE team_settings DROP COLUMN search_parents;
+
+DROP INDEX idx_teams_parent;
+
+CREATE TABLE teams_new (
+    team_name TEXT PRIMARY KEY
+);
+
+INSERT INTO teams_new SELECT team_name FROM teams;
+DROP TABLE teams;
+ALTER TABLE teams_new RENAME TO teams;
+
+COMMIT;
+
+PRAGMA foreign_keys = ON;
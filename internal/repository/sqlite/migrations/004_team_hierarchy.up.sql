@@ -0,0 +1,7 @@
+-- Teams form a tree (organization -> department -> team). /team/delete re-parents
+-- the children of a deleted team; SET NULL only keeps manual deletes consistent.
+ALTER TABLE teams ADD COLUMN parent_team TEXT REFERENCES teams(team_name) ON DELETE SET NULL CHECK (parent_team <> team_name);
+CREATE INDEX idx_teams_parent ON teams(parent_team);
+
+-- Candidates are searched in the parent teams when the team itself has too few.
+ALTER TABLE team_settings ADD COLUMN search_parents INTEGER NOT NULL DEFAULT 0;
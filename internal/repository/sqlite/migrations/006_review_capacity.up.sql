@@ -0,0 +1,3 @@
+-- A user with max_open_reviews > 0 is not picked as a reviewer while they already
+-- review that many open PRs; 0 means no limit.
+ALTER TABLE users ADD COLUMN max_open_reviews INTEGER NOT NULL DEFAULT 0 CHECK (max_open_reviews >= 0);
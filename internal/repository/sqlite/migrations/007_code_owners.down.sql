@@ -0,0 +1,2 @@
+DROP TABLE team_code_owners;
+ALTER TABLE pull_requests DROP COLUMN changed_files;
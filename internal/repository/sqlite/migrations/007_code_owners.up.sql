@@ -0,0 +1,12 @@
+-- Paths changed by the PR as a JSON array; reviewers owning them are preferred.
+ALTER TABLE pull_requests ADD COLUMN changed_files TEXT NOT NULL DEFAULT '[]';
+
+-- CODEOWNERS-style rules of a team in file order; a later matching rule wins.
+-- owners is a JSON array of user ids.
+CREATE TABLE team_code_owners (
+    team_name TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    pattern TEXT NOT NULL,
+    owners TEXT NOT NULL,
+    PRIMARY KEY (team_name, position)
+);
@@ -0,0 +1,17 @@
+-- Deliveries waiting for their next attempt. The notifier tries a subscriber once
+-- inline; a transient failure is queued here and retried by the notifier's worker.
+-- Attempts counts the failed ones.
+CREATE TABLE webhook_retries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    subscriber_id INTEGER NOT NULL REFERENCES webhook_subscribers(id) ON DELETE CASCADE,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    UNIQUE (subscriber_id, event_id)
+);
+
+CREATE INDEX idx_webhook_retries_due ON webhook_retries(next_attempt_at);
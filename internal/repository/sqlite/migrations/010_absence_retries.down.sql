@@ -0,0 +1,2 @@
+ALTER TABLE user_absences DROP COLUMN next_attempt_at;
+ALTER TABLE user_absences DROP COLUMN attempts;
@@ -0,0 +1,4 @@
+-- A failed handover is retried with a backoff: the absence job skips the absence
+-- until next_attempt_at, so it does not hold back the ones started after it.
+ALTER TABLE user_absences ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE user_absences ADD COLUMN next_attempt_at TEXT;
@@ -0,0 +1,188 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+)
+
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// recordEvents stores events in the caller's transaction so that they are
+// committed or rolled back together with the change they describe.
+func recordEvents(ctx context.Context, tx *sql.Tx, events []event.Event) error {
+	if err := insertOutbox(ctx, tx, events); err != nil {
+		return err
+	}
+	return insertAssignmentChanges(ctx, tx, events)
+}
+
+func insertOutbox(ctx context.Context, tx *sql.Tx, events []event.Event) error {
+	const q = `
+	INSERT INTO outbox (event_id, event_type, payload, occurred_at, actor)
+	VALUES (?1, ?2, ?3, ?4, ?5)
+	`
+
+	for _, ev := range events {
+		payload, err := json.Marshal(ev.Data)
+		if err != nil {
+			return fmt.Errorf("Marshal outbox payload: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, q, ev.ID, string(ev.Type), string(payload), encodeTime(ev.OccurredAt), ev.Actor); err != nil {
+			return fmt.Errorf("Exec insert outbox: %w", err)
+		}
+	}
+	return nil
+}
+
+// ClaimPending locks up to limit unpublished events for the lease duration.
+// Events whose lease expired (crashed relay, failed sink) are claimed again.
+func (r *OutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]event.Event, error) {
+	const op = "internal.repository.sqlite.outbox_repo.ClaimPending"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	UPDATE outbox
+	SET locked_until = ?3
+	WHERE id IN (
+		SELECT id
+		FROM outbox
+		WHERE published_at IS NULL AND dead_at IS NULL AND (locked_until IS NULL OR locked_until <= ?2)
+		ORDER BY id
+		LIMIT ?1
+	)
+	RETURNING id, event_id, event_type, payload, occurred_at, actor
+	`
+
+	now := time.Now()
+	rows, err := r.db.QueryContext(ctx, q, limit, encodeTime(now), encodeTime(now.Add(lease)))
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	type claimed struct {
+		id int64
+		ev event.Event
+	}
+	var batch []claimed
+	for rows.Next() {
+		var (
+			c       claimed
+			typ     string
+			payload string
+		)
+		if err := rows.Scan(&c.id, &c.ev.ID, &typ, &payload, timeValue{&c.ev.OccurredAt}, &c.ev.Actor); err != nil {
+			return nil, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		c.ev.Type = event.Type(typ)
+		c.ev.Data = json.RawMessage(payload)
+		batch = append(batch, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s, rows: %w", op, err)
+	}
+
+	// RETURNING does not keep the subquery order.
+	events := make([]event.Event, len(batch))
+	sort.Slice(batch, func(i, j int) bool { return batch[i].id < batch[j].id })
+	for i, c := range batch {
+		events[i] = c.ev
+	}
+	return events, nil
+}
+
+func (r *OutboxRepository) MarkPublished(ctx context.Context, eventIDs []string) error {
+	const op = "internal.repository.sqlite.outbox_repo.MarkPublished"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	in, args := inArgs(2, eventIDs)
+	q := `
+	UPDATE outbox
+	SET published_at = ?1, locked_until = NULL
+	WHERE event_id IN (` + in + `)
+	`
+
+	if _, err := r.db.ExecContext(ctx, q, append([]any{encodeTime(time.Now())}, args...)...); err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	return nil
+}
+
+// MarkFailed records the failure and keeps the lease, so the event is retried after it expires.
+// The failure that reaches maxAttempts dead-letters the event instead.
+func (r *OutboxRepository) MarkFailed(ctx context.Context, eventID string, cause error, maxAttempts int) (bool, error) {
+	const op = "internal.repository.sqlite.outbox_repo.MarkFailed"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	UPDATE outbox
+	SET attempts = attempts + 1,
+	    last_error = ?2,
+	    dead_at = CASE WHEN attempts + 1 >= ?3 THEN ?4 END,
+	    locked_until = CASE WHEN attempts + 1 >= ?3 THEN NULL ELSE locked_until END
+	WHERE event_id = ?1
+	RETURNING dead_at IS NOT NULL
+	`
+
+	var dead bool
+	err := r.db.QueryRowContext(ctx, q, eventID, cause.Error(), maxAttempts, encodeTime(time.Now())).Scan(&dead)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("%s, QueryRowContext: %w", op, err)
+	}
+	return dead, nil
+}
+
+func (r *OutboxRepository) PrunePublished(ctx context.Context, retention time.Duration, limit int) (int, error) {
+	const op = "internal.repository.sqlite.outbox_repo.PrunePublished"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	DELETE FROM outbox
+	WHERE id IN (
+		SELECT id
+		FROM outbox
+		WHERE published_at < ?1
+		ORDER BY published_at
+		LIMIT ?2
+	)
+	`
+
+	res, err := r.db.ExecContext(ctx, q, encodeTime(time.Now().Add(-retention)), limit)
+	if err != nil {
+		return 0, fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s, RowsAffected: %w", op, err)
+	}
+	return int(n), nil
+}
@@ -0,0 +1,630 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type PRRepository struct {
+	db *sql.DB
+}
+
+func NewPRRepository(db *sql.DB) *PRRepository {
+	return &PRRepository{db: db}
+}
+
+// CreateWithReviewers stores the PR with its reviewers and the events in one transaction.
+func (r *PRRepository) CreateWithReviewers(ctx context.Context, pr pullrequest.PullRequest, events ...event.Event) error {
+	const op = "internal.repository.sqlite.pr_repo.CreateWithReviewers"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := r.createMany(ctx, []pullrequest.PullRequest{pr}, events); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// CreateManyWithReviewers stores the PRs with their reviewers and the events in one transaction.
+func (r *PRRepository) CreateManyWithReviewers(ctx context.Context, prs []pullrequest.PullRequest, events ...event.Event) error {
+	const op = "internal.repository.sqlite.pr_repo.CreateManyWithReviewers"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := r.createMany(ctx, prs, events); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (r *PRRepository) createMany(ctx context.Context, prs []pullrequest.PullRequest, events []event.Event) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	const qPR = `
+	INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at, merged_at, changed_files)
+	VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7)
+	`
+
+	for _, pr := range prs {
+		files, err := encodeStrings(pr.ChangedFiles)
+		if err != nil {
+			return fmt.Errorf("%s: %w", pr.ID, err)
+		}
+		if _, err := tx.ExecContext(ctx, qPR, pr.ID, pr.Name, pr.AuthorID, pr.Status, encodeTime(pr.CreatedAt), encodeNullTime(pr.MergedAt), files); err != nil {
+			return fmt.Errorf("ExecContextPr %s: %w", pr.ID, err)
+		}
+		if err := insertReviewers(ctx, tx, pr.ID, pr.Reviewers); err != nil {
+			return fmt.Errorf("%s: %w", pr.ID, err)
+		}
+	}
+	if err := recordEvents(ctx, tx, events); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("Commit: %w", err)
+	}
+	return nil
+}
+
+func (r *PRRepository) GetWithReviewers(ctx context.Context, id string) (*pullrequest.PullRequest, error) {
+	const op = "internal.repository.sqlite.pr_repo.GetWithReviewers"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	pr, err := r.get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return pr, nil
+}
+
+func (r *PRRepository) get(ctx context.Context, id string) (*pullrequest.PullRequest, error) {
+	const qPR = `
+	SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at, closed_at, changed_files
+	FROM pull_requests
+	WHERE pull_request_id = ?1
+	`
+
+	pr := &pullrequest.PullRequest{}
+	err := r.db.QueryRowContext(ctx, qPR, id).Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status,
+		timeValue{&pr.CreatedAt}, nullTimeValue{&pr.MergedAt}, nullTimeValue{&pr.ClosedAt}, stringsValue{&pr.ChangedFiles})
+	if err == sql.ErrNoRows {
+		return nil, repo_errors.ErrPRNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("QueryRow: %w", err)
+	}
+
+	reviews, err := r.getReviews(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	pr.Reviews = reviews
+	pr.Reviewers = make([]string, 0, len(reviews))
+	for _, rv := range reviews {
+		pr.Reviewers = append(pr.Reviewers, rv.UserID)
+	}
+	return pr, nil
+}
+
+func (r *PRRepository) getReviews(ctx context.Context, prID string) ([]pullrequest.Review, error) {
+	const q = `
+	SELECT user_id, state, state_updated_at
+	FROM pull_request_reviewers
+	WHERE pull_request_id = ?1
+	ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, q, prID)
+	if err != nil {
+		return nil, fmt.Errorf("QueryContext reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []pullrequest.Review{}
+	for rows.Next() {
+		var rv pullrequest.Review
+		if err := rows.Scan(&rv.UserID, &rv.State, nullTimeValue{&rv.UpdatedAt}); err != nil {
+			return nil, fmt.Errorf("Scan reviews: %w", err)
+		}
+		reviews = append(reviews, rv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// Merge moves an OPEN PR to MERGED. The events are stored only when the
+// status actually changed, so repeated merges do not emit them again.
+// A PR that was moved to another status concurrently gives ErrPRStatusChanged.
+func (r *PRRepository) Merge(ctx context.Context, id string, now time.Time, events ...event.Event) (*pullrequest.PullRequest, bool, error) {
+	const op = "internal.repository.sqlite.pr_repo.Merge"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	const qUpdate = `
+	UPDATE pull_requests
+	SET status = ?2, merged_at = COALESCE(merged_at, ?3)
+	WHERE pull_request_id = ?1 AND status = 'OPEN'
+	`
+
+	res, err := tx.ExecContext(ctx, qUpdate, id, pullrequest.StatusMerged, encodeTime(now))
+	if err != nil {
+		return nil, false, fmt.Errorf("%s, Exec update: %w", op, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, false, fmt.Errorf("%s, RowsAffected: %w", op, err)
+	}
+	merged := affected > 0
+	if merged {
+		if err := recordEvents(ctx, tx, events); err != nil {
+			return nil, false, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("%s, Commit: %w", op, err)
+	}
+
+	pr, err := r.get(ctx, id)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+	if !merged && pr.Status != pullrequest.StatusMerged {
+		return nil, false, fmt.Errorf("%s: %w", op, repo_errors.ErrPRStatusChanged)
+	}
+	return pr, merged, nil
+}
+
+func (r *PRRepository) ReplaceReviewers(ctx context.Context, prID, oldRevID, newRevID string, events ...event.Event) error {
+	const op = "internal.repository.sqlite.pr_repo.ReplaceReviewers"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := deleteReviewer(ctx, tx, prID, oldRevID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := insertReviewers(ctx, tx, prID, []string{newRevID}); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := recordEvents(ctx, tx, events); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s, Commit: %w", op, err)
+	}
+	return nil
+}
+
+func (r *PRRepository) RemoveReviewer(ctx context.Context, prID, revID string, events ...event.Event) error {
+	const op = "internal.repository.sqlite.pr_repo.RemoveReviewer"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := deleteReviewer(ctx, tx, prID, revID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := recordEvents(ctx, tx, events); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s, Commit: %w", op, err)
+	}
+	return nil
+}
+
+// HandOver applies the prepared handovers and stores their events in one transaction.
+func (r *PRRepository) HandOver(ctx context.Context, handovers []assignment.Handover) error {
+	const op = "internal.repository.sqlite.pr_repo.HandOver"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if len(handovers) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := applyHandovers(ctx, tx, handovers); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s, Commit: %w", op, err)
+	}
+	return nil
+}
+
+// applyHandovers applies prepared handovers in the caller's transaction.
+func applyHandovers(ctx context.Context, tx *sql.Tx, handovers []assignment.Handover) error {
+	events := make([]event.Event, 0, len(handovers))
+	for _, h := range handovers {
+		if err := deleteReviewer(ctx, tx, h.PullRequestID, h.ReviewerID); err != nil {
+			return err
+		}
+		if h.NewReviewerID != "" {
+			if err := insertReviewers(ctx, tx, h.PullRequestID, []string{h.NewReviewerID}); err != nil {
+				return err
+			}
+		}
+		events = append(events, h.Event)
+	}
+	return recordEvents(ctx, tx, events)
+}
+
+// deleteReviewer unassigns revID from a not merged PR.
+func deleteReviewer(ctx context.Context, tx *sql.Tx, prID, revID string) error {
+	status, err := prStatus(ctx, tx, prID)
+	if err != nil {
+		return err
+	}
+	if status == pullrequest.StatusMerged {
+		return repo_errors.ErrPRMerged
+	}
+
+	const qDel = `
+	DELETE FROM pull_request_reviewers
+	WHERE pull_request_id = ?1 AND user_id = ?2
+	`
+
+	res, err := tx.ExecContext(ctx, qDel, prID, revID)
+	if err != nil {
+		return fmt.Errorf("Exec delete: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("RowsAffected delete: %w", err)
+	}
+	if affected == 0 {
+		return repo_errors.ErrReviewersNotFound
+	}
+	return nil
+}
+
+func prStatus(ctx context.Context, tx *sql.Tx, prID string) (pullrequest.Status, error) {
+	const q = `SELECT status FROM pull_requests WHERE pull_request_id = ?1`
+
+	var status pullrequest.Status
+	err := tx.QueryRowContext(ctx, q, prID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", repo_errors.ErrPRNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("QueryRow PR: %w", err)
+	}
+	return status, nil
+}
+
+func (r *PRRepository) FindShortByReviewer(ctx context.Context, userID string) ([]pullrequest.PullRequestShort, error) {
+	const op = "internal.repository.sqlite.pr_repo.FindShortByReviewer"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status
+	FROM pull_requests pr
+	JOIN pull_request_reviewers r ON pr.pull_request_id = r.pull_request_id
+	WHERE r.user_id = ?1
+	ORDER BY pr.rowid
+	`
+
+	rows, err := r.db.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	var result []pullrequest.PullRequestShort
+	for rows.Next() {
+		var s pullrequest.PullRequestShort
+		if err := rows.Scan(&s.ID, &s.Name, &s.AuthorID, &s.Status); err != nil {
+			return nil, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		result = append(result, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s, rows.Err: %w", op, err)
+	}
+	return result, nil
+}
+
+func (r *PRRepository) GetOpenPRIDsByReviewer(ctx context.Context, userID string) ([]string, error) {
+	const op = "internal.repository.sqlite.pr_repo.GetOpenPRIDsByReviewer"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	SELECT pr.pull_request_id
+	FROM pull_requests pr
+	JOIN pull_request_reviewers r ON pr.pull_request_id = r.pull_request_id
+	WHERE r.user_id = ?1 AND pr.status = 'OPEN'
+	ORDER BY pr.rowid
+	`
+
+	rows, err := r.db.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s, rows.Err: %w", op, err)
+	}
+	return ids, nil
+}
+
+func (r *PRRepository) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	const op = "internal.repository.sqlite.pr_repo.CountOpenReviews"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	loads := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return loads, nil
+	}
+
+	in, args := inArgs(1, userIDs)
+	q := `
+	SELECT r.user_id, COUNT(*)
+	FROM pull_request_reviewers r
+	JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+	WHERE r.user_id IN (` + in + `) AND pr.status = 'OPEN'
+	GROUP BY r.user_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var uid string
+		var cnt int
+		if err := rows.Scan(&uid, &cnt); err != nil {
+			return nil, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		loads[uid] = cnt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s, rows.Err: %w", op, err)
+	}
+	return loads, nil
+}
+
+func (r *PRRepository) OpenReviewsByTeam(ctx context.Context) (map[string]int, error) {
+	const op = "internal.repository.sqlite.pr_repo.OpenReviewsByTeam"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	SELECT u.team_name, COUNT(*)
+	FROM pull_request_reviewers r
+	JOIN pull_requests pr ON pr.pull_request_id = r.pull_request_id
+	JOIN users u ON u.user_id = r.user_id
+	WHERE pr.status = 'OPEN' AND u.team_name <> ''
+	GROUP BY u.team_name
+	`
+
+	rows, err := r.db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var teamName string
+		var cnt int
+		if err := rows.Scan(&teamName, &cnt); err != nil {
+			return nil, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		counts[teamName] = cnt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s, rows.Err: %w", op, err)
+	}
+	return counts, nil
+}
+
+func (r *PRRepository) SetReviewState(ctx context.Context, prID, userID string, state pullrequest.ReviewState, now time.Time) error {
+	const op = "internal.repository.sqlite.pr_repo.SetReviewState"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	status, err := prStatus(ctx, tx, prID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if status == pullrequest.StatusMerged {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrPRMerged)
+	}
+
+	const qUpd = `
+	UPDATE pull_request_reviewers
+	SET state = ?3, state_updated_at = ?4
+	WHERE pull_request_id = ?1 AND user_id = ?2
+	`
+
+	res, err := tx.ExecContext(ctx, qUpd, prID, userID, state, encodeTime(now))
+	if err != nil {
+		return fmt.Errorf("%s, Exec update: %w", op, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s, RowsAffected update: %w", op, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrReviewersNotFound)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s, Commit: %w", op, err)
+	}
+	return nil
+}
+
+func (r *PRRepository) MarkReady(ctx context.Context, prID string, reviewers []string, events ...event.Event) error {
+	const op = "internal.repository.sqlite.pr_repo.MarkReady"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const qUpd = `
+	UPDATE pull_requests
+	SET status = 'OPEN'
+	WHERE pull_request_id = ?1 AND status = 'DRAFT'
+	`
+
+	return r.transition(ctx, op, prID, reviewers, events, qUpd)
+}
+
+func (r *PRRepository) Close(ctx context.Context, prID string, now time.Time, events ...event.Event) error {
+	const op = "internal.repository.sqlite.pr_repo.Close"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const qUpd = `
+	UPDATE pull_requests
+	SET status = 'CLOSED', closed_at = ?2
+	WHERE pull_request_id = ?1 AND status IN ('OPEN', 'DRAFT')
+	`
+
+	return r.transition(ctx, op, prID, nil, events, qUpd, encodeTime(now))
+}
+
+func (r *PRRepository) Reopen(ctx context.Context, prID string, reviewers []string, events ...event.Event) error {
+	const op = "internal.repository.sqlite.pr_repo.Reopen"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const qUpd = `
+	UPDATE pull_requests
+	SET status = 'OPEN', closed_at = NULL
+	WHERE pull_request_id = ?1 AND status = 'CLOSED'
+	`
+
+	return r.transition(ctx, op, prID, reviewers, events, qUpd)
+}
+
+// transition runs the conditional status update q, replaces the reviewers with the
+// given ones (none for a closed PR) and stores the events in one transaction.
+func (r *PRRepository) transition(ctx context.Context, op, prID string, reviewers []string, events []event.Event, q string, args ...any) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, q, append([]any{prID}, args...)...)
+	if err != nil {
+		return fmt.Errorf("%s, Exec update: %w", op, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s, RowsAffected update: %w", op, err)
+	}
+	if affected == 0 {
+		if _, err := prStatus(ctx, tx, prID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrPRStatusChanged)
+	}
+
+	const qDel = `DELETE FROM pull_request_reviewers WHERE pull_request_id = ?1`
+
+	if _, err := tx.ExecContext(ctx, qDel, prID); err != nil {
+		return fmt.Errorf("%s, Exec delete: %w", op, err)
+	}
+	if err := insertReviewers(ctx, tx, prID, reviewers); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := recordEvents(ctx, tx, events); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s, Commit: %w", op, err)
+	}
+	return nil
+}
+
+// insertReviewers adds a batch of reviewers. Reviews are read back in insertion order,
+// so the batch is inserted sorted by user id, like the postgres (created_at, user_id) order.
+func insertReviewers(ctx context.Context, tx *sql.Tx, prID string, reviewers []string) error {
+	const qRev = `
+	INSERT INTO pull_request_reviewers (pull_request_id, user_id)
+	VALUES (?1, ?2)
+	`
+
+	sorted := append([]string(nil), reviewers...)
+	sort.Strings(sorted)
+	for _, reviewerID := range sorted {
+		if _, err := tx.ExecContext(ctx, qRev, prID, reviewerID); err != nil {
+			return fmt.Errorf("Exec insert reviewer: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+// Package sqlite is a single-file storage backend for single-node and developer deployments.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/hihikaAAa/PRManager/internal/migrator"
+	"github.com/hihikaAAa/PRManager/internal/repository"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// New opens the database file at path. Use ":memory:" for a throwaway database.
+// The schema is created by the migrator returned from Migrator.
+func New(path string) (*sql.DB, error) {
+	const op = "internal.repository.sqlite.New"
+
+	dsn := "file:" + path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+	if path != ":memory:" {
+		dsn += "&_pragma=journal_mode(WAL)"
+	}
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	// SQLite has a single writer; one connection serializes transactions
+	// (the postgres backend relies on row locks instead) and keeps ":memory:" a single database.
+	db.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return db, nil
+}
+
+// Migrator returns the migrator for the SQLite schema.
+func Migrator(db *sql.DB) (*migrator.Migrator, error) {
+	sub, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return migrator.New(db, sub, migrator.SQLite)
+}
+
+// Repositories returns all repositories sharing db.
+func Repositories(db *sql.DB) repository.Repositories {
+	return repository.Repositories{
+		PRs:         NewPRRepository(db),
+		Users:       NewUserRepository(db),
+		Teams:       NewTeamRepository(db),
+		Subscribers: NewSubscriberRepository(db),
+		Outbox:      NewOutboxRepository(db),
+		Absences:    NewAbsenceRepository(db),
+	}
+}
+
+// Timestamps are stored as fixed-width UTC text, so that string order is chronological.
+const timeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+func encodeTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+func encodeNullTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return encodeTime(*t)
+}
+
+// timeValue scans a stored timestamp into a time.Time.
+type timeValue struct {
+	t *time.Time
+}
+
+func (v timeValue) Scan(src any) error {
+	s, err := asString(src)
+	if err != nil {
+		return err
+	}
+	parsed, err := time.Parse(timeLayout, s)
+	if err != nil {
+		return err
+	}
+	*v.t = parsed
+	return nil
+}
+
+// nullTimeValue scans a nullable stored timestamp into a *time.Time.
+type nullTimeValue struct {
+	t **time.Time
+}
+
+func (v nullTimeValue) Scan(src any) error {
+	if src == nil {
+		*v.t = nil
+		return nil
+	}
+	var t time.Time
+	if err := (timeValue{t: &t}).Scan(src); err != nil {
+		return err
+	}
+	*v.t = &t
+	return nil
+}
+
+// encodeStrings stores a string list as a JSON array; nil is stored as an empty one.
+func encodeStrings(values []string) (string, error) {
+	if values == nil {
+		values = []string{}
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// stringsValue scans a JSON array stored by encodeStrings into a string list.
+type stringsValue struct {
+	v *[]string
+}
+
+func (v stringsValue) Scan(src any) error {
+	s, err := asString(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(s), v.v)
+}
+
+func asString(src any) (string, error) {
+	switch s := src.(type) {
+	case string:
+		return s, nil
+	case []byte:
+		return string(s), nil
+	default:
+		return "", fmt.Errorf("unexpected text type %T", src)
+	}
+}
+
+// inArgs returns "?n,?n+1,..." placeholders for values starting at position first.
+func inArgs(first int, values []string) (string, []any) {
+	ph := make([]string, 0, len(values))
+	args := make([]any, 0, len(values))
+	for i, v := range values {
+		ph = append(ph, "?"+strconv.Itoa(first+i))
+		args = append(args, v)
+	}
+	return strings.Join(ph, ", "), args
+}
@@ -0,0 +1,187 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+	"github.com/hihikaAAa/PRManager/internal/repository"
+)
+
+// statsPRs selects the PRs matching the stats filter (?1 from, ?2 to, ?3 team)
+// with the current team of their author.
+const statsPRs = `
+	WITH prs AS (
+		SELECT p.pull_request_id, p.status, p.created_at, p.merged_at, COALESCE(u.team_name, '') AS team_name
+		FROM pull_requests p
+		JOIN users u ON u.user_id = p.author_id
+		WHERE (?1 IS NULL OR p.created_at >= ?1)
+		  AND (?2 IS NULL OR p.created_at < ?2)
+		  AND (?3 = '' OR u.team_name = ?3)
+	)
+`
+
+func (r *PRRepository) GetStats(ctx context.Context, f repository.StatsFilter) (repository.Stats, error) {
+	const op = "internal.repository.sqlite.stats_repo.GetStats"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	args := []any{encodeFilterTime(f.From), encodeFilterTime(f.To), f.TeamName}
+	stats := repository.Stats{}
+	teams := make(map[string]*repository.TeamStats)
+	team := func(name string) *repository.TeamStats {
+		if name == "" {
+			return nil
+		}
+		if teams[name] == nil {
+			teams[name] = &repository.TeamStats{TeamName: name}
+		}
+		return teams[name]
+	}
+
+	const qStatus = statsPRs + `
+	SELECT team_name, status, COUNT(*)
+	FROM prs
+	GROUP BY team_name, status
+	`
+
+	rows, err := r.db.QueryContext(ctx, qStatus, args...)
+	if err != nil {
+		return stats, fmt.Errorf("%s, QueryContext status: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var teamName string
+		var status pullrequest.Status
+		var cnt int
+		if err := rows.Scan(&teamName, &status, &cnt); err != nil {
+			return stats, fmt.Errorf("%s, Scan status: %w", op, err)
+		}
+		stats.AddStatus(status, cnt)
+		if ts := team(teamName); ts != nil {
+			ts.AddStatus(status, cnt)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return stats, fmt.Errorf("%s, rowsErr status: %w", op, err)
+	}
+	rows.Close()
+
+	// SQLite has no percentile aggregate, so the medians are taken in Go.
+	const qMerged = statsPRs + `
+	SELECT team_name, created_at, merged_at
+	FROM prs
+	WHERE status = 'MERGED' AND merged_at IS NOT NULL
+	`
+
+	mRows, err := r.db.QueryContext(ctx, qMerged, args...)
+	if err != nil {
+		return stats, fmt.Errorf("%s, QueryContext merged: %w", op, err)
+	}
+	defer mRows.Close()
+
+	var merged []time.Duration
+	mergedByTeam := make(map[string][]time.Duration)
+	for mRows.Next() {
+		var teamName string
+		var createdAt, mergedAt time.Time
+		if err := mRows.Scan(&teamName, timeValue{&createdAt}, timeValue{&mergedAt}); err != nil {
+			return stats, fmt.Errorf("%s, Scan merged: %w", op, err)
+		}
+		d := mergedAt.Sub(createdAt)
+		merged = append(merged, d)
+		mergedByTeam[teamName] = append(mergedByTeam[teamName], d)
+	}
+	if err := mRows.Err(); err != nil {
+		return stats, fmt.Errorf("%s, rowsErr merged: %w", op, err)
+	}
+	mRows.Close()
+
+	stats.MedianTimeToMerge = repository.MedianDuration(merged)
+	for name, ds := range mergedByTeam {
+		if ts := team(name); ts != nil {
+			ts.MedianTimeToMerge = repository.MedianDuration(ds)
+		}
+	}
+
+	// Reassignments are counted by when they happened, on PRs of any age.
+	const qReassignments = `
+	SELECT COALESCE(u.team_name, ''), COUNT(*)
+	FROM assignment_events e
+	JOIN pull_requests p ON p.pull_request_id = e.pull_request_id
+	JOIN users u ON u.user_id = p.author_id
+	WHERE e.old_reviewer_id IS NOT NULL AND e.new_reviewer_id IS NOT NULL
+	  AND (?1 IS NULL OR e.created_at >= ?1)
+	  AND (?2 IS NULL OR e.created_at < ?2)
+	  AND (?3 = '' OR u.team_name = ?3)
+	GROUP BY 1
+	`
+
+	aRows, err := r.db.QueryContext(ctx, qReassignments, args...)
+	if err != nil {
+		return stats, fmt.Errorf("%s, QueryContext reassignments: %w", op, err)
+	}
+	defer aRows.Close()
+
+	for aRows.Next() {
+		var teamName string
+		var cnt int
+		if err := aRows.Scan(&teamName, &cnt); err != nil {
+			return stats, fmt.Errorf("%s, Scan reassignments: %w", op, err)
+		}
+		stats.Reassignments += cnt
+		if ts := team(teamName); ts != nil {
+			ts.Reassignments = cnt
+		}
+	}
+	if err := aRows.Err(); err != nil {
+		return stats, fmt.Errorf("%s, rowsErr reassignments: %w", op, err)
+	}
+	aRows.Close()
+
+	for _, ts := range teams {
+		stats.Teams = append(stats.Teams, *ts)
+	}
+	sort.Slice(stats.Teams, func(i, j int) bool { return stats.Teams[i].TeamName < stats.Teams[j].TeamName })
+
+	const qReviewers = statsPRs + `
+	SELECT r.user_id, COUNT(*),
+		SUM(CASE WHEN prs.status = 'OPEN' THEN 1 ELSE 0 END),
+		SUM(CASE WHEN prs.status = 'MERGED' THEN 1 ELSE 0 END)
+	FROM pull_request_reviewers r
+	JOIN prs ON prs.pull_request_id = r.pull_request_id
+	GROUP BY r.user_id
+	ORDER BY r.user_id
+	`
+
+	rRows, err := r.db.QueryContext(ctx, qReviewers, args...)
+	if err != nil {
+		return stats, fmt.Errorf("%s, QueryContext reviewers: %w", op, err)
+	}
+	defer rRows.Close()
+
+	for rRows.Next() {
+		var s repository.ReviewerStat
+		if err := rRows.Scan(&s.UserID, &s.Count, &s.Open, &s.Completed); err != nil {
+			return stats, fmt.Errorf("%s, Scan reviewers: %w", op, err)
+		}
+		stats.Reviewers = append(stats.Reviewers, s)
+	}
+	if err := rRows.Err(); err != nil {
+		return stats, fmt.Errorf("%s, rowsErr reviewers: %w", op, err)
+	}
+	return stats, nil
+}
+
+// encodeFilterTime stores an unset bound as NULL.
+func encodeFilterTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return encodeTime(t)
+}
@@ -0,0 +1,289 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	"github.com/hihikaAAa/PRManager/internal/domain/subscriber"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type SubscriberRepository struct {
+	db *sql.DB
+}
+
+func NewSubscriberRepository(db *sql.DB) *SubscriberRepository {
+	return &SubscriberRepository{db: db}
+}
+
+func (r *SubscriberRepository) Create(ctx context.Context, s subscriber.Subscriber) (subscriber.Subscriber, error) {
+	const op = "internal.repository.sqlite.subscriber_repo.Create"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	events, err := json.Marshal(s.Events)
+	if err != nil {
+		return subscriber.Subscriber{}, fmt.Errorf("%s, Marshal events: %w", op, err)
+	}
+
+	const q = `
+	INSERT INTO webhook_subscribers (url, secret, events, is_active, created_at)
+	VALUES (?1, ?2, ?3, 1, ?4)
+	RETURNING id, is_active, created_at
+	`
+
+	err = r.db.QueryRowContext(ctx, q, s.URL, s.Secret, string(events), encodeTime(time.Now())).
+		Scan(&s.ID, &s.IsActive, timeValue{&s.CreatedAt})
+	if err != nil {
+		return subscriber.Subscriber{}, fmt.Errorf("%s, QueryRow: %w", op, err)
+	}
+	return s, nil
+}
+
+// List returns all subscribers ordered by id, secrets included.
+func (r *SubscriberRepository) List(ctx context.Context) ([]subscriber.Subscriber, error) {
+	const op = "internal.repository.sqlite.subscriber_repo.List"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	SELECT id, url, secret, events, is_active, created_at
+	FROM webhook_subscribers
+	ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	var res []subscriber.Subscriber
+	for rows.Next() {
+		var (
+			s      subscriber.Subscriber
+			events string
+		)
+		if err := rows.Scan(&s.ID, &s.URL, &s.Secret, &events, &s.IsActive, timeValue{&s.CreatedAt}); err != nil {
+			return nil, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		if err := json.Unmarshal([]byte(events), &s.Events); err != nil {
+			return nil, fmt.Errorf("%s, Unmarshal events: %w", op, err)
+		}
+		if len(s.Events) == 0 {
+			s.Events = nil
+		}
+		res = append(res, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s, rows: %w", op, err)
+	}
+	return res, nil
+}
+
+func (r *SubscriberRepository) Delete(ctx context.Context, id int64) error {
+	const op = "internal.repository.sqlite.subscriber_repo.Delete"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `DELETE FROM webhook_subscribers WHERE id = ?1`
+
+	res, err := r.db.ExecContext(ctx, q, id)
+	if err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s, RowsAffected: %w", op, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrSubscriberNotFound)
+	}
+	return nil
+}
+
+func (r *SubscriberRepository) SaveDeadLetter(ctx context.Context, dl subscriber.DeadLetter) error {
+	const op = "internal.repository.sqlite.subscriber_repo.SaveDeadLetter"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	INSERT INTO webhook_dead_letters (subscriber_id, event_id, event_type, payload, attempts, last_error, created_at)
+	VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7)
+	`
+
+	_, err := r.db.ExecContext(ctx, q, dl.SubscriberID, dl.EventID, string(dl.EventType), string(dl.Payload), dl.Attempts, dl.LastError, encodeTime(time.Now()))
+	if err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	return nil
+}
+
+func (r *SubscriberRepository) ScheduleRetry(ctx context.Context, rt subscriber.Retry) error {
+	const op = "internal.repository.sqlite.subscriber_repo.ScheduleRetry"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	INSERT INTO webhook_retries (subscriber_id, event_id, event_type, payload, attempts, last_error, next_attempt_at, created_at)
+	VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8)
+	ON CONFLICT (subscriber_id, event_id) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, q, rt.SubscriberID, rt.EventID, string(rt.EventType), string(rt.Payload), rt.Attempts, rt.LastError,
+		encodeTime(rt.NextAttemptAt), encodeTime(time.Now()))
+	if err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	return nil
+}
+
+func (r *SubscriberRepository) RetryingSubscribers(ctx context.Context) ([]int64, error) {
+	const op = "internal.repository.sqlite.subscriber_repo.RetryingSubscribers"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT DISTINCT subscriber_id FROM webhook_retries ORDER BY subscriber_id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s, rows: %w", op, err)
+	}
+	return ids, nil
+}
+
+func (r *SubscriberRepository) ClaimDueRetries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]subscriber.Retry, error) {
+	const op = "internal.repository.sqlite.subscriber_repo.ClaimDueRetries"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	UPDATE webhook_retries
+	SET next_attempt_at = ?3
+	WHERE id IN (
+		SELECT r.id
+		FROM webhook_retries r
+		WHERE r.next_attempt_at <= ?1
+		  AND NOT EXISTS (
+			SELECT 1
+			FROM webhook_retries e
+			WHERE e.subscriber_id = r.subscriber_id AND e.id < r.id AND e.next_attempt_at > ?1
+		  )
+		ORDER BY r.id
+		LIMIT ?2
+	)
+	RETURNING id, subscriber_id, event_id, event_type, payload, attempts, last_error, next_attempt_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, q, encodeTime(now), limit, encodeTime(now.Add(lease)))
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	var res []subscriber.Retry
+	for rows.Next() {
+		var (
+			rt           subscriber.Retry
+			typ, payload string
+		)
+		if err := rows.Scan(&rt.ID, &rt.SubscriberID, &rt.EventID, &typ, &payload, &rt.Attempts, &rt.LastError, timeValue{&rt.NextAttemptAt}); err != nil {
+			return nil, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		rt.EventType = event.Type(typ)
+		rt.Payload = []byte(payload)
+		res = append(res, rt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s, rows: %w", op, err)
+	}
+
+	// RETURNING does not keep the subquery order.
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res, nil
+}
+
+func (r *SubscriberRepository) RescheduleRetry(ctx context.Context, rt subscriber.Retry) error {
+	const op = "internal.repository.sqlite.subscriber_repo.RescheduleRetry"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	UPDATE webhook_retries
+	SET attempts = ?2, last_error = ?3, next_attempt_at = ?4
+	WHERE id = ?1
+	`
+
+	if _, err := r.db.ExecContext(ctx, q, rt.ID, rt.Attempts, rt.LastError, encodeTime(rt.NextAttemptAt)); err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	return nil
+}
+
+func (r *SubscriberRepository) DeleteRetry(ctx context.Context, id int64) error {
+	const op = "internal.repository.sqlite.subscriber_repo.DeleteRetry"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM webhook_retries WHERE id = ?1`, id); err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	return nil
+}
+
+func (r *SubscriberRepository) DeadLetterRetry(ctx context.Context, rt subscriber.Retry) error {
+	const op = "internal.repository.sqlite.subscriber_repo.DeadLetterRetry"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	const qInsert = `
+	INSERT INTO webhook_dead_letters (subscriber_id, event_id, event_type, payload, attempts, last_error, created_at)
+	SELECT subscriber_id, event_id, event_type, payload, ?2, ?3, ?4
+	FROM webhook_retries
+	WHERE id = ?1
+	`
+
+	if _, err := tx.ExecContext(ctx, qInsert, rt.ID, rt.Attempts, rt.LastError, encodeTime(time.Now())); err != nil {
+		return fmt.Errorf("%s, Exec insert: %w", op, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_retries WHERE id = ?1`, rt.ID); err != nil {
+		return fmt.Errorf("%s, Exec delete: %w", op, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s, Commit: %w", op, err)
+	}
+	return nil
+}
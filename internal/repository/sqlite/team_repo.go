@@ -0,0 +1,548 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+)
+
+type TeamRepository struct {
+	db *sql.DB
+}
+
+func NewTeamRepository(db *sql.DB) *TeamRepository {
+	return &TeamRepository{db: db}
+}
+
+func (r *TeamRepository) CreateTeam(ctx context.Context, name string) error {
+	const op = "internal.repository.sqlite.team_repo.CreateTeam"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `INSERT INTO teams (team_name) VALUES (?1)`
+
+	if _, err := r.db.ExecContext(ctx, q, name); err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	return nil
+}
+
+func (r *TeamRepository) Exists(ctx context.Context, name string) (bool, error) {
+	const op = "internal.repository.sqlite.team_repo.Exists"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `SELECT 1 FROM teams WHERE team_name = ?1`
+
+	var dummy int
+	err := r.db.QueryRowContext(ctx, q, name).Scan(&dummy)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("%s, QueryRow: %w", op, err)
+	}
+	return true, nil
+}
+
+func (r *TeamRepository) GetWithMembers(ctx context.Context, name string) (*team.Team, error) {
+	const op = "internal.repository.sqlite.team_repo.GetWithMembers"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	t := &team.Team{}
+	const qTeam = `SELECT team_name, COALESCE(parent_team, '') FROM teams WHERE team_name = ?1`
+	if err := r.db.QueryRowContext(ctx, qTeam, name).Scan(&t.TeamName, &t.ParentTeam); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, repo_errors.ErrTeamNotFound)
+		}
+		return nil, fmt.Errorf("%s, QueryRow: %w", op, err)
+	}
+
+	const qMembers = `
+	SELECT user_id, username, team_name, is_active, max_open_reviews
+	FROM users
+	WHERE team_name = ?1
+	ORDER BY user_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, qMembers, name)
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		u := &user.User{}
+		if err := rows.Scan(&u.ID, &u.Name, &u.TeamName, &u.IsActive, &u.MaxOpenReviews); err != nil {
+			return nil, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		t.Members = append(t.Members, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s, rows.Err: %w", op, err)
+	}
+	return t, nil
+}
+
+func (r *TeamRepository) List(ctx context.Context) ([]team.Team, error) {
+	const op = "internal.repository.sqlite.team_repo.List"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `SELECT team_name, COALESCE(parent_team, '') FROM teams ORDER BY team_name`
+
+	rows, err := r.db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	var teams []team.Team
+	for rows.Next() {
+		var t team.Team
+		if err := rows.Scan(&t.TeamName, &t.ParentTeam); err != nil {
+			return nil, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		teams = append(teams, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s, rows.Err: %w", op, err)
+	}
+	return teams, nil
+}
+
+func (r *TeamRepository) SetParent(ctx context.Context, name, parent string) error {
+	const op = "internal.repository.sqlite.team_repo.SetParent"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// The single connection serializes transactions, so the tree cannot change between the check and the move.
+	if parent != "" {
+		rows, err := tx.QueryContext(ctx, qChain+`SELECT team_name FROM chain`, parent)
+		if err != nil {
+			return fmt.Errorf("%s, QueryContext: %w", op, err)
+		}
+		found, cycle := false, false
+		for rows.Next() {
+			var t string
+			if err := rows.Scan(&t); err != nil {
+				rows.Close()
+				return fmt.Errorf("%s, Scan: %w", op, err)
+			}
+			found = true
+			cycle = cycle || t == name
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("%s, rows.Err: %w", op, err)
+		}
+		if !found {
+			return fmt.Errorf("%s: %w", op, repo_errors.ErrParentTeamNotFound)
+		}
+		if cycle {
+			return fmt.Errorf("%s: %w", op, team.ErrInvalidParent)
+		}
+	}
+
+	const q = `UPDATE teams SET parent_team = NULLIF(?2, '') WHERE team_name = ?1`
+
+	res, err := tx.ExecContext(ctx, q, name, parent)
+	if err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("%s, RowsAffected: %w", op, err)
+	} else if n == 0 {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrTeamNotFound)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s, Commit: %w", op, err)
+	}
+	return nil
+}
+
+// qChain walks from the team up to the root; depth 0 is the team itself.
+// The depth bound stops the walk should a cycle ever get into the table.
+const qChain = `
+	WITH RECURSIVE chain (team_name, parent_team, depth) AS (
+		SELECT team_name, parent_team, 0 FROM teams WHERE team_name = ?1
+		UNION ALL
+		SELECT t.team_name, t.parent_team, c.depth + 1
+		FROM teams t
+		JOIN chain c ON t.team_name = c.parent_team
+		WHERE c.depth < 32
+	)
+`
+
+func (r *TeamRepository) Ancestors(ctx context.Context, name string) ([]string, error) {
+	const op = "internal.repository.sqlite.team_repo.Ancestors"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = qChain + `SELECT team_name FROM chain ORDER BY depth`
+
+	rows, err := r.db.QueryContext(ctx, q, name)
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	var chain []string
+	for rows.Next() {
+		var t string
+		if err := rows.Scan(&t); err != nil {
+			return nil, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		chain = append(chain, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s, rows.Err: %w", op, err)
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, repo_errors.ErrTeamNotFound)
+	}
+	return chain[1:], nil
+}
+
+// GetSettings returns the team's own settings or, when it has none, those of the
+// nearest ancestor that has them.
+func (r *TeamRepository) GetSettings(ctx context.Context, name string) (team.Settings, error) {
+	const op = "internal.repository.sqlite.team_repo.GetSettings"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = qChain + `
+	SELECT c.team_name, s.reviewers_required, s.min_reviewers, s.strategy, s.required_approvals, s.search_parents
+	FROM chain c
+	LEFT JOIN team_settings s ON s.team_name = c.team_name
+	ORDER BY s.team_name IS NULL, c.depth
+	LIMIT 1
+	`
+
+	var (
+		source    string
+		required  sql.NullInt64
+		min       sql.NullInt64
+		strategy  sql.NullString
+		approvals sql.NullInt64
+		parents   sql.NullBool
+	)
+	if err := r.db.QueryRowContext(ctx, q, name).Scan(&source, &required, &min, &strategy, &approvals, &parents); err != nil {
+		if err == sql.ErrNoRows {
+			return team.Settings{}, fmt.Errorf("%s: %w", op, repo_errors.ErrTeamNotFound)
+		}
+		return team.Settings{}, fmt.Errorf("%s, QueryRow: %w", op, err)
+	}
+
+	s := team.DefaultSettings(name)
+	if !required.Valid {
+		return s, nil
+	}
+	s.ReviewersRequired = int(required.Int64)
+	s.MinReviewers = int(min.Int64)
+	s.Strategy = strategy.String
+	s.RequiredApprovals = int(approvals.Int64)
+	s.SearchParents = parents.Bool
+	if source != name {
+		s.InheritedFrom = source
+	}
+
+	const qFallbacks = `
+	SELECT fallback_team
+	FROM team_fallbacks
+	WHERE team_name = ?1
+	ORDER BY position
+	`
+
+	rows, err := r.db.QueryContext(ctx, qFallbacks, source)
+	if err != nil {
+		return team.Settings{}, fmt.Errorf("%s, QueryContext fallbacks: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var fb string
+		if err := rows.Scan(&fb); err != nil {
+			return team.Settings{}, fmt.Errorf("%s, Scan fallbacks: %w", op, err)
+		}
+		s.FallbackTeams = append(s.FallbackTeams, fb)
+	}
+	if err := rows.Err(); err != nil {
+		return team.Settings{}, fmt.Errorf("%s, rows.Err: %w", op, err)
+	}
+	return s, nil
+}
+
+func (r *TeamRepository) UpsertSettings(ctx context.Context, s team.Settings) error {
+	const op = "internal.repository.sqlite.team_repo.UpsertSettings"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	const q = `
+	INSERT INTO team_settings (team_name, reviewers_required, min_reviewers, strategy, required_approvals, search_parents)
+	VALUES (?1, ?2, ?3, ?4, ?5, ?6)
+	ON CONFLICT (team_name)
+	DO UPDATE SET
+		reviewers_required = excluded.reviewers_required,
+		min_reviewers = excluded.min_reviewers,
+		strategy = excluded.strategy,
+		required_approvals = excluded.required_approvals,
+		search_parents = excluded.search_parents
+	`
+
+	if _, err := tx.ExecContext(ctx, q, s.TeamName, s.ReviewersRequired, s.MinReviewers, s.Strategy, s.RequiredApprovals, s.SearchParents); err != nil {
+		return fmt.Errorf("%s, ExecContext: %w", op, err)
+	}
+
+	const qDelFallbacks = `DELETE FROM team_fallbacks WHERE team_name = ?1`
+
+	if _, err := tx.ExecContext(ctx, qDelFallbacks, s.TeamName); err != nil {
+		return fmt.Errorf("%s, Exec delete fallbacks: %w", op, err)
+	}
+
+	const qInsFallback = `
+	INSERT INTO team_fallbacks (team_name, fallback_team, position)
+	VALUES (?1, ?2, ?3)
+	`
+
+	for i, fb := range s.FallbackTeams {
+		if _, err := tx.ExecContext(ctx, qInsFallback, s.TeamName, fb, i); err != nil {
+			return fmt.Errorf("%s, Exec insert fallback: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s, Commit: %w", op, err)
+	}
+	return nil
+}
+
+func (r *TeamRepository) GetCodeOwners(ctx context.Context, name string) (team.CodeOwners, error) {
+	const op = "internal.repository.sqlite.team_repo.GetCodeOwners"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	const q = `
+	SELECT t.team_name, c.pattern, c.owners
+	FROM teams t
+	LEFT JOIN team_code_owners c ON c.team_name = t.team_name
+	WHERE t.team_name = ?1
+	ORDER BY c.position
+	`
+
+	rows, err := r.db.QueryContext(ctx, q, name)
+	if err != nil {
+		return team.CodeOwners{}, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	c := team.CodeOwners{}
+	found := false
+	for rows.Next() {
+		var pattern, owners sql.NullString
+		if err := rows.Scan(&c.TeamName, &pattern, &owners); err != nil {
+			return team.CodeOwners{}, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		found = true
+		if !pattern.Valid {
+			continue
+		}
+		rule := team.OwnershipRule{Pattern: pattern.String}
+		if err := (stringsValue{&rule.Owners}).Scan(owners.String); err != nil {
+			return team.CodeOwners{}, fmt.Errorf("%s, Scan owners: %w", op, err)
+		}
+		c.Rules = append(c.Rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return team.CodeOwners{}, fmt.Errorf("%s, rows.Err: %w", op, err)
+	}
+	if !found {
+		return team.CodeOwners{}, fmt.Errorf("%s: %w", op, repo_errors.ErrTeamNotFound)
+	}
+	return c, nil
+}
+
+func (r *TeamRepository) SetCodeOwners(ctx context.Context, c team.CodeOwners) error {
+	const op = "internal.repository.sqlite.team_repo.SetCodeOwners"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM team_code_owners WHERE team_name = ?1`, c.TeamName); err != nil {
+		return fmt.Errorf("%s, Exec delete: %w", op, err)
+	}
+
+	const qIns = `
+	INSERT INTO team_code_owners (team_name, position, pattern, owners)
+	VALUES (?1, ?2, ?3, ?4)
+	`
+	for i, rule := range c.Rules {
+		owners, err := encodeStrings(rule.Owners)
+		if err != nil {
+			return fmt.Errorf("%s, Marshal owners: %w", op, err)
+		}
+		if _, err := tx.ExecContext(ctx, qIns, c.TeamName, i, rule.Pattern, owners); err != nil {
+			return fmt.Errorf("%s, Exec insert: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s, Commit: %w", op, err)
+	}
+	return nil
+}
+
+// Rename moves the users, settings and fallback references to a new team row and
+// deletes the old one, so the foreign keys hold at every step.
+func (r *TeamRepository) Rename(ctx context.Context, oldName, newName string) error {
+	const op = "internal.repository.sqlite.team_repo.Rename"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	const qInsert = `INSERT INTO teams (team_name, parent_team) SELECT ?2, parent_team FROM teams WHERE team_name = ?1`
+	res, err := tx.ExecContext(ctx, qInsert, oldName, newName)
+	if err != nil {
+		return fmt.Errorf("%s, Exec insert team: %w", op, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("%s, RowsAffected: %w", op, err)
+	} else if n == 0 {
+		return fmt.Errorf("%s: %w", op, repo_errors.ErrTeamNotFound)
+	}
+
+	updates := []string{
+		`UPDATE users SET team_name = ?2 WHERE team_name = ?1`,
+		`UPDATE team_settings SET team_name = ?2 WHERE team_name = ?1`,
+		`UPDATE team_fallbacks SET team_name = ?2 WHERE team_name = ?1`,
+		`UPDATE team_fallbacks SET fallback_team = ?2 WHERE fallback_team = ?1`,
+		`UPDATE team_code_owners SET team_name = ?2 WHERE team_name = ?1`,
+		`UPDATE teams SET parent_team = ?2 WHERE parent_team = ?1`,
+		`DELETE FROM teams WHERE team_name = ?1`,
+	}
+	for _, q := range updates {
+		if _, err := tx.ExecContext(ctx, q, oldName, newName); err != nil {
+			return fmt.Errorf("%s, ExecContext: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s, Commit: %w", op, err)
+	}
+	return nil
+}
+
+// Delete removes the team together with its settings and the fallback references
+// to it. The members move to target or, when target is empty, are left without a
+// team and deactivated, after the handovers of their reviews are applied.
+// It returns the ids of the affected members.
+func (r *TeamRepository) Delete(ctx context.Context, name, target string, handovers ...assignment.Handover) ([]string, error) {
+	const op = "internal.repository.sqlite.team_repo.Delete"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s, BeginTx: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var dummy int
+	if err := tx.QueryRowContext(ctx, `SELECT 1 FROM teams WHERE team_name = ?1`, name).Scan(&dummy); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%s: %w", op, repo_errors.ErrTeamNotFound)
+		}
+		return nil, fmt.Errorf("%s, QueryRow: %w", op, err)
+	}
+
+	if err := applyHandovers(ctx, tx, handovers); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	q := `UPDATE users SET team_name = ?2 WHERE team_name = ?1 RETURNING user_id`
+	args := []any{name, target}
+	if target == "" {
+		q = `UPDATE users SET team_name = NULL, is_active = 0 WHERE team_name = ?1 RETURNING user_id`
+		args = args[:1]
+	}
+	rows, err := tx.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s, QueryContext: %w", op, err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s, Scan: %w", op, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s, rows.Err: %w", op, err)
+	}
+	rows.Close()
+
+	// Sub-teams move up to the deleted team's parent.
+	const qChildren = `
+	UPDATE teams SET parent_team = (SELECT parent_team FROM teams WHERE team_name = ?1)
+	WHERE parent_team = ?1
+	`
+	if _, err := tx.ExecContext(ctx, qChildren, name); err != nil {
+		return nil, fmt.Errorf("%s, Exec reparent: %w", op, err)
+	}
+
+	// Settings and fallbacks are removed by ON DELETE CASCADE.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM teams WHERE team_name = ?1`, name); err != nil {
+		return nil, fmt.Errorf("%s, Exec delete team: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s, Commit: %w", op, err)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
@@ -0,0 +1,38 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+	"github.com/hihikaAAa/PRManager/internal/repository/sqlite"
+)
+
+func TestQueriesAreTraced(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+
+	repos := sqlite.Repositories(open(t, ":memory:"))
+
+	ctx, parent := tracing.Start(context.Background(), "test")
+	if _, err := repos.Teams.Exists(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	query := spans[0]
+	if query.Name() != "internal.repository.sqlite.team_repo.Exists" {
+		t.Fatalf("unexpected span name %q", query.Name())
+	}
+	if query.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatal("query span is not a child of the caller's span")
+	}
+}
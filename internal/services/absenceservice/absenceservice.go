@@ -0,0 +1,101 @@
+// Package absenceservice manages out-of-office windows of users and hands over
+// the open reviews of users whose absence has started.
+package absenceservice
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type Store interface {
+	Create(ctx context.Context, a user.Absence) (user.Absence, error)
+	Get(ctx context.Context, id int64) (user.Absence, error)
+	ListByUser(ctx context.Context, userID string) ([]user.Absence, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type UserGetter interface {
+	GetByID(ctx context.Context, id string) (*user.User, error)
+}
+
+type AbsenceService struct {
+	store    Store
+	userRepo UserGetter
+}
+
+func New(store Store, userRepo UserGetter) *AbsenceService {
+	return &AbsenceService{store: store, userRepo: userRepo}
+}
+
+// Add schedules an absence. The user stops getting new reviews once it starts;
+// the open ones are handed over by the Worker.
+func (s *AbsenceService) Add(ctx context.Context, a user.Absence) (user.Absence, error) {
+	const op = "internal.services.absenceservice.Add"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := a.Validate(); err != nil {
+		return user.Absence{}, err
+	}
+	created, err := s.store.Create(ctx, a)
+	if err != nil {
+		if errors.Is(err, repo_errors.ErrUserNotFound) {
+			return user.Absence{}, serviceerrors.ErrUserNotFound
+		}
+		return user.Absence{}, err
+	}
+	return created, nil
+}
+
+func (s *AbsenceService) Get(ctx context.Context, id int64) (user.Absence, error) {
+	const op = "internal.services.absenceservice.Get"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	a, err := s.store.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, repo_errors.ErrAbsenceNotFound) {
+			return user.Absence{}, serviceerrors.ErrAbsenceNotFound
+		}
+		return user.Absence{}, err
+	}
+	return a, nil
+}
+
+func (s *AbsenceService) List(ctx context.Context, userID string) ([]user.Absence, error) {
+	const op = "internal.services.absenceservice.List"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, repo_errors.ErrUserNotFound) {
+			return nil, serviceerrors.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return s.store.ListByUser(ctx, userID)
+}
+
+// Delete cancels an absence; a user whose absence is in progress gets reviews again.
+func (s *AbsenceService) Delete(ctx context.Context, id int64) error {
+	const op = "internal.services.absenceservice.Delete"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := s.store.Delete(ctx, id); err != nil {
+		if errors.Is(err, repo_errors.ErrAbsenceNotFound) {
+			return serviceerrors.ErrAbsenceNotFound
+		}
+		return err
+	}
+	return nil
+}
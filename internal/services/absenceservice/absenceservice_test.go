@@ -0,0 +1,74 @@
+package absenceservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/repository"
+	"github.com/hihikaAAa/PRManager/internal/repository/memory"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+// newTestRepos returns in-memory storage with team "backend" (u1-u4).
+func newTestRepos(t *testing.T) repository.Repositories {
+	t.Helper()
+	ctx := context.Background()
+
+	repos := memory.NewStorage().Repositories()
+	if err := repos.Teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	err := repos.Users.UpsertManyForTeam(ctx, "backend", []*user.User{
+		{ID: "u1", IsActive: true}, {ID: "u2", IsActive: true}, {ID: "u3", IsActive: true}, {ID: "u4", IsActive: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repos
+}
+
+func TestAddListDelete(t *testing.T) {
+	repos := newTestRepos(t)
+	svc := New(repos.Absences, repos.Users)
+	ctx := context.Background()
+	start := time.Now()
+
+	if _, err := svc.Add(ctx, user.Absence{UserID: "u2", StartsAt: start, EndsAt: start}); !errors.Is(err, user.ErrInvalidAbsence) {
+		t.Fatalf("expected ErrInvalidAbsence, got %v", err)
+	}
+	if _, err := svc.Add(ctx, user.Absence{UserID: "nobody", StartsAt: start, EndsAt: start.Add(time.Hour)}); !errors.Is(err, serviceerrors.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+	a, err := svc.Add(ctx, user.Absence{UserID: "u2", StartsAt: start, EndsAt: start.Add(time.Hour), Reason: "vacation"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := svc.List(ctx, "u2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].ID != a.ID {
+		t.Fatalf("unexpected absences: %+v", list)
+	}
+	if _, err := svc.List(ctx, "nobody"); !errors.Is(err, serviceerrors.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+	if got, err := svc.Get(ctx, a.ID); err != nil || got.UserID != "u2" {
+		t.Fatalf("unexpected absence %+v, err %v", got, err)
+	}
+
+	if err := svc.Delete(ctx, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Delete(ctx, a.ID); !errors.Is(err, serviceerrors.ErrAbsenceNotFound) {
+		t.Fatalf("expected ErrAbsenceNotFound, got %v", err)
+	}
+	if _, err := svc.Get(ctx, a.ID); !errors.Is(err, serviceerrors.ErrAbsenceNotFound) {
+		t.Fatalf("expected ErrAbsenceNotFound, got %v", err)
+	}
+}
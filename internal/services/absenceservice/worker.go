@@ -0,0 +1,159 @@
+package absenceservice
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/lib/health"
+	"github.com/hihikaAAa/PRManager/internal/lib/logger/sl"
+	"github.com/hihikaAAa/PRManager/internal/services/teamservice"
+)
+
+type StartedStore interface {
+	ListStarted(ctx context.Context, now time.Time, limit int) ([]user.Absence, error)
+	MarkHandled(ctx context.Context, id int64, now time.Time) error
+	MarkFailed(ctx context.Context, id int64, next time.Time) error
+}
+
+// ReviewReleaser hands over the open reviews of a user; TeamService implements it.
+type ReviewReleaser interface {
+	ReleaseReviews(ctx context.Context, userID, reason string) (teamservice.Reassignment, error)
+}
+
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int
+	// InitialBackoff is the delay before a failed handover is tried again;
+	// it doubles with every failure up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Worker hands over the open reviews of users whose absence has started.
+// An absence is marked handled only after the handover succeeded; a failed one
+// is logged and retried after a backoff, so it does not hold back the others.
+type Worker struct {
+	store    StartedStore
+	releaser ReviewReleaser
+	cfg      Config
+	log      *slog.Logger
+	now      func() time.Time
+	state    health.WorkerState
+}
+
+func NewWorker(log *slog.Logger, store StartedStore, releaser ReviewReleaser, cfg Config) *Worker {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = time.Minute
+	}
+	return &Worker{
+		store:    store,
+		releaser: releaser,
+		cfg:      cfg,
+		log:      log.With(slog.String("component", "absence_worker")),
+		now:      time.Now,
+	}
+}
+
+// Run polls for started absences until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	w.state.Started()
+	defer w.state.Stopped()
+
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		var err error
+		for {
+			var n int
+			n, err = w.ProcessBatch(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					w.log.Error("failed to process started absences", sl.Err(err))
+				}
+				break
+			}
+			if n < w.cfg.BatchSize {
+				break
+			}
+		}
+		if ctx.Err() == nil {
+			w.state.Polled(w.now(), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Status reports whether Run is polling and the outcome of the last poll.
+func (w *Worker) Status() health.WorkerStatus {
+	return w.state.Status()
+}
+
+// ProcessBatch handles one batch of started absences and returns how many were handled.
+// Run fetches the next batch only while whole batches are handled.
+func (w *Worker) ProcessBatch(ctx context.Context) (int, error) {
+	const op = "internal.services.absenceservice.ProcessBatch"
+
+	now := w.now()
+	started, err := w.store.ListStarted(ctx, now, w.cfg.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	handled := 0
+	for _, a := range started {
+		res, err := w.releaser.ReleaseReviews(ctx, a.UserID, event.ReasonAbsence)
+		if err != nil {
+			if ctx.Err() != nil {
+				return handled, fmt.Errorf("%s: %w", op, ctx.Err())
+			}
+			next := now.Add(w.backoff(a.Attempts + 1))
+			w.log.Warn("failed to hand over reviews of absent user",
+				slog.String("user_id", a.UserID),
+				slog.Int64("absence_id", a.ID),
+				slog.Int("attempts", a.Attempts+1),
+				slog.Time("next_attempt_at", next),
+				sl.Err(err),
+			)
+			if err := w.store.MarkFailed(ctx, a.ID, next); err != nil {
+				return handled, fmt.Errorf("%s: %w", op, err)
+			}
+			continue
+		}
+		if err := w.store.MarkHandled(ctx, a.ID, now); err != nil {
+			return handled, fmt.Errorf("%s: %w", op, err)
+		}
+		handled++
+		w.log.Info("reviews of absent user handed over",
+			slog.String("user_id", a.UserID),
+			slog.Int64("absence_id", a.ID),
+			slog.Int("reassigned", res.ReassignedCount),
+			slog.Int("removed", res.RemovedCount),
+		)
+	}
+	return handled, nil
+}
+
+// backoff returns the delay after the given number of failed handovers.
+func (w *Worker) backoff(failed int) time.Duration {
+	d := w.cfg.InitialBackoff
+	for i := 1; i < failed; i++ {
+		d *= 2
+		if w.cfg.MaxBackoff > 0 && d >= w.cfg.MaxBackoff {
+			return w.cfg.MaxBackoff
+		}
+	}
+	return d
+}
@@ -0,0 +1,145 @@
+package absenceservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	"github.com/hihikaAAa/PRManager/internal/services/assigner"
+	"github.com/hihikaAAa/PRManager/internal/services/teamservice"
+)
+
+func TestWorker_HandsOverReviewsWhenAbsenceStarts(t *testing.T) {
+	repos := newTestRepos(t)
+	ctx := context.Background()
+
+	a, err := assigner.New(repos.Users, repos.Teams, repos.PRs, assigner.StrategyRandom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	teams := teamservice.New(repos.Users, repos.Teams, repos.PRs, a)
+
+	pr := pullrequest.PullRequest{ID: "pr-1", AuthorID: "u1", Status: pullrequest.StatusOpen, CreatedAt: time.Now(), Reviewers: []string{"u2", "u3"}}
+	if err := repos.PRs.CreateWithReviewers(ctx, pr); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Add(time.Hour)
+	if _, err := repos.Absences.Create(ctx, user.Absence{UserID: "u2", StartsAt: start, EndsAt: start.Add(24 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWorker(slogdiscard.NewDiscardLogger(), repos.Absences, teams, Config{BatchSize: 10})
+	w.now = func() time.Time { return start.Add(-time.Minute) }
+	if n, err := w.ProcessBatch(ctx); err != nil || n != 0 {
+		t.Fatalf("absence handled before it started: %d, %v", n, err)
+	}
+
+	w.now = func() time.Time { return start }
+	if n, err := w.ProcessBatch(ctx); err != nil || n != 1 {
+		t.Fatalf("expected 1 handled absence, got %d, %v", n, err)
+	}
+	got, err := repos.PRs.GetWithReviewers(ctx, "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Reviewers) != 2 || got.Reviewers[0] == "u2" || got.Reviewers[1] == "u2" {
+		t.Fatalf("u2 was not replaced: %v", got.Reviewers)
+	}
+	hist, err := repos.PRs.HistoryByPR(ctx, "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := hist[len(hist)-1]
+	if last.OldReviewerID != "u2" || last.NewReviewerID != "u4" || last.Reason != event.ReasonAbsence {
+		t.Fatalf("unexpected history entry: %+v", last)
+	}
+
+	if n, err := w.ProcessBatch(ctx); err != nil || n != 0 {
+		t.Fatalf("absence handled twice: %d, %v", n, err)
+	}
+}
+
+type releaserMock struct {
+	failFor string
+	calls   []string
+}
+
+func (m *releaserMock) ReleaseReviews(ctx context.Context, userID, reason string) (teamservice.Reassignment, error) {
+	m.calls = append(m.calls, userID)
+	if userID == m.failFor {
+		return teamservice.Reassignment{}, errors.New("boom")
+	}
+	return teamservice.Reassignment{}, nil
+}
+
+func TestWorker_FailedHandoverIsRetried(t *testing.T) {
+	repos := newTestRepos(t)
+	ctx := context.Background()
+	start := time.Now()
+
+	for _, id := range []string{"u2", "u3"} {
+		if _, err := repos.Absences.Create(ctx, user.Absence{UserID: id, StartsAt: start, EndsAt: start.Add(time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	releaser := &releaserMock{failFor: "u2"}
+	w := NewWorker(slogdiscard.NewDiscardLogger(), repos.Absences, releaser, Config{BatchSize: 10, InitialBackoff: time.Minute})
+	w.now = func() time.Time { return start }
+
+	if n, err := w.ProcessBatch(ctx); err != nil || n != 1 {
+		t.Fatalf("expected 1 handled absence, got %d, %v", n, err)
+	}
+	releaser.failFor = ""
+	if n, err := w.ProcessBatch(ctx); err != nil || n != 0 {
+		t.Fatalf("failed absence retried before its backoff: %d, %v", n, err)
+	}
+	w.now = func() time.Time { return start.Add(time.Minute) }
+	if n, err := w.ProcessBatch(ctx); err != nil || n != 1 {
+		t.Fatalf("expected the failed absence to be retried, got %d, %v", n, err)
+	}
+	if len(releaser.calls) != 3 || releaser.calls[2] != "u2" {
+		t.Fatalf("unexpected calls: %v", releaser.calls)
+	}
+}
+
+func TestWorker_FailingAbsenceDoesNotStarveOthers(t *testing.T) {
+	repos := newTestRepos(t)
+	ctx := context.Background()
+	start := time.Now()
+
+	for i, id := range []string{"u2", "u3"} {
+		at := start.Add(time.Duration(i) * time.Minute)
+		if _, err := repos.Absences.Create(ctx, user.Absence{UserID: id, StartsAt: at, EndsAt: at.Add(time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	releaser := &releaserMock{failFor: "u2"}
+	w := NewWorker(slogdiscard.NewDiscardLogger(), repos.Absences, releaser, Config{BatchSize: 1, InitialBackoff: time.Hour})
+	w.now = func() time.Time { return start.Add(time.Minute) }
+
+	// u2 started first and fails: it must leave the head of the queue.
+	if n, err := w.ProcessBatch(ctx); err != nil || n != 0 {
+		t.Fatalf("expected no handled absence, got %d, %v", n, err)
+	}
+	if n, err := w.ProcessBatch(ctx); err != nil || n != 1 {
+		t.Fatalf("expected u3 to be handled, got %d, %v", n, err)
+	}
+	if len(releaser.calls) != 2 || releaser.calls[1] != "u3" {
+		t.Fatalf("unexpected calls: %v", releaser.calls)
+	}
+	list, err := repos.Absences.ListByUser(ctx, "u2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list[0].Attempts != 1 || list[0].NextAttemptAt == nil || !list[0].NextAttemptAt.Equal(start.Add(time.Minute+time.Hour)) {
+		t.Fatalf("failed handover was not recorded: %+v", list[0])
+	}
+}
@@ -0,0 +1,285 @@
+package assigner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type CandidateFinder interface {
+	FindActiveByTeamExcept(ctx context.Context, teamName string, excluded []string) ([]*user.User, error)
+}
+
+type SettingsGetter interface {
+	GetSettings(ctx context.Context, name string) (team.Settings, error)
+	Ancestors(ctx context.Context, name string) ([]string, error)
+	GetCodeOwners(ctx context.Context, name string) (team.CodeOwners, error)
+}
+
+// Assigner finds reviewers for a team honoring the team's settings.
+// It is shared by PRService and TeamService so that both assign reviewers the same way.
+type Assigner struct {
+	userRepo        CandidateFinder
+	teamRepo        SettingsGetter
+	loads           LoadCounter
+	defaultStrategy string
+}
+
+func New(userRepo CandidateFinder, teamRepo SettingsGetter, loads LoadCounter, defaultStrategy string) (*Assigner, error) {
+	const op = "internal.services.assigner.New"
+
+	if _, err := NewStrategy(defaultStrategy, loads); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Assigner{userRepo: userRepo, teamRepo: teamRepo, loads: loads, defaultStrategy: defaultStrategy}, nil
+}
+
+// WithPendingLoads returns a copy of the assigner that also counts the reviews
+// recorded in the returned PendingLoads. A batch of assignments stored together
+// records each pick there, so that capacity and least_loaded see the earlier ones.
+func (a *Assigner) WithPendingLoads() (*Assigner, *PendingLoads) {
+	pending := &PendingLoads{base: a.loads, added: make(map[string]int)}
+	c := *a
+	c.loads = pending
+	return &c, pending
+}
+
+func IsKnownStrategy(name string) bool {
+	_, err := NewStrategy(name, nil)
+	return err == nil
+}
+
+// Settings returns the team's settings. Users removed from their team have no team
+// name; they get the defaults and no candidates.
+func (a *Assigner) Settings(ctx context.Context, teamName string) (team.Settings, error) {
+	const op = "internal.services.assigner.Settings"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if teamName == "" {
+		return team.DefaultSettings(""), nil
+	}
+	return a.teamRepo.GetSettings(ctx, teamName)
+}
+
+func (a *Assigner) strategyFor(s team.Settings) (Strategy, error) {
+	if s.Strategy == "" {
+		return NewStrategy(a.defaultStrategy, a.loads)
+	}
+	return NewStrategy(s.Strategy, a.loads)
+}
+
+// Assignment is the result of a reviewer search.
+// Fallback is the subset of Reviewers taken from the team's fallback teams;
+// reviewers found in parent teams are not fallback ones.
+// AtCapacity is set when some candidates were skipped because they already
+// review as many open PRs as their capacity allows.
+type Assignment struct {
+	Reviewers  []string
+	Fallback   []string
+	AtCapacity bool
+}
+
+// PickReviewers selects up to ReviewersRequired reviewers from the team,
+// filling missing slots from its parent teams when SearchParents is set
+// and then from the fallback teams in order. Candidates owning any of the
+// changed files under the team's code owners are picked first.
+// It returns ErrNoCandidates when fewer than MinReviewers can be found, or
+// ErrCandidatesAtCapacity when that is because candidates are at capacity.
+func (a *Assigner) PickReviewers(ctx context.Context, teamName string, exclude, files []string) (Assignment, error) {
+	const op = "internal.services.assigner.PickReviewers"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	settings, err := a.Settings(ctx, teamName)
+	if err != nil {
+		return Assignment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := a.pick(ctx, settings, exclude, files, settings.ReviewersRequired)
+	if err != nil {
+		return Assignment{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(res.Reviewers) < settings.MinReviewers {
+		if res.AtCapacity {
+			return Assignment{}, serviceerrors.ErrCandidatesAtCapacity
+		}
+		return Assignment{}, serviceerrors.ErrNoCandidates
+	}
+	return res, nil
+}
+
+// PickReplacement selects a single reviewer from the team, its parent teams or its fallback teams,
+// preferring owners of the changed files. The assignment has no reviewers when nobody is available.
+func (a *Assigner) PickReplacement(ctx context.Context, teamName string, exclude, files []string) (Assignment, error) {
+	const op = "internal.services.assigner.PickReplacement"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	settings, err := a.Settings(ctx, teamName)
+	if err != nil {
+		return Assignment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := a.pick(ctx, settings, exclude, files, 1)
+	if err != nil {
+		return Assignment{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return res, nil
+}
+
+func (a *Assigner) pick(ctx context.Context, settings team.Settings, exclude, files []string, limit int) (Assignment, error) {
+	strategy, err := a.strategyFor(settings)
+	if err != nil {
+		return Assignment{}, err
+	}
+
+	owners, err := a.ownersOf(ctx, settings.TeamName, files)
+	if err != nil {
+		return Assignment{}, err
+	}
+
+	excluded := make([]string, 0, len(exclude)+limit)
+	excluded = append(excluded, exclude...)
+
+	teams := []string{settings.TeamName}
+	if settings.SearchParents && settings.TeamName != "" {
+		parents, err := a.teamRepo.Ancestors(ctx, settings.TeamName)
+		if err != nil {
+			return Assignment{}, err
+		}
+		teams = append(teams, parents...)
+	}
+	own := len(teams)
+	teams = append(teams, settings.FallbackTeams...)
+
+	var res Assignment
+	for i, teamName := range teams {
+		missing := limit - len(res.Reviewers)
+		if missing <= 0 {
+			break
+		}
+		if teamName == "" {
+			continue
+		}
+
+		candidates, err := a.userRepo.FindActiveByTeamExcept(ctx, teamName, excluded)
+		if err != nil {
+			return Assignment{}, err
+		}
+		candidates, full, err := a.belowCapacity(ctx, candidates)
+		if err != nil {
+			return Assignment{}, err
+		}
+		if full {
+			res.AtCapacity = true
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+
+		picked, err := pickOwnersFirst(ctx, strategy, candidates, owners, missing)
+		if err != nil {
+			return Assignment{}, err
+		}
+
+		res.Reviewers = append(res.Reviewers, picked...)
+		if i >= own {
+			res.Fallback = append(res.Fallback, picked...)
+		}
+		excluded = append(excluded, picked...)
+	}
+	return res, nil
+}
+
+// belowCapacity drops the candidates who already review as many open PRs as they may
+// and reports whether anyone was dropped. Loads are only counted for users with a limit.
+func (a *Assigner) belowCapacity(ctx context.Context, candidates []*user.User) ([]*user.User, bool, error) {
+	var limited []string
+	for _, u := range candidates {
+		if u.MaxOpenReviews > 0 {
+			limited = append(limited, u.ID)
+		}
+	}
+	if len(limited) == 0 {
+		return candidates, false, nil
+	}
+
+	loads, err := a.loads.CountOpenReviews(ctx, limited)
+	if err != nil {
+		return nil, false, err
+	}
+
+	kept := make([]*user.User, 0, len(candidates))
+	for _, u := range candidates {
+		if u.AtCapacity(loads[u.ID]) {
+			continue
+		}
+		kept = append(kept, u)
+	}
+	return kept, len(kept) < len(candidates), nil
+}
+
+// ownersOf returns the owners of the files under the team's code owners.
+func (a *Assigner) ownersOf(ctx context.Context, teamName string, files []string) (map[string]struct{}, error) {
+	if teamName == "" || len(files) == 0 {
+		return nil, nil
+	}
+
+	codeOwners, err := a.teamRepo.GetCodeOwners(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := codeOwners.OwnersOf(files)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	owners := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		owners[id] = struct{}{}
+	}
+	return owners, nil
+}
+
+// pickOwnersFirst lets the strategy choose among the owners first and fills the
+// remaining slots from the other candidates.
+func pickOwnersFirst(ctx context.Context, strategy Strategy, candidates []*user.User, owners map[string]struct{}, limit int) ([]string, error) {
+	if len(owners) == 0 {
+		return strategy.Pick(ctx, candidates, limit)
+	}
+
+	var preferred, others []*user.User
+	for _, u := range candidates {
+		if _, ok := owners[u.ID]; ok {
+			preferred = append(preferred, u)
+		} else {
+			others = append(others, u)
+		}
+	}
+
+	var picked []string
+	if len(preferred) > 0 {
+		ids, err := strategy.Pick(ctx, preferred, limit)
+		if err != nil {
+			return nil, err
+		}
+		picked = ids
+	}
+	if len(picked) < limit && len(others) > 0 {
+		ids, err := strategy.Pick(ctx, others, limit-len(picked))
+		if err != nil {
+			return nil, err
+		}
+		picked = append(picked, ids...)
+	}
+	return picked, nil
+}
@@ -0,0 +1,148 @@
+package assigner
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+)
+
+const (
+	StrategyRandom      = "random"
+	StrategyLeastLoaded = "least_loaded"
+)
+
+// Strategy decides which of the candidates become reviewers.
+// Candidates are already filtered (active, not the author, not assigned yet).
+type Strategy interface {
+	Pick(ctx context.Context, candidates []*user.User, limit int) ([]string, error)
+}
+
+// LoadCounter returns the number of OPEN pull requests each user currently reviews.
+// Users without open reviews may be absent from the result.
+type LoadCounter interface {
+	CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error)
+}
+
+// PendingLoads adds reviews that are assigned but not stored yet to the loads
+// counted by the underlying LoadCounter. It is not safe for concurrent use.
+type PendingLoads struct {
+	base  LoadCounter
+	added map[string]int
+}
+
+// Add records one more open review for each of the users.
+func (p *PendingLoads) Add(userIDs []string) {
+	for _, id := range userIDs {
+		p.added[id]++
+	}
+}
+
+func (p *PendingLoads) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	loads, err := p.base.CountOpenReviews(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	if loads == nil {
+		loads = make(map[string]int, len(userIDs))
+	}
+	for _, id := range userIDs {
+		loads[id] += p.added[id]
+	}
+	return loads, nil
+}
+
+func NewStrategy(name string, loads LoadCounter) (Strategy, error) {
+	switch name {
+	case "", StrategyRandom:
+		return Random{}, nil
+	case StrategyLeastLoaded:
+		return LeastLoaded{loads: loads}, nil
+	default:
+		return nil, fmt.Errorf("unknown reviewer strategy %q", name)
+	}
+}
+
+// Random picks reviewers uniformly at random.
+type Random struct{}
+
+func (Random) Pick(_ context.Context, candidates []*user.User, limit int) ([]string, error) {
+	return pickRandomReviewers(candidates, limit), nil
+}
+
+// LeastLoaded picks the candidates with the fewest open reviews.
+// Candidates with equal load are ordered randomly.
+type LeastLoaded struct {
+	loads LoadCounter
+}
+
+func (s LeastLoaded) Pick(ctx context.Context, candidates []*user.User, limit int) ([]string, error) {
+	const op = "internal.services.assigner.LeastLoaded.Pick"
+
+	if len(candidates) == 0 || limit <= 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(candidates))
+	for _, u := range candidates {
+		ids = append(ids, u.ID)
+	}
+
+	loads, err := s.loads.CountOpenReviews(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return pickLeastLoaded(candidates, loads, limit), nil
+}
+
+func pickLeastLoaded(available []*user.User, loads map[string]int, limit int) []string {
+	tmp := make([]*user.User, len(available))
+	copy(tmp, available)
+	rand.Shuffle(len(tmp), func(i, j int) {
+		tmp[i], tmp[j] = tmp[j], tmp[i]
+	})
+
+	sort.SliceStable(tmp, func(i, j int) bool {
+		return loads[tmp[i].ID] < loads[tmp[j].ID]
+	})
+
+	if len(tmp) > limit {
+		tmp = tmp[:limit]
+	}
+	out := make([]string, 0, len(tmp))
+	for _, u := range tmp {
+		out = append(out, u.ID)
+	}
+	return out
+}
+
+func pickRandomReviewers(available []*user.User, limit int) []string {
+	if len(available) == 0 {
+		return nil
+	}
+
+	if len(available) <= limit {
+		out := make([]string, 0, len(available))
+		for _, u := range available {
+			out = append(out, u.ID)
+		}
+		return out
+	}
+
+	tmp := make([]*user.User, len(available))
+	copy(tmp, available)
+
+	for i := len(tmp) - 1; i > 0; i-- {
+		j := rand.Intn(i + 1)
+		tmp[i], tmp[j] = tmp[j], tmp[i]
+	}
+
+	out := make([]string, 0, limit)
+	for i := 0; i < limit; i++ {
+		out = append(out, tmp[i].ID)
+	}
+	return out
+}
@@ -0,0 +1,170 @@
+package assigner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+)
+
+func TestPickRandomReviewers_Empty(t *testing.T) {
+	t.Parallel()
+
+	got := pickRandomReviewers(nil, 2)
+	if got != nil {
+		t.Fatalf("expected nil slice, got %#v", got)
+	}
+}
+
+func TestPickRandomReviewers_LessOrEqualThanLimit(t *testing.T) {
+	t.Parallel()
+
+	available := []*user.User{
+		{ID: "u1"},
+		{ID: "u2"},
+	}
+
+	got := pickRandomReviewers(available, 3)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 reviewers, got %d", len(got))
+	}
+	if got[0] != "u1" || got[1] != "u2" {
+		t.Fatalf("unexpected reviewers slice: %#v", got)
+	}
+}
+
+func TestPickRandomReviewers_MoreThanLimit(t *testing.T) {
+	t.Parallel()
+
+	available := []*user.User{
+		{ID: "u1"},
+		{ID: "u2"},
+		{ID: "u3"},
+		{ID: "u4"},
+	}
+
+	limit := 2
+	got := pickRandomReviewers(available, limit)
+
+	if len(got) != limit {
+		t.Fatalf("expected %d reviewers, got %d", limit, len(got))
+	}
+
+	seen := map[string]bool{}
+	for _, id := range got {
+		seen[id] = true
+		found := false
+		for _, u := range available {
+			if u.ID == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("id %q not from available list", id)
+		}
+	}
+
+	if len(seen) != len(got) {
+		t.Fatalf("expected all reviewers unique, got %v", got)
+	}
+}
+
+func TestPickRandomReviewers_BigLimit(t *testing.T) {
+	t.Parallel()
+
+	available := []*user.User{
+		{ID: "u1"},
+		{ID: "u2"},
+	}
+
+	got := pickRandomReviewers(available, 100)
+	if len(got) != len(available) {
+		t.Fatalf("expected %d reviewers, got %d", len(available), len(got))
+	}
+}
+
+type loadCounterMock struct {
+	loads map[string]int
+	err   error
+}
+
+func (m *loadCounterMock) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	return m.loads, m.err
+}
+
+func TestPickLeastLoaded_PrefersLowestLoad(t *testing.T) {
+	t.Parallel()
+
+	available := []*user.User{
+		{ID: "u1"},
+		{ID: "u2"},
+		{ID: "u3"},
+		{ID: "u4"},
+	}
+	loads := map[string]int{"u1": 5, "u2": 0, "u3": 3}
+
+	got := pickLeastLoaded(available, loads, 2)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 reviewers, got %d", len(got))
+	}
+
+	seen := map[string]bool{got[0]: true, got[1]: true}
+	if !seen["u2"] || !seen["u4"] {
+		t.Fatalf("expected u2 and u4 (no open reviews), got %v", got)
+	}
+}
+
+func TestPickLeastLoaded_BigLimit(t *testing.T) {
+	t.Parallel()
+
+	available := []*user.User{
+		{ID: "u1"},
+		{ID: "u2"},
+	}
+
+	got := pickLeastLoaded(available, map[string]int{"u1": 1}, 10)
+	if len(got) != 2 || got[0] != "u2" || got[1] != "u1" {
+		t.Fatalf("unexpected reviewers slice: %#v", got)
+	}
+}
+
+func TestLeastLoaded_Pick_LoadError(t *testing.T) {
+	t.Parallel()
+
+	s := LeastLoaded{loads: &loadCounterMock{err: errors.New("db down")}}
+
+	if _, err := s.Pick(context.Background(), []*user.User{{ID: "u1"}}, 1); err == nil {
+		t.Fatalf("expected error from load counter")
+	}
+}
+
+func TestNewStrategy(t *testing.T) {
+	t.Parallel()
+
+	loads := &loadCounterMock{}
+
+	if s, err := NewStrategy("", loads); err != nil || s == nil {
+		t.Fatalf("expected default strategy, got %v, %v", s, err)
+	}
+	if _, ok := mustStrategy(t, StrategyRandom, loads).(Random); !ok {
+		t.Fatalf("expected Random strategy")
+	}
+	if _, ok := mustStrategy(t, StrategyLeastLoaded, loads).(LeastLoaded); !ok {
+		t.Fatalf("expected LeastLoaded strategy")
+	}
+	if _, err := NewStrategy("round_robin", loads); err == nil {
+		t.Fatalf("expected error for unknown strategy")
+	}
+}
+
+func mustStrategy(t *testing.T, name string, loads LoadCounter) Strategy {
+	t.Helper()
+
+	s, err := NewStrategy(name, loads)
+	if err != nil {
+		t.Fatalf("NewStrategy(%q): %v", name, err)
+	}
+	return s
+}
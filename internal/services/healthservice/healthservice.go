@@ -0,0 +1,172 @@
+package healthservice
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/lib/health"
+	"github.com/hihikaAAa/PRManager/internal/lib/logger/sl"
+	"github.com/hihikaAAa/PRManager/internal/migrator"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// checkTimeout bounds the database round trips of one readiness check.
+const checkTimeout = 2 * time.Second
+
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+type Migrations interface {
+	Status(ctx context.Context) (migrator.Status, error)
+	Latest() int
+}
+
+type Worker interface {
+	Status() health.WorkerStatus
+}
+
+type Report struct {
+	Status       string                 `json:"status"`
+	ShuttingDown bool                   `json:"shutting_down,omitempty"`
+	Database     *Check                 `json:"database,omitempty"`
+	Migrations   *MigrationsCheck       `json:"migrations,omitempty"`
+	Workers      map[string]WorkerCheck `json:"workers"`
+}
+
+func (r Report) Ready() bool {
+	return r.Status == StatusOK
+}
+
+// The report is served without authentication, so it carries no error texts of
+// drivers or workers: they are logged, and only the fixed reasons below are reported.
+type Check struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type MigrationsCheck struct {
+	Status  string `json:"status"`
+	Version int    `json:"version"`
+	Latest  int    `json:"latest"`
+	Dirty   bool   `json:"dirty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type WorkerCheck struct {
+	Status         string     `json:"status"`
+	Running        bool       `json:"running"`
+	LastRunAt      *time.Time `json:"last_run_at,omitempty"`
+	LastPollFailed bool       `json:"last_poll_failed,omitempty"`
+}
+
+// HealthService reports whether the instance can serve traffic. Checks of
+// dependencies that are not set are skipped, as for the memory storage.
+type HealthService struct {
+	log          *slog.Logger
+	db           Pinger
+	migrations   Migrations
+	workers      map[string]Worker
+	shuttingDown atomic.Bool
+}
+
+func New(log *slog.Logger) *HealthService {
+	return &HealthService{
+		log:     log.With(slog.String("component", "healthservice")),
+		workers: make(map[string]Worker),
+	}
+}
+
+func (s *HealthService) SetDatabase(db Pinger) {
+	s.db = db
+}
+
+func (s *HealthService) SetMigrations(m Migrations) {
+	s.migrations = m
+}
+
+// AddWorker registers a background worker that must be running for the instance to be ready.
+func (s *HealthService) AddWorker(name string, w Worker) {
+	s.workers[name] = w
+}
+
+// ShutDown makes the instance unready for good, so that load balancers stop
+// routing to it before the server stops accepting connections.
+func (s *HealthService) ShutDown() {
+	s.shuttingDown.Store(true)
+}
+
+// Ready runs every check; the report is ready only if all of them passed.
+func (s *HealthService) Ready(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	rep := Report{Status: StatusOK, Workers: make(map[string]WorkerCheck, len(s.workers))}
+	fail := func() { rep.Status = StatusFail }
+
+	if s.shuttingDown.Load() {
+		rep.ShuttingDown = true
+		fail()
+	}
+
+	if s.db != nil {
+		rep.Database = &Check{Status: StatusOK}
+		if err := s.db.PingContext(ctx); err != nil {
+			s.log.Warn("database ping failed", sl.Err(err))
+			rep.Database = &Check{Status: StatusFail}
+			fail()
+		}
+	}
+
+	if s.migrations != nil {
+		rep.Migrations = s.checkMigrations(ctx)
+		if rep.Migrations.Status != StatusOK {
+			fail()
+		}
+	}
+
+	for name, w := range s.workers {
+		st := w.Status()
+		c := WorkerCheck{Status: StatusOK, Running: st.Running, LastRunAt: st.LastRunAt, LastPollFailed: st.LastError != ""}
+		if !st.Running {
+			c.Status = StatusFail
+			fail()
+		}
+		rep.Workers[name] = c
+	}
+	return rep
+}
+
+// checkMigrations passes when the schema is clean and at least at the newest
+// known version; a newer schema is left by a newer release during a rollout.
+func (s *HealthService) checkMigrations(ctx context.Context) *MigrationsCheck {
+	c := &MigrationsCheck{Status: StatusOK, Latest: s.migrations.Latest()}
+
+	st, err := s.migrations.Status(ctx)
+	if err != nil {
+		s.log.Warn("failed to read migration status", sl.Err(err))
+		c.Status = StatusFail
+		c.Error = "status unavailable"
+		return c
+	}
+	c.Version = st.Version
+	c.Dirty = st.Dirty
+	switch {
+	case st.NotMigrated:
+		c.Status = StatusFail
+		c.Error = "not migrated"
+	case st.Dirty:
+		c.Status = StatusFail
+		c.Error = "schema is dirty"
+	case st.Version < c.Latest:
+		c.Status = StatusFail
+		c.Error = "migrations are pending"
+	}
+	return c
+}
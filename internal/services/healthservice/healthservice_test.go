@@ -0,0 +1,166 @@
+package healthservice
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/lib/health"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+	"github.com/hihikaAAa/PRManager/internal/migrator"
+	"github.com/hihikaAAa/PRManager/internal/repository/sqlite"
+)
+
+type workerMock struct {
+	status health.WorkerStatus
+}
+
+func (m workerMock) Status() health.WorkerStatus {
+	return m.status
+}
+
+func newSQLite(t *testing.T) (*sql.DB, *migrator.Migrator) {
+	t.Helper()
+
+	db, err := sqlite.New(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	m, err := sqlite.Migrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, m
+}
+
+func newService(db *sql.DB, m *migrator.Migrator) *HealthService {
+	s := New(slogdiscard.NewDiscardLogger())
+	s.SetDatabase(db)
+	s.SetMigrations(m)
+	s.AddWorker("outbox_relay", workerMock{status: health.WorkerStatus{Running: true}})
+	return s
+}
+
+func TestReady_AllChecksPass(t *testing.T) {
+	db, m := newSQLite(t)
+	ctx := context.Background()
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	rep := newService(db, m).Ready(ctx)
+	if !rep.Ready() {
+		t.Fatalf("expected ready, got %+v", rep)
+	}
+	if rep.Database == nil || rep.Database.Status != StatusOK {
+		t.Fatalf("unexpected database check: %+v", rep.Database)
+	}
+	if rep.Migrations == nil || rep.Migrations.Version != m.Latest() || rep.Migrations.Status != StatusOK {
+		t.Fatalf("unexpected migrations check: %+v", rep.Migrations)
+	}
+	if rep.Workers["outbox_relay"].Status != StatusOK {
+		t.Fatalf("unexpected workers: %+v", rep.Workers)
+	}
+}
+
+func TestReady_Failures(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T) *HealthService
+		check func(t *testing.T, rep Report)
+	}{
+		{
+			name: "not migrated",
+			setup: func(t *testing.T) *HealthService {
+				db, m := newSQLite(t)
+				return newService(db, m)
+			},
+			check: func(t *testing.T, rep Report) {
+				if rep.Migrations.Status != StatusFail || rep.Migrations.Error != "not migrated" {
+					t.Fatalf("unexpected migrations check: %+v", rep.Migrations)
+				}
+			},
+		},
+		{
+			name: "pending migrations",
+			setup: func(t *testing.T) *HealthService {
+				db, m := newSQLite(t)
+				if _, err := m.Up(ctx); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := m.Down(ctx, 1); err != nil {
+					t.Fatal(err)
+				}
+				return newService(db, m)
+			},
+			check: func(t *testing.T, rep Report) {
+				if rep.Migrations.Status != StatusFail || rep.Migrations.Version != rep.Migrations.Latest-1 {
+					t.Fatalf("unexpected migrations check: %+v", rep.Migrations)
+				}
+			},
+		},
+		{
+			name: "database down",
+			setup: func(t *testing.T) *HealthService {
+				db, m := newSQLite(t)
+				if _, err := m.Up(ctx); err != nil {
+					t.Fatal(err)
+				}
+				db.Close()
+				return newService(db, m)
+			},
+			check: func(t *testing.T, rep Report) {
+				if rep.Database.Status != StatusFail || rep.Database.Error != "" {
+					t.Fatalf("unexpected database check: %+v", rep.Database)
+				}
+			},
+		},
+		{
+			name: "worker stopped",
+			setup: func(t *testing.T) *HealthService {
+				s := New(slogdiscard.NewDiscardLogger())
+				s.AddWorker("absence_worker", workerMock{status: health.WorkerStatus{LastError: "db down"}})
+				return s
+			},
+			check: func(t *testing.T, rep Report) {
+				w := rep.Workers["absence_worker"]
+				if w.Status != StatusFail || !w.LastPollFailed {
+					t.Fatalf("unexpected worker check: %+v", w)
+				}
+			},
+		},
+		{
+			name: "shutting down",
+			setup: func(t *testing.T) *HealthService {
+				s := New(slogdiscard.NewDiscardLogger())
+				s.ShutDown()
+				return s
+			},
+			check: func(t *testing.T, rep Report) {
+				if !rep.ShuttingDown {
+					t.Fatalf("expected shutting_down, got %+v", rep)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rep := tt.setup(t).Ready(ctx)
+			if rep.Ready() || rep.Status != StatusFail {
+				t.Fatalf("expected not ready, got %+v", rep)
+			}
+			tt.check(t, rep)
+		})
+	}
+}
+
+func TestReady_SkipsUnsetDependencies(t *testing.T) {
+	rep := New(slogdiscard.NewDiscardLogger()).Ready(context.Background())
+	if !rep.Ready() || rep.Database != nil || rep.Migrations != nil {
+		t.Fatalf("unexpected report: %+v", rep)
+	}
+}
@@ -0,0 +1,247 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	"github.com/hihikaAAa/PRManager/internal/domain/subscriber"
+	"github.com/hihikaAAa/PRManager/internal/lib/health"
+)
+
+const (
+	HeaderEvent     = "X-PRManager-Event"
+	HeaderDelivery  = "X-PRManager-Delivery"
+	HeaderSignature = "X-PRManager-Signature-256"
+
+	signaturePrefix = "sha256="
+)
+
+type Store interface {
+	List(ctx context.Context) ([]subscriber.Subscriber, error)
+	SaveDeadLetter(ctx context.Context, dl subscriber.DeadLetter) error
+	RetryStore
+}
+
+type Config struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	RequestTimeout time.Duration
+	// PollInterval, BatchSize and Lease drive the retry worker (Run) the way
+	// they drive the outbox relay.
+	PollInterval time.Duration
+	BatchSize    int
+	Lease        time.Duration
+}
+
+// Notifier delivers events to webhook subscribers. It is an outbox sink:
+// Publish tries each subscriber once, a transiently failed delivery is queued
+// with exponential backoff and retried by Run, and deliveries that still fail
+// after MaxAttempts are written to the dead-letter table. This way a dead
+// subscriber never holds up the relay for longer than one request.
+type Notifier struct {
+	store  Store
+	client *http.Client
+	cfg    Config
+	log    *slog.Logger
+	now    func() time.Time
+	state  health.WorkerState
+}
+
+func New(log *slog.Logger, store Store, cfg Config) *Notifier {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	return &Notifier{
+		store:  store,
+		client: &http.Client{Timeout: cfg.RequestTimeout},
+		cfg:    cfg,
+		log:    log.With(slog.String("component", "notifier")),
+		now:    time.Now,
+	}
+}
+
+func (n *Notifier) Name() string {
+	return "webhook"
+}
+
+// Publish delivers the event to all interested subscribers concurrently, one
+// attempt each. Subscribers with queued retries get the event queued behind
+// them instead, so that a failing subscriber is not waited for again.
+// An error means the event must be published again.
+func (n *Notifier) Publish(ctx context.Context, ev event.Event) error {
+	const op = "internal.services.notifier.Publish"
+
+	subs, err := n.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	ids, err := n.store.RetryingSubscribers(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	retrying := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		retrying[id] = true
+	}
+
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		lastErr error
+	)
+	for _, sub := range subs {
+		if !sub.Wants(ev.Type) {
+			continue
+		}
+		wg.Add(1)
+		go func(sub subscriber.Subscriber) {
+			defer wg.Done()
+			var err error
+			if retrying[sub.ID] {
+				err = n.queue(ctx, sub, ev, body)
+			} else {
+				err = n.deliver(ctx, sub, ev, body)
+			}
+			if err != nil {
+				mu.Lock()
+				lastErr = err
+				mu.Unlock()
+			}
+		}(sub)
+	}
+	wg.Wait()
+
+	if lastErr != nil {
+		return fmt.Errorf("%s: %w", op, lastErr)
+	}
+	return nil
+}
+
+// deliver makes the first attempt. It returns an error if the delivery was interrupted
+// by shutdown or could not be queued or dead-lettered; the relay then publishes the event again.
+func (n *Notifier) deliver(ctx context.Context, sub subscriber.Subscriber, ev event.Event, body []byte) error {
+	retry, err := n.send(ctx, sub, ev.Type, ev.ID, body)
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("delivery interrupted: %w", ctx.Err())
+	}
+
+	rt := subscriber.Retry{
+		SubscriberID: sub.ID,
+		EventID:      ev.ID,
+		EventType:    ev.Type,
+		Payload:      body,
+		Attempts:     1,
+		LastError:    err.Error(),
+	}
+	if !retry || rt.Attempts >= n.cfg.MaxAttempts {
+		return n.deadLetter(ctx, rt)
+	}
+	rt.NextAttemptAt = n.now().Add(n.backoff(rt.Attempts))
+	if err := n.store.ScheduleRetry(ctx, rt); err != nil {
+		return fmt.Errorf("schedule retry: %w", err)
+	}
+	return nil
+}
+
+// queue puts the event behind the subscriber's queued retries without trying it.
+func (n *Notifier) queue(ctx context.Context, sub subscriber.Subscriber, ev event.Event, body []byte) error {
+	err := n.store.ScheduleRetry(ctx, subscriber.Retry{
+		SubscriberID:  sub.ID,
+		EventID:       ev.ID,
+		EventType:     ev.Type,
+		Payload:       body,
+		NextAttemptAt: n.now(),
+	})
+	if err != nil {
+		return fmt.Errorf("schedule retry: %w", err)
+	}
+	return nil
+}
+
+func (n *Notifier) deadLetter(ctx context.Context, rt subscriber.Retry) error {
+	n.log.Warn("webhook delivery failed",
+		slog.Int64("subscriber_id", rt.SubscriberID),
+		slog.String("event_id", rt.EventID),
+		slog.Int("attempts", rt.Attempts),
+		slog.String("err", rt.LastError),
+	)
+
+	err := n.store.SaveDeadLetter(ctx, subscriber.DeadLetter{
+		SubscriberID: rt.SubscriberID,
+		EventID:      rt.EventID,
+		EventType:    rt.EventType,
+		Payload:      rt.Payload,
+		Attempts:     rt.Attempts,
+		LastError:    rt.LastError,
+	})
+	if err != nil {
+		return fmt.Errorf("save dead letter: %w", err)
+	}
+	return nil
+}
+
+// send performs a single delivery attempt. retry reports whether the failure is transient:
+// network errors, 429 and 5xx are retried, other statuses are not.
+func (n *Notifier) send(ctx context.Context, sub subscriber.Subscriber, typ event.Type, eventID string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(typ))
+	req.Header.Set(HeaderDelivery, eventID)
+	req.Header.Set(HeaderSignature, Sign([]byte(sub.Secret), body))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("unexpected status %d", resp.StatusCode)
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// backoff returns the delay before the attempt following the given number of failed ones.
+func (n *Notifier) backoff(failed int) time.Duration {
+	d := n.cfg.InitialBackoff
+	for i := 1; i < failed; i++ {
+		d *= 2
+		if n.cfg.MaxBackoff > 0 && d >= n.cfg.MaxBackoff {
+			return n.cfg.MaxBackoff
+		}
+	}
+	return d
+}
+
+// Sign returns the X-PRManager-Signature-256 header value: HMAC-SHA256 of the body with the subscriber secret.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,442 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	"github.com/hihikaAAa/PRManager/internal/domain/subscriber"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+)
+
+type storeMock struct {
+	mu          sync.Mutex
+	subs        []subscriber.Subscriber
+	deadLetters []subscriber.DeadLetter
+	retries     []subscriber.Retry
+	retrySeq    int64
+}
+
+func (m *storeMock) List(ctx context.Context) ([]subscriber.Subscriber, error) {
+	return m.subs, nil
+}
+
+func (m *storeMock) SaveDeadLetter(ctx context.Context, dl subscriber.DeadLetter) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deadLetters = append(m.deadLetters, dl)
+	return nil
+}
+
+func (m *storeMock) ScheduleRetry(ctx context.Context, rt subscriber.Retry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, cur := range m.retries {
+		if cur.SubscriberID == rt.SubscriberID && cur.EventID == rt.EventID {
+			return nil
+		}
+	}
+	m.retrySeq++
+	rt.ID = m.retrySeq
+	m.retries = append(m.retries, rt)
+	return nil
+}
+
+func (m *storeMock) RetryingSubscribers(ctx context.Context) ([]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var ids []int64
+	for _, rt := range m.retries {
+		ids = append(ids, rt.SubscriberID)
+	}
+	return ids, nil
+}
+
+func (m *storeMock) ClaimDueRetries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]subscriber.Retry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []subscriber.Retry
+	blocked := make(map[int64]bool)
+	for i := range m.retries {
+		if len(out) == limit {
+			break
+		}
+		if blocked[m.retries[i].SubscriberID] || m.retries[i].NextAttemptAt.After(now) {
+			blocked[m.retries[i].SubscriberID] = true
+			continue
+		}
+		m.retries[i].NextAttemptAt = now.Add(lease)
+		out = append(out, m.retries[i])
+	}
+	return out, nil
+}
+
+func (m *storeMock) RescheduleRetry(ctx context.Context, rt subscriber.Retry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.retries {
+		if m.retries[i].ID == rt.ID {
+			m.retries[i] = rt
+		}
+	}
+	return nil
+}
+
+func (m *storeMock) DeleteRetry(ctx context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.retries {
+		if m.retries[i].ID == id {
+			m.retries = append(m.retries[:i], m.retries[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *storeMock) DeadLetterRetry(ctx context.Context, rt subscriber.Retry) error {
+	if err := m.DeleteRetry(ctx, rt.ID); err != nil {
+		return err
+	}
+	return m.SaveDeadLetter(ctx, subscriber.DeadLetter{
+		SubscriberID: rt.SubscriberID,
+		EventID:      rt.EventID,
+		EventType:    rt.EventType,
+		Payload:      rt.Payload,
+		Attempts:     rt.Attempts,
+		LastError:    rt.LastError,
+	})
+}
+
+func testConfig() Config {
+	return Config{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		RequestTimeout: time.Second,
+		BatchSize:      10,
+		Lease:          time.Minute,
+	}
+}
+
+func testEvent() event.Event {
+	return event.New(context.Background(), event.TypeReviewerReassigned, event.ReviewerReassigned{
+		PullRequestID: "pr-1", OldReviewerID: "u2", NewReviewerID: "u3", Reason: event.ReasonManualReassign,
+	})
+}
+
+func TestDeliver_SignedPayload(t *testing.T) {
+	const secret = "s3cr3t"
+
+	var (
+		gotSignature, gotEvent, gotDelivery string
+		gotBody                             []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSignature = r.Header.Get(HeaderSignature)
+		gotEvent = r.Header.Get(HeaderEvent)
+		gotDelivery = r.Header.Get(HeaderDelivery)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	store := &storeMock{subs: []subscriber.Subscriber{{ID: 1, URL: srv.URL, Secret: secret, IsActive: true}}}
+	n := New(slogdiscard.NewDiscardLogger(), store, testConfig())
+
+	ev := testEvent()
+	if err := n.Publish(context.Background(), ev); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotSignature != Sign([]byte(secret), gotBody) {
+		t.Fatalf("signature %q does not match body", gotSignature)
+	}
+	if gotEvent != string(event.TypeReviewerReassigned) || gotDelivery != ev.ID {
+		t.Fatalf("unexpected headers: event=%q delivery=%q", gotEvent, gotDelivery)
+	}
+
+	var payload struct {
+		Type event.Type               `json:"type"`
+		Data event.ReviewerReassigned `json:"data"`
+	}
+	if err := json.Unmarshal(gotBody, &payload); err != nil {
+		t.Fatalf("invalid json body: %v", err)
+	}
+	if payload.Type != event.TypeReviewerReassigned || payload.Data.NewReviewerID != "u3" {
+		t.Fatalf("unexpected payload: %s", gotBody)
+	}
+	if len(store.deadLetters) != 0 {
+		t.Fatalf("unexpected dead letters: %+v", store.deadLetters)
+	}
+}
+
+// retryAll runs the retry worker at the time each queued delivery becomes due.
+func retryAll(t *testing.T, n *Notifier, store *storeMock) {
+	t.Helper()
+
+	for i := 0; len(store.retries) > 0; i++ {
+		if i == 10 {
+			t.Fatalf("retries were not drained: %+v", store.retries)
+		}
+		due := store.retries[0].NextAttemptAt
+		n.now = func() time.Time { return due }
+		if _, err := n.ProcessRetries(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestDeliver_RetriesTransientErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	store := &storeMock{subs: []subscriber.Subscriber{{ID: 1, URL: srv.URL, Secret: "s", IsActive: true}}}
+	n := New(slogdiscard.NewDiscardLogger(), store, testConfig())
+
+	ev := testEvent()
+	if err := n.Publish(context.Background(), ev); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single attempt while publishing, got %d", calls.Load())
+	}
+	if len(store.retries) != 1 || store.retries[0].EventID != ev.ID || store.retries[0].Attempts != 1 {
+		t.Fatalf("unexpected retries: %+v", store.retries)
+	}
+
+	retryAll(t, n, store)
+
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls.Load())
+	}
+	if len(store.deadLetters) != 0 {
+		t.Fatalf("unexpected dead letters: %+v", store.deadLetters)
+	}
+}
+
+func TestDeliver_DeadLetterAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	store := &storeMock{subs: []subscriber.Subscriber{{ID: 7, URL: srv.URL, Secret: "s", IsActive: true}}}
+	n := New(slogdiscard.NewDiscardLogger(), store, testConfig())
+
+	ev := testEvent()
+	if err := n.Publish(context.Background(), ev); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	retryAll(t, n, store)
+
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls.Load())
+	}
+	if len(store.deadLetters) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(store.deadLetters))
+	}
+	dl := store.deadLetters[0]
+	if dl.SubscriberID != 7 || dl.EventID != ev.ID || dl.Attempts != 3 || dl.LastError == "" {
+		t.Fatalf("unexpected dead letter: %+v", dl)
+	}
+}
+
+func TestPublish_QueuesBehindRetryingSubscriber(t *testing.T) {
+	var down atomic.Bool
+	down.Store(true)
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		mu.Lock()
+		got = append(got, r.Header.Get(HeaderDelivery))
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	store := &storeMock{subs: []subscriber.Subscriber{{ID: 1, URL: srv.URL, Secret: "s", IsActive: true}}}
+	n := New(slogdiscard.NewDiscardLogger(), store, testConfig())
+	start := time.Now()
+	n.now = func() time.Time { return start }
+
+	first, second := testEvent(), testEvent()
+	if err := n.Publish(context.Background(), first); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	down.Store(false)
+	if err := n.Publish(context.Background(), second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("event delivered ahead of the queued one: %v", got)
+	}
+	if len(store.retries) != 2 || store.retries[1].EventID != second.ID || store.retries[1].Attempts != 0 {
+		t.Fatalf("unexpected retries: %+v", store.retries)
+	}
+
+	// The second event is due at once, but must wait out the backoff of the first.
+	if claimed, err := n.ProcessRetries(context.Background()); err != nil || claimed != 0 {
+		t.Fatalf("expected nothing claimed before the backoff, got %d, %v", claimed, err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("event delivered ahead of the queued one: %v", got)
+	}
+
+	retryAll(t, n, store)
+
+	if len(got) != 2 || got[0] != first.ID || got[1] != second.ID {
+		t.Fatalf("unexpected delivery order: %v", got)
+	}
+}
+
+func TestProcessRetries_FailureHoldsLaterDeliveries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	store := &storeMock{subs: []subscriber.Subscriber{{ID: 1, URL: srv.URL, Secret: "s", IsActive: true}}}
+	for _, id := range []string{"ev-1", "ev-2", "ev-3"} {
+		store.ScheduleRetry(context.Background(), subscriber.Retry{SubscriberID: 1, EventID: id, Attempts: 1, NextAttemptAt: now})
+	}
+	n := New(slogdiscard.NewDiscardLogger(), store, testConfig())
+	n.now = func() time.Time { return now }
+
+	if claimed, err := n.ProcessRetries(context.Background()); err != nil || claimed != 3 {
+		t.Fatalf("expected 3 claimed, got %d, %v", claimed, err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single attempt, got %d", calls.Load())
+	}
+	next := now.Add(n.backoff(2))
+	for _, rt := range store.retries {
+		if !rt.NextAttemptAt.Equal(next) {
+			t.Fatalf("expected all deliveries due at %v: %+v", next, store.retries)
+		}
+	}
+	if store.retries[0].Attempts != 2 || store.retries[1].Attempts != 1 {
+		t.Fatalf("only the tried delivery counts an attempt: %+v", store.retries)
+	}
+}
+
+func TestProcessRetries_InactiveSubscriber(t *testing.T) {
+	store := &storeMock{subs: []subscriber.Subscriber{{ID: 1, URL: "http://127.0.0.1:0", Secret: "s", IsActive: false}}}
+	now := time.Now()
+	store.ScheduleRetry(context.Background(), subscriber.Retry{SubscriberID: 1, EventID: "ev-1", Attempts: 2, NextAttemptAt: now})
+	store.ScheduleRetry(context.Background(), subscriber.Retry{SubscriberID: 9, EventID: "ev-1", Attempts: 1, NextAttemptAt: now})
+	n := New(slogdiscard.NewDiscardLogger(), store, testConfig())
+	n.now = func() time.Time { return now }
+
+	if _, err := n.ProcessRetries(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.retries) != 0 {
+		t.Fatalf("unexpected retries: %+v", store.retries)
+	}
+	if len(store.deadLetters) != 1 || store.deadLetters[0].SubscriberID != 1 || store.deadLetters[0].Attempts != 2 {
+		t.Fatalf("unexpected dead letters: %+v", store.deadLetters)
+	}
+}
+
+func TestDeliver_ClientErrorIsNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer srv.Close()
+
+	store := &storeMock{subs: []subscriber.Subscriber{{ID: 1, URL: srv.URL, Secret: "s", IsActive: true}}}
+	n := New(slogdiscard.NewDiscardLogger(), store, testConfig())
+
+	if err := n.Publish(context.Background(), testEvent()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single attempt, got %d", calls.Load())
+	}
+	if len(store.deadLetters) != 1 || store.deadLetters[0].Attempts != 1 {
+		t.Fatalf("unexpected dead letters: %+v", store.deadLetters)
+	}
+}
+
+func TestDispatch_SkipsUnsubscribedEvents(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	store := &storeMock{subs: []subscriber.Subscriber{
+		{ID: 1, URL: srv.URL, Secret: "s", IsActive: true, Events: []event.Type{event.TypePRMerged}},
+		{ID: 2, URL: srv.URL, Secret: "s", IsActive: false},
+	}}
+	n := New(slogdiscard.NewDiscardLogger(), store, testConfig())
+
+	if err := n.Publish(context.Background(), testEvent()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls.Load() != 0 {
+		t.Fatalf("expected no deliveries, got %d", calls.Load())
+	}
+}
+
+func TestPublish_InterruptedDeliveryIsNotDeadLettered(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	store := &storeMock{subs: []subscriber.Subscriber{{ID: 1, URL: srv.URL, Secret: "s", IsActive: true}}}
+	n := New(slogdiscard.NewDiscardLogger(), store, testConfig())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := n.Publish(ctx, testEvent()); err == nil {
+		t.Fatalf("expected error for interrupted delivery")
+	}
+	if len(store.deadLetters) != 0 || len(store.retries) != 0 {
+		t.Fatalf("interrupted delivery must be republished by the relay: %+v %+v", store.deadLetters, store.retries)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	n := New(slogdiscard.NewDiscardLogger(), &storeMock{}, Config{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second})
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := n.backoff(i + 1); got != w {
+			t.Fatalf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
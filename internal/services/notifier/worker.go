@@ -0,0 +1,196 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/subscriber"
+	"github.com/hihikaAAa/PRManager/internal/lib/health"
+	"github.com/hihikaAAa/PRManager/internal/lib/logger/sl"
+)
+
+type RetryStore interface {
+	ScheduleRetry(ctx context.Context, rt subscriber.Retry) error
+	RetryingSubscribers(ctx context.Context) ([]int64, error)
+	ClaimDueRetries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]subscriber.Retry, error)
+	RescheduleRetry(ctx context.Context, rt subscriber.Retry) error
+	DeleteRetry(ctx context.Context, id int64) error
+	DeadLetterRetry(ctx context.Context, rt subscriber.Retry) error
+}
+
+// Run retries queued deliveries until ctx is cancelled.
+func (n *Notifier) Run(ctx context.Context) {
+	n.state.Started()
+	defer n.state.Stopped()
+
+	ticker := time.NewTicker(n.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		var err error
+		for {
+			var claimed int
+			claimed, err = n.ProcessRetries(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					n.log.Error("failed to retry webhook deliveries", sl.Err(err))
+				}
+				break
+			}
+			if claimed < n.cfg.BatchSize {
+				break
+			}
+		}
+		if ctx.Err() == nil {
+			n.state.Polled(n.now(), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Status reports whether Run is polling and the outcome of the last poll.
+func (n *Notifier) Status() health.WorkerStatus {
+	return n.state.Status()
+}
+
+// ProcessRetries claims one batch of due deliveries for Lease and returns how many were claimed.
+// Subscribers are handled concurrently, the deliveries of one subscriber in the order
+// they were queued: after a failure the rest of them wait for the same next attempt.
+// Deliveries not tried within half the lease are released, so a batch never outlives its claim.
+func (n *Notifier) ProcessRetries(ctx context.Context) (int, error) {
+	const op = "internal.services.notifier.ProcessRetries"
+
+	now := n.now()
+	due, err := n.store.ClaimDueRetries(ctx, now, n.cfg.BatchSize, n.cfg.Lease)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(due) == 0 {
+		return 0, nil
+	}
+
+	subs, err := n.store.List(ctx)
+	if err != nil {
+		return len(due), fmt.Errorf("%s: %w", op, err)
+	}
+	byID := make(map[int64]subscriber.Subscriber, len(subs))
+	for _, s := range subs {
+		byID[s.ID] = s
+	}
+
+	var order []int64
+	groups := make(map[int64][]subscriber.Retry)
+	for _, rt := range due {
+		if _, ok := groups[rt.SubscriberID]; !ok {
+			order = append(order, rt.SubscriberID)
+		}
+		groups[rt.SubscriberID] = append(groups[rt.SubscriberID], rt)
+	}
+
+	deadline := now.Add(n.cfg.Lease / 2)
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		lastErr error
+	)
+	for _, id := range order {
+		sub, ok := byID[id]
+		wg.Add(1)
+		go func(retries []subscriber.Retry) {
+			defer wg.Done()
+			var err error
+			if ok {
+				err = n.retry(ctx, sub, retries, deadline)
+			} else {
+				err = n.drop(ctx, retries)
+			}
+			if err != nil {
+				mu.Lock()
+				lastErr = err
+				mu.Unlock()
+			}
+		}(groups[id])
+	}
+	wg.Wait()
+
+	if lastErr != nil {
+		return len(due), fmt.Errorf("%s: %w", op, lastErr)
+	}
+	return len(due), nil
+}
+
+// retry tries the claimed deliveries of one subscriber in order.
+func (n *Notifier) retry(ctx context.Context, sub subscriber.Subscriber, retries []subscriber.Retry, deadline time.Time) error {
+	var blockedUntil time.Time
+	for _, rt := range retries {
+		if !sub.IsActive {
+			rt.LastError = "subscriber is inactive"
+			if err := n.store.DeadLetterRetry(ctx, rt); err != nil {
+				return fmt.Errorf("dead-letter retry: %w", err)
+			}
+			continue
+		}
+
+		now := n.now()
+		if !blockedUntil.IsZero() || now.After(deadline) {
+			rt.NextAttemptAt = blockedUntil
+			if rt.NextAttemptAt.IsZero() {
+				rt.NextAttemptAt = now
+			}
+			if err := n.store.RescheduleRetry(ctx, rt); err != nil {
+				return fmt.Errorf("reschedule retry: %w", err)
+			}
+			continue
+		}
+
+		retry, err := n.send(ctx, sub, rt.EventType, rt.EventID, rt.Payload)
+		if err == nil {
+			if err := n.store.DeleteRetry(ctx, rt.ID); err != nil {
+				return fmt.Errorf("delete retry: %w", err)
+			}
+			continue
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("delivery interrupted: %w", ctx.Err())
+		}
+
+		rt.Attempts++
+		rt.LastError = err.Error()
+		if !retry || rt.Attempts >= n.cfg.MaxAttempts {
+			n.log.Warn("webhook delivery failed",
+				slog.Int64("subscriber_id", rt.SubscriberID),
+				slog.String("event_id", rt.EventID),
+				slog.Int("attempts", rt.Attempts),
+				slog.String("err", rt.LastError),
+			)
+			if err := n.store.DeadLetterRetry(ctx, rt); err != nil {
+				return fmt.Errorf("dead-letter retry: %w", err)
+			}
+			continue
+		}
+		rt.NextAttemptAt = now.Add(n.backoff(rt.Attempts))
+		if err := n.store.RescheduleRetry(ctx, rt); err != nil {
+			return fmt.Errorf("reschedule retry: %w", err)
+		}
+		blockedUntil = rt.NextAttemptAt
+	}
+	return nil
+}
+
+// drop removes the deliveries of a subscriber that no longer exists.
+func (n *Notifier) drop(ctx context.Context, retries []subscriber.Retry) error {
+	for _, rt := range retries {
+		if err := n.store.DeleteRetry(ctx, rt.ID); err != nil {
+			return fmt.Errorf("delete retry: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	"github.com/hihikaAAa/PRManager/internal/lib/health"
+	"github.com/hihikaAAa/PRManager/internal/lib/logger/sl"
+)
+
+type Store interface {
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]event.Event, error)
+	MarkPublished(ctx context.Context, eventIDs []string) error
+	MarkFailed(ctx context.Context, eventID string, cause error, maxAttempts int) (bool, error)
+	PrunePublished(ctx context.Context, retention time.Duration, limit int) (int, error)
+}
+
+// Sink publishes an event somewhere. Publish must be safe to call again for
+// the same event: the relay guarantees at-least-once, not exactly-once delivery.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, ev event.Event) error
+}
+
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int
+	// Lease is how long a claimed event stays invisible to other relays.
+	// A failed event is retried after its lease expires.
+	Lease time.Duration
+	// MaxAttempts is how many times an event is tried before it is dead-lettered.
+	MaxAttempts int
+	// Retention is how long published events are kept; 0 keeps them forever.
+	Retention time.Duration
+}
+
+// Relay moves events from the outbox table to the sinks.
+// An event is marked published only after every sink accepted it.
+type Relay struct {
+	store Store
+	sinks []Sink
+	cfg   Config
+	log   *slog.Logger
+	state health.WorkerState
+}
+
+func NewRelay(log *slog.Logger, store Store, cfg Config, sinks ...Sink) *Relay {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 10
+	}
+	return &Relay{store: store, sinks: sinks, cfg: cfg, log: log.With(slog.String("component", "outbox_relay"))}
+}
+
+// Run polls the outbox until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	r.state.Started()
+	defer r.state.Stopped()
+
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		var err error
+		for {
+			var n int
+			n, err = r.ProcessBatch(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					r.log.Error("failed to process outbox batch", sl.Err(err))
+				}
+				break
+			}
+			if n < r.cfg.BatchSize {
+				break
+			}
+		}
+		if err == nil && r.cfg.Retention > 0 {
+			if _, err = r.Prune(ctx); err != nil && ctx.Err() == nil {
+				r.log.Error("failed to prune published events", sl.Err(err))
+			}
+		}
+		if ctx.Err() == nil {
+			r.state.Polled(time.Now(), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Status reports whether Run is polling and the outcome of the last poll.
+func (r *Relay) Status() health.WorkerStatus {
+	return r.state.Status()
+}
+
+// ProcessBatch publishes one batch of pending events and returns how many were claimed.
+func (r *Relay) ProcessBatch(ctx context.Context) (int, error) {
+	const op = "internal.services.outbox.ProcessBatch"
+
+	events, err := r.store.ClaimPending(ctx, r.cfg.BatchSize, r.cfg.Lease)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	published := make([]string, 0, len(events))
+	for _, ev := range events {
+		if err := r.publish(ctx, ev); err != nil {
+			r.log.Warn("failed to publish event",
+				slog.String("event_id", ev.ID),
+				slog.String("type", string(ev.Type)),
+				sl.Err(err),
+			)
+			dead, markErr := r.store.MarkFailed(ctx, ev.ID, err, r.cfg.MaxAttempts)
+			if markErr != nil {
+				return len(events), fmt.Errorf("%s: %w", op, markErr)
+			}
+			if dead {
+				r.log.Error("event dead-lettered after max attempts",
+					slog.String("event_id", ev.ID),
+					slog.String("type", string(ev.Type)),
+					slog.Int("attempts", r.cfg.MaxAttempts),
+				)
+			}
+			continue
+		}
+		published = append(published, ev.ID)
+	}
+
+	if len(published) > 0 {
+		if err := r.store.MarkPublished(ctx, published); err != nil {
+			return len(events), fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	return len(events), nil
+}
+
+// Prune deletes the events published more than Retention ago and returns how many.
+func (r *Relay) Prune(ctx context.Context) (int, error) {
+	const op = "internal.services.outbox.Prune"
+
+	total := 0
+	for {
+		n, err := r.store.PrunePublished(ctx, r.cfg.Retention, r.cfg.BatchSize)
+		total += n
+		if err != nil {
+			return total, fmt.Errorf("%s: %w", op, err)
+		}
+		if n < r.cfg.BatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (r *Relay) publish(ctx context.Context, ev event.Event) error {
+	for _, s := range r.sinks {
+		if err := s.Publish(ctx, ev); err != nil {
+			return fmt.Errorf("sink %s: %w", s.Name(), err)
+		}
+	}
+	return nil
+}
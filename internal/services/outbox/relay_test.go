@@ -0,0 +1,337 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	slogdiscard "github.com/hihikaAAa/PRManager/internal/lib/logger/slogdiscard"
+)
+
+// storeMock is an in-memory outbox with leases.
+type storeMock struct {
+	mu        sync.Mutex
+	now       time.Time
+	rows      []*row
+	claimErr  error
+	published []string
+}
+
+type row struct {
+	ev          event.Event
+	lockedUntil time.Time
+	published   bool
+	publishedAt time.Time
+	dead        bool
+	attempts    int
+	lastErr     string
+}
+
+func newStore(events ...event.Event) *storeMock {
+	s := &storeMock{now: time.Unix(0, 0)}
+	for _, ev := range events {
+		s.rows = append(s.rows, &row{ev: ev})
+	}
+	return s
+}
+
+func (s *storeMock) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]event.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.claimErr != nil {
+		return nil, s.claimErr
+	}
+	var out []event.Event
+	for _, r := range s.rows {
+		if len(out) == limit {
+			break
+		}
+		if r.published || r.dead || r.lockedUntil.After(s.now) {
+			continue
+		}
+		r.lockedUntil = s.now.Add(lease)
+		out = append(out, r.ev)
+	}
+	return out, nil
+}
+
+func (s *storeMock) MarkPublished(ctx context.Context, ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		for _, r := range s.rows {
+			if r.ev.ID == id {
+				r.published = true
+				r.publishedAt = s.now
+			}
+		}
+		s.published = append(s.published, id)
+	}
+	return nil
+}
+
+func (s *storeMock) MarkFailed(ctx context.Context, id string, cause error, maxAttempts int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.rows {
+		if r.ev.ID == id {
+			r.attempts++
+			r.lastErr = cause.Error()
+			r.dead = r.attempts >= maxAttempts
+			return r.dead, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *storeMock) PrunePublished(ctx context.Context, retention time.Duration, limit int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var (
+		kept []*row
+		n    int
+	)
+	for _, r := range s.rows {
+		if n < limit && r.published && r.publishedAt.Before(s.now.Add(-retention)) {
+			n++
+			continue
+		}
+		kept = append(kept, r)
+	}
+	s.rows = kept
+	return n, nil
+}
+
+func (s *storeMock) advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = s.now.Add(d)
+}
+
+// flakySink fails the first failures calls.
+type flakySink struct {
+	failures int
+	calls    int
+}
+
+func (s *flakySink) Name() string { return "flaky" }
+
+func (s *flakySink) Publish(ctx context.Context, ev event.Event) error {
+	s.calls++
+	if s.calls <= s.failures {
+		return errors.New("sink unavailable")
+	}
+	return nil
+}
+
+// rejectingSink fails every event with the given ID.
+type rejectingSink struct {
+	eventID string
+}
+
+func (s rejectingSink) Name() string { return "rejecting" }
+
+func (s rejectingSink) Publish(ctx context.Context, ev event.Event) error {
+	if ev.ID == s.eventID {
+		return errors.New("event rejected")
+	}
+	return nil
+}
+
+func testEvent(prID string) event.Event {
+	return event.New(context.Background(), event.TypeReviewersAssigned, event.ReviewersAssigned{PullRequestID: prID, Reviewers: []string{"u2"}})
+}
+
+func TestProcessBatch_PublishesToAllSinks(t *testing.T) {
+	first, second := testEvent("pr-1"), testEvent("pr-2")
+	store := newStore(first, second)
+	mem := NewMemorySink()
+	logSink := NewLogSink(slogdiscard.NewDiscardLogger())
+
+	relay := NewRelay(slogdiscard.NewDiscardLogger(), store, Config{BatchSize: 10, Lease: time.Minute}, logSink, mem)
+
+	n, err := relay.ProcessBatch(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 claimed events, got %d", n)
+	}
+
+	got := mem.Events()
+	if len(got) != 2 || got[0].ID != first.ID || got[1].ID != second.ID {
+		t.Fatalf("unexpected events in sink: %+v", got)
+	}
+	if len(store.published) != 2 {
+		t.Fatalf("expected both events to be marked published, got %v", store.published)
+	}
+
+	if n, _ := relay.ProcessBatch(context.Background()); n != 0 {
+		t.Fatalf("published events must not be claimed again, got %d", n)
+	}
+}
+
+func TestProcessBatch_RetriesFailedEventAfterLease(t *testing.T) {
+	ev := testEvent("pr-1")
+	store := newStore(ev)
+	sink := &flakySink{failures: 1}
+	mem := NewMemorySink()
+
+	relay := NewRelay(slogdiscard.NewDiscardLogger(), store, Config{BatchSize: 10, Lease: time.Minute}, sink, mem)
+
+	if _, err := relay.ProcessBatch(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.published) != 0 || store.rows[0].attempts != 1 || store.rows[0].lastErr == "" {
+		t.Fatalf("failed event must stay pending with the error recorded: %+v", store.rows[0])
+	}
+
+	if n, _ := relay.ProcessBatch(context.Background()); n != 0 {
+		t.Fatalf("event must stay leased until the lease expires, claimed %d", n)
+	}
+
+	store.advance(2 * time.Minute)
+	if _, err := relay.ProcessBatch(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.published) != 1 || store.published[0] != ev.ID {
+		t.Fatalf("expected event to be published on retry, got %v", store.published)
+	}
+	if len(mem.Events()) != 1 {
+		t.Fatalf("later sinks must not receive the event before earlier ones succeed, got %d", len(mem.Events()))
+	}
+}
+
+func TestProcessBatch_DeadLettersAfterMaxAttempts(t *testing.T) {
+	failing, next := testEvent("pr-1"), testEvent("pr-2")
+	store := newStore(failing, next)
+	sink := rejectingSink{eventID: failing.ID}
+
+	relay := NewRelay(slogdiscard.NewDiscardLogger(), store, Config{BatchSize: 10, Lease: time.Minute, MaxAttempts: 2}, sink)
+
+	for i := 0; i < 2; i++ {
+		if _, err := relay.ProcessBatch(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		store.advance(2 * time.Minute)
+	}
+	if !store.rows[0].dead || store.rows[0].attempts != 2 {
+		t.Fatalf("expected event to be dead-lettered after 2 attempts: %+v", store.rows[0])
+	}
+	if len(store.published) != 1 || store.published[0] != next.ID {
+		t.Fatalf("expected only the second event to be published, got %v", store.published)
+	}
+
+	if n, _ := relay.ProcessBatch(context.Background()); n != 0 {
+		t.Fatalf("dead-lettered events must not be claimed again, got %d", n)
+	}
+}
+
+func TestPrune_DeletesPublishedAfterRetention(t *testing.T) {
+	store := newStore(testEvent("pr-1"), testEvent("pr-2"), testEvent("pr-3"))
+	sink := &flakySink{failures: 1}
+
+	relay := NewRelay(slogdiscard.NewDiscardLogger(), store, Config{BatchSize: 1, Lease: time.Minute, Retention: time.Hour}, sink)
+	for i := 0; i < 3; i++ {
+		if _, err := relay.ProcessBatch(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if n, err := relay.Prune(context.Background()); err != nil || n != 0 {
+		t.Fatalf("pruned events within retention: %d, %v", n, err)
+	}
+	store.advance(2 * time.Hour)
+	if n, err := relay.Prune(context.Background()); err != nil || n != 2 {
+		t.Fatalf("expected 2 pruned events, got %d, %v", n, err)
+	}
+	if len(store.rows) != 1 || store.rows[0].published {
+		t.Fatalf("unpublished events must be kept: %+v", store.rows)
+	}
+}
+
+func TestProcessBatch_ClaimError(t *testing.T) {
+	store := newStore()
+	store.claimErr = errors.New("db down")
+
+	relay := NewRelay(slogdiscard.NewDiscardLogger(), store, Config{BatchSize: 10, Lease: time.Minute}, NewMemorySink())
+
+	if _, err := relay.ProcessBatch(context.Background()); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestRun_DrainsUntilCancelled(t *testing.T) {
+	store := newStore(testEvent("pr-1"), testEvent("pr-2"), testEvent("pr-3"))
+	mem := NewMemorySink()
+
+	relay := NewRelay(slogdiscard.NewDiscardLogger(), store, Config{PollInterval: time.Millisecond, BatchSize: 2, Lease: time.Minute}, mem)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.After(2 * time.Second)
+	for len(mem.Events()) < 3 {
+		select {
+		case <-deadline:
+			t.Fatalf("expected 3 events, got %d", len(mem.Events()))
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	cancel()
+	<-done
+}
+
+func TestRun_ReportsStatus(t *testing.T) {
+	store := newStore()
+	store.claimErr = errors.New("db down")
+
+	relay := NewRelay(slogdiscard.NewDiscardLogger(), store, Config{PollInterval: time.Millisecond, BatchSize: 2, Lease: time.Minute}, NewMemorySink())
+	if relay.Status().Running {
+		t.Fatalf("relay is running before Run")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.After(2 * time.Second)
+	for relay.Status().LastError == "" {
+		select {
+		case <-deadline:
+			t.Fatalf("poll error was not reported: %+v", relay.Status())
+		case <-time.After(time.Millisecond):
+		}
+	}
+	if st := relay.Status(); !st.Running || st.LastRunAt == nil {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+
+	store.mu.Lock()
+	store.claimErr = nil
+	store.mu.Unlock()
+	for relay.Status().LastError != "" {
+		select {
+		case <-deadline:
+			t.Fatalf("successful poll did not clear the error: %+v", relay.Status())
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	cancel()
+	<-done
+	if relay.Status().Running {
+		t.Fatalf("relay is running after Run returned")
+	}
+}
@@ -0,0 +1,66 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+)
+
+// LogSink writes events to the log.
+type LogSink struct {
+	log *slog.Logger
+}
+
+func NewLogSink(log *slog.Logger) *LogSink {
+	return &LogSink{log: log}
+}
+
+func (s *LogSink) Name() string {
+	return "log"
+}
+
+func (s *LogSink) Publish(_ context.Context, ev event.Event) error {
+	data, err := json.Marshal(ev.Data)
+	if err != nil {
+		return err
+	}
+	s.log.Info("domain event",
+		slog.String("event_id", ev.ID),
+		slog.String("type", string(ev.Type)),
+		slog.Time("occurred_at", ev.OccurredAt),
+		slog.String("data", string(data)),
+	)
+	return nil
+}
+
+// MemorySink keeps published events in memory. It is meant for tests.
+type MemorySink struct {
+	mu     sync.Mutex
+	events []event.Event
+}
+
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+func (s *MemorySink) Name() string {
+	return "memory"
+}
+
+func (s *MemorySink) Publish(_ context.Context, ev event.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, ev)
+	return nil
+}
+
+func (s *MemorySink) Events() []event.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]event.Event, len(s.events))
+	copy(out, s.events)
+	return out
+}
@@ -0,0 +1,187 @@
+package prservice
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	"github.com/hihikaAAa/PRManager/internal/lib/metrics"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/services/assigner"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+// BulkBatchSize is the number of PRs stored per transaction by BulkCreate.
+const BulkBatchSize = 100
+
+// BulkItem is a PR to import. Nil Reviewers are assigned as on Create; a non-nil
+// list is kept as it is, so an existing PR keeps the reviewers it already has.
+type BulkItem struct {
+	ID        string
+	Name      string
+	AuthorID  string
+	Draft     bool
+	Reviewers []string
+}
+
+// BulkResult is the outcome of one BulkItem: the created PR or the error Create would return.
+type BulkResult struct {
+	PR  *pullrequest.PullRequest
+	Err error
+}
+
+// BulkCreate creates the PRs in batches of BulkBatchSize, each batch in one transaction.
+// A failed item does not affect the others. Automatic assignment counts the reviews
+// given to the earlier items of the batch, as if they had been created one by one.
+func (s *PRService) BulkCreate(ctx context.Context, items []BulkItem) []BulkResult {
+	const op = "internal.services.prservice.BulkCreate"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	results := make([]BulkResult, len(items))
+	seen := make(map[string]struct{}, len(items))
+
+	for start := 0; start < len(items); start += BulkBatchSize {
+		end := min(start+BulkBatchSize, len(items))
+		s.bulkBatch(ctx, items[start:end], results[start:end], seen)
+	}
+	return results
+}
+
+func (s *PRService) bulkBatch(ctx context.Context, items []BulkItem, results []BulkResult, seen map[string]struct{}) {
+	type prepared struct {
+		idx    int
+		pr     pullrequest.PullRequest
+		events []event.Event
+	}
+
+	a, pending := s.assigner.WithPendingLoads()
+	var batch []prepared
+	for i, it := range items {
+		if _, dup := seen[it.ID]; dup {
+			results[i].Err = serviceerrors.ErrPRExists
+			continue
+		}
+
+		pr, events, err := s.prepareImport(ctx, a, it)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		if pr.Status == pullrequest.StatusOpen {
+			pending.Add(pr.Reviewers)
+		}
+		// Only a prepared item claims its id: a later item with the same id may fix a rejected one.
+		seen[it.ID] = struct{}{}
+		batch = append(batch, prepared{idx: i, pr: pr, events: events})
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	prs := make([]pullrequest.PullRequest, 0, len(batch))
+	var events []event.Event
+	for _, p := range batch {
+		prs = append(prs, p.pr)
+		events = append(events, p.events...)
+	}
+	if err := s.prRepo.CreateManyWithReviewers(ctx, prs, events...); err == nil {
+		for _, p := range batch {
+			results[p.idx].PR = &p.pr
+		}
+		metrics.PRsCreated.Add(float64(len(batch)))
+		return
+	}
+
+	// Something changed since validation (a PR created concurrently, a user removed)
+	// and failed the whole batch; store the PRs one by one to find the culprit.
+	for _, p := range batch {
+		if err := s.prRepo.CreateWithReviewers(ctx, p.pr, p.events...); err != nil {
+			if _, getErr := s.prRepo.GetWithReviewers(ctx, p.pr.ID); getErr == nil {
+				err = serviceerrors.ErrPRExists
+			} else {
+				delete(seen, p.pr.ID)
+			}
+			results[p.idx].Err = err
+			continue
+		}
+		results[p.idx].PR = &p.pr
+		metrics.PRsCreated.Inc()
+	}
+}
+
+// prepareImport validates the item and builds the PR with its events, without storing anything.
+func (s *PRService) prepareImport(ctx context.Context, a *assigner.Assigner, it BulkItem) (pullrequest.PullRequest, []event.Event, error) {
+	if _, err := s.prRepo.GetWithReviewers(ctx, it.ID); err == nil {
+		return pullrequest.PullRequest{}, nil, serviceerrors.ErrPRExists
+	} else if !errors.Is(err, repo_errors.ErrPRNotFound) {
+		return pullrequest.PullRequest{}, nil, err
+	}
+	if err := s.userExists(ctx, it.AuthorID); err != nil {
+		return pullrequest.PullRequest{}, nil, err
+	}
+
+	pr := pullrequest.PullRequest{
+		ID: it.ID, Name: it.Name, AuthorID: it.AuthorID, Status: pullrequest.StatusOpen, CreatedAt: time.Now(),
+	}
+	switch {
+	case it.Draft:
+		if len(it.Reviewers) > 0 {
+			return pullrequest.PullRequest{}, nil, serviceerrors.ErrInvalidReviewers
+		}
+		pr.Status = pullrequest.StatusDraft
+		return pr, nil, nil
+	case it.Reviewers != nil:
+		if err := s.checkImportedReviewers(ctx, it.AuthorID, it.Reviewers); err != nil {
+			return pullrequest.PullRequest{}, nil, err
+		}
+		pr.Reviewers = it.Reviewers
+		pr.Reviews = pullrequest.PendingReviews(it.Reviewers)
+		if len(it.Reviewers) == 0 {
+			return pr, nil, nil
+		}
+		return pr, []event.Event{event.New(ctx, event.TypeReviewersAssigned, event.ReviewersAssigned{
+			PullRequestID: pr.ID, PullRequestName: pr.Name, AuthorID: pr.AuthorID,
+			Reviewers: pr.Reviewers, Reason: event.ReasonImport,
+		})}, nil
+	default:
+		res, err := s.assignReviewers(ctx, a, it.AuthorID, nil)
+		if err != nil {
+			return pullrequest.PullRequest{}, nil, err
+		}
+		pr.Reviewers = res.Reviewers
+		pr.FallbackReviewers = res.Fallback
+		pr.Reviews = pullrequest.PendingReviews(res.Reviewers)
+		return pr, assignedEvents(ctx, &pr, assigner.Assignment{Reviewers: res.Reviewers, Fallback: res.Fallback}), nil
+	}
+}
+
+// checkImportedReviewers accepts any existing users except the author, each listed once.
+// Inactive users are allowed: the import records reviews that already exist.
+func (s *PRService) checkImportedReviewers(ctx context.Context, authorID string, reviewers []string) error {
+	seen := make(map[string]struct{}, len(reviewers))
+	for _, id := range reviewers {
+		if _, dup := seen[id]; dup || id == authorID || id == "" {
+			return serviceerrors.ErrInvalidReviewers
+		}
+		seen[id] = struct{}{}
+		if err := s.userExists(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *PRService) userExists(ctx context.Context, id string) error {
+	if _, err := s.userRepo.GetByID(ctx, id); err != nil {
+		if errors.Is(err, repo_errors.ErrUserNotFound) {
+			return serviceerrors.ErrUserNotFound
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package prservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	"github.com/hihikaAAa/PRManager/internal/repository"
+	"github.com/hihikaAAa/PRManager/internal/services/assigner"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+func TestBulkCreate_PerItemResults(t *testing.T) {
+	svc, _ := newTestService(t)
+	ctx := context.Background()
+
+	if _, err := svc.Create(ctx, "pr-old", "Old", "u1", false, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	results := svc.BulkCreate(ctx, []BulkItem{
+		{ID: "pr-1", Name: "Auto", AuthorID: "u1"},
+		{ID: "pr-2", Name: "Imported", AuthorID: "u1", Reviewers: []string{"u4"}},
+		{ID: "pr-3", Name: "Draft", AuthorID: "u2", Draft: true},
+		{ID: "pr-1", Name: "Duplicate in request", AuthorID: "u1"},
+		{ID: "pr-old", Name: "Exists", AuthorID: "u1"},
+		{ID: "pr-4", Name: "No author", AuthorID: "nobody"},
+		{ID: "pr-5", Name: "No reviewer", AuthorID: "u1", Reviewers: []string{"nobody"}},
+		{ID: "pr-6", Name: "Self review", AuthorID: "u1", Reviewers: []string{"u1"}},
+		{ID: "pr-7", Name: "Draft with reviewers", AuthorID: "u1", Draft: true, Reviewers: []string{"u2"}},
+		{ID: "pr-8", Name: "No reviewers", AuthorID: "u1", Reviewers: []string{}},
+	})
+
+	wantErr := []error{
+		nil, nil, nil,
+		serviceerrors.ErrPRExists, serviceerrors.ErrPRExists,
+		serviceerrors.ErrUserNotFound, serviceerrors.ErrUserNotFound,
+		serviceerrors.ErrInvalidReviewers, serviceerrors.ErrInvalidReviewers,
+		nil,
+	}
+	for i, want := range wantErr {
+		r := results[i]
+		if want == nil && (r.Err != nil || r.PR == nil) {
+			t.Fatalf("item %d: unexpected result %+v", i, r)
+		}
+		if want != nil && (!errors.Is(r.Err, want) || r.PR != nil) {
+			t.Fatalf("item %d: expected %v, got %+v", i, want, r)
+		}
+	}
+
+	if len(results[0].PR.Reviewers) != 2 {
+		t.Fatalf("expected automatic reviewers, got %v", results[0].PR.Reviewers)
+	}
+	if results[2].PR.Status != pullrequest.StatusDraft {
+		t.Fatalf("unexpected draft: %+v", results[2].PR)
+	}
+
+	pr, err := svc.prRepo.GetWithReviewers(ctx, "pr-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pr.Reviewers) != 1 || pr.Reviewers[0] != "u4" {
+		t.Fatalf("imported reviewers were not kept: %v", pr.Reviewers)
+	}
+	hist, err := svc.History(ctx, "pr-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hist) != 1 || hist[0].Reason != event.ReasonImport {
+		t.Fatalf("unexpected history: %+v", hist)
+	}
+
+	for _, id := range []string{"pr-4", "pr-5", "pr-6", "pr-7"} {
+		if _, err := svc.prRepo.GetWithReviewers(ctx, id); err == nil {
+			t.Fatalf("failed item %s was stored", id)
+		}
+	}
+}
+
+func TestBulkCreate_RejectedItemDoesNotClaimID(t *testing.T) {
+	svc, _ := newTestService(t)
+	ctx := context.Background()
+
+	results := svc.BulkCreate(ctx, []BulkItem{
+		{ID: "pr-1", Name: "Unknown author", AuthorID: "nobody"},
+		{ID: "pr-1", Name: "Fixed", AuthorID: "u1"},
+		{ID: "pr-1", Name: "Duplicate of the fixed one", AuthorID: "u1"},
+	})
+
+	if !errors.Is(results[0].Err, serviceerrors.ErrUserNotFound) {
+		t.Fatalf("item 0: expected ErrUserNotFound, got %+v", results[0])
+	}
+	if results[1].Err != nil || results[1].PR == nil || results[1].PR.Name != "Fixed" {
+		t.Fatalf("item 1: the corrected item must be created, got %+v", results[1])
+	}
+	if !errors.Is(results[2].Err, serviceerrors.ErrPRExists) {
+		t.Fatalf("item 2: expected ErrPRExists, got %+v", results[2])
+	}
+}
+
+func TestBulkCreate_ManyBatches(t *testing.T) {
+	svc, _ := newTestService(t)
+	ctx := context.Background()
+
+	items := make([]BulkItem, 2*BulkBatchSize+5)
+	for i := range items {
+		items[i] = BulkItem{ID: fmt.Sprintf("pr-%d", i), Name: "PR", AuthorID: "u1", Reviewers: []string{"u2"}}
+	}
+
+	for i, r := range svc.BulkCreate(ctx, items) {
+		if r.Err != nil {
+			t.Fatalf("item %d: %v", i, r.Err)
+		}
+	}
+	if _, err := svc.prRepo.GetWithReviewers(ctx, items[len(items)-1].ID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// racingPRRepository creates a PR of the batch right before the batch is stored,
+// as a concurrent /pullRequest/create would.
+type racingPRRepository struct {
+	repository.PRRepository
+	racedID string
+}
+
+func (r *racingPRRepository) CreateManyWithReviewers(ctx context.Context, prs []pullrequest.PullRequest, events ...event.Event) error {
+	pr := pullrequest.PullRequest{ID: r.racedID, Name: "Raced", AuthorID: "u1", Status: pullrequest.StatusOpen}
+	if err := r.PRRepository.CreateWithReviewers(ctx, pr); err != nil {
+		return err
+	}
+	return r.PRRepository.CreateManyWithReviewers(ctx, prs, events...)
+}
+
+func TestBulkCreate_FailedBatchFallsBackToSingleInserts(t *testing.T) {
+	_, repos := newTestService(t)
+	ctx := context.Background()
+
+	a, err := assigner.New(repos.Users, repos.Teams, repos.PRs, assigner.StrategyRandom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := New(&racingPRRepository{PRRepository: repos.PRs, racedID: "pr-2"}, repos.Users, a)
+
+	results := svc.BulkCreate(ctx, []BulkItem{
+		{ID: "pr-1", Name: "One", AuthorID: "u1", Reviewers: []string{"u2"}},
+		{ID: "pr-2", Name: "Two", AuthorID: "u1", Reviewers: []string{"u3"}},
+		{ID: "pr-3", Name: "Three", AuthorID: "u1", Reviewers: []string{"u4"}},
+	})
+
+	if results[0].Err != nil || results[2].Err != nil {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if !errors.Is(results[1].Err, serviceerrors.ErrPRExists) {
+		t.Fatalf("expected ErrPRExists, got %v", results[1].Err)
+	}
+	for _, id := range []string{"pr-1", "pr-3"} {
+		if _, err := repos.PRs.GetWithReviewers(ctx, id); err != nil {
+			t.Fatalf("%s: %v", id, err)
+		}
+	}
+}
+
+func TestBulkCreate_EnforcesCapacityWithinBatch(t *testing.T) {
+	svc, repos := newTestService(t)
+	ctx := context.Background()
+
+	err := repos.Teams.UpsertSettings(ctx, team.Settings{TeamName: "backend", ReviewersRequired: 1, MinReviewers: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"u2", "u3", "u4"} {
+		if _, err := repos.Users.SetMaxOpenReviews(ctx, id, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	items := make([]BulkItem, 4)
+	for i := range items {
+		items[i] = BulkItem{ID: fmt.Sprintf("pr-%d", i), Name: "Bulk", AuthorID: "u1"}
+	}
+	results := svc.BulkCreate(ctx, items)
+
+	assigned := make(map[string]int)
+	for _, r := range results[:3] {
+		if r.Err != nil || len(r.PR.Reviewers) != 1 {
+			t.Fatalf("unexpected result %+v", r)
+		}
+		assigned[r.PR.Reviewers[0]]++
+	}
+	if len(assigned) != 3 {
+		t.Fatalf("a reviewer got more than their capacity: %v", assigned)
+	}
+	if !errors.Is(results[3].Err, serviceerrors.ErrCandidatesAtCapacity) {
+		t.Fatalf("expected ErrCandidatesAtCapacity, got %+v", results[3])
+	}
+}
+
+func TestBulkCreate_LeastLoadedSpreadsBatch(t *testing.T) {
+	svc, repos := newTestService(t)
+	ctx := context.Background()
+
+	err := repos.Teams.UpsertSettings(ctx, team.Settings{
+		TeamName: "backend", ReviewersRequired: 1, MinReviewers: 1, Strategy: assigner.StrategyLeastLoaded,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := make([]BulkItem, 6)
+	for i := range items {
+		items[i] = BulkItem{ID: fmt.Sprintf("pr-%d", i), Name: "Bulk", AuthorID: "u1"}
+	}
+
+	assigned := make(map[string]int)
+	for _, r := range svc.BulkCreate(ctx, items) {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+		assigned[r.PR.Reviewers[0]]++
+	}
+	for _, id := range []string{"u2", "u3", "u4"} {
+		if assigned[id] != 2 {
+			t.Fatalf("expected the batch spread evenly, got %v", assigned)
+		}
+	}
+}
@@ -4,65 +4,312 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/rand"
 	"time"
 
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
 	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
 	"github.com/hihikaAAa/PRManager/internal/domain/user"
-	"github.com/hihikaAAa/PRManager/internal/repository/postgres"
-	"github.com/hihikaAAa/PRManager/internal/repository/postgres/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/lib/metrics"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/services/assigner"
 	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
 )
 
+type PRRepository interface{
+	CreateWithReviewers(ctx context.Context, pr pullrequest.PullRequest, events ...event.Event) error
+	CreateManyWithReviewers(ctx context.Context, prs []pullrequest.PullRequest, events ...event.Event) error
+	GetWithReviewers(ctx context.Context, id string)(*pullrequest.PullRequest, error)
+	Merge(ctx context.Context, id string, now time.Time, events ...event.Event)(*pullrequest.PullRequest, bool, error)
+	ReplaceReviewers(ctx context.Context, prID, oldRevID, newRevID string, events ...event.Event) error
+	SetReviewState(ctx context.Context, prID, userID string, state pullrequest.ReviewState, now time.Time) error
+	MarkReady(ctx context.Context, prID string, reviewers []string, events ...event.Event) error
+	Close(ctx context.Context, prID string, now time.Time, events ...event.Event) error
+	Reopen(ctx context.Context, prID string, reviewers []string, events ...event.Event) error
+	HistoryByPR(ctx context.Context, prID string)([]assignment.Change, error)
+}
+
+type UserGetter interface{
+	GetByID(ctx context.Context, id string)(*user.User, error)
+}
+
 type PRService struct{
-	prRepo *postgres.PRRepository
-	userRepo *postgres.UserRepository
+	prRepo PRRepository
+	userRepo UserGetter
+	assigner *assigner.Assigner
 }
 
-func New(prRepo *postgres.PRRepository, userRepo *postgres.UserRepository) *PRService{
-	return &PRService{prRepo: prRepo, userRepo: userRepo}
+func New(prRepo PRRepository, userRepo UserGetter, assigner *assigner.Assigner) *PRService{
+	return &PRService{prRepo: prRepo, userRepo: userRepo, assigner: assigner}
 }
 
-func (s *PRService) Create(ctx context.Context, id,name,authorID string)(*pullrequest.PullRequest, error){
+// Create stores a new PR. Owners of the changed files are preferred as reviewers,
+// now or when a draft becomes ready.
+func (s *PRService) Create(ctx context.Context, id,name,authorID string, draft bool, changedFiles []string)(*pullrequest.PullRequest, error){
 	const op = "internal.services.prservice.Create"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	if _, err := s.prRepo.GetWithReviewers(ctx,id); err == nil{
 		return nil, serviceerrors.ErrPRExists
 	} else if !errors.Is(err, repo_errors.ErrPRNotFound){
 		return nil, fmt.Errorf("%s: %w", op , err)
 	}
 
-	author, err := s.userRepo.GetByID(ctx,authorID); 
+	now := time.Now()
+	pr := pullrequest.PullRequest{
+		ID : id, Name: name, AuthorID: authorID, Status: pullrequest.StatusOpen, CreatedAt: now, MergedAt: nil,
+		ChangedFiles: changedFiles,
+	}
+	if draft{
+		if _, err := s.userRepo.GetByID(ctx, authorID); err != nil{
+			if errors.Is(err, repo_errors.ErrUserNotFound){
+				return nil, serviceerrors.ErrUserNotFound
+			}
+			return nil, err
+		}
+		pr.Status = pullrequest.StatusDraft
+	} else{
+		assignment, err := s.assignReviewers(ctx, s.assigner, authorID, changedFiles)
+		if err != nil{
+			return nil, err
+		}
+		pr.Reviewers = assignment.Reviewers
+		pr.FallbackReviewers = assignment.Fallback
+		pr.Reviews = pullrequest.PendingReviews(assignment.Reviewers)
+	}
+
+	if err := s.prRepo.CreateWithReviewers(ctx, pr, assignedEvents(ctx, &pr, assigner.Assignment{Reviewers: pr.Reviewers, Fallback: pr.FallbackReviewers})...); err != nil{
+		return nil, err
+	}
+	metrics.PRsCreated.Inc()
+
+	return &pr, nil
+}
+
+// assignedEvents returns the events for reviewers automatically assigned to the PR.
+func assignedEvents(ctx context.Context, pr *pullrequest.PullRequest, assignment assigner.Assignment) []event.Event{
+	if len(assignment.Reviewers) == 0{
+		return nil
+	}
+	return []event.Event{event.New(ctx, event.TypeReviewersAssigned, event.ReviewersAssigned{
+		PullRequestID: pr.ID, PullRequestName: pr.Name, AuthorID: pr.AuthorID,
+		Reviewers: assignment.Reviewers, Fallback: assignment.Fallback, Reason: event.ReasonAutoCreate,
+	})}
+}
+
+// assignReviewers picks reviewers for a PR of the author from the author's team,
+// owners of the changed files first.
+func (s *PRService) assignReviewers(ctx context.Context, a *assigner.Assigner, authorID string, changedFiles []string)(assigner.Assignment, error){
+	const op = "internal.services.prservice.assignReviewers"
+
+	author, err := s.userRepo.GetByID(ctx,authorID);
 	if err != nil{
 		if errors.Is(err, repo_errors.ErrUserNotFound){
-			return nil, serviceerrors.ErrUserNotFound
+			return assigner.Assignment{}, serviceerrors.ErrUserNotFound
 		}
-		return nil, err
+		return assigner.Assignment{}, err
 	}
 	excluded := []string{authorID}
-	candidates, err := s.userRepo.FindActiveByTeamExcept(ctx, author.TeamName, excluded)
+	assignment, err := a.PickReviewers(ctx, author.TeamName, excluded, changedFiles)
 	if err != nil{
-		return nil, fmt.Errorf("%s: %w", op, err)
+		if errors.Is(err, serviceerrors.ErrNoCandidates){
+			metrics.NoCandidate.WithLabelValues("assign").Inc()
+			return assigner.Assignment{}, err
+		}
+		return assigner.Assignment{}, fmt.Errorf("%s: %w", op, err)
 	}
-	reviewers := pickRandomReviewers(candidates,2)
-	now := time.Now()
-	pr := pullrequest.PullRequest{
-		ID : id, Name: name, AuthorID: authorID, Status: pullrequest.StatusOpen, Reviewers: reviewers, CreatedAt: now, MergedAt: nil,
+	return assignment, nil
+}
+
+// Merge merges an open PR once it has the approvals its team requires.
+func (s *PRService) Merge(ctx context.Context, id string)(*pullrequest.PullRequest, error){
+	const op = "internal.services.prservice.Merge"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	return s.merge(ctx, op, id, true)
+}
+
+// MarkMerged records a merge that already happened on the code hosting provider,
+// so the approval gate does not apply.
+func (s *PRService) MarkMerged(ctx context.Context, id string)(*pullrequest.PullRequest, error){
+	const op = "internal.services.prservice.MarkMerged"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	return s.merge(ctx, op, id, false)
+}
+
+func (s *PRService) merge(ctx context.Context, op, id string, checkApprovals bool)(*pullrequest.PullRequest, error){
+	pr, err := s.prRepo.GetWithReviewers(ctx, id)
+	if err != nil{
+		return nil, err
 	}
 
-	if err := s.prRepo.CreateWithReviewers(ctx, pr); err != nil{
+	now := time.Now().UTC()
+	wasOpen := pr.Status == pullrequest.StatusOpen
+	if err := pr.Merge(now); err != nil{
 		return nil, err
 	}
 
-	return &pr, nil
+	if wasOpen && checkApprovals{
+		author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+		if err != nil{
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		settings, err := s.assigner.Settings(ctx, author.TeamName)
+		if err != nil{
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if pr.Approvals() < settings.RequiredApprovals{
+			return nil, serviceerrors.ErrNotEnoughApprovals
+		}
+	}
+
+	merged := event.New(ctx, event.TypePRMerged, event.PRMerged{
+		PullRequestID: pr.ID, AuthorID: pr.AuthorID, Reviewers: pr.Reviewers, MergedAt: now,
+	})
+	mergedPR, transitioned, err := s.prRepo.Merge(ctx, id, now, merged)
+	if err != nil{
+		return nil, mapTransitionErr(err)
+	}
+	if transitioned{
+		metrics.PRsMerged.Inc()
+	}
+	return mergedPR, nil
 }
 
-func (s *PRService) Merge(ctx context.Context, id string)(*pullrequest.PullRequest, error){
-	now := time.Now().UTC()
-	return s.prRepo.Merge(ctx,id,now)
+func (s *PRService) MarkReady(ctx context.Context, id string)(*pullrequest.PullRequest, error){
+	const op = "internal.services.prservice.MarkReady"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	pr, err := s.prRepo.GetWithReviewers(ctx, id)
+	if err != nil{
+		return nil, err
+	}
+	if err := pr.MarkReady(); err != nil{
+		return nil, err
+	}
+
+	assignment, err := s.assignReviewers(ctx, s.assigner, pr.AuthorID, pr.ChangedFiles)
+	if err != nil{
+		return nil, err
+	}
+	if err := s.prRepo.MarkReady(ctx, id, assignment.Reviewers, assignedEvents(ctx, pr, assignment)...); err != nil{
+		return nil, mapTransitionErr(err)
+	}
+
+	return s.reload(ctx, id, assignment.Fallback)
+}
+
+func (s *PRService) Close(ctx context.Context, id string)(*pullrequest.PullRequest, error){
+	const op = "internal.services.prservice.Close"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	pr, err := s.prRepo.GetWithReviewers(ctx, id)
+	if err != nil{
+		return nil, err
+	}
+	if pr.Status == pullrequest.StatusClosed{
+		return pr, nil
+	}
+	released := pr.Reviewers
+	if err := pr.Close(time.Now().UTC()); err != nil{
+		return nil, err
+	}
+
+	removed := make([]event.Event, 0, len(released))
+	for _, uid := range released{
+		removed = append(removed, event.New(ctx, event.TypeReviewerRemoved, event.ReviewerRemoved{
+			PullRequestID: id, ReviewerID: uid, Reason: event.ReasonPRClosed,
+		}))
+	}
+	if err := s.prRepo.Close(ctx, id, *pr.ClosedAt, removed...); err != nil{
+		return nil, mapTransitionErr(err)
+	}
+
+	return s.prRepo.GetWithReviewers(ctx, id)
+}
+
+func (s *PRService) Reopen(ctx context.Context, id string)(*pullrequest.PullRequest, error){
+	const op = "internal.services.prservice.Reopen"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	pr, err := s.prRepo.GetWithReviewers(ctx, id)
+	if err != nil{
+		return nil, err
+	}
+	if err := pr.Reopen(); err != nil{
+		return nil, err
+	}
+
+	assignment, err := s.assignReviewers(ctx, s.assigner, pr.AuthorID, pr.ChangedFiles)
+	if err != nil{
+		return nil, err
+	}
+	if err := s.prRepo.Reopen(ctx, id, assignment.Reviewers, assignedEvents(ctx, pr, assignment)...); err != nil{
+		return nil, mapTransitionErr(err)
+	}
+
+	return s.reload(ctx, id, assignment.Fallback)
+}
+
+func (s *PRService) reload(ctx context.Context, id string, fallback []string)(*pullrequest.PullRequest, error){
+	pr, err := s.prRepo.GetWithReviewers(ctx, id)
+	if err != nil{
+		return nil, err
+	}
+	pr.FallbackReviewers = fallback
+	return pr, nil
+}
+
+func mapTransitionErr(err error) error{
+	if errors.Is(err, repo_errors.ErrPRStatusChanged){
+		return pullrequest.ErrInvalidTransition
+	}
+	return err
+}
+
+func (s *PRService) Review(ctx context.Context, prID, userID string, state pullrequest.ReviewState)(*pullrequest.PullRequest, error){
+	const op = "internal.services.prservice.Review"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if !state.ValidSubmission(){
+		return nil, pullrequest.ErrInvalidReviewState
+	}
+
+	if err := s.prRepo.SetReviewState(ctx, prID, userID, state, time.Now().UTC()); err != nil{
+		switch{
+		case errors.Is(err, repo_errors.ErrPRMerged):
+			return nil, serviceerrors.ErrPRMerged
+		case errors.Is(err, repo_errors.ErrReviewersNotFound):
+			return nil, serviceerrors.ErrReviewerNotFound
+		}
+		return nil, err
+	}
+
+	return s.prRepo.GetWithReviewers(ctx, prID)
 }
 
 func (s *PRService) Reassign(ctx context.Context, prID, oldReviewerID string)(*pullrequest.PullRequest, string, error){
+	const op = "internal.services.prservice.Reassign"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	pr, err := s.prRepo.GetWithReviewers(ctx, prID)
 	if err != nil{
 		return nil, "", err
@@ -91,49 +338,46 @@ func (s *PRService) Reassign(ctx context.Context, prID, oldReviewerID string)(*p
 	exclude = append(exclude, pr.AuthorID)
 	exclude = append(exclude, pr.Reviewers...)
 
-	candidates , err := s.userRepo.FindActiveByTeamExcept(ctx,oldUser.TeamName,exclude)
+	replacement, err := s.assigner.PickReplacement(ctx, oldUser.TeamName, exclude, pr.ChangedFiles)
 	if err != nil{
 		return nil, "", err
 	}
-	if len(candidates) == 0{
+	if len(replacement.Reviewers) == 0{
+		metrics.NoCandidate.WithLabelValues("reassign").Inc()
+		if replacement.AtCapacity{
+			return nil, "", serviceerrors.ErrCandidatesAtCapacity
+		}
 		return nil, "", serviceerrors.ErrNoCandidates
 	}
-	newUserID := candidates[rand.Intn(len(candidates))].ID
-	if err := s.prRepo.ReplaceReviewers(ctx,prID,oldReviewerID,newUserID); err != nil{
+	newUserID, fromFallback := replacement.Reviewers[0], len(replacement.Fallback) > 0
+	reassigned := event.New(ctx, event.TypeReviewerReassigned, event.ReviewerReassigned{
+		PullRequestID: prID, OldReviewerID: oldReviewerID, NewReviewerID: newUserID,
+		Reason: event.ReasonManualReassign, FromFallback: fromFallback,
+	})
+	if err := s.prRepo.ReplaceReviewers(ctx,prID,oldReviewerID,newUserID, reassigned); err != nil{
 		return nil, "", err
 	}
+	metrics.Reassignments.WithLabelValues(event.ReasonManualReassign).Inc()
 
 	updatedPR , err := s.prRepo.GetWithReviewers(ctx,prID)
 	if err != nil{
 		return nil, "", err
 	}
+	if fromFallback{
+		updatedPR.FallbackReviewers = []string{newUserID}
+	}
 	return updatedPR, newUserID, nil
 }
 
-func pickRandomReviewers(available []*user.User,limit int)[]string{
-	if len(available) == 0{
-		return nil
-	}
+// History returns the reviewer assignment changes of the PR, oldest first.
+func (s *PRService) History(ctx context.Context, prID string)([]assignment.Change, error){
+	const op = "internal.services.prservice.History"
 
-	if len(available) <= limit{
-		out := make([]string,0,len(available))
-		for _, u := range available{
-			out = append(out, u.ID)
-		}
-		return out
-	}
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
-	tmp := make([]*user.User,len(available))
-	copy(tmp,available)
-
-	for i := len(tmp)-1; i>0; i--{
-		j := rand.Intn(i+1)
-		tmp[i],tmp[j] = tmp[j],tmp[i]
-	}
-
-	out := make([]string,0,limit)
-	for i := 0; i< limit; i++{
-		out = append(out, tmp[i].ID)
+	if _, err := s.prRepo.GetWithReviewers(ctx, prID); err != nil{
+		return nil, err
 	}
-	return out
-}
\ No newline at end of file
+	return s.prRepo.HistoryByPR(ctx, prID)
+}
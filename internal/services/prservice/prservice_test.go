@@ -1,84 +1,404 @@
 package prservice
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
 	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/lib/actor"
+	"github.com/hihikaAAa/PRManager/internal/lib/metrics"
+	"github.com/hihikaAAa/PRManager/internal/repository"
+	"github.com/hihikaAAa/PRManager/internal/repository/memory"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/services/assigner"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
 )
 
-func TestPickRandomReviewers_Empty(t *testing.T) {
-	t.Parallel()
+// newTestService returns a service over in-memory storage with team "backend" (u1-u4).
+func newTestService(t *testing.T) (*PRService, repository.Repositories) {
+	t.Helper()
+	ctx := context.Background()
+
+	repos := memory.NewStorage().Repositories()
+	if err := repos.Teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	err := repos.Users.UpsertManyForTeam(ctx, "backend", []*user.User{
+		{ID: "u1", IsActive: true}, {ID: "u2", IsActive: true}, {ID: "u3", IsActive: true}, {ID: "u4", IsActive: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	got := pickRandomReviewers(nil, 2)
-	if got != nil {
-		t.Fatalf("expected nil slice, got %#v", got)
+	a, err := assigner.New(repos.Users, repos.Teams, repos.PRs, assigner.StrategyRandom)
+	if err != nil {
+		t.Fatal(err)
 	}
+	return New(repos.PRs, repos.Users, a), repos
 }
 
-func TestPickRandomReviewers_LessOrEqualThanLimit(t *testing.T) {
-	t.Parallel()
+func TestCreate_AssignsTeamReviewersAndRecordsHistory(t *testing.T) {
+	svc, repos := newTestService(t)
+	ctx := actor.WithActor(context.Background(), "u1")
+
+	pr, err := svc.Create(ctx, "pr-1", "Add search", "u1", false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pr.Reviewers) != team.DefaultReviewersRequired {
+		t.Fatalf("expected %d reviewers, got %v", team.DefaultReviewersRequired, pr.Reviewers)
+	}
+	for _, id := range pr.Reviewers {
+		if id == "u1" {
+			t.Fatal("author was assigned as a reviewer")
+		}
+	}
+
+	hist, err := svc.History(ctx, "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hist) != 2 || hist[0].Actor != "u1" || hist[0].Reason != "auto_create" {
+		t.Fatalf("unexpected history: %+v", hist)
+	}
 
-	available := []*user.User{
-		{ID: "u1"},
-		{ID: "u2"},
+	events, err := repos.Outbox.ClaimPending(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 outbox event, got %d", len(events))
 	}
 
-	got := pickRandomReviewers(available, 3)
-	if len(got) != 2 {
-		t.Fatalf("expected 2 reviewers, got %d", len(got))
+	if _, err := svc.Create(ctx, "pr-1", "Add search", "u1", false, nil); !errors.Is(err, serviceerrors.ErrPRExists) {
+		t.Fatalf("expected ErrPRExists, got %v", err)
 	}
-	if got[0] != "u1" || got[1] != "u2" {
-		t.Fatalf("unexpected reviewers slice: %#v", got)
+	if _, err := svc.Create(ctx, "pr-2", "Add search", "nobody", false, nil); !errors.Is(err, serviceerrors.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
 	}
 }
 
-func TestPickRandomReviewers_MoreThanLimit(t *testing.T) {
-	t.Parallel()
+func TestReassign(t *testing.T) {
+	svc, repos := newTestService(t)
+	ctx := context.Background()
+
+	pr, err := svc.Create(ctx, "pr-1", "Add search", "u1", false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := pr.Reviewers[0]
 
-	available := []*user.User{
-		{ID: "u1"},
-		{ID: "u2"},
-		{ID: "u3"},
-		{ID: "u4"},
+	updated, newID, err := svc.Reassign(ctx, "pr-1", old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newID == old || newID == "u1" {
+		t.Fatalf("unexpected replacement %q for %q", newID, old)
+	}
+	for _, id := range updated.Reviewers {
+		if id == old {
+			t.Fatalf("old reviewer is still assigned: %v", updated.Reviewers)
+		}
 	}
 
-	limit := 2
-	got := pickRandomReviewers(available, limit)
+	if _, _, err := svc.Reassign(ctx, "pr-1", "u1"); !errors.Is(err, serviceerrors.ErrReviewerNotFound) {
+		t.Fatalf("expected ErrReviewerNotFound, got %v", err)
+	}
 
-	if len(got) != limit {
-		t.Fatalf("expected %d reviewers, got %d", limit, len(got))
+	// The only spare member is the replaced reviewer; once inactive, nobody is left.
+	if _, err := repos.Users.SetIsActive(ctx, old, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := svc.Reassign(ctx, "pr-1", newID); !errors.Is(err, serviceerrors.ErrNoCandidates) {
+		t.Fatalf("expected ErrNoCandidates, got %v", err)
 	}
 
-	seen := map[string]bool{}
-	for _, id := range got {
-		seen[id] = true
-		found := false
-		for _, u := range available {
-			if u.ID == id {
-				found = true
-				break
-			}
+	if _, err := svc.Merge(ctx, "pr-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := svc.Reassign(ctx, "pr-1", newID); !errors.Is(err, serviceerrors.ErrPRMerged) {
+		t.Fatalf("expected ErrPRMerged, got %v", err)
+	}
+}
+
+func TestMetrics_DomainCounters(t *testing.T) {
+	svc, repos := newTestService(t)
+	ctx := context.Background()
+
+	created, merged := testutil.ToFloat64(metrics.PRsCreated), testutil.ToFloat64(metrics.PRsMerged)
+	reassigned := testutil.ToFloat64(metrics.Reassignments.WithLabelValues("manual_reassign"))
+	noCandidate := testutil.ToFloat64(metrics.NoCandidate.WithLabelValues("reassign"))
+
+	pr, err := svc.Create(ctx, "pr-1", "Add search", "u1", false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, newID, err := svc.Reassign(ctx, "pr-1", pr.Reviewers[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repos.Users.SetIsActive(ctx, pr.Reviewers[0], false); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := svc.Reassign(ctx, "pr-1", newID); !errors.Is(err, serviceerrors.ErrNoCandidates) {
+		t.Fatalf("expected ErrNoCandidates, got %v", err)
+	}
+	// The second merge is a no-op and is not counted.
+	for i := 0; i < 2; i++ {
+		if _, err := svc.Merge(ctx, "pr-1"); err != nil {
+			t.Fatal(err)
 		}
-		if !found {
-			t.Fatalf("id %q not from available list", id)
+	}
+
+	if got := testutil.ToFloat64(metrics.PRsCreated) - created; got != 1 {
+		t.Fatalf("expected 1 created PR, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.PRsMerged) - merged; got != 1 {
+		t.Fatalf("expected 1 merged PR, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.Reassignments.WithLabelValues("manual_reassign")) - reassigned; got != 1 {
+		t.Fatalf("expected 1 reassignment, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.NoCandidate.WithLabelValues("reassign")) - noCandidate; got != 1 {
+		t.Fatalf("expected 1 NO_CANDIDATE failure, got %v", got)
+	}
+}
+
+// closingPRs closes the PR right before merging it, as a concurrent /pullRequest/close would.
+type closingPRs struct {
+	PRRepository
+}
+
+func (r closingPRs) Merge(ctx context.Context, id string, now time.Time, events ...event.Event) (*pullrequest.PullRequest, bool, error) {
+	if err := r.PRRepository.Close(ctx, id, now); err != nil {
+		return nil, false, err
+	}
+	return r.PRRepository.Merge(ctx, id, now, events...)
+}
+
+func TestMerge_ClosedConcurrently(t *testing.T) {
+	svc, _ := newTestService(t)
+	ctx := context.Background()
+
+	if _, err := svc.Create(ctx, "pr-1", "Add search", "u1", false, nil); err != nil {
+		t.Fatal(err)
+	}
+	svc.prRepo = closingPRs{svc.prRepo}
+	merged := testutil.ToFloat64(metrics.PRsMerged)
+
+	if _, err := svc.Merge(ctx, "pr-1"); !errors.Is(err, pullrequest.ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition, got %v", err)
+	}
+	if got := testutil.ToFloat64(metrics.PRsMerged) - merged; got != 0 {
+		t.Fatalf("a merge that did not happen was counted: %v", got)
+	}
+}
+
+func TestMerge_RequiresApprovals(t *testing.T) {
+	svc, repos := newTestService(t)
+	ctx := context.Background()
+
+	settings := team.DefaultSettings("backend")
+	settings.RequiredApprovals = 1
+	if err := repos.Teams.UpsertSettings(ctx, settings); err != nil {
+		t.Fatal(err)
+	}
+
+	pr, err := svc.Create(ctx, "pr-1", "Add search", "u1", false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Merge(ctx, "pr-1"); !errors.Is(err, serviceerrors.ErrNotEnoughApprovals) {
+		t.Fatalf("expected ErrNotEnoughApprovals, got %v", err)
+	}
+
+	if _, err := svc.Review(ctx, "pr-1", pr.Reviewers[0], pullrequest.ReviewApproved); err != nil {
+		t.Fatal(err)
+	}
+	merged, err := svc.Merge(ctx, "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.Status != pullrequest.StatusMerged || merged.MergedAt == nil {
+		t.Fatalf("unexpected merged PR: %+v", merged)
+	}
+}
+
+func TestDraftLifecycle(t *testing.T) {
+	svc, _ := newTestService(t)
+	ctx := context.Background()
+
+	pr, err := svc.Create(ctx, "pr-1", "WIP", "u1", true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Status != pullrequest.StatusDraft || len(pr.Reviewers) != 0 {
+		t.Fatalf("unexpected draft: %+v", pr)
+	}
+
+	pr, err = svc.MarkReady(ctx, "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Status != pullrequest.StatusOpen || len(pr.Reviewers) != team.DefaultReviewersRequired {
+		t.Fatalf("unexpected ready PR: %+v", pr)
+	}
+
+	pr, err = svc.Close(ctx, "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Status != pullrequest.StatusClosed || len(pr.Reviewers) != 0 {
+		t.Fatalf("unexpected closed PR: %+v", pr)
+	}
+	hist, err := svc.History(ctx, "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hist) != 4 || hist[3].Reason != "pr_closed" {
+		t.Fatalf("unexpected history: %+v", hist)
+	}
+
+	if _, err := svc.MarkReady(ctx, "pr-1"); !errors.Is(err, pullrequest.ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition, got %v", err)
+	}
+	if _, err := svc.History(ctx, "missing"); !errors.Is(err, repo_errors.ErrPRNotFound) {
+		t.Fatalf("expected ErrPRNotFound, got %v", err)
+	}
+}
+
+func TestCreate_AuthorWithoutTeam(t *testing.T) {
+	svc, repos := newTestService(t)
+	ctx := context.Background()
+
+	if _, err := repos.Users.SetTeam(ctx, "u1", ""); err != nil {
+		t.Fatal(err)
+	}
+	// Nobody reviews for a user without a team, and the defaults allow that.
+	pr, err := svc.Create(ctx, "pr-1", "Add search", "u1", false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Status != pullrequest.StatusOpen || len(pr.Reviewers) != 0 {
+		t.Fatalf("unexpected PR: %+v", pr)
+	}
+	if _, err := svc.Merge(ctx, "pr-1"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCapacity_SkipsUsersAtCapacity(t *testing.T) {
+	svc, repos := newTestService(t)
+	ctx := context.Background()
+
+	err := repos.Teams.UpsertSettings(ctx, team.Settings{TeamName: "backend", ReviewersRequired: 2, MinReviewers: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"u2", "u3", "u4"} {
+		if _, err := repos.Users.SetMaxOpenReviews(ctx, id, 1); err != nil {
+			t.Fatal(err)
 		}
 	}
 
-	if len(seen) != len(got) {
-		t.Fatalf("expected all reviewers unique, got %v", got)
+	pr1, err := svc.Create(ctx, "pr-1", "First", "u1", false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pr1.Reviewers) != 2 {
+		t.Fatalf("expected 2 reviewers, got %v", pr1.Reviewers)
+	}
+	// Only one of u2-u4 is below capacity now.
+	pr2, err := svc.Create(ctx, "pr-2", "Second", "u1", false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pr2.Reviewers) != 1 {
+		t.Fatalf("expected 1 reviewer, got %v", pr2.Reviewers)
+	}
+	if _, err := svc.Create(ctx, "pr-3", "Third", "u1", false, nil); !errors.Is(err, serviceerrors.ErrCandidatesAtCapacity) {
+		t.Fatalf("expected ErrCandidatesAtCapacity, got %v", err)
+	}
+	if _, _, err := svc.Reassign(ctx, "pr-2", pr2.Reviewers[0]); !errors.Is(err, serviceerrors.ErrCandidatesAtCapacity) || !errors.Is(err, serviceerrors.ErrNoCandidates) {
+		t.Fatalf("expected ErrCandidatesAtCapacity, got %v", err)
+	}
+
+	// Lifting the limit of a pr-1 reviewer makes them a candidate again.
+	if _, err := repos.Users.SetMaxOpenReviews(ctx, pr1.Reviewers[0], 0); err != nil {
+		t.Fatal(err)
+	}
+	_, newID, err := svc.Reassign(ctx, "pr-2", pr2.Reviewers[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newID != pr1.Reviewers[0] {
+		t.Fatalf("expected %s, got %s", pr1.Reviewers[0], newID)
 	}
 }
 
-func TestPickRandomReviewers_BigLimit(t *testing.T) {
-	t.Parallel()
+func TestCodeOwners_PreferOwnersOfChangedFiles(t *testing.T) {
+	svc, repos := newTestService(t)
+	ctx := context.Background()
 
-	available := []*user.User{
-		{ID: "u1"},
-		{ID: "u2"},
+	err := repos.Teams.SetCodeOwners(ctx, team.CodeOwners{TeamName: "backend", Rules: []team.OwnershipRule{
+		{Pattern: "*", Owners: []string{"u2"}},
+		{Pattern: "/migrations/", Owners: []string{"u4"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	got := pickRandomReviewers(available, 100)
-	if len(got) != len(available) {
-		t.Fatalf("expected %d reviewers, got %d", len(available), len(got))
+	// u4 owns the only changed file, the other slot is filled by the strategy.
+	for i := 0; i < 10; i++ {
+		id := "pr-" + string(rune('a'+i))
+		pr, err := svc.Create(ctx, id, "Schema", "u1", false, []string{"migrations/001_init.up.sql"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pr.Reviewers) != 2 || pr.Reviewers[0] != "u4" {
+			t.Fatalf("expected owner u4 first, got %v", pr.Reviewers)
+		}
+		if len(pr.ChangedFiles) != 1 {
+			t.Fatalf("changed files were not stored: %v", pr.ChangedFiles)
+		}
+	}
+
+	// Both owners are picked when the PR touches both rules.
+	pr, err := svc.Create(ctx, "pr-z", "Mixed", "u1", false, []string{"README.md", "migrations/002.sql"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertReviewers(t, pr.Reviewers, "u2", "u4")
+
+	// With every owner already assigned, the replacement comes from the rest of the team.
+	_, newID, err := svc.Reassign(ctx, "pr-z", "u4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newID != "u3" {
+		t.Fatalf("expected u3 as the only remaining candidate, got %s", newID)
+	}
+}
+
+func assertReviewers(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	set := make(map[string]bool, len(got))
+	for _, id := range got {
+		set[id] = true
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected reviewers %v, got %v", want, got)
+	}
+	for _, id := range want {
+		if !set[id] {
+			t.Fatalf("expected reviewers %v, got %v", want, got)
+		}
 	}
 }
@@ -1,13 +1,30 @@
 package serviceerrors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrPRExists = errors.New("pr already exists")
 	ErrPRMerged = errors.New("pr already merged")
 	ErrReviewerNotFound = errors.New("reviewer not found")
 	ErrNoCandidates = errors.New("no candidates")
+	// ErrCandidatesAtCapacity is an ErrNoCandidates caused by the review capacity of the candidates.
+	ErrCandidatesAtCapacity = fmt.Errorf("%w: every candidate is at review capacity", ErrNoCandidates)
 	ErrTeamExists = errors.New("team already exists")
 	ErrUserNotFound = errors.New("user not found")
 	ErrTeamNotFound = errors.New("team not found")
+	ErrUnknownStrategy = errors.New("unknown reviewer strategy")
+	ErrFallbackTeamNotFound = errors.New("fallback team not found")
+	ErrNotEnoughApprovals = errors.New("not enough approvals")
+	ErrUnknownProvider = errors.New("unknown provider")
+	ErrIdentityNotLinked = errors.New("provider login is not linked to a user")
+	ErrInvalidReviewers = errors.New("invalid reviewers")
+	ErrTargetTeamNotFound = errors.New("target team not found")
+	ErrSameTeam = errors.New("target team is the team itself")
+	ErrTeamNotEmpty = errors.New("team has members")
+	ErrParentTeamNotFound = errors.New("parent team not found")
+	ErrAbsenceNotFound = errors.New("absence not found")
+	ErrOwnerNotInTeam = errors.New("code owner is not a team member")
 )
\ No newline at end of file
@@ -2,32 +2,85 @@ package statsservice
 
 import(
 	"context"
+	"time"
 
-	"github.com/hihikaAAa/PRManager/internal/repository/postgres"
+	"github.com/hihikaAAa/PRManager/internal/repository"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
 )
 
+type StatsRepository interface{
+	GetStats(ctx context.Context, f repository.StatsFilter)(repository.Stats, error)
+}
+
+type TeamChecker interface{
+	Exists(ctx context.Context, name string)(bool, error)
+}
+
 type StatsService struct{
-	prRepo *postgres.PRRepository
+	prRepo StatsRepository
+	teamRepo TeamChecker
+}
+
+func New(prRepo StatsRepository, teamRepo TeamChecker) *StatsService{
+	return &StatsService{prRepo: prRepo, teamRepo: teamRepo}
 }
 
-func New(prRepo *postgres.PRRepository) *StatsService{
-	return &StatsService{prRepo: prRepo}
+// Filter selects the PRs created in [From, To) by a member of TeamName;
+// reassignments are selected by when they happened. Zero values do not restrict.
+type Filter struct {
+	From time.Time
+	To time.Time
+	TeamName string
 }
 
 type Stats struct {
     TotalPR int `json:"total_pr"`
     OpenPR int `json:"open_pr"`
     MergedPR int `json:"merged_pr"`
+    DraftPR int `json:"draft_pr"`
+    ClosedPR int `json:"closed_pr"`
+    MedianTimeToMergeSeconds *int64 `json:"median_time_to_merge_seconds"`
+    Reassignments int `json:"reassignments"`
+    Teams []TeamStat `json:"teams"`
     Reviewers []ReviewerStat `json:"reviewers"`
 }
 
+type TeamStat struct {
+    TeamName string `json:"team_name"`
+    TotalPR int `json:"total_pr"`
+    OpenPR int `json:"open_pr"`
+    MergedPR int `json:"merged_pr"`
+    DraftPR int `json:"draft_pr"`
+    ClosedPR int `json:"closed_pr"`
+    MedianTimeToMergeSeconds *int64 `json:"median_time_to_merge_seconds"`
+    Reassignments int `json:"reassignments"`
+}
+
 type ReviewerStat struct {
     UserID string `json:"user_id"`
     Count int `json:"count"`
+    Open int `json:"open"`
+    Completed int `json:"completed"`
 }
 
-func (s *StatsService) GetStats(ctx context.Context)(Stats, error){
-	raw, reviewers, err := s.prRepo.GetStats(ctx)
+func (s *StatsService) GetStats(ctx context.Context, f Filter)(Stats, error){
+	const op = "internal.services.statsservice.GetStats"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if f.TeamName != ""{
+		exists, err := s.teamRepo.Exists(ctx, f.TeamName)
+		if err != nil{
+			return Stats{}, err
+		}
+		if !exists{
+			return Stats{}, serviceerrors.ErrTeamNotFound
+		}
+	}
+
+	raw, err := s.prRepo.GetStats(ctx, repository.StatsFilter{From: f.From, To: f.To, TeamName: f.TeamName})
 	if err != nil{
 		return Stats{}, err
 	}
@@ -36,14 +89,44 @@ func (s *StatsService) GetStats(ctx context.Context)(Stats, error){
 		TotalPR: raw.TotalPR,
 		OpenPR: raw.OpenPR,
 		MergedPR: raw.MergedPR,
+		DraftPR: raw.DraftPR,
+		ClosedPR: raw.ClosedPR,
+		MedianTimeToMergeSeconds: medianSeconds(raw.PRStats),
+		Reassignments: raw.Reassignments,
+		Teams: []TeamStat{},
+		Reviewers: []ReviewerStat{},
 	}
 
-	for _, r := range reviewers{
+	for _, t := range raw.Teams{
+		out.Teams = append(out.Teams, TeamStat{
+			TeamName: t.TeamName,
+			TotalPR: t.TotalPR,
+			OpenPR: t.OpenPR,
+			MergedPR: t.MergedPR,
+			DraftPR: t.DraftPR,
+			ClosedPR: t.ClosedPR,
+			MedianTimeToMergeSeconds: medianSeconds(t.PRStats),
+			Reassignments: t.Reassignments,
+		})
+	}
+
+	for _, r := range raw.Reviewers{
 		out.Reviewers = append(out.Reviewers, ReviewerStat{
 			UserID: r.UserID,
 			Count: r.Count,
+			Open: r.Open,
+			Completed: r.Completed,
 		})
 	}
 
 	return out,nil
-}
\ No newline at end of file
+}
+
+// medianSeconds is nil when nothing was merged, so that no data is not reported as zero.
+func medianSeconds(s repository.PRStats) *int64{
+	if s.MergedPR == 0{
+		return nil
+	}
+	sec := int64(s.MedianTimeToMerge / time.Second)
+	return &sec
+}
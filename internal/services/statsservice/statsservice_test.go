@@ -0,0 +1,58 @@
+package statsservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/repository/memory"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+func TestGetStats(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewStorage().Repositories()
+	if err := repos.Teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Users.UpsertManyForTeam(ctx, "backend", []*user.User{{ID: "u1", IsActive: true}, {ID: "u2", IsActive: true}}); err != nil {
+		t.Fatal(err)
+	}
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, id := range []string{"pr-1", "pr-2"} {
+		pr := pullrequest.PullRequest{ID: id, AuthorID: "u1", Status: pullrequest.StatusOpen, CreatedAt: created, Reviewers: []string{"u2"}}
+		if err := repos.PRs.CreateWithReviewers(ctx, pr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc := New(repos.PRs, repos.Teams)
+
+	st, err := svc.GetStats(ctx, Filter{TeamName: "backend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.MedianTimeToMergeSeconds != nil || len(st.Teams) != 1 || st.Teams[0].MedianTimeToMergeSeconds != nil {
+		t.Fatalf("median must be null without merged PRs: %+v", st)
+	}
+
+	if _, _, err := repos.PRs.Merge(ctx, "pr-1", created.Add(90*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	st, err = svc.GetStats(ctx, Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.MedianTimeToMergeSeconds == nil || *st.MedianTimeToMergeSeconds != 5400 {
+		t.Fatalf("expected median of 5400s, got %+v", st)
+	}
+	if len(st.Reviewers) != 1 || st.Reviewers[0] != (ReviewerStat{UserID: "u2", Count: 2, Open: 1, Completed: 1}) {
+		t.Fatalf("unexpected reviewers: %+v", st.Reviewers)
+	}
+
+	if _, err := svc.GetStats(ctx, Filter{TeamName: "ghost"}); !errors.Is(err, serviceerrors.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+}
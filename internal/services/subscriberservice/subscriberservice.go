@@ -0,0 +1,52 @@
+package subscriberservice
+
+import (
+	"context"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/subscriber"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+)
+
+type SubscriberRepository interface {
+	Create(ctx context.Context, s subscriber.Subscriber) (subscriber.Subscriber, error)
+	List(ctx context.Context) ([]subscriber.Subscriber, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type SubscriberService struct {
+	repo SubscriberRepository
+}
+
+func New(repo SubscriberRepository) *SubscriberService {
+	return &SubscriberService{repo: repo}
+}
+
+func (s *SubscriberService) Add(ctx context.Context, sub subscriber.Subscriber) (subscriber.Subscriber, error) {
+	const op = "internal.services.subscriberservice.Add"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := sub.Validate(); err != nil {
+		return subscriber.Subscriber{}, err
+	}
+	return s.repo.Create(ctx, sub)
+}
+
+func (s *SubscriberService) List(ctx context.Context) ([]subscriber.Subscriber, error) {
+	const op = "internal.services.subscriberservice.List"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	return s.repo.List(ctx)
+}
+
+func (s *SubscriberService) Delete(ctx context.Context, id int64) error {
+	const op = "internal.services.subscriberservice.Delete"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	return s.repo.Delete(ctx, id)
+}
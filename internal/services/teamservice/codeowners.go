@@ -0,0 +1,65 @@
+package teamservice
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+// GetCodeOwners returns the team's ownership rules in order.
+func (ts *TeamService) GetCodeOwners(ctx context.Context, teamName string) (team.CodeOwners, error) {
+	const op = "internal.services.teamservice.GetCodeOwners"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	c, err := ts.teamRepo.GetCodeOwners(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, repo_errors.ErrTeamNotFound) {
+			return team.CodeOwners{}, serviceerrors.ErrTeamNotFound
+		}
+		return team.CodeOwners{}, err
+	}
+	return c, nil
+}
+
+// SetCodeOwners replaces the team's ownership rules. Every owner must be a member
+// of the team; owners who leave it later are simply no longer picked.
+func (ts *TeamService) SetCodeOwners(ctx context.Context, c team.CodeOwners) (team.CodeOwners, error) {
+	const op = "internal.services.teamservice.SetCodeOwners"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := c.Validate(); err != nil {
+		return team.CodeOwners{}, err
+	}
+
+	tm, err := ts.teamRepo.GetWithMembers(ctx, c.TeamName)
+	if err != nil {
+		if errors.Is(err, repo_errors.ErrTeamNotFound) {
+			return team.CodeOwners{}, serviceerrors.ErrTeamNotFound
+		}
+		return team.CodeOwners{}, err
+	}
+	members := make(map[string]struct{}, len(tm.Members))
+	for _, m := range tm.Members {
+		members[m.ID] = struct{}{}
+	}
+	for _, r := range c.Rules {
+		for _, o := range r.Owners {
+			if _, ok := members[o]; !ok {
+				return team.CodeOwners{}, serviceerrors.ErrOwnerNotInTeam
+			}
+		}
+	}
+
+	if err := ts.teamRepo.SetCodeOwners(ctx, c); err != nil {
+		return team.CodeOwners{}, err
+	}
+	return ts.teamRepo.GetCodeOwners(ctx, c.TeamName)
+}
@@ -0,0 +1,69 @@
+package teamservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+func TestSetCodeOwners(t *testing.T) {
+	svc, _ := newMembershipService(t)
+	ctx := context.Background()
+
+	c, err := svc.SetCodeOwners(ctx, team.CodeOwners{TeamName: "backend", Rules: []team.OwnershipRule{
+		{Pattern: "*", Owners: []string{"u1"}},
+		{Pattern: "/internal/db/", Owners: []string{"u2", "u3"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Rules) != 2 || c.Rules[1].Pattern != "/internal/db/" {
+		t.Fatalf("unexpected rules: %+v", c.Rules)
+	}
+
+	got, err := svc.GetCodeOwners(ctx, "backend")
+	if err != nil || len(got.Rules) != 2 {
+		t.Fatalf("unexpected code owners: %+v, %v", got, err)
+	}
+
+	_, err = svc.SetCodeOwners(ctx, team.CodeOwners{TeamName: "backend", Rules: []team.OwnershipRule{{Pattern: "*", Owners: []string{"f1"}}}})
+	if !errors.Is(err, serviceerrors.ErrOwnerNotInTeam) {
+		t.Fatalf("expected ErrOwnerNotInTeam, got %v", err)
+	}
+	_, err = svc.SetCodeOwners(ctx, team.CodeOwners{TeamName: "backend", Rules: []team.OwnershipRule{{Pattern: "!*.go", Owners: []string{"u1"}}}})
+	if !errors.Is(err, team.ErrInvalidCodeOwners) {
+		t.Fatalf("expected ErrInvalidCodeOwners, got %v", err)
+	}
+	if _, err := svc.SetCodeOwners(ctx, team.CodeOwners{TeamName: "nobody"}); !errors.Is(err, serviceerrors.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+	if _, err := svc.GetCodeOwners(ctx, "nobody"); !errors.Is(err, serviceerrors.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+}
+
+func TestReleaseReviews_PrefersCodeOwners(t *testing.T) {
+	svc, repos := newMembershipService(t)
+	ctx := context.Background()
+
+	if _, err := svc.SetCodeOwners(ctx, team.CodeOwners{TeamName: "backend", Rules: []team.OwnershipRule{
+		{Pattern: "*.sql", Owners: []string{"u3"}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	createPRWithFiles(t, repos, "pr-1", "u1", []string{"migrations/001.sql"}, "u2")
+
+	res, err := svc.ReleaseReviews(ctx, "u2", "absence")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ReassignedCount != 1 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if got := reviewersOf(t, repos, "pr-1"); len(got) != 1 || got[0] != "u3" {
+		t.Fatalf("expected the owner u3, got %v", got)
+	}
+}
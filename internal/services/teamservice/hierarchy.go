@@ -0,0 +1,69 @@
+package teamservice
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+// SetParent moves the team under parent, or to the top level when parent is empty.
+// A parent that is the team itself or one of its sub-teams gives team.ErrInvalidParent.
+func (ts *TeamService) SetParent(ctx context.Context, teamName, parent string) (*team.Team, error) {
+	const op = "internal.services.teamservice.SetParent"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := ts.ensureTeam(ctx, teamName); err != nil {
+		return nil, err
+	}
+	if err := ts.teamRepo.SetParent(ctx, teamName, parent); err != nil {
+		switch {
+		case errors.Is(err, repo_errors.ErrTeamNotFound):
+			return nil, serviceerrors.ErrTeamNotFound
+		case errors.Is(err, repo_errors.ErrParentTeamNotFound):
+			return nil, serviceerrors.ErrParentTeamNotFound
+		case errors.Is(err, team.ErrInvalidParent):
+			return nil, team.ErrInvalidParent
+		}
+		return nil, err
+	}
+	return ts.teamRepo.GetWithMembers(ctx, teamName)
+}
+
+// Tree returns the team hierarchy. With a non-empty root only the subtree of that team is returned.
+func (ts *TeamService) Tree(ctx context.Context, root string) ([]*team.Node, error) {
+	const op = "internal.services.teamservice.Tree"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	teams, err := ts.teamRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roots := team.BuildTree(teams)
+	if root == "" {
+		return roots, nil
+	}
+	if n := findNode(roots, root); n != nil {
+		return []*team.Node{n}, nil
+	}
+	return nil, serviceerrors.ErrTeamNotFound
+}
+
+func findNode(nodes []*team.Node, name string) *team.Node {
+	for _, n := range nodes {
+		if n.TeamName == name {
+			return n
+		}
+		if found := findNode(n.Children, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
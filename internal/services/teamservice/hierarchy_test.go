@@ -0,0 +1,136 @@
+package teamservice
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+// newHierarchyService returns a service with "platform" (p1) over "backend" (u1, u2),
+// and "frontend" (f1) at the top level.
+func newHierarchyService(t *testing.T) *TeamService {
+	t.Helper()
+	svc, _ := newTestService(t)
+	ctx := context.Background()
+
+	teams := []struct {
+		name, parent string
+		members      []*user.User
+	}{
+		{"platform", "", []*user.User{{ID: "p1", IsActive: true}}},
+		{"backend", "platform", []*user.User{{ID: "u1", IsActive: true}, {ID: "u2", IsActive: true}}},
+		{"frontend", "", []*user.User{{ID: "f1", IsActive: true}}},
+	}
+	for _, tm := range teams {
+		if err := svc.AddTeam(ctx, tm.name, tm.parent, tm.members); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return svc
+}
+
+func TestAddTeam_UnknownParent(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	err := svc.AddTeam(context.Background(), "backend", "platform", nil)
+	if !errors.Is(err, serviceerrors.ErrParentTeamNotFound) {
+		t.Fatalf("expected ErrParentTeamNotFound, got %v", err)
+	}
+}
+
+func TestSetParent(t *testing.T) {
+	svc := newHierarchyService(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name, team, parent string
+		want               error
+	}{
+		{"itself", "platform", "platform", team.ErrInvalidParent},
+		{"own sub-team", "platform", "backend", team.ErrInvalidParent},
+		{"unknown parent", "backend", "nope", serviceerrors.ErrParentTeamNotFound},
+		{"unknown team", "nope", "platform", serviceerrors.ErrTeamNotFound},
+	}
+	for _, tc := range tests {
+		if _, err := svc.SetParent(ctx, tc.team, tc.parent); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	tm, err := svc.SetParent(ctx, "platform", "frontend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tm.ParentTeam != "frontend" || len(tm.Members) != 1 {
+		t.Fatalf("unexpected team: %+v", tm)
+	}
+	if _, err := svc.SetParent(ctx, "frontend", "backend"); !errors.Is(err, team.ErrInvalidParent) {
+		t.Fatalf("expected ErrInvalidParent for a deeper cycle, got %v", err)
+	}
+}
+
+func TestTree(t *testing.T) {
+	svc := newHierarchyService(t)
+	ctx := context.Background()
+
+	roots, err := svc.Tree(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roots) != 2 || roots[0].TeamName != "frontend" || roots[1].TeamName != "platform" {
+		t.Fatalf("unexpected roots: %+v", roots)
+	}
+	if len(roots[1].Children) != 1 || roots[1].Children[0].TeamName != "backend" {
+		t.Fatalf("unexpected children: %+v", roots[1].Children)
+	}
+
+	sub, err := svc.Tree(ctx, "backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sub) != 1 || sub[0].TeamName != "backend" || len(sub[0].Children) != 0 {
+		t.Fatalf("unexpected subtree: %+v", sub)
+	}
+	if _, err := svc.Tree(ctx, "nope"); !errors.Is(err, serviceerrors.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+}
+
+func TestPickReviewers_SearchParents(t *testing.T) {
+	svc := newHierarchyService(t)
+	ctx := context.Background()
+
+	// backend has no settings of its own and inherits them from platform.
+	settings := team.DefaultSettings("platform")
+	settings.ReviewersRequired, settings.FallbackTeams = 2, []string{"frontend"}
+	if _, err := svc.SetSettings(ctx, settings); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := svc.assigner.PickReviewers(ctx, "backend", []string{"u1"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(res.Reviewers)
+	if len(res.Reviewers) != 2 || res.Reviewers[0] != "f1" || res.Reviewers[1] != "u2" || len(res.Fallback) != 1 {
+		t.Fatalf("expected u2 and fallback f1, got %+v", res)
+	}
+
+	settings.SearchParents = true
+	if _, err := svc.SetSettings(ctx, settings); err != nil {
+		t.Fatal(err)
+	}
+	res, err = svc.assigner.PickReviewers(ctx, "backend", []string{"u1"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(res.Reviewers)
+	if len(res.Reviewers) != 2 || res.Reviewers[0] != "p1" || res.Reviewers[1] != "u2" || len(res.Fallback) != 0 {
+		t.Fatalf("expected u2 and p1 from the parent team, got %+v", res)
+	}
+}
@@ -0,0 +1,122 @@
+package teamservice
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type DeleteResult struct {
+	TeamName    string
+	TargetTeam  string
+	Moved       []string
+	Deactivated []string
+	Reassignment
+}
+
+// RenameTeam renames the team; members, settings and fallback references follow.
+func (ts *TeamService) RenameTeam(ctx context.Context, oldName, newName string) (*team.Team, error) {
+	const op = "internal.services.teamservice.RenameTeam"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if oldName == newName {
+		return nil, serviceerrors.ErrSameTeam
+	}
+	if err := ts.ensureTeam(ctx, oldName); err != nil {
+		return nil, err
+	}
+	exists, err := ts.teamRepo.Exists(ctx, newName)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, serviceerrors.ErrTeamExists
+	}
+
+	if err := ts.teamRepo.Rename(ctx, oldName, newName); err != nil {
+		if errors.Is(err, repo_errors.ErrTeamNotFound) {
+			return nil, serviceerrors.ErrTeamNotFound
+		}
+		return nil, err
+	}
+	return ts.teamRepo.GetWithMembers(ctx, newName)
+}
+
+// DeleteTeam deletes the team. Members move to target and keep their reviews, or,
+// with force and no target, are deactivated first: their open reviews are handed
+// over like in DeactivateAndReassign, then they are left without a team.
+// A team with members cannot be deleted without either. The handovers are stored
+// in the same transaction as the delete.
+func (ts *TeamService) DeleteTeam(ctx context.Context, name, target string, force bool) (DeleteResult, error) {
+	const op = "internal.services.teamservice.DeleteTeam"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	res := DeleteResult{TeamName: name, TargetTeam: target}
+	var (
+		plan      *handoverPlan
+		handovers []assignment.Handover
+	)
+	tm, err := ts.teamRepo.GetWithMembers(ctx, name)
+	if err != nil {
+		if errors.Is(err, repo_errors.ErrTeamNotFound) {
+			return res, serviceerrors.ErrTeamNotFound
+		}
+		return res, err
+	}
+
+	switch {
+	case target != "":
+		if target == name {
+			return res, serviceerrors.ErrSameTeam
+		}
+		exists, err := ts.teamRepo.Exists(ctx, target)
+		if err != nil {
+			return res, err
+		}
+		if !exists {
+			return res, serviceerrors.ErrTargetTeamNotFound
+		}
+	case force:
+		ids := make([]string, 0, len(tm.Members))
+		for _, m := range tm.Members {
+			ids = append(ids, m.ID)
+		}
+		plan = ts.newHandoverPlan(event.ReasonTeamDeactivation)
+		for _, id := range ids {
+			if err := ts.planHandover(ctx, plan, name, id, ids); err != nil {
+				return res, err
+			}
+		}
+		handovers = plan.handovers
+	case len(tm.Members) > 0:
+		return res, serviceerrors.ErrTeamNotEmpty
+	}
+
+	ids, err := ts.teamRepo.Delete(ctx, name, target, handovers...)
+	if err != nil {
+		if errors.Is(err, repo_errors.ErrTeamNotFound) {
+			return res, serviceerrors.ErrTeamNotFound
+		}
+		return res, err
+	}
+	if plan != nil {
+		plan.recordMetrics()
+		res.Reassignment = plan.res
+	}
+	if target != "" {
+		res.Moved = ids
+	} else {
+		res.Deactivated = ids
+	}
+	return res, nil
+}
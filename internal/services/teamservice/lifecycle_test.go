@@ -0,0 +1,160 @@
+package teamservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+func TestRenameTeam(t *testing.T) {
+	svc, repos := newMembershipService(t)
+	ctx := context.Background()
+
+	settings := team.DefaultSettings("frontend")
+	settings.FallbackTeams = []string{"backend"}
+	if _, err := svc.SetSettings(ctx, settings); err != nil {
+		t.Fatal(err)
+	}
+
+	tm, err := svc.RenameTeam(ctx, "backend", "platform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tm.TeamName != "platform" || len(tm.Members) != 4 {
+		t.Fatalf("unexpected team: %+v", tm)
+	}
+	u, err := repos.Users.GetByID(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.TeamName != "platform" {
+		t.Fatalf("member was not renamed: %+v", u)
+	}
+	got, err := svc.GetSettings(ctx, "frontend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.FallbackTeams) != 1 || got.FallbackTeams[0] != "platform" {
+		t.Fatalf("fallback was not renamed: %v", got.FallbackTeams)
+	}
+
+	if _, err := svc.RenameTeam(ctx, "backend", "other"); !errors.Is(err, serviceerrors.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+	if _, err := svc.RenameTeam(ctx, "platform", "frontend"); !errors.Is(err, serviceerrors.ErrTeamExists) {
+		t.Fatalf("expected ErrTeamExists, got %v", err)
+	}
+	if _, err := svc.RenameTeam(ctx, "platform", "platform"); !errors.Is(err, serviceerrors.ErrSameTeam) {
+		t.Fatalf("expected ErrSameTeam, got %v", err)
+	}
+}
+
+func TestDeleteTeam_MovesMembersToTarget(t *testing.T) {
+	svc, repos := newMembershipService(t)
+	ctx := context.Background()
+	createPR(t, repos, "pr-1", "u1", "u2")
+
+	if _, err := svc.DeleteTeam(ctx, "backend", "", false); !errors.Is(err, serviceerrors.ErrTeamNotEmpty) {
+		t.Fatalf("expected ErrTeamNotEmpty, got %v", err)
+	}
+	if _, err := svc.DeleteTeam(ctx, "backend", "nobody", false); !errors.Is(err, serviceerrors.ErrTargetTeamNotFound) {
+		t.Fatalf("expected ErrTargetTeamNotFound, got %v", err)
+	}
+	if _, err := svc.DeleteTeam(ctx, "backend", "backend", false); !errors.Is(err, serviceerrors.ErrSameTeam) {
+		t.Fatalf("expected ErrSameTeam, got %v", err)
+	}
+
+	res, err := svc.DeleteTeam(ctx, "backend", "frontend", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Moved) != 4 || len(res.Deactivated) != 0 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	// Moved members stay active and keep their reviews.
+	if got := reviewersOf(t, repos, "pr-1"); len(got) != 1 || got[0] != "u2" {
+		t.Fatalf("unexpected reviewers: %v", got)
+	}
+	tm, err := svc.GetTeam(ctx, "frontend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tm.Members) != 7 {
+		t.Fatalf("expected 7 members, got %d", len(tm.Members))
+	}
+	if _, err := svc.DeleteTeam(ctx, "backend", "frontend", false); !errors.Is(err, serviceerrors.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+}
+
+func TestDeleteTeam_Force(t *testing.T) {
+	svc, repos := newMembershipService(t)
+	ctx := context.Background()
+	createPR(t, repos, "pr-1", "u1", "u2", "u3")
+
+	settings := team.DefaultSettings("backend")
+	settings.FallbackTeams = []string{"frontend"}
+	if _, err := svc.SetSettings(ctx, settings); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := svc.DeleteTeam(ctx, "backend", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Deactivated) != 4 || res.ReassignedCount != 2 || res.FallbackCount != 2 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	// Both reviews went to the fallback team.
+	for _, id := range reviewersOf(t, repos, "pr-1") {
+		if id[0] != 'f' {
+			t.Fatalf("unexpected reviewer %q", id)
+		}
+	}
+	u, err := repos.Users.GetByID(ctx, "u2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.IsActive || u.TeamName != "" {
+		t.Fatalf("unexpected user of a deleted team: %+v", u)
+	}
+
+	// An empty team needs neither a target nor force.
+	if err := svc.AddTeam(ctx, "empty", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.DeleteTeam(ctx, "empty", "", false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteTeam_ForceIsAtomic(t *testing.T) {
+	svc, repos := newMembershipService(t)
+	ctx := context.Background()
+	createPR(t, repos, "pr-1", "u1", "u2", "u3")
+
+	// u2 is unassigned between planning and the delete, so the handover no longer applies.
+	svc.prRepo = &racingPRs{PRRepository: repos.PRs, repos: repos, steal: "u2"}
+	if _, err := svc.DeleteTeam(ctx, "backend", "", true); !errors.Is(err, repo_errors.ErrReviewersNotFound) {
+		t.Fatalf("expected ErrReviewersNotFound, got %v", err)
+	}
+	if exists, err := repos.Teams.Exists(ctx, "backend"); err != nil || !exists {
+		t.Fatalf("team must survive a failed delete: exists=%v err=%v", exists, err)
+	}
+	for _, id := range []string{"u1", "u2", "u3", "u4"} {
+		u, err := repos.Users.GetByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !u.IsActive || u.TeamName != "backend" {
+			t.Fatalf("a failed delete must leave the members as they were: %+v", u)
+		}
+	}
+	if got := reviewersOf(t, repos, "pr-1"); len(got) != 1 || got[0] != "u3" {
+		t.Fatalf("unexpected pr-1 reviewers: %v", got)
+	}
+}
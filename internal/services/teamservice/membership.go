@@ -0,0 +1,153 @@
+package teamservice
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+type MembershipResult struct {
+	TeamName string   `json:"team_name"`
+	Added    []string `json:"added,omitempty"`
+	Removed  []string `json:"removed,omitempty"`
+	Reassignment
+}
+
+type MoveResult struct {
+	User     *user.User
+	FromTeam string
+	Reassignment
+}
+
+// AddMembers adds users to an existing team, creating unknown ones and updating the
+// name and activity of known ones. Members coming from another team hand over their
+// open reviews there.
+func (ts *TeamService) AddMembers(ctx context.Context, teamName string, members []*user.User) (MembershipResult, error) {
+	const op = "internal.services.teamservice.AddMembers"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	res := MembershipResult{TeamName: teamName}
+	if err := ts.ensureTeam(ctx, teamName); err != nil {
+		return res, err
+	}
+
+	// Previous teams are read before the upsert moves the users.
+	leftTeam := make(map[string]string)
+	for _, m := range members {
+		u, err := ts.userRepo.GetByID(ctx, m.ID)
+		if err != nil {
+			if errors.Is(err, repo_errors.ErrUserNotFound) {
+				continue
+			}
+			return res, err
+		}
+		if u.TeamName != "" && u.TeamName != teamName {
+			leftTeam[m.ID] = u.TeamName
+		}
+	}
+
+	if err := ts.userRepo.UpsertManyForTeam(ctx, teamName, members); err != nil {
+		return res, err
+	}
+	for _, m := range members {
+		res.Added = append(res.Added, m.ID)
+		old, ok := leftTeam[m.ID]
+		if !ok {
+			continue
+		}
+		if err := ts.handOverReviews(ctx, old, m.ID, nil, event.ReasonTeamChange, &res.Reassignment); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// RemoveMembers leaves the users without a team and hands over their open reviews.
+// Users from other teams are skipped.
+func (ts *TeamService) RemoveMembers(ctx context.Context, teamName string, userIDs []string) (MembershipResult, error) {
+	const op = "internal.services.teamservice.RemoveMembers"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	res := MembershipResult{TeamName: teamName}
+	if len(userIDs) == 0 {
+		return res, nil
+	}
+	if err := ts.ensureTeam(ctx, teamName); err != nil {
+		return res, err
+	}
+	for _, uid := range userIDs {
+		u, err := ts.userRepo.GetByID(ctx, uid)
+		if err != nil {
+			if errors.Is(err, repo_errors.ErrUserNotFound) {
+				return res, serviceerrors.ErrUserNotFound
+			}
+			return res, err
+		}
+		if u.TeamName != teamName {
+			continue
+		}
+		if _, err := ts.userRepo.SetTeam(ctx, uid, ""); err != nil {
+			return res, err
+		}
+		res.Removed = append(res.Removed, uid)
+		if err := ts.handOverReviews(ctx, teamName, uid, userIDs, event.ReasonTeamChange, &res.Reassignment); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// MoveMember moves the user to another team; the open reviews are handed over in the old one.
+func (ts *TeamService) MoveMember(ctx context.Context, userID, teamName string) (MoveResult, error) {
+	const op = "internal.services.teamservice.MoveMember"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	var res MoveResult
+	if err := ts.ensureTeam(ctx, teamName); err != nil {
+		return res, err
+	}
+	u, err := ts.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repo_errors.ErrUserNotFound) {
+			return res, serviceerrors.ErrUserNotFound
+		}
+		return res, err
+	}
+	res.User, res.FromTeam = u, u.TeamName
+	if u.TeamName == teamName {
+		return res, nil
+	}
+
+	if res.User, err = ts.userRepo.SetTeam(ctx, userID, teamName); err != nil {
+		return res, err
+	}
+	if u.TeamName == "" {
+		return res, nil
+	}
+	if err := ts.handOverReviews(ctx, u.TeamName, userID, nil, event.ReasonTeamChange, &res.Reassignment); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+func (ts *TeamService) ensureTeam(ctx context.Context, teamName string) error {
+	exists, err := ts.teamRepo.Exists(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return serviceerrors.ErrTeamNotFound
+	}
+	return nil
+}
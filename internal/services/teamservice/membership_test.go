@@ -0,0 +1,150 @@
+package teamservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/repository"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+// newMembershipService returns a service with teams "backend" (u1-u4) and "frontend" (f1-f3).
+func newMembershipService(t *testing.T) (*TeamService, repository.Repositories) {
+	t.Helper()
+	svc, repos := newTestService(t)
+	ctx := context.Background()
+
+	err := svc.AddTeam(ctx, "backend", "", []*user.User{
+		{ID: "u1", IsActive: true}, {ID: "u2", IsActive: true}, {ID: "u3", IsActive: true}, {ID: "u4", IsActive: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = svc.AddTeam(ctx, "frontend", "", []*user.User{
+		{ID: "f1", IsActive: true}, {ID: "f2", IsActive: true}, {ID: "f3", IsActive: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc, repos
+}
+
+func reviewersOf(t *testing.T, repos repository.Repositories, prID string) []string {
+	t.Helper()
+	pr, err := repos.PRs.GetWithReviewers(context.Background(), prID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pr.Reviewers
+}
+
+func TestMoveMember(t *testing.T) {
+	svc, repos := newMembershipService(t)
+	ctx := context.Background()
+	createPR(t, repos, "pr-1", "u1", "u2", "u3")
+
+	res, err := svc.MoveMember(ctx, "u2", "frontend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.FromTeam != "backend" || res.User.TeamName != "frontend" || res.ReassignedCount != 1 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	// u4 is the only backend member who is neither the author nor a reviewer.
+	if got := reviewersOf(t, repos, "pr-1"); len(got) != 2 || got[0] != "u3" || got[1] != "u4" {
+		t.Fatalf("unexpected reviewers: %v", got)
+	}
+	hist, err := repos.PRs.HistoryByUser(ctx, "u2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hist) != 1 || hist[0].Reason != "team_change" || hist[0].NewReviewerID != "u4" {
+		t.Fatalf("unexpected history: %+v", hist)
+	}
+
+	res, err = svc.MoveMember(ctx, "u2", "frontend")
+	if err != nil || res.ReassignedCount != 0 {
+		t.Fatalf("moving into the same team: %+v, %v", res, err)
+	}
+	if _, err := svc.MoveMember(ctx, "u2", "nobody"); !errors.Is(err, serviceerrors.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+	if _, err := svc.MoveMember(ctx, "nobody", "frontend"); !errors.Is(err, serviceerrors.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestRemoveMembers(t *testing.T) {
+	svc, repos := newMembershipService(t)
+	ctx := context.Background()
+	createPR(t, repos, "pr-1", "u1", "u2", "u3")
+
+	// u4 leaves as well, so nobody can replace u3; f1 is not a backend member.
+	res, err := svc.RemoveMembers(ctx, "backend", []string{"u3", "u4", "f1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Removed) != 2 || res.RemovedCount != 1 || res.ReassignedCount != 0 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if got := reviewersOf(t, repos, "pr-1"); len(got) != 1 || got[0] != "u2" {
+		t.Fatalf("unexpected reviewers: %v", got)
+	}
+	u, err := repos.Users.GetByID(ctx, "u3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.TeamName != "" {
+		t.Fatalf("removed user still has a team: %+v", u)
+	}
+	tm, err := svc.GetTeam(ctx, "backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tm.Members) != 2 {
+		t.Fatalf("expected 2 members left, got %d", len(tm.Members))
+	}
+
+	// A user without a team joins a team without handing anything over.
+	res2, err := svc.MoveMember(ctx, "u3", "frontend")
+	if err != nil || res2.FromTeam != "" || res2.User.TeamName != "frontend" {
+		t.Fatalf("unexpected move: %+v, %v", res2, err)
+	}
+
+	if _, err := svc.RemoveMembers(ctx, "backend", []string{"nobody"}); !errors.Is(err, serviceerrors.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestAddMembers(t *testing.T) {
+	svc, repos := newMembershipService(t)
+	ctx := context.Background()
+	createPR(t, repos, "pr-f", "f2", "f1")
+
+	res, err := svc.AddMembers(ctx, "backend", []*user.User{
+		{ID: "f1", Name: "Frank", IsActive: true}, {ID: "n1", Name: "Nina", IsActive: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Added) != 2 || res.ReassignedCount != 1 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	// f1 handed the frontend review over to f3.
+	if got := reviewersOf(t, repos, "pr-f"); len(got) != 1 || got[0] != "f3" {
+		t.Fatalf("unexpected reviewers: %v", got)
+	}
+	tm, err := svc.GetTeam(ctx, "backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tm.Members) != 6 {
+		t.Fatalf("expected 6 members, got %d", len(tm.Members))
+	}
+
+	if _, err := svc.AddMembers(ctx, "nobody", []*user.User{{ID: "n2"}}); !errors.Is(err, serviceerrors.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+}
@@ -3,35 +3,81 @@ package teamservice
 import (
 	"context"
 	"errors"
-	"math/rand"
+	"slices"
 
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
+	"github.com/hihikaAAa/PRManager/internal/domain/event"
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
 	"github.com/hihikaAAa/PRManager/internal/domain/team"
 	"github.com/hihikaAAa/PRManager/internal/domain/user"
-	"github.com/hihikaAAa/PRManager/internal/repository/postgres"
-	"github.com/hihikaAAa/PRManager/internal/repository/postgres/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/lib/metrics"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/services/assigner"
 	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
-	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
 )
 
+type UserRepository interface{
+	UpsertManyForTeam(ctx context.Context, teamName string, users []*user.User) error
+	GetByID(ctx context.Context, id string)(*user.User, error)
+	Deactivate(ctx context.Context, userIDs []string, handovers ...assignment.Handover) error
+	SetTeam(ctx context.Context, id, teamName string)(*user.User, error)
+}
+
+type TeamRepository interface{
+	CreateTeam(ctx context.Context, name string) error
+	Exists(ctx context.Context, name string)(bool, error)
+	GetWithMembers(ctx context.Context, name string)(*team.Team, error)
+	GetSettings(ctx context.Context, name string)(team.Settings, error)
+	UpsertSettings(ctx context.Context, s team.Settings) error
+	GetCodeOwners(ctx context.Context, name string) (team.CodeOwners, error)
+	SetCodeOwners(ctx context.Context, c team.CodeOwners) error
+	List(ctx context.Context) ([]team.Team, error)
+	SetParent(ctx context.Context, name, parent string) error
+	Ancestors(ctx context.Context, name string) ([]string, error)
+	Rename(ctx context.Context, oldName, newName string) error
+	Delete(ctx context.Context, name, target string, handovers ...assignment.Handover) ([]string, error)
+}
+
+type PRRepository interface{
+	GetOpenPRIDsByReviewer(ctx context.Context, userID string)([]string, error)
+	GetWithReviewers(ctx context.Context, id string)(*pullrequest.PullRequest, error)
+	HandOver(ctx context.Context, handovers []assignment.Handover) error
+}
+
 type TeamService struct{
-	userRepo *postgres.UserRepository
-	teamRepo *postgres.TeamRepository
-	prRepo   *postgres.PRRepository
+	userRepo UserRepository
+	teamRepo TeamRepository
+	prRepo   PRRepository
+	assigner *assigner.Assigner
+}
+
+// Reassignment counts the open reviews handed over by members who left a team or were deactivated.
+type Reassignment struct {
+	ReassignedCount int `json:"reassigned_count"`
+	RemovedCount int `json:"removed_count"`
+	FallbackCount int `json:"fallback_count"`
+	UnderstaffedPRs []string `json:"understaffed_prs,omitempty"`
 }
 
 type DeactivateResult struct {
 	TeamName string `json:"team_name"`
 	Deactivated []string `json:"deactivated"`
-	ReassignedCount int `json:"reassigned_count"`
-	RemovedCount int `json:"removed_count"`
+	Reassignment
 }
 
 
-func New(userRepo *postgres.UserRepository, teamRepo *postgres.TeamRepository, prRepo  *postgres.PRRepository) *TeamService{
-	return &TeamService{ userRepo: userRepo, teamRepo: teamRepo, prRepo: prRepo}
+func New(userRepo UserRepository, teamRepo TeamRepository, prRepo PRRepository, assigner *assigner.Assigner) *TeamService{
+	return &TeamService{ userRepo: userRepo, teamRepo: teamRepo, prRepo: prRepo, assigner: assigner}
 }
 
-func (ts *TeamService) AddTeam(ctx context.Context, teamName string, members []*user.User) error{
+// AddTeam creates the team with its members; a non-empty parent places it under that team.
+func (ts *TeamService) AddTeam(ctx context.Context, teamName, parent string, members []*user.User) error{
+	const op = "internal.services.teamservice.AddTeam"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	exists, err := ts.teamRepo.Exists(ctx,teamName)
 	if err != nil{
 		return err
@@ -39,11 +85,25 @@ func (ts *TeamService) AddTeam(ctx context.Context, teamName string, members []*
 	if exists{
 		return serviceerrors.ErrTeamExists
 	}
+	if parent != "" {
+		exists, err := ts.teamRepo.Exists(ctx, parent)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return serviceerrors.ErrParentTeamNotFound
+		}
+	}
 
 	err = ts.teamRepo.CreateTeam(ctx,teamName)
 	if err != nil {
 		return err
 	}
+	if parent != "" {
+		if err := ts.teamRepo.SetParent(ctx, teamName, parent); err != nil {
+			return err
+		}
+	}
 	
 	err = ts.userRepo.UpsertManyForTeam(ctx,teamName,members)
 	if err != nil{
@@ -53,6 +113,11 @@ func (ts *TeamService) AddTeam(ctx context.Context, teamName string, members []*
 }
 
 func (ts *TeamService) GetTeam(ctx context.Context, teamName string)(*team.Team, error){
+	const op = "internal.services.teamservice.GetTeam"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	team, err := ts.teamRepo.GetWithMembers(ctx,teamName)
 	if err != nil{
 		return nil, err
@@ -60,7 +125,15 @@ func (ts *TeamService) GetTeam(ctx context.Context, teamName string)(*team.Team,
 	return team, nil
 }
 
+// DeactivateAndReassign deactivates the listed members of the team and hands over their
+// open reviews. The deactivation and every handover are stored in one transaction:
+// if a PR changed in the meantime nothing is applied and the call can be repeated.
 func (ts *TeamService) DeactivateAndReassign(ctx context.Context, teamName string, userIDs []string) (DeactivateResult, error) {
+	const op = "internal.services.teamservice.DeactivateAndReassign"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	res := DeactivateResult{TeamName: teamName}
 	if len(userIDs) == 0 {
 		return res, nil
@@ -72,10 +145,8 @@ func (ts *TeamService) DeactivateAndReassign(ctx context.Context, teamName strin
 	if !exists {
 		return res, serviceerrors.ErrTeamNotFound
 	}
-	deactivatedSet := make(map[string]struct{}, len(userIDs))
-	for _, id := range userIDs {
-		deactivatedSet[id] = struct{}{}
-	}
+	plan := ts.newHandoverPlan(event.ReasonTeamDeactivation)
+	var members []string
 	for _, uid := range userIDs {
 		u, err := ts.userRepo.GetByID(ctx, uid)
 		if err != nil {
@@ -84,48 +155,247 @@ func (ts *TeamService) DeactivateAndReassign(ctx context.Context, teamName strin
 			}
 			return res, err
 		}
-		if u.TeamName != teamName {
+		if u.TeamName != teamName || slices.Contains(members, uid) {
 			continue
 		}
-		if _, err := ts.userRepo.SetIsActive(ctx, uid, false); err != nil {
+		members = append(members, uid)
+		if err := ts.planHandover(ctx, plan, teamName, uid, userIDs); err != nil {
 			return res, err
 		}
-		res.Deactivated = append(res.Deactivated, uid)
-		prIDs, err := ts.prRepo.GetOpenPRIDsByReviewer(ctx, uid)
-		if err != nil {
-			return res, err
+	}
+	if len(members) == 0 {
+		return res, nil
+	}
+	if err := ts.userRepo.Deactivate(ctx, members, plan.handovers...); err != nil {
+		if errors.Is(err, repo_errors.ErrUserNotFound) {
+			return res, serviceerrors.ErrUserNotFound
 		}
-		for _, prID := range prIDs {
-			pr, err := ts.prRepo.GetWithReviewers(ctx, prID)
-			if err != nil {
-				return res, err
-			}
-			if pr.Status == pullrequest.StatusMerged {
-				continue
+		return res, err
+	}
+	plan.recordMetrics()
+	res.Deactivated, res.Reassignment = members, plan.res
+	return res, nil
+}
+
+// ReleaseReviews hands over the user's open reviews within their team the way
+// DeactivateAndReassign does, but leaves the user active and in the team.
+func (ts *TeamService) ReleaseReviews(ctx context.Context, userID, reason string) (Reassignment, error) {
+	const op = "internal.services.teamservice.ReleaseReviews"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	var res Reassignment
+	u, err := ts.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repo_errors.ErrUserNotFound) {
+			return res, serviceerrors.ErrUserNotFound
+		}
+		return res, err
+	}
+	err = ts.handOverReviews(ctx, u.TeamName, userID, nil, reason, &res)
+	return res, err
+}
+
+// handOverReviews plans the handover of one user's open reviews and stores it at once.
+func (ts *TeamService) handOverReviews(ctx context.Context, teamName, uid string, leaving []string, reason string, res *Reassignment) error {
+	plan := ts.newHandoverPlan(reason)
+	if err := ts.planHandover(ctx, plan, teamName, uid, leaving); err != nil {
+		return err
+	}
+	if len(plan.handovers) == 0 {
+		return nil
+	}
+	if err := ts.prRepo.HandOver(ctx, plan.handovers); err != nil {
+		return err
+	}
+	plan.recordMetrics()
+	res.add(plan.res)
+	return nil
+}
+
+// handoverPlan collects the handovers of open reviews before they are stored.
+// PRs are kept with the changes planned so far, and the picked reviewers count
+// towards the loads, so later picks see the earlier ones.
+type handoverPlan struct {
+	reason       string
+	handovers    []assignment.Handover
+	res          Reassignment
+	assigner     *assigner.Assigner
+	pending      *assigner.PendingLoads
+	prs          map[string]*pullrequest.PullRequest
+	minReviewers map[string]int
+}
+
+func (ts *TeamService) newHandoverPlan(reason string) *handoverPlan {
+	a, pending := ts.assigner.WithPendingLoads()
+	return &handoverPlan{
+		reason:       reason,
+		assigner:     a,
+		pending:      pending,
+		prs:          make(map[string]*pullrequest.PullRequest),
+		minReviewers: make(map[string]int),
+	}
+}
+
+// planHandover plans to replace the user in their open reviews with members of the
+// team (or its fallback teams) the user leaves. Reviewers nobody can replace are removed.
+// The users in leaving are never picked.
+func (ts *TeamService) planHandover(ctx context.Context, plan *handoverPlan, teamName, uid string, leaving []string) error {
+	prIDs, err := ts.prRepo.GetOpenPRIDsByReviewer(ctx, uid)
+	if err != nil {
+		return err
+	}
+	for _, prID := range prIDs {
+		pr, ok := plan.prs[prID]
+		if !ok {
+			if pr, err = ts.prRepo.GetWithReviewers(ctx, prID); err != nil {
+				return err
 			}
-			exclude := make([]string, 0, len(pr.Reviewers)+1+len(userIDs))
-			exclude = append(exclude, pr.AuthorID)
-			exclude = append(exclude, pr.Reviewers...)
-			for id := range deactivatedSet {
-				exclude = append(exclude, id)
+			plan.prs[prID] = pr
+		}
+		if pr.Status == pullrequest.StatusMerged || !slices.Contains(pr.Reviewers, uid) {
+			continue
+		}
+		exclude := make([]string, 0, len(pr.Reviewers)+1+len(leaving))
+		exclude = append(exclude, pr.AuthorID)
+		exclude = append(exclude, pr.Reviewers...)
+		exclude = append(exclude, leaving...)
+		replacement, err := plan.assigner.PickReplacement(ctx, teamName, exclude, pr.ChangedFiles)
+		if err != nil {
+			return err
+		}
+
+		reviewers := make([]string, 0, len(pr.Reviewers))
+		for _, id := range pr.Reviewers {
+			if id != uid {
+				reviewers = append(reviewers, id)
 			}
-			candidates, err := ts.userRepo.FindActiveByTeamExcept(ctx, teamName, exclude)
+		}
+		if len(replacement.Reviewers) == 0 {
+			pr.Reviewers = reviewers
+			plan.handovers = append(plan.handovers, assignment.Handover{
+				PullRequestID: prID, ReviewerID: uid,
+				Event: event.New(ctx, event.TypeReviewerRemoved, event.ReviewerRemoved{
+					PullRequestID: prID, ReviewerID: uid, Reason: plan.reason,
+				}),
+			})
+			plan.res.RemovedCount++
+			minReviewers, err := ts.authorMinReviewers(ctx, plan, pr.AuthorID)
 			if err != nil {
-				return res, err
+				return err
 			}
-			if len(candidates) == 0 {
-				if err := ts.prRepo.RemoveReviewer(ctx, prID, uid); err != nil {
-					return res, err
-				}
-				res.RemovedCount++
-				continue
+			if len(reviewers) < minReviewers && !slices.Contains(plan.res.UnderstaffedPRs, prID) {
+				plan.res.UnderstaffedPRs = append(plan.res.UnderstaffedPRs, prID)
 			}
-			newUser := candidates[rand.Intn(len(candidates))]
-			if err := ts.prRepo.ReplaceReviewers(ctx, prID, uid, newUser.ID); err != nil {
-				return res, err
-			}
-			res.ReassignedCount++
+			continue
+		}
+		newUserID, fromFallback := replacement.Reviewers[0], len(replacement.Fallback) > 0
+		pr.Reviewers = append(reviewers, newUserID)
+		plan.pending.Add([]string{newUserID})
+		plan.handovers = append(plan.handovers, assignment.Handover{
+			PullRequestID: prID, ReviewerID: uid, NewReviewerID: newUserID,
+			Event: event.New(ctx, event.TypeReviewerReassigned, event.ReviewerReassigned{
+				PullRequestID: prID, OldReviewerID: uid, NewReviewerID: newUserID,
+				Reason: plan.reason, FromFallback: fromFallback,
+			}),
+		})
+		plan.res.ReassignedCount++
+		if fromFallback {
+			plan.res.FallbackCount++
 		}
 	}
-	return res, nil
+	return nil
+}
+
+// authorMinReviewers returns the minimum reviewers of the PR author's team, which is
+// what a PR is staffed against regardless of the team its reviewers come from.
+func (ts *TeamService) authorMinReviewers(ctx context.Context, plan *handoverPlan, authorID string) (int, error) {
+	if n, ok := plan.minReviewers[authorID]; ok {
+		return n, nil
+	}
+	var teamName string
+	author, err := ts.userRepo.GetByID(ctx, authorID)
+	switch {
+	case err == nil:
+		teamName = author.TeamName
+	case !errors.Is(err, repo_errors.ErrUserNotFound):
+		return 0, err
+	}
+	settings, err := plan.assigner.Settings(ctx, teamName)
+	if err != nil {
+		return 0, err
+	}
+	plan.minReviewers[authorID] = settings.MinReviewers
+	return settings.MinReviewers, nil
+}
+
+// recordMetrics counts the planned handovers; it is called once they are stored.
+func (plan *handoverPlan) recordMetrics() {
+	if plan.res.ReassignedCount > 0 {
+		metrics.Reassignments.WithLabelValues(plan.reason).Add(float64(plan.res.ReassignedCount))
+	}
+	if plan.res.RemovedCount > 0 {
+		metrics.ReviewersRemoved.WithLabelValues(plan.reason).Add(float64(plan.res.RemovedCount))
+	}
+}
+
+func (r *Reassignment) add(o Reassignment) {
+	r.ReassignedCount += o.ReassignedCount
+	r.RemovedCount += o.RemovedCount
+	r.FallbackCount += o.FallbackCount
+	r.UnderstaffedPRs = append(r.UnderstaffedPRs, o.UnderstaffedPRs...)
+}
+
+func (ts *TeamService) GetSettings(ctx context.Context, teamName string) (team.Settings, error) {
+	const op = "internal.services.teamservice.GetSettings"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	settings, err := ts.teamRepo.GetSettings(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, repo_errors.ErrTeamNotFound) {
+			return team.Settings{}, serviceerrors.ErrTeamNotFound
+		}
+		return team.Settings{}, err
+	}
+	return settings, nil
+}
+
+func (ts *TeamService) SetSettings(ctx context.Context, settings team.Settings) (team.Settings, error) {
+	const op = "internal.services.teamservice.SetSettings"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := settings.Validate(); err != nil {
+		return team.Settings{}, err
+	}
+	if !assigner.IsKnownStrategy(settings.Strategy) {
+		return team.Settings{}, serviceerrors.ErrUnknownStrategy
+	}
+
+	exists, err := ts.teamRepo.Exists(ctx, settings.TeamName)
+	if err != nil {
+		return team.Settings{}, err
+	}
+	if !exists {
+		return team.Settings{}, serviceerrors.ErrTeamNotFound
+	}
+
+	for _, fb := range settings.FallbackTeams {
+		exists, err := ts.teamRepo.Exists(ctx, fb)
+		if err != nil {
+			return team.Settings{}, err
+		}
+		if !exists {
+			return team.Settings{}, serviceerrors.ErrFallbackTeamNotFound
+		}
+	}
+
+	if err := ts.teamRepo.UpsertSettings(ctx, settings); err != nil {
+		return team.Settings{}, err
+	}
+	return settings, nil
 }
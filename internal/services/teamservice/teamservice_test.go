@@ -0,0 +1,234 @@
+package teamservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/repository"
+	"github.com/hihikaAAa/PRManager/internal/repository/memory"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/services/assigner"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+)
+
+func newTestService(t *testing.T) (*TeamService, repository.Repositories) {
+	t.Helper()
+
+	repos := memory.NewStorage().Repositories()
+	a, err := assigner.New(repos.Users, repos.Teams, repos.PRs, assigner.StrategyRandom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(repos.Users, repos.Teams, repos.PRs, a), repos
+}
+
+func createPR(t *testing.T, repos repository.Repositories, id, author string, reviewers ...string) {
+	t.Helper()
+	pr := pullrequest.PullRequest{ID: id, AuthorID: author, Status: pullrequest.StatusOpen, CreatedAt: time.Now(), Reviewers: reviewers}
+	if err := repos.PRs.CreateWithReviewers(context.Background(), pr); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func createPRWithFiles(t *testing.T, repos repository.Repositories, id, author string, files []string, reviewers ...string) {
+	t.Helper()
+	pr := pullrequest.PullRequest{ID: id, AuthorID: author, Status: pullrequest.StatusOpen, CreatedAt: time.Now(), Reviewers: reviewers, ChangedFiles: files}
+	if err := repos.PRs.CreateWithReviewers(context.Background(), pr); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAddTeam(t *testing.T) {
+	svc, _ := newTestService(t)
+	ctx := context.Background()
+
+	members := []*user.User{{ID: "u1", Name: "Alice", IsActive: true}, {ID: "u2", Name: "Bob", IsActive: true}}
+	if err := svc.AddTeam(ctx, "backend", "", members); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.AddTeam(ctx, "backend", "", members); !errors.Is(err, serviceerrors.ErrTeamExists) {
+		t.Fatalf("expected ErrTeamExists, got %v", err)
+	}
+
+	tm, err := svc.GetTeam(ctx, "backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tm.Members) != 2 {
+		t.Fatalf("expected 2 members, got %d", len(tm.Members))
+	}
+}
+
+func TestDeactivateAndReassign(t *testing.T) {
+	svc, repos := newTestService(t)
+	ctx := context.Background()
+
+	err := svc.AddTeam(ctx, "backend", "", []*user.User{
+		{ID: "u1", IsActive: true}, {ID: "u2", IsActive: true}, {ID: "u3", IsActive: true}, {ID: "u4", IsActive: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	createPR(t, repos, "pr-1", "u1", "u2", "u3")
+	createPR(t, repos, "pr-2", "u4", "u2")
+
+	res, err := svc.DeactivateAndReassign(ctx, "backend", []string{"u2", "u3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Deactivated) != 2 {
+		t.Fatalf("expected 2 deactivated users, got %v", res.Deactivated)
+	}
+
+	// pr-1: u2 and u3 can only be replaced by u4, the second slot is freed.
+	pr1, err := repos.PRs.GetWithReviewers(ctx, "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pr1.Reviewers) != 1 || pr1.Reviewers[0] != "u4" {
+		t.Fatalf("unexpected pr-1 reviewers: %v", pr1.Reviewers)
+	}
+	// pr-2: the author u4 is excluded and u1 is the only active member left.
+	pr2, err := repos.PRs.GetWithReviewers(ctx, "pr-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pr2.Reviewers) != 1 || pr2.Reviewers[0] != "u1" {
+		t.Fatalf("unexpected pr-2 reviewers: %v", pr2.Reviewers)
+	}
+	if res.ReassignedCount != 2 || res.RemovedCount != 1 {
+		t.Fatalf("unexpected counts: %+v", res)
+	}
+
+	hist, err := repos.PRs.HistoryByUser(ctx, "u2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range hist {
+		if c.Reason != "team_deactivation" {
+			t.Fatalf("unexpected reason: %+v", c)
+		}
+	}
+
+	if _, err := svc.DeactivateAndReassign(ctx, "nobody", []string{"u1"}); !errors.Is(err, serviceerrors.ErrTeamNotFound) {
+		t.Fatalf("expected ErrTeamNotFound, got %v", err)
+	}
+}
+
+func TestDeactivateAndReassign_SkipsUsersAtCapacity(t *testing.T) {
+	svc, repos := newTestService(t)
+	ctx := context.Background()
+
+	err := svc.AddTeam(ctx, "backend", "", []*user.User{
+		{ID: "u1", IsActive: true}, {ID: "u2", IsActive: true}, {ID: "u3", IsActive: true}, {ID: "u4", IsActive: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repos.Users.SetMaxOpenReviews(ctx, "u4", 1); err != nil {
+		t.Fatal(err)
+	}
+	createPR(t, repos, "pr-1", "u1", "u2", "u3")
+	createPR(t, repos, "pr-2", "u3", "u4")
+
+	// u4 is the only possible replacement for u2 but already reviews pr-2.
+	res, err := svc.DeactivateAndReassign(ctx, "backend", []string{"u2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ReassignedCount != 0 || res.RemovedCount != 1 {
+		t.Fatalf("unexpected counts: %+v", res)
+	}
+	pr1, err := repos.PRs.GetWithReviewers(ctx, "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pr1.Reviewers) != 1 || pr1.Reviewers[0] != "u3" {
+		t.Fatalf("unexpected pr-1 reviewers: %v", pr1.Reviewers)
+	}
+}
+
+// racingPRs unassigns steal from every PR right after it is read, as a concurrent
+// request would.
+type racingPRs struct {
+	PRRepository
+	repos repository.Repositories
+	steal string
+}
+
+func (r *racingPRs) GetWithReviewers(ctx context.Context, id string) (*pullrequest.PullRequest, error) {
+	pr, err := r.PRRepository.GetWithReviewers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.repos.PRs.RemoveReviewer(ctx, id, r.steal); err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+func TestDeactivateAndReassign_Atomic(t *testing.T) {
+	svc, repos := newTestService(t)
+	ctx := context.Background()
+
+	err := svc.AddTeam(ctx, "backend", "", []*user.User{
+		{ID: "u1", IsActive: true}, {ID: "u2", IsActive: true}, {ID: "u3", IsActive: true}, {ID: "u4", IsActive: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	createPR(t, repos, "pr-1", "u1", "u2", "u3")
+
+	svc.prRepo = &racingPRs{PRRepository: repos.PRs, repos: repos, steal: "u2"}
+	if _, err := svc.DeactivateAndReassign(ctx, "backend", []string{"u4", "u2"}); !errors.Is(err, repo_errors.ErrReviewersNotFound) {
+		t.Fatalf("expected ErrReviewersNotFound, got %v", err)
+	}
+	for _, id := range []string{"u2", "u4"} {
+		u, err := repos.Users.GetByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !u.IsActive {
+			t.Fatalf("%s must stay active after a failed handover", id)
+		}
+	}
+	pr1, err := repos.PRs.GetWithReviewers(ctx, "pr-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pr1.Reviewers) != 1 || pr1.Reviewers[0] != "u3" {
+		t.Fatalf("unexpected pr-1 reviewers: %v", pr1.Reviewers)
+	}
+}
+
+func TestDeactivateAndReassign_UnderstaffedByAuthorTeam(t *testing.T) {
+	svc, repos := newTestService(t)
+	ctx := context.Background()
+
+	if err := svc.AddTeam(ctx, "backend", "", []*user.User{{ID: "u1", IsActive: true}, {ID: "u2", IsActive: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.AddTeam(ctx, "frontend", "", []*user.User{{ID: "f1", IsActive: true}}); err != nil {
+		t.Fatal(err)
+	}
+	settings := team.DefaultSettings("frontend")
+	settings.MinReviewers = 2
+	if _, err := svc.SetSettings(ctx, settings); err != nil {
+		t.Fatal(err)
+	}
+	createPR(t, repos, "pr-1", "f1", "u1", "u2")
+
+	// backend needs no reviewers, but the author's team needs two.
+	res, err := svc.DeactivateAndReassign(ctx, "backend", []string{"u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.RemovedCount != 1 || len(res.UnderstaffedPRs) != 1 || res.UnderstaffedPRs[0] != "pr-1" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
@@ -0,0 +1,39 @@
+package teamservice
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestDeactivateAndReassign_Traced(t *testing.T) {
+	svc, repos := newMembershipService(t)
+	createPRWithFiles(t, repos, "pr-1", "u1", nil, "u2")
+
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+
+	if _, err := svc.DeactivateAndReassign(context.Background(), "backend", []string{"u2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := sr.Ended()
+	byName := make(map[string]sdktrace.ReadOnlySpan, len(spans))
+	for _, s := range spans {
+		byName[s.Name()] = s
+	}
+	root, ok := byName["internal.services.teamservice.DeactivateAndReassign"]
+	if !ok {
+		t.Fatalf("no DeactivateAndReassign span among %d spans", len(spans))
+	}
+	pick, ok := byName["internal.services.assigner.PickReplacement"]
+	if !ok {
+		t.Fatal("no PickReplacement span")
+	}
+	if pick.Parent().SpanID() != root.SpanContext().SpanID() {
+		t.Fatal("PickReplacement span is not a child of DeactivateAndReassign")
+	}
+}
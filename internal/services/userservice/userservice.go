@@ -2,26 +2,68 @@ package userservice
 
 import(
 	"context"
+	"errors"
+	"strings"
 	
+	"github.com/hihikaAAa/PRManager/internal/domain/assignment"
 	"github.com/hihikaAAa/PRManager/internal/domain/user"
-	"github.com/hihikaAAa/PRManager/internal/repository/postgres"
 	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	"github.com/hihikaAAa/PRManager/internal/webhooks"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
 )
+type UserRepository interface{
+	GetByID(ctx context.Context, id string)(*user.User, error)
+	SetIsActive(ctx context.Context, id string, active bool)(*user.User, error)
+	SetMaxOpenReviews(ctx context.Context, id string, max int)(*user.User, error)
+	LinkIdentity(ctx context.Context, provider, login, userID string) error
+}
+
+type PRRepository interface{
+	FindShortByReviewer(ctx context.Context, userID string)([]pullrequest.PullRequestShort, error)
+	HistoryByUser(ctx context.Context, userID string)([]assignment.Change, error)
+}
+
 type UserService struct{
-	userRepo *postgres.UserRepository
-	prRepo *postgres.PRRepository
+	userRepo UserRepository
+	prRepo PRRepository
 }
 
-func New(prRepo *postgres.PRRepository, userRepo *postgres.UserRepository)*UserService{
+func New(prRepo PRRepository, userRepo UserRepository)*UserService{
 	return &UserService{prRepo : prRepo, userRepo: userRepo}
 }
 
 func (u *UserService) SetIsActive(ctx context.Context,userID string, isActive bool) (*user.User, error){
+	const op = "internal.services.userservice.SetIsActive"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	user, err := u.userRepo.SetIsActive(ctx,userID,isActive)
 	return user,err
 }
 
+// SetCapacity limits the open PRs the user reviews at once; 0 removes the limit.
+// Reviews the user already has are kept even when they exceed the new limit.
+func (u *UserService) SetCapacity(ctx context.Context, userID string, maxOpenReviews int) (*user.User, error){
+	const op = "internal.services.userservice.SetCapacity"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if maxOpenReviews < 0{
+		return nil, user.ErrInvalidCapacity
+	}
+	return u.userRepo.SetMaxOpenReviews(ctx, userID, maxOpenReviews)
+}
+
 func (u *UserService) GetReviewPRs(ctx context.Context, userID string)([]pullrequest.PullRequestShort, error){
+	const op = "internal.services.userservice.GetReviewPRs"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	if _, err := u.userRepo.GetByID(ctx, userID); err != nil{
 		return nil, err
 	}
@@ -33,3 +75,61 @@ func (u *UserService) GetReviewPRs(ctx context.Context, userID string)([]pullreq
 
 	return prs, nil
 }
+
+// LinkIdentity maps a login on a code hosting provider to our user.
+// Logins are case-insensitive on the providers, so they are stored lowercased.
+func (u *UserService) LinkIdentity(ctx context.Context, userID, provider, login string) (*user.User, error){
+	const op = "internal.services.userservice.LinkIdentity"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if !webhooks.IsKnownProvider(provider){
+		return nil, serviceerrors.ErrUnknownProvider
+	}
+
+	usr, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil{
+		return nil, err
+	}
+
+	if err := u.userRepo.LinkIdentity(ctx, provider, strings.ToLower(login), userID); err != nil{
+		return nil, err
+	}
+	return usr, nil
+}
+
+// History returns the reviewer assignment changes involving the user, oldest first.
+func (u *UserService) History(ctx context.Context, userID string)([]assignment.Change, error){
+	const op = "internal.services.userservice.History"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if _, err := u.userRepo.GetByID(ctx, userID); err != nil{
+		return nil, err
+	}
+	return u.prRepo.HistoryByUser(ctx, userID)
+}
+
+// SameTeam reports whether both users are in one team. A user without a team or
+// an unknown user shares it with nobody.
+func (u *UserService) SameTeam(ctx context.Context, userID, otherID string)(bool, error){
+	const op = "internal.services.userservice.SameTeam"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	var teams [2]string
+	for i, id := range []string{userID, otherID}{
+		usr, err := u.userRepo.GetByID(ctx, id)
+		if errors.Is(err, repo_errors.ErrUserNotFound){
+			return false, nil
+		}
+		if err != nil{
+			return false, err
+		}
+		teams[i] = usr.TeamName
+	}
+	return teams[0] != "" && teams[0] == teams[1], nil
+}
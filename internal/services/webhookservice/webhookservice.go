@@ -0,0 +1,98 @@
+package webhookservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/lib/actor"
+	"github.com/hihikaAAa/PRManager/internal/lib/tracing"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+	"github.com/hihikaAAa/PRManager/internal/webhooks"
+)
+
+type PROperator interface {
+	Create(ctx context.Context, id, name, authorID string, draft bool, changedFiles []string) (*pullrequest.PullRequest, error)
+	MarkReady(ctx context.Context, id string) (*pullrequest.PullRequest, error)
+	// MarkMerged skips the approval gate: the provider has merged the PR already.
+	MarkMerged(ctx context.Context, id string) (*pullrequest.PullRequest, error)
+	Close(ctx context.Context, id string) (*pullrequest.PullRequest, error)
+	Reopen(ctx context.Context, id string) (*pullrequest.PullRequest, error)
+}
+
+type IdentityResolver interface {
+	FindByIdentity(ctx context.Context, provider, login string) (*user.User, error)
+}
+
+// Result describes what an event did.
+// Applied is false for redelivered events that were already reflected (PR already exists).
+type Result struct {
+	Applied     bool
+	PullRequest *pullrequest.PullRequest
+}
+
+type WebhookService struct {
+	prs        PROperator
+	identities IdentityResolver
+}
+
+func New(prs PROperator, identities IdentityResolver) *WebhookService {
+	return &WebhookService{prs: prs, identities: identities}
+}
+
+// Apply drives the PR lifecycle from a provider event.
+func (s *WebhookService) Apply(ctx context.Context, ev webhooks.Event) (Result, error) {
+	const op = "internal.services.webhookservice.Apply"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	// The payload does not say who acted on the provider side, so the history records the provider itself.
+	ctx = actor.WithActor(ctx, "webhook:"+ev.Provider)
+
+	var (
+		pr  *pullrequest.PullRequest
+		err error
+	)
+	switch ev.Action {
+	case webhooks.ActionOpened:
+		return s.open(ctx, ev)
+	case webhooks.ActionReady:
+		pr, err = s.prs.MarkReady(ctx, ev.PullRequestID)
+	case webhooks.ActionMerged:
+		pr, err = s.prs.MarkMerged(ctx, ev.PullRequestID)
+	case webhooks.ActionClosed:
+		pr, err = s.prs.Close(ctx, ev.PullRequestID)
+	case webhooks.ActionReopen:
+		pr, err = s.prs.Reopen(ctx, ev.PullRequestID)
+	default:
+		return Result{}, fmt.Errorf("%s: unsupported action %q", op, ev.Action)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Applied: true, PullRequest: pr}, nil
+}
+
+func (s *WebhookService) open(ctx context.Context, ev webhooks.Event) (Result, error) {
+	author, err := s.identities.FindByIdentity(ctx, ev.Provider, strings.ToLower(ev.AuthorLogin))
+	if err != nil {
+		if errors.Is(err, repo_errors.ErrIdentityNotFound) {
+			return Result{}, serviceerrors.ErrIdentityNotLinked
+		}
+		return Result{}, err
+	}
+
+	pr, err := s.prs.Create(ctx, ev.PullRequestID, ev.Title, author.ID, ev.Draft, nil)
+	if err != nil {
+		if errors.Is(err, serviceerrors.ErrPRExists) {
+			return Result{Applied: false}, nil
+		}
+		return Result{}, err
+	}
+	return Result{Applied: true, PullRequest: pr}, nil
+}
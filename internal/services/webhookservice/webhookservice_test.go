@@ -0,0 +1,185 @@
+package webhookservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pullrequest "github.com/hihikaAAa/PRManager/internal/domain/pull-request"
+	"github.com/hihikaAAa/PRManager/internal/domain/team"
+	"github.com/hihikaAAa/PRManager/internal/domain/user"
+	"github.com/hihikaAAa/PRManager/internal/repository/memory"
+	"github.com/hihikaAAa/PRManager/internal/repository/repo_errors"
+	"github.com/hihikaAAa/PRManager/internal/services/assigner"
+	"github.com/hihikaAAa/PRManager/internal/services/prservice"
+	serviceerrors "github.com/hihikaAAa/PRManager/internal/services/serviceErrors"
+	"github.com/hihikaAAa/PRManager/internal/webhooks"
+)
+
+type prOperatorMock struct {
+	calls     []string
+	createErr error
+	gotAuthor string
+	gotDraft  bool
+}
+
+func (m *prOperatorMock) record(call, id string) (*pullrequest.PullRequest, error) {
+	m.calls = append(m.calls, call)
+	return &pullrequest.PullRequest{ID: id}, nil
+}
+
+func (m *prOperatorMock) Create(ctx context.Context, id, name, authorID string, draft bool, changedFiles []string) (*pullrequest.PullRequest, error) {
+	m.gotAuthor, m.gotDraft = authorID, draft
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+	return m.record("create", id)
+}
+
+func (m *prOperatorMock) MarkReady(ctx context.Context, id string) (*pullrequest.PullRequest, error) {
+	return m.record("ready", id)
+}
+
+func (m *prOperatorMock) MarkMerged(ctx context.Context, id string) (*pullrequest.PullRequest, error) {
+	return m.record("merge", id)
+}
+
+func (m *prOperatorMock) Close(ctx context.Context, id string) (*pullrequest.PullRequest, error) {
+	return m.record("close", id)
+}
+
+func (m *prOperatorMock) Reopen(ctx context.Context, id string) (*pullrequest.PullRequest, error) {
+	return m.record("reopen", id)
+}
+
+type identitiesMock map[string]string
+
+func (m identitiesMock) FindByIdentity(ctx context.Context, provider, login string) (*user.User, error) {
+	id, ok := m[provider+"/"+login]
+	if !ok {
+		return nil, repo_errors.ErrIdentityNotFound
+	}
+	return &user.User{ID: id}, nil
+}
+
+func TestApply_DispatchesActions(t *testing.T) {
+	tests := []struct {
+		action webhooks.Action
+		call   string
+	}{
+		{webhooks.ActionOpened, "create"},
+		{webhooks.ActionReady, "ready"},
+		{webhooks.ActionMerged, "merge"},
+		{webhooks.ActionClosed, "close"},
+		{webhooks.ActionReopen, "reopen"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.action), func(t *testing.T) {
+			prs := &prOperatorMock{}
+			svc := New(prs, identitiesMock{"github/octocat": "u1"})
+
+			res, err := svc.Apply(context.Background(), webhooks.Event{
+				Provider:      webhooks.ProviderGitHub,
+				Action:        tt.action,
+				PullRequestID: "octo-org/api#42",
+				AuthorLogin:   "Octocat",
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !res.Applied || res.PullRequest.ID != "octo-org/api#42" {
+				t.Fatalf("unexpected result: %+v", res)
+			}
+			if len(prs.calls) != 1 || prs.calls[0] != tt.call {
+				t.Fatalf("expected %s call, got %v", tt.call, prs.calls)
+			}
+		})
+	}
+}
+
+func TestApply_OpenedResolvesAuthorAndDraft(t *testing.T) {
+	prs := &prOperatorMock{}
+	svc := New(prs, identitiesMock{"github/octocat": "u1"})
+
+	_, err := svc.Apply(context.Background(), webhooks.Event{
+		Provider: webhooks.ProviderGitHub, Action: webhooks.ActionOpened,
+		PullRequestID: "octo-org/api#43", AuthorLogin: "Octocat", Draft: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prs.gotAuthor != "u1" || !prs.gotDraft {
+		t.Fatalf("expected draft PR by u1, got author=%q draft=%v", prs.gotAuthor, prs.gotDraft)
+	}
+}
+
+func TestApply_OpenedUnknownLogin(t *testing.T) {
+	svc := New(&prOperatorMock{}, identitiesMock{})
+
+	_, err := svc.Apply(context.Background(), webhooks.Event{
+		Provider: webhooks.ProviderGitHub, Action: webhooks.ActionOpened,
+		PullRequestID: "octo-org/api#42", AuthorLogin: "stranger",
+	})
+	if !errors.Is(err, serviceerrors.ErrIdentityNotLinked) {
+		t.Fatalf("expected ErrIdentityNotLinked, got %v", err)
+	}
+}
+
+func TestApply_OpenedRedelivery(t *testing.T) {
+	prs := &prOperatorMock{createErr: serviceerrors.ErrPRExists}
+	svc := New(prs, identitiesMock{"github/octocat": "u1"})
+
+	res, err := svc.Apply(context.Background(), webhooks.Event{
+		Provider: webhooks.ProviderGitHub, Action: webhooks.ActionOpened,
+		PullRequestID: "octo-org/api#42", AuthorLogin: "octocat",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Applied {
+		t.Fatalf("expected redelivered event not to be applied")
+	}
+}
+
+// A merge on the provider has happened already, so it is recorded even when the
+// PR lacks the approvals /pullRequest/merge would require.
+func TestApply_MergedSkipsApprovalGate(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewStorage().Repositories()
+	if err := repos.Teams.CreateTeam(ctx, "backend"); err != nil {
+		t.Fatal(err)
+	}
+	err := repos.Users.UpsertManyForTeam(ctx, "backend", []*user.User{
+		{ID: "u1", IsActive: true}, {ID: "u2", IsActive: true}, {ID: "u3", IsActive: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings := team.DefaultSettings("backend")
+	settings.RequiredApprovals = 2
+	if err := repos.Teams.UpsertSettings(ctx, settings); err != nil {
+		t.Fatal(err)
+	}
+	a, err := assigner.New(repos.Users, repos.Teams, repos.PRs, assigner.StrategyRandom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prs := prservice.New(repos.PRs, repos.Users, a)
+	if _, err := prs.Create(ctx, "octo-org/api#42", "Add search", "u1", false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prs.Merge(ctx, "octo-org/api#42"); !errors.Is(err, serviceerrors.ErrNotEnoughApprovals) {
+		t.Fatalf("expected ErrNotEnoughApprovals, got %v", err)
+	}
+
+	res, err := New(prs, identitiesMock{}).Apply(ctx, webhooks.Event{
+		Provider: webhooks.ProviderGitHub, Action: webhooks.ActionMerged, PullRequestID: "octo-org/api#42",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.Applied || res.PullRequest.Status != pullrequest.StatusMerged || res.PullRequest.MergedAt == nil {
+		t.Fatalf("unexpected result: %+v", res.PullRequest)
+	}
+}
@@ -0,0 +1,137 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hihikaAAa/PRManager/internal/webhooks"
+)
+
+const (
+	HeaderEvent     = "X-GitHub-Event"
+	HeaderSignature = "X-Hub-Signature-256"
+	HeaderDelivery  = "X-GitHub-Delivery"
+
+	EventPing        = "ping"
+	EventPullRequest = "pull_request"
+
+	signaturePrefix = "sha256="
+)
+
+var ErrInvalidSignature = fmt.Errorf("%w: invalid signature", webhooks.ErrUnauthorized)
+
+// Provider receives GitHub repository webhooks signed with a shared secret.
+type Provider struct {
+	secret []byte
+}
+
+func NewProvider(secret string) *Provider {
+	return &Provider{secret: []byte(secret)}
+}
+
+func (p *Provider) Name() string {
+	return webhooks.ProviderGitHub
+}
+
+func (p *Provider) Authenticate(header http.Header, body []byte) error {
+	return VerifySignature(p.secret, body, header.Get(HeaderSignature))
+}
+
+func (p *Provider) Parse(header http.Header, body []byte) (webhooks.Event, bool, error) {
+	if header.Get(HeaderEvent) != EventPullRequest {
+		return webhooks.Event{}, false, nil
+	}
+	return ParsePullRequest(body)
+}
+
+type pullRequestPayload struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Title  string `json:"title"`
+		Draft  bool   `json:"draft"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// VerifySignature checks the X-Hub-Signature-256 header against the HMAC-SHA256 of the body.
+func VerifySignature(secret, body []byte, header string) error {
+	if len(secret) == 0 || !strings.HasPrefix(header, signaturePrefix) {
+		return ErrInvalidSignature
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(header, signaturePrefix))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Sign returns the X-Hub-Signature-256 header value for the body.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// PullRequestID maps a GitHub pull request onto our pull_request_id, e.g. "octo-org/api#42".
+func PullRequestID(repoFullName string, number int) string {
+	return fmt.Sprintf("%s#%d", repoFullName, number)
+}
+
+// ParsePullRequest converts a pull_request payload into an Event.
+// ok is false for actions the service does not track (edited, labeled, ...).
+func ParsePullRequest(body []byte) (webhooks.Event, bool, error) {
+	const op = "internal.webhooks.github.ParsePullRequest"
+
+	var p pullRequestPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return webhooks.Event{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+	if p.Repository.FullName == "" || p.Number == 0 {
+		return webhooks.Event{}, false, fmt.Errorf("%s: repository or number is missing", op)
+	}
+
+	ev := webhooks.Event{
+		Provider:      webhooks.ProviderGitHub,
+		PullRequestID: PullRequestID(p.Repository.FullName, p.Number),
+		Title:         p.PullRequest.Title,
+		AuthorLogin:   p.PullRequest.User.Login,
+		Draft:         p.PullRequest.Draft,
+	}
+
+	switch p.Action {
+	case "opened":
+		ev.Action = webhooks.ActionOpened
+	case "ready_for_review":
+		ev.Action = webhooks.ActionReady
+	case "closed":
+		ev.Action = webhooks.ActionClosed
+		if p.PullRequest.Merged {
+			ev.Action = webhooks.ActionMerged
+		}
+	case "reopened":
+		ev.Action = webhooks.ActionReopen
+	default:
+		return webhooks.Event{}, false, nil
+	}
+
+	return ev, true, nil
+}
@@ -0,0 +1,137 @@
+package github
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/webhooks"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("read fixture %s: %v", name, err)
+	}
+	return body
+}
+
+func TestVerifySignature(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("It's a Secret to Everybody")
+	body := []byte("Hello, World!")
+
+	// Example from the GitHub webhook validation docs.
+	const docsSignature = "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+
+	if err := VerifySignature(secret, body, docsSignature); err != nil {
+		t.Fatalf("expected docs signature to be valid, got %v", err)
+	}
+	if got := Sign(secret, body); got != docsSignature {
+		t.Fatalf("expected %s, got %s", docsSignature, got)
+	}
+
+	cases := map[string]string{
+		"empty":      "",
+		"no prefix":  "757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17",
+		"sha1":       "sha1=757107ea0eb2509fc211221cce984b8a37570b6d",
+		"not hex":    "sha256=zz",
+		"wrong hmac": Sign([]byte("other secret"), body),
+	}
+	for name, header := range cases {
+		if err := VerifySignature(secret, body, header); !errors.Is(err, ErrInvalidSignature) {
+			t.Fatalf("%s: expected ErrInvalidSignature, got %v", name, err)
+		}
+	}
+
+	if err := VerifySignature(nil, body, Sign(nil, body)); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("empty secret must never verify, got %v", err)
+	}
+}
+
+func TestParsePullRequest_Fixtures(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		fixture string
+		action  webhooks.Action
+		id      string
+		draft   bool
+	}{
+		{fixture: "pull_request_opened.json", action: webhooks.ActionOpened, id: "octo-org/api#42"},
+		{fixture: "pull_request_opened_draft.json", action: webhooks.ActionOpened, id: "octo-org/api#43", draft: true},
+		{fixture: "pull_request_ready_for_review.json", action: webhooks.ActionReady, id: "octo-org/api#43"},
+		{fixture: "pull_request_closed_merged.json", action: webhooks.ActionMerged, id: "octo-org/api#42"},
+		{fixture: "pull_request_closed.json", action: webhooks.ActionClosed, id: "octo-org/api#42"},
+		{fixture: "pull_request_reopened.json", action: webhooks.ActionReopen, id: "octo-org/api#42"},
+	}
+
+	for _, tc := range cases {
+		ev, ok, err := ParsePullRequest(readFixture(t, tc.fixture))
+		if err != nil || !ok {
+			t.Fatalf("%s: expected event, got ok=%v err=%v", tc.fixture, ok, err)
+		}
+		if ev.Action != tc.action || ev.PullRequestID != tc.id || ev.Draft != tc.draft {
+			t.Fatalf("%s: unexpected event %#v", tc.fixture, ev)
+		}
+		if ev.Provider != webhooks.ProviderGitHub || ev.AuthorLogin != "Octocat" || ev.Title == "" {
+			t.Fatalf("%s: unexpected event %#v", tc.fixture, ev)
+		}
+	}
+}
+
+func TestParsePullRequest_IgnoredAction(t *testing.T) {
+	t.Parallel()
+
+	_, ok, err := ParsePullRequest(readFixture(t, "pull_request_labeled.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Fatalf("labeled action must be ignored")
+	}
+}
+
+func TestParsePullRequest_Invalid(t *testing.T) {
+	t.Parallel()
+
+	if _, _, err := ParsePullRequest([]byte(`{"action":`)); err == nil {
+		t.Fatalf("expected error for malformed json")
+	}
+	if _, _, err := ParsePullRequest([]byte(`{"action":"opened"}`)); err == nil {
+		t.Fatalf("expected error for payload without repository")
+	}
+}
+
+func TestProvider(t *testing.T) {
+	t.Parallel()
+
+	p := NewProvider("s3cr3t")
+	body := readFixture(t, "pull_request_opened.json")
+
+	header := http.Header{}
+	header.Set(HeaderEvent, EventPullRequest)
+	header.Set(HeaderSignature, Sign([]byte("s3cr3t"), body))
+
+	if err := p.Authenticate(header, body); err != nil {
+		t.Fatalf("expected signed delivery to authenticate, got %v", err)
+	}
+	if ev, ok, err := p.Parse(header, body); err != nil || !ok || ev.Action != webhooks.ActionOpened {
+		t.Fatalf("unexpected parse result: %#v ok=%v err=%v", ev, ok, err)
+	}
+
+	header.Set(HeaderSignature, Sign([]byte("other"), body))
+	if err := p.Authenticate(header, body); !errors.Is(err, webhooks.ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+
+	header.Set(HeaderEvent, EventPing)
+	if _, ok, err := p.Parse(header, readFixture(t, "ping.json")); err != nil || ok {
+		t.Fatalf("ping must be ignored, got ok=%v err=%v", ok, err)
+	}
+}
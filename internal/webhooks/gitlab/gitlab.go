@@ -0,0 +1,135 @@
+package gitlab
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hihikaAAa/PRManager/internal/webhooks"
+)
+
+const (
+	HeaderEvent = "X-Gitlab-Event"
+	HeaderToken = "X-Gitlab-Token"
+
+	EventMergeRequest = "Merge Request Hook"
+)
+
+var ErrInvalidToken = fmt.Errorf("%w: invalid token", webhooks.ErrUnauthorized)
+
+type change[T any] struct {
+	Previous T `json:"previous"`
+	Current  T `json:"current"`
+}
+
+type mergeRequestPayload struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID            int    `json:"iid"`
+		Title          string `json:"title"`
+		Action         string `json:"action"`
+		Draft          bool   `json:"draft"`
+		WorkInProgress bool   `json:"work_in_progress"`
+	} `json:"object_attributes"`
+	Changes struct {
+		Draft          *change[bool] `json:"draft"`
+		WorkInProgress *change[bool] `json:"work_in_progress"`
+	} `json:"changes"`
+}
+
+// Provider receives GitLab project webhooks protected with a secret token.
+type Provider struct {
+	token []byte
+}
+
+func NewProvider(token string) *Provider {
+	return &Provider{token: []byte(token)}
+}
+
+func (p *Provider) Name() string {
+	return webhooks.ProviderGitLab
+}
+
+// Authenticate compares X-Gitlab-Token with the configured token in constant time.
+func (p *Provider) Authenticate(header http.Header, _ []byte) error {
+	got := []byte(header.Get(HeaderToken))
+	if len(p.token) == 0 || subtle.ConstantTimeCompare(got, p.token) != 1 {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+func (p *Provider) Parse(header http.Header, body []byte) (webhooks.Event, bool, error) {
+	if header.Get(HeaderEvent) != EventMergeRequest {
+		return webhooks.Event{}, false, nil
+	}
+	return ParseMergeRequest(body)
+}
+
+// MergeRequestID maps a GitLab merge request onto our pull_request_id, e.g. "octo-group/api!7".
+func MergeRequestID(projectPath string, iid int) string {
+	return fmt.Sprintf("%s!%d", projectPath, iid)
+}
+
+// ParseMergeRequest converts a Merge Request Hook payload into an Event.
+// GitLab does not send the author login, so the user who triggered the event is used;
+// for "open" it is the author.
+// ok is false for actions the service does not track (approved, plain updates, ...).
+func ParseMergeRequest(body []byte) (webhooks.Event, bool, error) {
+	const op = "internal.webhooks.gitlab.ParseMergeRequest"
+
+	var p mergeRequestPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return webhooks.Event{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+	if p.Project.PathWithNamespace == "" || p.ObjectAttributes.IID == 0 {
+		return webhooks.Event{}, false, fmt.Errorf("%s: project or iid is missing", op)
+	}
+
+	attrs := p.ObjectAttributes
+	ev := webhooks.Event{
+		Provider:      webhooks.ProviderGitLab,
+		PullRequestID: MergeRequestID(p.Project.PathWithNamespace, attrs.IID),
+		Title:         attrs.Title,
+		AuthorLogin:   p.User.Username,
+		Draft:         attrs.Draft || attrs.WorkInProgress,
+	}
+
+	switch attrs.Action {
+	case "open":
+		ev.Action = webhooks.ActionOpened
+	case "update":
+		if !p.leftDraft() {
+			return webhooks.Event{}, false, nil
+		}
+		ev.Action = webhooks.ActionReady
+	case "merge":
+		ev.Action = webhooks.ActionMerged
+	case "close":
+		ev.Action = webhooks.ActionClosed
+	case "reopen":
+		ev.Action = webhooks.ActionReopen
+	default:
+		return webhooks.Event{}, false, nil
+	}
+
+	return ev, true, nil
+}
+
+// leftDraft reports whether an update marked the merge request as ready.
+// Older GitLab versions only send work_in_progress.
+func (p mergeRequestPayload) leftDraft() bool {
+	for _, c := range []*change[bool]{p.Changes.Draft, p.Changes.WorkInProgress} {
+		if c != nil && c.Previous && !c.Current {
+			return true
+		}
+	}
+	return false
+}
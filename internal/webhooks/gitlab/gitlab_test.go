@@ -0,0 +1,111 @@
+package gitlab
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hihikaAAa/PRManager/internal/webhooks"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("read fixture %s: %v", name, err)
+	}
+	return body
+}
+
+func TestProvider_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	p := NewProvider("s3cr3t")
+
+	header := http.Header{}
+	header.Set(HeaderToken, "s3cr3t")
+	if err := p.Authenticate(header, nil); err != nil {
+		t.Fatalf("expected valid token, got %v", err)
+	}
+
+	for _, token := range []string{"", "S3CR3T", "s3cr3t "} {
+		header.Set(HeaderToken, token)
+		if err := p.Authenticate(header, nil); !errors.Is(err, webhooks.ErrUnauthorized) {
+			t.Fatalf("token %q: expected ErrUnauthorized, got %v", token, err)
+		}
+	}
+
+	header.Set(HeaderToken, "")
+	if err := NewProvider("").Authenticate(header, nil); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("empty token must never authenticate, got %v", err)
+	}
+}
+
+func TestParseMergeRequest_Fixtures(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		fixture string
+		action  webhooks.Action
+		id      string
+		draft   bool
+	}{
+		{fixture: "merge_request_open.json", action: webhooks.ActionOpened, id: "octo-group/api!7"},
+		{fixture: "merge_request_open_draft.json", action: webhooks.ActionOpened, id: "octo-group/api!8", draft: true},
+		{fixture: "merge_request_update_ready.json", action: webhooks.ActionReady, id: "octo-group/api!8"},
+		{fixture: "merge_request_merge.json", action: webhooks.ActionMerged, id: "octo-group/api!7"},
+		{fixture: "merge_request_close.json", action: webhooks.ActionClosed, id: "octo-group/api!7"},
+		{fixture: "merge_request_reopen.json", action: webhooks.ActionReopen, id: "octo-group/api!7"},
+	}
+
+	for _, tc := range cases {
+		ev, ok, err := ParseMergeRequest(readFixture(t, tc.fixture))
+		if err != nil || !ok {
+			t.Fatalf("%s: expected event, got ok=%v err=%v", tc.fixture, ok, err)
+		}
+		if ev.Action != tc.action || ev.PullRequestID != tc.id || ev.Draft != tc.draft {
+			t.Fatalf("%s: unexpected event %#v", tc.fixture, ev)
+		}
+		if ev.Provider != webhooks.ProviderGitLab || ev.AuthorLogin != "Octocat" || ev.Title == "" {
+			t.Fatalf("%s: unexpected event %#v", tc.fixture, ev)
+		}
+	}
+}
+
+func TestParseMergeRequest_Ignored(t *testing.T) {
+	t.Parallel()
+
+	for _, fixture := range []string{"merge_request_update_title.json", "merge_request_approved.json"} {
+		_, ok, err := ParseMergeRequest(readFixture(t, fixture))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", fixture, err)
+		}
+		if ok {
+			t.Fatalf("%s: must be ignored", fixture)
+		}
+	}
+}
+
+func TestProvider_ParseOtherEvents(t *testing.T) {
+	t.Parallel()
+
+	header := http.Header{}
+	header.Set(HeaderEvent, "Push Hook")
+	if _, ok, err := NewProvider("t").Parse(header, []byte(`{"object_kind":"push"}`)); err != nil || ok {
+		t.Fatalf("push hook must be ignored, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestParseMergeRequest_Invalid(t *testing.T) {
+	t.Parallel()
+
+	if _, _, err := ParseMergeRequest([]byte(`{"object_kind":`)); err == nil {
+		t.Fatalf("expected error for malformed json")
+	}
+	if _, _, err := ParseMergeRequest([]byte(`{"object_kind":"merge_request"}`)); err == nil {
+		t.Fatalf("expected error for payload without project")
+	}
+}
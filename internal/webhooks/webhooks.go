@@ -0,0 +1,56 @@
+package webhooks
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Action is a pull request change reported by a code hosting provider.
+type Action string
+
+const (
+	ActionOpened Action = "opened"
+	ActionReady  Action = "ready"
+	ActionMerged Action = "merged"
+	ActionClosed Action = "closed"
+	ActionReopen Action = "reopened"
+)
+
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// ErrUnauthorized is returned by Provider.Authenticate when the delivery is not signed by the provider.
+var ErrUnauthorized = errors.New("webhook authentication failed")
+
+func IsKnownProvider(name string) bool {
+	switch name {
+	case ProviderGitHub, ProviderGitLab:
+		return true
+	default:
+		return false
+	}
+}
+
+// Event is a provider-neutral pull request event.
+// PullRequestID is already mapped to our pull_request_id.
+type Event struct {
+	Provider      string
+	Action        Action
+	PullRequestID string
+	Title         string
+	AuthorLogin   string
+	Draft         bool
+}
+
+// Provider turns raw deliveries of one code hosting provider into Events.
+// Adding a provider means implementing this interface and registering it in main.
+type Provider interface {
+	// Name is the provider name used in routes and user identities.
+	Name() string
+	// Authenticate checks the delivery and returns an error wrapping ErrUnauthorized on mismatch.
+	Authenticate(header http.Header, body []byte) error
+	// Parse returns ok = false for deliveries the service does not track.
+	Parse(header http.Header, body []byte) (ev Event, ok bool, err error)
+}
@@ -0,0 +1,12 @@
+BEGIN;
+
+CREATE TABLE team_settings (
+    team_name TEXT PRIMARY KEY REFERENCES teams(team_name) ON DELETE CASCADE,
+    reviewers_required INT NOT NULL DEFAULT 2 CHECK (reviewers_required >= 0),
+    min_reviewers INT NOT NULL DEFAULT 0 CHECK (min_reviewers >= 0),
+    strategy TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (min_reviewers <= reviewers_required)
+);
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+CREATE TABLE team_fallbacks (
+    team_name TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    fallback_team TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    position INT NOT NULL,
+    PRIMARY KEY (team_name, fallback_team),
+    CHECK (team_name <> fallback_team)
+);
+
+CREATE INDEX idx_team_fallbacks_order ON team_fallbacks(team_name, position);
+
+COMMIT;
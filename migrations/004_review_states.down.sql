@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE team_settings DROP COLUMN IF EXISTS required_approvals;
+
+ALTER TABLE pull_request_reviewers
+    DROP COLUMN IF EXISTS state_updated_at,
+    DROP COLUMN IF EXISTS state;
+
+DROP TYPE IF EXISTS review_state;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+CREATE TYPE review_state AS ENUM ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'COMMENTED');
+
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN state review_state NOT NULL DEFAULT 'PENDING',
+    ADD COLUMN state_updated_at TIMESTAMPTZ;
+
+ALTER TABLE team_settings
+    ADD COLUMN required_approvals INT NOT NULL DEFAULT 0 CHECK (required_approvals >= 0);
+
+COMMIT;
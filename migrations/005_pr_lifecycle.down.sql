@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Postgres cannot drop enum values, so the type is recreated.
+-- DRAFT and CLOSED pull requests fall back to OPEN.
+ALTER TABLE pull_requests ALTER COLUMN status TYPE TEXT;
+UPDATE pull_requests SET status = 'OPEN' WHERE status IN ('DRAFT', 'CLOSED');
+
+DROP TYPE pr_status;
+CREATE TYPE pr_status AS ENUM ('OPEN', 'MERGED');
+
+ALTER TABLE pull_requests ALTER COLUMN status TYPE pr_status USING status::pr_status;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS closed_at;
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+CREATE TABLE user_identities (
+    provider TEXT NOT NULL,
+    login TEXT NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (provider, login)
+);
+
+CREATE INDEX idx_user_identities_user ON user_identities(user_id);
+
+COMMIT;
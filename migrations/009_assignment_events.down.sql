@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS actor;
+
+DROP TABLE IF EXISTS assignment_events;
+DROP FUNCTION IF EXISTS assignment_events_append_only();
+
+COMMIT;
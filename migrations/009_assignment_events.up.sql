@@ -0,0 +1,33 @@
+BEGIN;
+
+CREATE TABLE assignment_events (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT NOT NULL,
+    old_reviewer_id TEXT,
+    new_reviewer_id TEXT,
+    reason TEXT NOT NULL CHECK (reason IN ('auto_create', 'fallback', 'manual_reassign', 'team_deactivation', 'pr_closed')),
+    from_fallback BOOLEAN NOT NULL DEFAULT FALSE,
+    actor TEXT NOT NULL,
+    event_id TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    CHECK (old_reviewer_id IS NOT NULL OR new_reviewer_id IS NOT NULL)
+);
+
+-- History is append-only and outlives deleted PRs and users, so there are no foreign keys.
+CREATE INDEX idx_assignment_events_pr ON assignment_events(pull_request_id, id);
+CREATE INDEX idx_assignment_events_old ON assignment_events(old_reviewer_id, id);
+CREATE INDEX idx_assignment_events_new ON assignment_events(new_reviewer_id, id);
+
+CREATE FUNCTION assignment_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'assignment_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER assignment_events_no_change
+BEFORE UPDATE OR DELETE ON assignment_events
+FOR EACH ROW EXECUTE FUNCTION assignment_events_append_only();
+
+ALTER TABLE outbox ADD COLUMN actor TEXT NOT NULL DEFAULT 'system';
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- History is append-only: imported rows are kept, the old constraint only applies to new rows.
+ALTER TABLE assignment_events DROP CONSTRAINT assignment_events_reason_check;
+ALTER TABLE assignment_events ADD CONSTRAINT assignment_events_reason_check
+    CHECK (reason IN ('auto_create', 'fallback', 'manual_reassign', 'team_deactivation', 'pr_closed')) NOT VALID;
+
+COMMIT;
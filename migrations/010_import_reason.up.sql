@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Reviewers taken over from an imported PR (/pullRequest/bulkCreate).
+ALTER TABLE assignment_events DROP CONSTRAINT assignment_events_reason_check;
+ALTER TABLE assignment_events ADD CONSTRAINT assignment_events_reason_check
+    CHECK (reason IN ('auto_create', 'fallback', 'manual_reassign', 'team_deactivation', 'pr_closed', 'import'));
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+-- History is append-only: existing rows are kept, the old constraint only applies to new rows.
+ALTER TABLE assignment_events DROP CONSTRAINT assignment_events_reason_check;
+ALTER TABLE assignment_events ADD CONSTRAINT assignment_events_reason_check
+    CHECK (reason IN ('auto_create', 'fallback', 'manual_reassign', 'team_deactivation', 'pr_closed', 'import')) NOT VALID;
+
+-- Fails while there are users without a team; add them to a team first.
+ALTER TABLE users ALTER COLUMN team_name SET NOT NULL;
+
+COMMIT;
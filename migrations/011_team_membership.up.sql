@@ -0,0 +1,11 @@
+BEGIN;
+
+-- Users removed from their team (/team/removeMembers) keep their PRs and history but have no team.
+ALTER TABLE users ALTER COLUMN team_name DROP NOT NULL;
+
+-- Reviews handed over when a member leaves a team.
+ALTER TABLE assignment_events DROP CONSTRAINT assignment_events_reason_check;
+ALTER TABLE assignment_events ADD CONSTRAINT assignment_events_reason_check
+    CHECK (reason IN ('auto_create', 'fallback', 'manual_reassign', 'team_deactivation', 'pr_closed', 'import', 'team_change'));
+
+COMMIT;
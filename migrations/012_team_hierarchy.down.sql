@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE team_settings DROP COLUMN IF EXISTS search_parents;
+
+DROP INDEX IF EXISTS idx_teams_parent;
+ALTER TABLE teams DROP COLUMN IF EXISTS parent_team;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+-- Teams form a tree (organization -> department -> team). /team/delete re-parents
+-- the children of a deleted team; SET NULL only keeps manual deletes consistent.
+ALTER TABLE teams ADD COLUMN parent_team TEXT REFERENCES teams(team_name) ON DELETE SET NULL;
+ALTER TABLE teams ADD CONSTRAINT teams_parent_check CHECK (parent_team <> team_name);
+CREATE INDEX idx_teams_parent ON teams(parent_team);
+
+-- Candidates are searched in the parent teams when the team itself has too few.
+ALTER TABLE team_settings ADD COLUMN search_parents BOOLEAN NOT NULL DEFAULT false;
+
+COMMIT;
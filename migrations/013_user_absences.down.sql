@@ -0,0 +1,10 @@
+BEGIN;
+
+-- History is append-only: existing rows are kept, the old constraint only applies to new rows.
+ALTER TABLE assignment_events DROP CONSTRAINT assignment_events_reason_check;
+ALTER TABLE assignment_events ADD CONSTRAINT assignment_events_reason_check
+    CHECK (reason IN ('auto_create', 'fallback', 'manual_reassign', 'team_deactivation', 'pr_closed', 'import', 'team_change')) NOT VALID;
+
+DROP TABLE IF EXISTS user_absences;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_max_open_reviews_check;
+ALTER TABLE users DROP COLUMN IF EXISTS max_open_reviews;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- A user with max_open_reviews > 0 is not picked as a reviewer while they already
+-- review that many open PRs; 0 means no limit.
+ALTER TABLE users ADD COLUMN max_open_reviews INT NOT NULL DEFAULT 0;
+ALTER TABLE users ADD CONSTRAINT users_max_open_reviews_check CHECK (max_open_reviews >= 0);
+
+COMMIT;
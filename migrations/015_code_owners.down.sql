@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS team_code_owners;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS changed_files;
+
+COMMIT;
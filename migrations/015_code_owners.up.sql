@@ -0,0 +1,15 @@
+BEGIN;
+
+-- Paths changed by the PR; reviewers owning them are preferred.
+ALTER TABLE pull_requests ADD COLUMN changed_files TEXT[] NOT NULL DEFAULT '{}';
+
+-- CODEOWNERS-style rules of a team in file order; a later matching rule wins.
+CREATE TABLE team_code_owners (
+    team_name TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    position INT NOT NULL,
+    pattern TEXT NOT NULL,
+    owners TEXT[] NOT NULL,
+    PRIMARY KEY (team_name, position)
+);
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+-- Deliveries waiting for their next attempt. The notifier tries a subscriber once
+-- inline; a transient failure is queued here and retried by the notifier's worker.
+-- Attempts counts the failed ones.
+CREATE TABLE webhook_retries (
+    id BIGSERIAL PRIMARY KEY,
+    subscriber_id BIGINT NOT NULL REFERENCES webhook_subscribers(id) ON DELETE CASCADE,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (subscriber_id, event_id)
+);
+
+CREATE INDEX idx_webhook_retries_due ON webhook_retries(next_attempt_at);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_outbox_published;
+DROP INDEX IF EXISTS idx_outbox_pending;
+CREATE INDEX idx_outbox_pending ON outbox(id) WHERE published_at IS NULL;
+ALTER TABLE outbox DROP COLUMN IF EXISTS dead_at;
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+-- The relay stops retrying an event after outbox.max_attempts failures and sets dead_at.
+-- Published events are deleted after outbox.retention.
+ALTER TABLE outbox ADD COLUMN dead_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS idx_outbox_pending;
+CREATE INDEX idx_outbox_pending ON outbox(id) WHERE published_at IS NULL AND dead_at IS NULL;
+CREATE INDEX idx_outbox_published ON outbox(published_at) WHERE published_at IS NOT NULL;
+
+COMMIT;
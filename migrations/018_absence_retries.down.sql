@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE user_absences DROP COLUMN IF EXISTS next_attempt_at;
+ALTER TABLE user_absences DROP COLUMN IF EXISTS attempts;
+
+COMMIT;
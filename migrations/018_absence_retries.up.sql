@@ -0,0 +1,8 @@
+BEGIN;
+
+-- A failed handover is retried with a backoff: the absence job skips the absence
+-- until next_attempt_at, so it does not hold back the ones started after it.
+ALTER TABLE user_absences ADD COLUMN attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE user_absences ADD COLUMN next_attempt_at TIMESTAMPTZ;
+
+COMMIT;
@@ -0,0 +1,7 @@
+// Package migrations embeds the PostgreSQL schema migrations into the service binary.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS